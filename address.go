@@ -0,0 +1,93 @@
+package peeringdb
+
+import (
+	"strings"
+)
+
+// PostalAddress is a normalized postal address of a facility or of an
+// organization.
+type PostalAddress struct {
+	Address1 string
+	Address2 string
+	Floor    string
+	Suite    string
+	City     string
+	State    string
+	Zipcode  string
+	Country  string
+}
+
+// newPostalAddress returns a PostalAddress with normalized values, leading,
+// trailing and repeated spaces being removed.
+func newPostalAddress(address1, address2, floor, suite, city, state, zipcode, country string) PostalAddress {
+	return PostalAddress{
+		Address1: normalizeSpaces(address1),
+		Address2: normalizeSpaces(address2),
+		Floor:    normalizeSpaces(floor),
+		Suite:    normalizeSpaces(suite),
+		City:     normalizeSpaces(city),
+		State:    normalizeSpaces(state),
+		Zipcode:  normalizeSpaces(zipcode),
+		Country:  strings.ToUpper(normalizeSpaces(country)),
+	}
+}
+
+// Lines returns the address as a list of lines, empty lines being left out.
+// Floor and suite are on the same line, as are city, state and zipcode.
+func (a PostalAddress) Lines() []string {
+	var lines []string
+	appendLine := func(parts []string, separator string) {
+		var values []string
+		for _, part := range parts {
+			if part != "" {
+				values = append(values, part)
+			}
+		}
+		if len(values) > 0 {
+			lines = append(lines, strings.Join(values, separator))
+		}
+	}
+
+	appendLine([]string{a.Address1}, "")
+	appendLine([]string{a.Address2}, "")
+	appendLine([]string{prefixLabel("Floor", a.Floor), prefixLabel("Suite", a.Suite)}, ", ")
+	appendLine([]string{a.City, strings.TrimSpace(a.State + " " + a.Zipcode)}, ", ")
+	appendLine([]string{a.Country}, "")
+
+	return lines
+}
+
+// String returns the address on a single line.
+func (a PostalAddress) String() string {
+	return strings.Join(a.Lines(), ", ")
+}
+
+// MultiLine returns the address on several lines, as written on an envelope.
+func (a PostalAddress) MultiLine() string {
+	return strings.Join(a.Lines(), "\n")
+}
+
+// Address returns the normalized postal address of the facility.
+func (f Facility) Address() PostalAddress {
+	return newPostalAddress(f.Address1, f.Address2, f.Floor, f.Suite, f.City, f.State, f.Zipcode, f.Country)
+}
+
+// Address returns the normalized postal address of the organization.
+func (o Organization) Address() PostalAddress {
+	return newPostalAddress(o.Address1, o.Address2, o.Floor, o.Suite, o.City, o.State, o.Zipcode, o.Country)
+}
+
+// normalizeSpaces removes leading, trailing and repeated spaces.
+func normalizeSpaces(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// prefixLabel prefixes a value with a label unless the value already starts
+// with it, "3" becoming "Floor 3" while "Floor 3" is kept as is.
+func prefixLabel(label, value string) string {
+	if value == "" || strings.HasPrefix(strings.ToLower(value), strings.ToLower(label)) {
+		return value
+	}
+
+	return label + " " + value
+}
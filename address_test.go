@@ -0,0 +1,30 @@
+package peeringdb
+
+import "testing"
+
+func TestAddress(t *testing.T) {
+	facility := Facility{
+		Address1: "  1 Main   Street ",
+		Floor:    "3",
+		Suite:    "Suite 12",
+		City:     "Springfield",
+		State:    "IL",
+		Zipcode:  "62701",
+		Country:  "us",
+	}
+
+	expected := "1 Main Street, Floor 3, Suite 12, Springfield, IL 62701, US"
+	if address := facility.Address().String(); address != expected {
+		t.Errorf("Address, want '%s' got '%s'", expected, address)
+	}
+
+	organization := Organization{Address1: "Rue de la Paix", City: "Paris", Zipcode: "75002", Country: "FR"}
+	expected = "Rue de la Paix\nParis, 75002\nFR"
+	if address := organization.Address().MultiLine(); address != expected {
+		t.Errorf("Address, want '%s' got '%s'", expected, address)
+	}
+
+	if address := (Organization{}).Address().String(); address != "" {
+		t.Errorf("Address, want empty address got '%s'", address)
+	}
+}
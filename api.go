@@ -1,12 +1,20 @@
 package peeringdb
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"sort"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -21,11 +29,51 @@ const (
 	internetExchangePrefixNamespace     = "ixpfx"
 	networkNamespace                    = "net"
 	networkFacilityNamespace            = "netfac"
-	networkInternetExchangeLANNamepsace = "netixlan"
+	networkInternetExchangeLANNamespace = "netixlan"
 	organizationNamespace               = "org"
 	networkContactNamespace             = "poc"
+
+	// maxIDsPerQuery is the maximum number of values that are put in a single
+	// "__in" filter. Keeping it reasonable avoids hitting URL length limits.
+	maxIDsPerQuery = 100
 )
 
+// namespaces lists all namespaces known by this package. They are sorted so
+// that objects come after the ones they reference.
+var namespaces = []string{
+	organizationNamespace,
+	campusNamespace,
+	facilityNamespace,
+	carrierNamespace,
+	carrierFacilityNamespace,
+	internetExchangeNamespace,
+	internetExchangeLANNamespace,
+	internetExchangePrefixNamespace,
+	internetExchangeFacilityNamespace,
+	networkNamespace,
+	networkFacilityNamespace,
+	networkInternetExchangeLANNamespace,
+	networkContactNamespace,
+}
+
+// namespaceTypes gives the structure used to represent the objects of each
+// namespace.
+var namespaceTypes = map[string]reflect.Type{
+	organizationNamespace:               reflect.TypeOf(Organization{}),
+	campusNamespace:                     reflect.TypeOf(Campus{}),
+	facilityNamespace:                   reflect.TypeOf(Facility{}),
+	carrierNamespace:                    reflect.TypeOf(Carrier{}),
+	carrierFacilityNamespace:            reflect.TypeOf(CarrierFacility{}),
+	internetExchangeNamespace:           reflect.TypeOf(InternetExchange{}),
+	internetExchangeLANNamespace:        reflect.TypeOf(InternetExchangeLAN{}),
+	internetExchangePrefixNamespace:     reflect.TypeOf(InternetExchangePrefix{}),
+	internetExchangeFacilityNamespace:   reflect.TypeOf(InternetExchangeFacility{}),
+	networkNamespace:                    reflect.TypeOf(Network{}),
+	networkFacilityNamespace:            reflect.TypeOf(NetworkFacility{}),
+	networkInternetExchangeLANNamespace: reflect.TypeOf(NetworkInternetExchangeLAN{}),
+	networkContactNamespace:             reflect.TypeOf(NetworkContact{}),
+}
+
 var (
 	// ErrBuildingURL is the error that will be returned if the URL to call the
 	// API cannot be built as expected.
@@ -34,7 +82,8 @@ var (
 	// request to call the API cannot be built as expected.
 	ErrBuildingRequest = errors.New("error while building the request to send to the peeringdb api")
 	// ErrQueryingAPI is the error that will be returned if there is an issue
-	// while making the request to the API.
+	// while making the request to the API. Errors returned in such a case are
+	// APIError values which can be checked with errors.Is.
 	ErrQueryingAPI = errors.New("error while querying peeringdb api")
 	// ErrRateLimitExceeded is the error that will be returned if the API rate
 	// limit is exceeded.
@@ -45,132 +94,477 @@ var (
 // main structure of this package. All functions to make API calls are
 // associated to this structure.
 type API struct {
-	url    string
-	apiKey string
+	url        string
+	apiKey     string
+	apiKeyType APIKeyType
+	username   string
+	password   string
+	client     *http.Client
+	timeout    time.Duration
+
+	urlBuilder         URLBuilder
+	retryPolicy        RetryPolicy
+	maxResults         int
+	pageSize           int
+	depth              int
+	requestIDHeader    string
+	requestIDGenerator func() string
+	responseHooks      []func(ResponseInfo)
+	cacheHooks         []func(CacheInfo)
+	bodyHooks          []func(ResponseInfo, []byte)
+	signers            []RequestSigner
+	keyPool            *APIKeyPool
+
+	// compatibility tracks supported namespaces if the compatibility mode
+	// is enabled
+	compatibility *compatibility
+	// explain records calls instead of making them if set
+	explain *queryRecorder
+	// diskCache stores the responses of lookups if set
+	diskCache *diskCache
+	// flights shares the responses of concurrent identical lookups
+	flights *singleflight.Group
+	// breaker stops calls while the API is failing if set
+	breaker *CircuitBreaker
+	// etags keeps responses to revalidate them if set
+	etags *etagCache
+	// memoryCache serves recent lookups from memory if set
+	memoryCache *memoryCache
+}
+
+// newAPI returns a pointer to a new API structure using the given URL and API
+// key, tuned with the given options.
+func newAPI(url, apiKey string, options []Option) *API {
+	api := &API{
+		url:             withTrailingSlash(url),
+		apiKey:          apiKey,
+		client:          &http.Client{},
+		urlBuilder:      StandardURLBuilder{},
+		pageSize:        DefaultPageSize,
+		requestIDHeader: DefaultRequestIDHeader,
+		flights:         &singleflight.Group{},
+	}
+	for _, option := range options {
+		option(api)
+	}
+
+	return api
 }
 
 // NewAPI returns a pointer to a new API structure. It uses the publicly known
 // PeeringDB API endpoint.
-func NewAPI() *API {
-	return &API{url: baseAPI}
+func NewAPI(options ...Option) *API {
+	return newAPI(baseAPI, "", options)
 }
 
 // NewAPIWithAuth returns a pointer to a new API structure. The API will point
 // to the publicly known PeeringDB API endpoint and will use the provided API
 // key for authentication while making API calls.
-func NewAPIWithAPIKey(apiKey string) *API {
-	return &API{
-		url:    baseAPI,
-		apiKey: apiKey,
-	}
+func NewAPIWithAPIKey(apiKey string, options ...Option) *API {
+	return newAPI(baseAPI, apiKey, options)
 }
 
 // NewAPIFromURL returns a pointer to a new API structure from a given URL. If
 // the given URL is empty it will use the default PeeringDB API URL.
-func NewAPIFromURL(url string) *API {
+func NewAPIFromURL(url string, options ...Option) *API {
 	if url == "" {
-		return NewAPI()
+		return NewAPI(options...)
 	}
 
-	return &API{url: url}
+	return newAPI(url, "", options)
 }
 
 // NewAPIFromURLWithAPIKey returns a pointer to a new API structure from a given
 // URL. If the given URL is empty it will use the default PeeringDB API URL. It
 // will use the provided API key for authentication while making API calls.
-func NewAPIFromURLWithAPIKey(url, apiKey string) *API {
+func NewAPIFromURLWithAPIKey(url, apiKey string, options ...Option) *API {
 	if url == "" {
-		return NewAPIWithAPIKey(apiKey)
+		return NewAPIWithAPIKey(apiKey, options...)
 	}
 
-	return &API{
-		url:    url,
-		apiKey: apiKey,
-	}
+	return newAPI(url, apiKey, options)
 }
 
 // formatSearchParameters is used to format parameters for a request. When
-// building the search string the keys will be used in the alphabetic order.
+// building the search string the keys will be used in the alphabetic order,
+// keys and values being escaped.
 func formatSearchParameters(parameters map[string]interface{}) string {
-	// Nothing in slice, just return empty string
-	if parameters == nil {
+	values := searchValues(parameters)
+
+	// Nothing to search for, just return empty string
+	if len(values) == 0 {
 		return ""
 	}
 
-	var search string
-	var keys []string
+	return "&" + values.Encode()
+}
 
-	// Get all map keys
-	for i := range parameters {
-		keys = append(keys, i)
+// searchValues converts a search parameters map to URL values.
+func searchValues(parameters map[string]interface{}) url.Values {
+	values := make(url.Values, len(parameters))
+	for key, value := range parameters {
+		values[key] = parameterValues(key, value)
 	}
 
-	// Sort the keys slice
-	sort.Strings(keys)
+	return values
+}
 
-	// For each element, append it to the request separated by a & symbol.
-	for _, key := range keys {
-		search = search + "&" + key + "=" + url.QueryEscape(fmt.Sprintf("%v", parameters[key]))
+// parameterValues returns the values of a search parameter. A slice gives
+// several values, sent as a repeated parameter, except for the "__in"
+// operator which expects a single comma separated list.
+func parameterValues(key string, value interface{}) []string {
+	v := reflect.ValueOf(value)
+	if value == nil || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return []string{formatFilterValue(value)}
 	}
 
-	return search
+	values := make([]string, v.Len())
+	for i := range values {
+		values[i] = formatFilterValue(v.Index(i).Interface())
+	}
+	if strings.HasSuffix(key, "__in") {
+		return []string{strings.Join(values, ",")}
+	}
+
+	return values
+}
+
+// chunkIDs splits a slice of integers into slices of at most size elements.
+// Duplicated values are only kept once.
+func chunkIDs(ids []int, size int) [][]int {
+	var chunks [][]int
+	var chunk []int
+
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		chunk = append(chunk, id)
+		if len(chunk) == size {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// joinIDs formats a slice of integers as a comma separated list suitable for
+// an "__in" filter.
+func joinIDs(ids []int) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// getChunked fetches objects whose field value is in the given list. The list
+// is split in several "__in" queries if it is too large to fit in a single
+// one. The results of all queries are concatenated.
+func getChunked[T any](ids []int, field string, get func(map[string]interface{}) (*[]T, error)) ([]T, error) {
+	var objects []T
+
+	for _, search := range InInts(field, ids).Chunks() {
+		found, err := get(search)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, *found...)
+	}
+
+	return objects, nil
+}
+
+// nonNilSlice makes sure the slice pointed to is not nil, so that callers can
+// always dereference results and get an empty slice if nothing matches.
+func nonNilSlice[T any](objects *[]T) *[]T {
+	if *objects == nil {
+		*objects = []T{}
+	}
+
+	return objects
 }
 
 // formatURL is used to format a URL to make a request on PeeringDB API.
 func formatURL(base, namespace string, search map[string]interface{}) string {
-	return fmt.Sprintf("%s%s?depth=1%s", base, namespace,
-		formatSearchParameters(search))
+	return StandardURLBuilder{}.URL(base, namespace, search)
 }
 
 // lookup is used to query the PeeringDB API given a namespace to use and data
 // to format the request. It returns an HTTP response that the caller must
 // decode with a JSON decoder.
 func (api *API) lookup(namespace string, search map[string]interface{}) (*http.Response, error) {
-	url := formatURL(api.url, namespace, search)
+	return api.lookupWithContext(context.Background(), namespace, search)
+}
+
+// lookupWithContext is the same as lookup but the request is made with the
+// given context. A request ID is sent with the request, taken from the context
+// or generated, and errors returned after sending the request carry it.
+func (api *API) lookupWithContext(ctx context.Context, namespace string, search map[string]interface{}) (*http.Response, error) {
+	search, limited := api.limitSearch(api.depthSearch(search))
+	url := api.urlBuilder.URL(api.url, namespace, search)
 	if url == "" {
 		return nil, ErrBuildingURL
 	}
 
-	// Prepare the GET request to the API, no need to set a body since
-	// everything is in the URL
-	request, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, ErrBuildingRequest
+	if api.explain != nil {
+		return api.explain.record(http.MethodGet, namespace, url, search), nil
+	}
+	if supported, known := api.compatibility.lookup(namespace); known && !supported {
+		return emptyResponse(), nil
+	}
+
+	key := api.flightKey(ctx, url)
+	response := api.memoryCache.get(key)
+	if api.memoryCache != nil {
+		api.notifyCache(CacheInfo{Namespace: namespace, URL: url, Hit: response != nil})
+	}
+	var err error
+	if response == nil {
+		response, err = api.diskCache.get(url)
+		if api.diskCache.used() {
+			api.notifyCache(CacheInfo{Namespace: namespace, URL: url, Hit: response != nil})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if response == nil {
+			response, err = api.getShared(ctx, namespace, url)
+			if errors.Is(err, ErrNamespaceNotSupported) && api.compatibility != nil {
+				api.compatibility.set(namespace, false)
+				return emptyResponse(), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = api.diskCache.put(url, response); err != nil {
+				return nil, err
+			}
+		}
+		if err = api.memoryCache.put(key, response); err != nil {
+			return nil, err
+		}
+	}
+	if limited {
+		if err = api.checkTruncated(namespace, response); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// setHeaders sets the authentication and request ID headers of a request
+// made with the given context. It returns the API key used, its type and the
+// request ID sent, if any.
+func (api *API) setHeaders(ctx context.Context, request *http.Request) (string, APIKeyType, string) {
+	apiKey, apiKeyType := api.credentials(ctx)
+	switch {
+	case apiKey != "":
+		request.Header.Add("Authorization", fmt.Sprintf("Api-Key %s", apiKey))
+	case api.basicAuth(ctx):
+		request.SetBasicAuth(api.username, api.password)
+	}
+	requestID := api.requestID(ctx)
+	if requestID != "" {
+		request.Header.Set(api.requestIDHeader, requestID)
 	}
 
-	if api.apiKey != "" {
-		request.Header.Add("Authorization", fmt.Sprintf("Api-Key %s", api.apiKey))
+	return apiKey, apiKeyType, requestID
+}
+
+// do sends a request about objects of the given namespace to the API. It
+// authenticates the request, sets its request ID and checks the status of the
+// response. The body of the returned response is read from memory.
+func (api *API) do(ctx context.Context, namespace string, request *http.Request) (*http.Response, error) {
+	if timeout := api.timeoutFor(ctx); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		request = request.WithContext(ctx)
 	}
 
-	// Send the request to the API using a simple HTTP client
-	client := &http.Client{}
-	response, err := client.Do(request)
+	url := request.URL.String()
+	apiKey, apiKeyType, requestID := api.setHeaders(ctx, request)
+	key := etagKey(api.authScope(ctx, apiKey), url)
+	cached := api.etags.conditional(request, key)
+	if err := api.sign(request); err != nil {
+		return nil, withRequestID(err, requestID)
+	}
+	// Asked once nothing can fail before sending the request, so that a
+	// probe let through by a half-open breaker always has its outcome
+	// recorded
+	if err := api.breaker.allow(); err != nil {
+		return nil, err
+	}
+	if apiKey != "" {
+		api.keyPool.used(apiKey)
+	}
+
+	// Send the request to the API using the HTTP client shared by all calls
+	// so that connections are kept alive and reused
+	start := time.Now()
+	response, err := api.client.Do(request)
 	if err != nil {
-		return nil, ErrQueryingAPI
+		apiError := &APIError{Namespace: namespace, Method: request.Method, URL: url, Err: fmt.Errorf("%w: %w", ErrQueryingAPI, err)}
+		// Tell if the call was canceled or if its deadline was exceeded
+		if ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+			apiError.Err = fmt.Errorf("%w: %w: %w", ErrQueryingAPI, ctx.Err(), err)
+		}
+		err = withRequestID(apiError, requestID)
+		api.notifyResponse(ResponseInfo{
+			Namespace: namespace,
+			URL:       url,
+			RequestID: requestID,
+			Duration:  time.Since(start),
+			Retry:     retryFromContext(ctx),
+			Err:       err,
+		})
+		return nil, err
+	}
+
+	// Read the whole body to measure the response, it is then served from
+	// memory to the caller
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	info := ResponseInfo{
+		Namespace:  namespace,
+		URL:        url,
+		RequestID:  requestID,
+		StatusCode: response.StatusCode,
+		Duration:   time.Since(start),
+		Size:       int64(len(body)),
+		Retry:      retryFromContext(ctx),
+	}
+	// The kept response is still valid, it is served as if the API sent it
+	if cached != nil && err == nil && response.StatusCode == http.StatusNotModified {
+		body = cached.revalidated(response)
 	}
 
+	apiError := &APIError{
+		Namespace:  namespace,
+		Method:     request.Method,
+		URL:        url,
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Message:    errorMessage(body),
+	}
+	switch {
+	case err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()):
+		apiError.Err = fmt.Errorf("%w: %w: %w", ErrQueryingAPI, ctx.Err(), err)
+	case err != nil:
+		apiError.Err = fmt.Errorf("%w: %w", ErrQueryingAPI, err)
 	// Special handling for PeeringDB rate limit
-	if response.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrRateLimitExceeded
+	case response.StatusCode == http.StatusTooManyRequests:
+		apiError.Err = &RateLimitError{RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now())}
+	// API key not allowed to make the call
+	case response.StatusCode == http.StatusForbidden:
+		apiError.Err = &InsufficientScopeError{
+			KeyType:   apiKeyType,
+			Namespace: namespace,
+			Message:   apiError.Message,
+		}
+	// Lookups of a namespace unknown to the server
+	case response.StatusCode == http.StatusNotFound && request.Method == http.MethodGet:
+		apiError.Err = fmt.Errorf("%w: %s", ErrNamespaceNotSupported, namespace)
+	// Generic handling for non-OK responses, the error is described by the
+	// status and the message of the response only
+	case response.StatusCode < 200 || response.StatusCode > 299:
+	// Queries rejected by PeeringDB with an error message in the metadata
+	// instead of objects
+	case request.Method == http.MethodGet && metaError(body) != "":
+	default:
+		apiError = nil
 	}
-	// Generic handling for non-OK responses
-	if response.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(response.Body)
-		return nil, fmt.Errorf("%s: %s", response.Status, body)
+	if apiError != nil {
+		info.Err = apiError
+	}
+
+	api.keyPool.report(apiKey, info.Err)
+	info.Err = withRequestID(info.Err, requestID)
+
+	api.notifyResponse(info)
+	api.notifyBody(info, body)
+	if info.Err != nil {
+		return nil, info.Err
 	}
+	api.etags.store(request, key, response, body)
 
 	return response, nil
 }
 
+// rawResource is the resource structure used when the objects are processed
+// generically, whatever their namespace, without decoding them into their
+// structures.
+type rawResource = resource[json.RawMessage]
+
+// getRawResource returns a pointer to a rawResource structure corresponding
+// to the API JSON response for the given namespace. An error can be returned
+// if something went wrong.
+func (api *API) getRawResource(namespace string, search map[string]interface{}) (*rawResource, error) {
+	return getResource[json.RawMessage](context.Background(), api, namespace, search)
+}
+
+// getRaw returns the objects of a namespace matching the given search
+// parameters map as raw JSON. It allows the API to be used as a rawSource.
+func (api *API) getRaw(namespace string, search map[string]interface{}) ([]json.RawMessage, error) {
+	resource, err := api.getRawResource(namespace, search)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource.Data, nil
+}
+
+// GetRaw returns the raw JSON response of the API to a lookup of the given
+// namespace with the given search parameters, metadata included. The
+// namespace does not have to be one known by this package, so that endpoints
+// and fields not covered by its structures yet can be used right away:
+//
+//	body, err := api.GetRaw(ctx, "as_set", map[string]interface{}{"asn": 64500})
+//
+// Lookups are made the same way as typed ones, with the depth, the caches and
+// the retry policy of the API structure.
+func (api *API) GetRaw(ctx context.Context, namespace string, search map[string]interface{}) (json.RawMessage, error) {
+	response, err := api.lookupWithContext(ctx, namespace, search)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(body), nil
+}
+
 // GetASN is a simplified function to get PeeringDB details about a given AS
 // number. It basically gets the Net object matching the AS number. If the AS
 // number cannot be found, nil is returned.
-func (api *API) GetASN(asn int) (*Network, error) {
+func (api *API) GetASN(asn ASN) (*Network, error) {
+	return api.GetASNWithContext(context.Background(), asn)
+}
+
+// GetASNWithContext is the same as GetASN but uses the given context for the
+// API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetASNWithContext(ctx context.Context, asn ASN) (*Network, error) {
 	search := make(map[string]interface{})
 	search["asn"] = asn
 
 	// Actually fetch the Network from PeeringDB
-	network, err := api.GetNetwork(search)
+	network, err := api.GetNetworkWithContext(ctx, search)
 
 	// Error, so nil pointer returned
 	if err != nil {
@@ -182,3 +576,29 @@ func (api *API) GetASN(asn int) (*Network, error) {
 	}
 	return &(*network)[0], nil
 }
+
+// GetASNs is the bulk version of GetASN. It gets the Network objects matching
+// the given AS numbers using as few API calls as possible. The returned map is
+// indexed by AS number, AS numbers without a matching network are absent from
+// it.
+func (api *API) GetASNs(asns []ASN) (map[ASN]*Network, error) {
+	return api.GetASNsWithContext(context.Background(), asns)
+}
+
+// GetASNsWithContext is the same as GetASNs but uses the given context for the
+// API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetASNsWithContext(ctx context.Context, asns []ASN) (map[ASN]*Network, error) {
+	networks, err := getChunked(asnInts(asns), "asn", func(search map[string]interface{}) (*[]Network, error) {
+		return api.GetNetworkWithContext(ctx, search)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[ASN]*Network, len(networks))
+	for i := range networks {
+		found[networks[i].ASN] = &networks[i]
+	}
+
+	return found, nil
+}
@@ -1,6 +1,15 @@
 package peeringdb
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestFormatSearchParameters(t *testing.T) {
 	var searchMap map[string]interface{}
@@ -44,6 +53,27 @@ func TestFormatSearchParameters(t *testing.T) {
 		t.Errorf("formatSearchParameters, want '%s' got '%s'", expected,
 			searchParameters)
 	}
+
+	// Test escaped keys and values
+	searchMap = make(map[string]interface{})
+	searchMap["name&x"] = "A & B"
+	expected = "&name%26x=A+%26+B"
+	searchParameters = formatSearchParameters(searchMap)
+	if searchParameters != expected {
+		t.Errorf("formatSearchParameters, want '%s' got '%s'", expected,
+			searchParameters)
+	}
+
+	// Test multi-value parameters
+	searchMap = make(map[string]interface{})
+	searchMap["id__in"] = []int{1, 2}
+	searchMap["info_type"] = []string{"NSP", "Content"}
+	expected = "&id__in=1%2C2&info_type=NSP&info_type=Content"
+	searchParameters = formatSearchParameters(searchMap)
+	if searchParameters != expected {
+		t.Errorf("formatSearchParameters, want '%s' got '%s'", expected,
+			searchParameters)
+	}
 }
 
 func TestFormatURL(t *testing.T) {
@@ -105,7 +135,7 @@ func TestFormatURL(t *testing.T) {
 
 	// Test netixlan namespace with search parameter
 	expected = "https://www.peeringdb.com/api/netixlan?depth=1&id=10"
-	url = formatURL(base, networkInternetExchangeLANNamepsace, searchMap)
+	url = formatURL(base, networkInternetExchangeLANNamespace, searchMap)
 	if url != expected {
 		t.Errorf("formatURL, want '%s' got '%s'", expected, url)
 	}
@@ -200,8 +230,15 @@ func TestNewAPIFromURLWithAPIKey(t *testing.T) {
 }
 
 func TestGetASN(t *testing.T) {
-	api := NewAPI()
-	expectedASN := 201281
+	// Responses are replayed from golden files, set PEERINGDB_RECORD to
+	// refresh them from the live API
+	mode := RecorderReplayOrRecord
+	if os.Getenv("PEERINGDB_RECORD") != "" {
+		mode = RecorderRecord
+	}
+	recorder := NewRecorder(filepath.Join("testdata", "recordings"), mode, nil)
+	api := NewAPI(WithHTTPClient(&http.Client{Transport: recorder}))
+	expectedASN := ASN(201281)
 	net, err := api.GetASN(expectedASN)
 
 	if err != nil {
@@ -213,3 +250,82 @@ func TestGetASN(t *testing.T) {
 		t.Errorf("GetASN, want ASN '%d' got '%d'", expectedASN, net.ASN)
 	}
 }
+
+func TestGetEmptyResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta": {}, "data": null}`))
+	}))
+	defer server.Close()
+
+	networks, err := NewAPIFromURL(server.URL + "/api/").GetNetwork(map[string]interface{}{"asn": 64500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if networks == nil || *networks == nil || len(*networks) != 0 {
+		t.Errorf("GetNetwork, want an empty non-nil slice got %v", networks)
+	}
+}
+
+func TestGetWithContext(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {{"id": 1, "asn": 64500}},
+	})
+	api := server.api()
+
+	network, err := api.GetNetworkByIDWithContext(context.Background(), 1)
+	if err != nil || network == nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByIDWithContext, unexpected result %+v: %v", network, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = api.GetAllNetworksWithContext(ctx); !errors.Is(err, context.Canceled) || !errors.Is(err, ErrQueryingAPI) {
+		t.Errorf("GetAllNetworksWithContext, want context.Canceled got %v", err)
+	}
+}
+
+func TestUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/net":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"meta": {"error": "Invalid API key"}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(strings.Repeat("<html>", 100)))
+		}
+	}))
+	defer server.Close()
+	api := NewAPIFromURL(server.URL + "/api/")
+
+	_, err := api.GetNetworkByID(1)
+	expected := "GET " + server.URL + "/api/net?depth=1&id=1: 401 Unauthorized: Invalid API key"
+	if err == nil || !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("GetNetworkByID, want '%s' got '%v'", expected, err)
+	}
+
+	_, err = api.GetFacilityByID(1)
+	if err == nil || !strings.Contains(err.Error(), "/api/fac?depth=1&id=1: 500 Internal Server Error") || len(err.Error()) > 400 {
+		t.Errorf("GetFacilityByID, unexpected error '%v'", err)
+	}
+}
+
+func TestGetRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/as_set" || r.URL.Query().Get("asn") != "64500" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"64500": "AS-EXAMPLE"}]}`))
+	}))
+	defer server.Close()
+	api := NewAPIFromURL(server.URL + "/api/")
+
+	body, err := api.GetRaw(context.Background(), "as_set", map[string]interface{}{"asn": 64500})
+	if err != nil || string(body) != `{"meta": {}, "data": [{"64500": "AS-EXAMPLE"}]}` {
+		t.Errorf("GetRaw, unexpected body %s: %v", body, err)
+	}
+	if _, err = api.GetRaw(context.Background(), "unknown", nil); !errors.Is(err, ErrNamespaceNotSupported) {
+		t.Errorf("GetRaw, want ErrNamespaceNotSupported got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxErrorMessageLength is the maximum length of the message of a response
+// kept in error texts, error pages can be large.
+const maxErrorMessageLength = 256
+
+// APIError is the error returned when an API call fails, either because no
+// response was received or because the response has an unexpected status. It
+// can be retrieved with errors.As to tell authentication failures, rate
+// limiting and server errors apart. It matches ErrQueryingAPI with errors.Is
+// and wraps the more specific error describing the failure, if any, such as
+// a RateLimitError, an InsufficientScopeError or ErrNamespaceNotSupported.
+type APIError struct {
+	// Namespace is the namespace of the objects of the call.
+	Namespace string
+	// Method is the HTTP method of the call.
+	Method string
+	// URL is the URL of the call.
+	URL string
+	// StatusCode is the HTTP status code of the response, it is 0 if no
+	// response was received.
+	StatusCode int
+	// Status is the HTTP status of the response, such as "404 Not Found".
+	Status string
+	// Message is the error message given by the API, or the body of the
+	// response if there is none.
+	Message string
+	// Err is the error describing the failure, it is nil if the status of
+	// the response is enough to describe it.
+	Err error
+}
+
+// Error returns the call and the status of the response, followed by the
+// wrapped error text if there is one, otherwise by the message of the
+// response.
+func (e *APIError) Error() string {
+	call := fmt.Sprintf("%s %s", e.Method, e.URL)
+	if e.Status != "" {
+		call = fmt.Sprintf("%s: %s", call, e.Status)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", call, e.Err)
+	}
+
+	message := e.Message
+	if len(message) > maxErrorMessageLength {
+		message = message[:maxErrorMessageLength] + "..."
+	}
+	if message == "" {
+		return call
+	}
+
+	return fmt.Sprintf("%s: %s", call, message)
+}
+
+// Unwrap returns the error describing the failure.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is tells if the target is ErrQueryingAPI.
+func (e *APIError) Is(target error) bool {
+	return target == ErrQueryingAPI
+}
+
+// Unauthorized tells if the call failed because of missing or invalid
+// credentials, or because the credentials do not allow it.
+func (e *APIError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// RateLimited tells if the call failed because the rate limit is exceeded.
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// Temporary tells if the call failed because of a transient issue, either no
+// complete response received or an error of the server, and could succeed if
+// retried.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == 0 || e.StatusCode >= 500 || errors.Is(e.Err, ErrQueryingAPI)
+}
+
+// metaError returns the error message set by PeeringDB in the metadata of a
+// response, if any.
+func metaError(body []byte) string {
+	var resource struct {
+		Meta struct {
+			Error string `json:"error"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &resource); err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(resource.Meta.Error)
+}
+
+// errorMessage returns the error message found in the body of an API
+// response, or the body itself if there is none. PeeringDB sets it in the
+// metadata of the response, while errors raised by the framework it uses are
+// given as a "detail" or "message" field, or as a list of messages per
+// invalid field.
+func errorMessage(body []byte) string {
+	if message := metaError(body); message != "" {
+		return message
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil {
+		for _, key := range []string{"detail", "message"} {
+			var message string
+			if err = json.Unmarshal(fields[key], &message); err == nil && message != "" {
+				return message
+			}
+		}
+
+		var messages []string
+		for field, value := range fields {
+			var list []string
+			if err = json.Unmarshal(value, &list); err == nil && len(list) > 0 {
+				messages = append(messages, fmt.Sprintf("%s: %s", field, strings.Join(list, " ")))
+			}
+		}
+		if len(messages) > 0 {
+			sort.Strings(messages)
+			return strings.Join(messages, "; ")
+		}
+	}
+
+	return strings.TrimSpace(string(body))
+}
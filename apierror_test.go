@@ -0,0 +1,115 @@
+package peeringdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/net":
+			http.Error(w, `{"meta": {"error": "Invalid API key"}}`, http.StatusUnauthorized)
+		case "/api/fac":
+			http.Error(w, `{"message": "Request was throttled."}`, http.StatusTooManyRequests)
+		case "/api/ix":
+			http.Error(w, "oops", http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	api := NewAPIFromURL(server.URL + "/api/")
+
+	tests := []struct {
+		call         func() error
+		namespace    string
+		statusCode   int
+		unauthorized bool
+		rateLimited  bool
+		temporary    bool
+		wraps        error
+	}{
+		{func() error { _, err := api.GetNetworkByID(1); return err }, networkNamespace, http.StatusUnauthorized, true, false, false, nil},
+		{func() error { _, err := api.GetFacilityByID(1); return err }, facilityNamespace, http.StatusTooManyRequests, false, true, false, ErrRateLimitExceeded},
+		{func() error { _, err := api.GetInternetExchangeByID(1); return err }, internetExchangeNamespace, http.StatusBadGateway, false, false, true, nil},
+		{func() error { _, err := api.GetCampusByID(1); return err }, campusNamespace, http.StatusNotFound, false, false, false, ErrNamespaceNotSupported},
+	}
+	for _, test := range tests {
+		err := test.call()
+
+		var apiError *APIError
+		if !errors.As(err, &apiError) || !errors.Is(err, ErrQueryingAPI) {
+			t.Errorf("%s, want APIError got %v", test.namespace, err)
+			continue
+		}
+		if apiError.Namespace != test.namespace || apiError.StatusCode != test.statusCode || apiError.Method != http.MethodGet {
+			t.Errorf("%s, unexpected error %+v", test.namespace, apiError)
+		}
+		if apiError.Unauthorized() != test.unauthorized || apiError.RateLimited() != test.rateLimited || apiError.Temporary() != test.temporary {
+			t.Errorf("%s, unexpected classification of %+v", test.namespace, apiError)
+		}
+		if test.wraps != nil && !errors.Is(err, test.wraps) {
+			t.Errorf("%s, want error wrapping %v got %v", test.namespace, test.wraps, err)
+		}
+		// The call and the status are part of the text, even with a wrapped
+		// error
+		if !strings.Contains(err.Error(), "GET "+apiError.URL+": "+apiError.Status) {
+			t.Errorf("%s, want call and status in error text got %s", test.namespace, err)
+		}
+	}
+
+	// No response at all
+	server.Close()
+	_, err := api.GetNetworkByID(1)
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != 0 || !apiError.Temporary() {
+		t.Errorf("GetNetworkByID, want temporary APIError got %v", err)
+	}
+	// The transport error is kept
+	var urlError *url.Error
+	if !errors.As(err, &urlError) || !errors.Is(err, ErrQueryingAPI) || !strings.Contains(err.Error(), "GET "+apiError.URL) {
+		t.Errorf("GetNetworkByID, want wrapped transport error got %v", err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := map[string]string{
+		`{"meta": {"error": "Invalid filter field 'foo'"}, "data": []}`: "Invalid filter field 'foo'",
+		`{"detail": "Authentication credentials were not provided."}`:   "Authentication credentials were not provided.",
+		`{"message": "Request was throttled."}`:                         "Request was throttled.",
+		`{"asn": ["This field is required."], "name": ["Too long."]}`:   "asn: This field is required.; name: Too long.",
+		"  <html>Bad Gateway</html>\n":                                  "<html>Bad Gateway</html>",
+	}
+	for body, want := range tests {
+		if got := errorMessage([]byte(body)); got != want {
+			t.Errorf("errorMessage(%s), want '%s' got '%s'", body, want, got)
+		}
+	}
+}
+
+func TestMetaError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/net" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte(`{"meta": {"error": "Invalid filter field 'foo'"}, "data": []}`))
+	}))
+	defer server.Close()
+	api := NewAPIFromURL(server.URL + "/api/")
+
+	// Rejected with or without an error status
+	for _, get := range []func() error{
+		func() error { _, err := api.GetNetwork(map[string]interface{}{"foo": 1}); return err },
+		func() error { _, err := api.GetFacility(map[string]interface{}{"foo": 1}); return err },
+	} {
+		err := get()
+		var apiError *APIError
+		if !errors.As(err, &apiError) || apiError.Message != "Invalid filter field 'foo'" || !strings.Contains(err.Error(), "Invalid filter field 'foo'") {
+			t.Errorf("want error with PeeringDB message got %v", err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidASN is the error that will be returned if an AS number cannot be
+// parsed or is not usable on the Internet.
+var ErrInvalidASN = errors.New("invalid as number")
+
+// ASN is an autonomous system number, 2-byte and 4-byte AS numbers are
+// supported. It is encoded as a JSON number, and can be decoded from a JSON
+// number or string using the formats accepted by ParseASN.
+type ASN uint32
+
+// asnRange is a range of AS numbers, bounds included.
+type asnRange struct {
+	first, last ASN
+}
+
+var (
+	// privateASNs are the ranges of AS numbers for private use (RFC 6996).
+	privateASNs = []asnRange{{64512, 65534}, {4200000000, 4294967294}}
+	// documentationASNs are the ranges of AS numbers for documentation
+	// (RFC 5398).
+	documentationASNs = []asnRange{{64496, 64511}, {65536, 65551}}
+	// reservedASNs are the AS numbers reserved by IANA, including AS_TRANS
+	// (RFC 6793) and the last 2-byte and 4-byte AS numbers (RFC 7300).
+	reservedASNs = []asnRange{{0, 0}, {23456, 23456}, {65535, 65535}, {65552, 131071}, {4294967295, 4294967295}}
+)
+
+// inRanges tells if the AS number is part of one of the given ranges.
+func (a ASN) inRanges(ranges []asnRange) bool {
+	for _, r := range ranges {
+		if a >= r.first && a <= r.last {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseASN parses an AS number written in the asplain format, such as
+// "4200000000", or in the asdot format, such as "64086.59904" (RFC 5396). The
+// number can be prefixed with "AS".
+func ParseASN(value string) (ASN, error) {
+	text := strings.TrimSpace(value)
+	if len(text) > 2 && strings.EqualFold(text[:2], "AS") {
+		text = text[2:]
+	}
+
+	if high, low, ok := strings.Cut(text, "."); ok {
+		h, errHigh := strconv.ParseUint(high, 10, 16)
+		l, errLow := strconv.ParseUint(low, 10, 16)
+		if errHigh != nil || errLow != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidASN, value)
+		}
+		return ASN(h<<16 | l), nil
+	}
+
+	asn, err := strconv.ParseUint(text, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidASN, value)
+	}
+
+	return ASN(asn), nil
+}
+
+// String returns the AS number in the asplain format, "4200000000" for
+// instance, which is the format used by PeeringDB and in search parameters.
+func (a ASN) String() string {
+	return strconv.FormatUint(uint64(a), 10)
+}
+
+// Dot returns the AS number in the asdot format: 4-byte AS numbers are
+// written as two 16-bit numbers, "64086.59904" for instance, 2-byte AS
+// numbers are written as in the asplain format.
+func (a ASN) Dot() string {
+	if !a.Is4Byte() {
+		return a.String()
+	}
+
+	return fmt.Sprintf("%d.%d", a>>16, a&0xffff)
+}
+
+// Is4Byte tells if the AS number does not fit in 2 bytes.
+func (a ASN) Is4Byte() bool {
+	return a > 65535
+}
+
+// IsPrivate tells if the AS number is reserved for private use.
+func (a ASN) IsPrivate() bool {
+	return a.inRanges(privateASNs)
+}
+
+// IsDocumentation tells if the AS number is reserved for documentation and
+// sample code.
+func (a ASN) IsDocumentation() bool {
+	return a.inRanges(documentationASNs)
+}
+
+// IsReserved tells if the AS number is reserved by IANA and must not be used.
+func (a ASN) IsReserved() bool {
+	return a.inRanges(reservedASNs)
+}
+
+// IsPublic tells if the AS number can be used on the Internet, that is it is
+// neither private, nor for documentation, nor reserved.
+func (a ASN) IsPublic() bool {
+	return !a.IsPrivate() && !a.IsDocumentation() && !a.IsReserved()
+}
+
+// Validate returns an error wrapping ErrInvalidASN, and telling why, if the AS
+// number cannot be used on the Internet.
+func (a ASN) Validate() error {
+	switch {
+	case a.IsPrivate():
+		return fmt.Errorf("%w: AS%s is for private use", ErrInvalidASN, a)
+	case a.IsDocumentation():
+		return fmt.Errorf("%w: AS%s is for documentation", ErrInvalidASN, a)
+	case a.IsReserved():
+		return fmt.Errorf("%w: AS%s is reserved", ErrInvalidASN, a)
+	default:
+		return nil
+	}
+}
+
+// UnmarshalJSON decodes an AS number from a JSON number or string. A null
+// value gives 0.
+func (a *ASN) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*a = 0
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		text = string(data)
+	}
+
+	asn, err := ParseASN(text)
+	if err != nil {
+		return err
+	}
+	*a = asn
+
+	return nil
+}
+
+// asnInts returns AS numbers as integers, as used by "__in" filters.
+func asnInts(asns []ASN) []int {
+	ints := make([]int, len(asns))
+	for i, asn := range asns {
+		ints[i] = int(asn)
+	}
+
+	return ints
+}
@@ -0,0 +1,86 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseASN(t *testing.T) {
+	for value, expected := range map[string]ASN{
+		"64500":         64500,
+		"AS64500":       64500,
+		" as4200000000": 4200000000,
+		"1.10":          65546,
+		"AS64086.59904": 4200000000,
+	} {
+		asn, err := ParseASN(value)
+		if err != nil || asn != expected {
+			t.Errorf("ParseASN(%q), want %d got %d (%v)", value, expected, asn, err)
+		}
+	}
+
+	for _, value := range []string{"", "AS", "-1", "4294967296", "1.65536", "AS64500a"} {
+		if _, err := ParseASN(value); !errors.Is(err, ErrInvalidASN) {
+			t.Errorf("ParseASN(%q), want ErrInvalidASN got %v", value, err)
+		}
+	}
+}
+
+func TestASNFormat(t *testing.T) {
+	if s := ASN(4200000000).String(); s != "4200000000" {
+		t.Errorf("String, want asplain got %s", s)
+	}
+	if s := ASN(4200000000).Dot(); s != "64086.59904" {
+		t.Errorf("Dot, want asdot got %s", s)
+	}
+	if s := ASN(64500).Dot(); s != "64500" {
+		t.Errorf("Dot, want 2-byte AS number unchanged got %s", s)
+	}
+}
+
+func TestASNValidate(t *testing.T) {
+	for _, asn := range []ASN{13335, 201281, 4199999999} {
+		if err := asn.Validate(); err != nil || !asn.IsPublic() {
+			t.Errorf("Validate(%d), unexpected error: %v", asn, err)
+		}
+	}
+
+	for _, asn := range []ASN{0, 23456, 64496, 64512, 65535, 65536, 70000, 4200000000, 4294967295} {
+		if err := asn.Validate(); !errors.Is(err, ErrInvalidASN) || asn.IsPublic() {
+			t.Errorf("Validate(%d), want ErrInvalidASN got %v", asn, err)
+		}
+	}
+
+	if !ASN(65000).IsPrivate() || !ASN(64510).IsDocumentation() || !ASN(23456).IsReserved() {
+		t.Error("unexpected AS number ranges")
+	}
+	if ASN(65535).Is4Byte() || !ASN(65536).Is4Byte() {
+		t.Error("Is4Byte, unexpected result")
+	}
+}
+
+func TestASNJSON(t *testing.T) {
+	var values struct {
+		Number ASN `json:"number"`
+		Text   ASN `json:"text"`
+		Null   ASN `json:"null"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 64500, "text": "AS1.10", "null": null}`), &values); err != nil {
+		t.Fatal(err)
+	}
+	if values.Number != 64500 || values.Text != 65546 || values.Null != 0 {
+		t.Errorf("Unmarshal, unexpected values %+v", values)
+	}
+	if err := json.Unmarshal([]byte(`{"number": -1}`), &values); !errors.Is(err, ErrInvalidASN) {
+		t.Errorf("Unmarshal, want ErrInvalidASN got %v", err)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"number":64500,"text":65546,"null":0}` {
+		t.Errorf("Marshal, want numbers got %s", data)
+	}
+}
@@ -0,0 +1,87 @@
+package peeringdb
+
+import (
+	"context"
+)
+
+// authKey is the key used to store credentials in a context.
+type authKey struct{}
+
+// contextAuth holds the credentials carried by a context.
+type contextAuth struct {
+	apiKey     string
+	apiKeyType APIKeyType
+}
+
+// WithAuth returns a copy of the context carrying the given user API key. API
+// calls made with this context, using GetNetworkWithContext for instance, are
+// authenticated with this key instead of the one of the API structure,
+// allowing a single API structure to make calls on behalf of different
+// PeeringDB accounts. An empty key makes anonymous calls.
+func WithAuth(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, authKey{}, contextAuth{apiKey: apiKey, apiKeyType: UserAPIKey})
+}
+
+// WithOrganizationAuth returns a copy of the context carrying the given
+// organization API key. It works like WithAuth.
+func WithOrganizationAuth(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, authKey{}, contextAuth{apiKey: apiKey, apiKeyType: OrganizationAPIKey})
+}
+
+// WithBasicAuth returns an option authenticating API calls with the given
+// username and password when they are not made with an API key, the API key of
+// the API structure or of the context taking precedence. PeeringDB recommends
+// API keys, basic authentication suits accounts without one. Nothing is done
+// if the username is empty.
+func WithBasicAuth(username, password string) Option {
+	return func(api *API) {
+		api.username = username
+		api.password = password
+	}
+}
+
+// basicAuth tells if a call made with the given context is authenticated with
+// the username and password of the API structure.
+func (api *API) basicAuth(ctx context.Context) bool {
+	_, ok := ctx.Value(authKey{}).(contextAuth)
+	return !ok && api.username != ""
+}
+
+// authScope returns what identifies the caller of a call made with the given
+// context and authenticated with the given API key, as the objects returned
+// depend on it: the authentication mode along with the API key or the
+// username.
+func (api *API) authScope(ctx context.Context, apiKey string) string {
+	switch {
+	case apiKey != "":
+		return "api-key:" + apiKey
+	case api.basicAuth(ctx):
+		return "basic:" + api.username
+	default:
+		return "anonymous"
+	}
+}
+
+// credentials returns the API key and its type to use for a call, taken from
+// the context if it carries some, else from the key pool or the API structure.
+func (api *API) credentials(ctx context.Context) (string, APIKeyType) {
+	if auth, ok := ctx.Value(authKey{}).(contextAuth); ok {
+		return auth.apiKey, auth.apiKeyType
+	}
+	if api.keyPool != nil {
+		return api.keyPool.credentials()
+	}
+
+	return api.apiKey, api.apiKeyType
+}
+
+// rotateKey tells if a lookup failing with the given error can be retried
+// right away with another key of the key pool. Calls authenticated with a key
+// carried by the context are not.
+func (api *API) rotateKey(ctx context.Context, err error) bool {
+	if _, ok := ctx.Value(authKey{}).(contextAuth); ok {
+		return false
+	}
+
+	return api.keyPool.rotate(err)
+}
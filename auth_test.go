@@ -0,0 +1,104 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithAuth(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if authorization == "Api-Key org-key" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+		w.Write([]byte(`{"meta": {}, "data": []}`))
+	}))
+	defer server.Close()
+
+	api := NewAPIFromURLWithAPIKey(server.URL+"/api/", "default-key")
+	ctx := context.Background()
+
+	tests := []struct {
+		ctx      context.Context
+		expected string
+	}{
+		{ctx, "Api-Key default-key"},
+		{WithAuth(ctx, "tenant-key"), "Api-Key tenant-key"},
+		{WithAuth(ctx, ""), ""},
+	}
+
+	for _, test := range tests {
+		if _, err := api.GetAllNetworksWithContext(test.ctx); err != nil {
+			t.Fatal(err)
+		}
+		if authorization != test.expected {
+			t.Errorf("GetAllNetworksWithContext, want authorization %q got %q", test.expected, authorization)
+		}
+	}
+
+	_, err := api.GetAllNetworksWithContext(WithOrganizationAuth(ctx, "org-key"))
+	var scopeError *InsufficientScopeError
+	if !errors.As(err, &scopeError) || scopeError.KeyType != OrganizationAPIKey {
+		t.Errorf("GetAllNetworksWithContext, want InsufficientScopeError for an organization key got %v", err)
+	}
+}
+
+func TestBasicAuthScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Unchanged responses are told whoever asks
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if _, _, ok := r.BasicAuth(); ok {
+			w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}, {"id": 2, "asn": 64501}]}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, option := range []Option{WithETagCache(0), WithMemoryCache(time.Minute, 0)} {
+		api := NewAPIFromURL(server.URL+"/api/", WithBasicAuth("user", "password"), option)
+
+		// Objects seen with basic authentication are not served to
+		// anonymous calls, nor the other way around
+		for _, test := range []struct {
+			ctx      context.Context
+			expected int
+		}{
+			{ctx, 2},
+			{WithAuth(ctx, ""), 1},
+			{ctx, 2},
+		} {
+			networks, err := api.GetAllNetworksWithContext(test.ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*networks) != test.expected {
+				t.Errorf("GetAllNetworksWithContext, want %d networks got %d", test.expected, len(*networks))
+			}
+		}
+	}
+
+	// Lookups of different scopes are not shared
+	api := NewAPIFromURL(server.URL+"/api/", WithBasicAuth("user", "password"))
+	keys := map[string]bool{
+		api.flightKey(ctx, "url"):                  true,
+		api.flightKey(WithAuth(ctx, ""), "url"):    true,
+		api.flightKey(WithAuth(ctx, "key"), "url"): true,
+	}
+	if len(keys) != 3 {
+		t.Errorf("flightKey, want 3 different keys got %d", len(keys))
+	}
+	if anonymous := NewAPI().flightKey(ctx, "url"); !keys[anonymous] {
+		t.Errorf("flightKey, want anonymous lookups shared got key %q", anonymous)
+	}
+}
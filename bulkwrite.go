@@ -0,0 +1,196 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BulkWriteOptions is a structure used to tune how a BulkWriter applies
+// changes.
+type BulkWriteOptions struct {
+	// Workers is the number of changes applied concurrently, 1 is used if it
+	// is not set.
+	Workers int
+	// MaxRateLimitRetries is the number of times a change refused because
+	// the rate limit is exceeded is tried again. Such changes have not been
+	// applied, so they are safe to retry.
+	MaxRateLimitRetries int
+	// RateLimitWait is the time waited when the rate limit is exceeded if
+	// the API does not tell how long to wait with a Retry-After header, 1
+	// minute is used if it is not set.
+	RateLimitWait time.Duration
+	// MaxRetryAfter is the maximum time to wait for the rate limit to be
+	// lifted. Changes are not retried if the API asks to wait longer. The
+	// time waited is not bounded if it is zero.
+	MaxRetryAfter time.Duration
+}
+
+// BulkWriteResult is the result of a change applied by a BulkWriter.
+type BulkWriteResult struct {
+	Change ImportChange
+	// ID is the ID of the object written. For a creation, it is the ID
+	// given to the object by the API.
+	ID int
+	// Object is the object written, as returned by the API in JSON. It is
+	// nil for a deletion.
+	Object json.RawMessage
+	// Err is the error which prevented the change from being applied, nil
+	// if it was applied.
+	Err error
+}
+
+// BulkWriter queues changes to apply to PeeringDB and applies them with a
+// bounded number of concurrent API calls, such as the hundreds of netixlan
+// updates following the renumbering of an IX LAN. When the rate limit is
+// exceeded, all workers pause for the time asked by the API before going on.
+// Changes are applied in no particular order, changes depending on others,
+// such as the creation of the objects of a network being created, must be
+// applied with separate calls to Flush. A BulkWriter is safe for concurrent
+// use.
+type BulkWriter struct {
+	api     *API
+	options BulkWriteOptions
+
+	mutex   sync.Mutex
+	changes []ImportChange
+	// paused is the time until which calls are not made, because the rate
+	// limit is exceeded
+	paused time.Time
+}
+
+// NewBulkWriter returns a pointer to a new BulkWriter applying changes with
+// the given API structure, which needs an API key allowed to write the
+// objects.
+func (api *API) NewBulkWriter(options BulkWriteOptions) *BulkWriter {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.RateLimitWait <= 0 {
+		options.RateLimitWait = time.Minute
+	}
+
+	return &BulkWriter{api: api, options: options}
+}
+
+// Add queues changes to apply with the next call to Flush.
+func (w *BulkWriter) Add(changes ...ImportChange) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.changes = append(w.changes, changes...)
+}
+
+// Len returns the number of changes queued.
+func (w *BulkWriter) Len() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return len(w.changes)
+}
+
+// Flush applies all queued changes and returns their results, in the order
+// the changes were added. A failing change does not stop the others. If the
+// context is canceled, changes not applied yet fail with the error of the
+// context.
+func (w *BulkWriter) Flush(ctx context.Context) []BulkWriteResult {
+	w.mutex.Lock()
+	changes := w.changes
+	w.changes = nil
+	w.mutex.Unlock()
+
+	results := make([]BulkWriteResult, len(changes))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(w.options.Workers, len(changes)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = w.apply(ctx, changes[index])
+			}
+		}()
+	}
+	for i := range changes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// apply applies a change, waiting for the rate limit to be lifted first if
+// it is exceeded and retrying the change if it is refused because of it.
+func (w *BulkWriter) apply(ctx context.Context, change ImportChange) BulkWriteResult {
+	result := BulkWriteResult{Change: change, ID: change.ID}
+
+	var object interface{}
+	if change.Object != nil {
+		object = change.Object
+	}
+
+	for retries := 0; ; retries++ {
+		if result.Err = w.pause(ctx); result.Err != nil {
+			return result
+		}
+
+		written, err := w.api.write(ctx, importMethods[change.Action], change.Namespace, change.ID, object, nil)
+		if err == nil {
+			result.Object = written
+			if written != nil && change.Action == ImportCreate {
+				result.ID, result.Err = objectID(written)
+			}
+			return result
+		}
+		result.Err = fmt.Errorf("%s: %w", change, err)
+
+		var rateLimit *RateLimitError
+		if !errors.As(err, &rateLimit) || retries >= w.options.MaxRateLimitRetries {
+			return result
+		}
+		wait := rateLimit.RetryAfter
+		if wait <= 0 {
+			wait = w.options.RateLimitWait
+		}
+		if w.options.MaxRetryAfter > 0 && wait > w.options.MaxRetryAfter {
+			return result
+		}
+		w.pauseFor(wait)
+	}
+}
+
+// pauseFor pauses all workers for the given time.
+func (w *BulkWriter) pauseFor(wait time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if until := time.Now().Add(wait); until.After(w.paused) {
+		w.paused = until
+	}
+}
+
+// pause waits until workers are not paused anymore, or until the context is
+// done.
+func (w *BulkWriter) pause(ctx context.Context) error {
+	w.mutex.Lock()
+	wait := time.Until(w.paused)
+	w.mutex.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkWriter(t *testing.T) {
+	netixlans := make([]map[string]interface{}, 10)
+	for i := range netixlans {
+		netixlans[i] = map[string]interface{}{"id": i + 1, "net_id": 1, "ixlan_id": 1, "speed": 1000}
+	}
+	server := newTestServer(t, map[string][]map[string]interface{}{networkInternetExchangeLANNamespace: netixlans})
+
+	// The rate limit is exceeded once, after a few writes
+	var writes atomic.Int32
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if writes.Add(1) == 4 {
+			http.Error(w, `{"message": "Request was throttled."}`, http.StatusTooManyRequests)
+			return
+		}
+		server.serve(w, r)
+	})
+
+	writer := server.api().NewBulkWriter(BulkWriteOptions{Workers: 3, MaxRateLimitRetries: 1, RateLimitWait: 10 * time.Millisecond})
+	for i := 1; i <= 10; i++ {
+		writer.Add(ImportChange{Action: ImportUpdate, Namespace: networkInternetExchangeLANNamespace, ID: i, Object: map[string]interface{}{"speed": 10000}})
+	}
+	writer.Add(ImportChange{Action: ImportCreate, Namespace: networkInternetExchangeLANNamespace, Object: map[string]interface{}{"net_id": 2, "ixlan_id": 1}})
+	writer.Add(ImportChange{Action: ImportDelete, Namespace: networkInternetExchangeLANNamespace, ID: 42})
+	if writer.Len() != 12 {
+		t.Fatalf("Add, expected 12 changes queued, got %d", writer.Len())
+	}
+
+	results := writer.Flush(context.Background())
+	if len(results) != 12 || writer.Len() != 0 {
+		t.Fatalf("Flush, expected 12 results and no change left, got %d and %d", len(results), writer.Len())
+	}
+	for i, result := range results[:10] {
+		if result.Err != nil || result.ID != i+1 {
+			t.Errorf("Flush, unexpected result %+v", result)
+		}
+	}
+	if results[10].Err != nil || results[10].ID != 11 || results[10].Object == nil {
+		t.Errorf("Flush, unexpected creation result %+v", results[10])
+	}
+	if !errors.Is(results[11].Err, ErrQueryingAPI) {
+		t.Errorf("Flush, expected the deletion of a missing object to fail, got %+v", results[11])
+	}
+	if writes.Load() != 13 {
+		t.Errorf("Flush, expected 13 writes, got %d", writes.Load())
+	}
+	for _, netixlan := range server.objects[networkInternetExchangeLANNamespace][:10] {
+		if speed, _ := netixlan["speed"].(float64); speed != 10000 {
+			t.Errorf("Flush, unexpected object %v", netixlan)
+		}
+	}
+
+	// Without retries, the change refused by the rate limit fails
+	writes.Store(0)
+	writer = server.api().NewBulkWriter(BulkWriteOptions{Workers: 2})
+	for i := 1; i <= 5; i++ {
+		writer.Add(ImportChange{Action: ImportUpdate, Namespace: networkInternetExchangeLANNamespace, ID: i, Object: map[string]interface{}{"speed": 100}})
+	}
+	failed := 0
+	for _, result := range writer.Flush(context.Background()) {
+		if errors.Is(result.Err, ErrRateLimitExceeded) {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("Flush, expected 1 change to fail, got %d", failed)
+	}
+}
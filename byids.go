@@ -0,0 +1,286 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// IDError is an ID whose object could not be fetched, with the reason why.
+type IDError struct {
+	ID  int
+	Err error
+}
+
+// ByIDsResult is the result of a bulk lookup by IDs. Instead of failing as a
+// whole, the lookup returns the objects it found and tells which IDs are
+// missing and which ones could not be looked up.
+type ByIDsResult[T any] struct {
+	// Objects are the objects found, in the order of the requested IDs.
+	Objects []T
+	// Missing are the IDs without a matching object.
+	Missing []int
+	// Failed are the IDs which could not be looked up because of an error,
+	// a failing query failing all the IDs it was looking for.
+	Failed []IDError
+
+	// errs are the errors of the failing queries, with the IDs they were
+	// looking for
+	errs []error
+}
+
+// Complete tells if all the requested objects were found.
+func (r *ByIDsResult[T]) Complete() bool {
+	return len(r.Missing) == 0 && len(r.Failed) == 0
+}
+
+// Err returns the errors of the queries which failed, joined together and
+// each one telling the IDs it was looking for, or nil if no query failed.
+// Missing IDs are not errors.
+func (r *ByIDsResult[T]) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// FetchOptions is a structure used to tune bulk lookups by IDs.
+type FetchOptions struct {
+	// Workers is the number of queries made concurrently, 1 is used if it
+	// is not set.
+	Workers int
+	// BatchSize is the number of IDs looked up by each "id__in" query. It
+	// cannot be more than 100, which is also used if it is not set.
+	BatchSize int
+}
+
+// getByIDs looks up objects by IDs in chunks, one query at a time. A failing
+// chunk does not stop the lookup of the others.
+func getByIDs[T any](ids []int, get func(map[string]interface{}) (*[]T, error)) *ByIDsResult[T] {
+	return fetchByIDs(ids, get, FetchOptions{})
+}
+
+// fetchByIDs looks up objects by IDs in chunks, running as many queries
+// concurrently as there are workers. A failing chunk does not stop the lookup
+// of the others.
+func fetchByIDs[T any](ids []int, get func(map[string]interface{}) (*[]T, error), options FetchOptions) *ByIDsResult[T] {
+	size := options.BatchSize
+	if size <= 0 || size > maxIDsPerQuery {
+		size = maxIDsPerQuery
+	}
+	chunks := chunkIDs(ids, size)
+	fetched := make([]*[]T, len(chunks))
+	errs := make([]error, len(chunks))
+	workers := make(chan struct{}, max(options.Workers, 1))
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, chunk []int) {
+			defer wg.Done()
+			search := make(map[string]interface{})
+			search["id__in"] = joinIDs(chunk)
+			fetched[i], errs[i] = get(search)
+			<-workers
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	result := &ByIDsResult[T]{Objects: []T{}}
+	found := make(map[int]T, len(ids))
+	failed := make(map[int]error)
+
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			for _, id := range chunk {
+				failed[id] = errs[i]
+			}
+			result.errs = append(result.errs, fmt.Errorf("looking up IDs %s: %w", joinIDs(chunk), errs[i]))
+			continue
+		}
+		for _, object := range *fetched[i] {
+			found[int(reflect.ValueOf(object).FieldByName("ID").Int())] = object
+		}
+	}
+
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		// Report each ID once, even if it was given twice
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if object, ok := found[id]; ok {
+			result.Objects = append(result.Objects, object)
+		} else if err, ok := failed[id]; ok {
+			result.Failed = append(result.Failed, IDError{ID: id, Err: err})
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+
+	return result
+}
+
+// Object is the constraint satisfied by the structures representing PeeringDB
+// objects, such as Network or Facility.
+type Object interface {
+	Campus | Carrier | CarrierFacility | Facility | InternetExchange |
+		InternetExchangeLAN | InternetExchangePrefix | InternetExchangeFacility |
+		Network | NetworkContact | NetworkFacility | NetworkInternetExchangeLAN |
+		Organization
+}
+
+// getterFor returns the function of the client looking up objects of type T
+// with a search parameters map.
+func getterFor[T Object](client Client) func(map[string]interface{}) (*[]T, error) {
+	var get interface{}
+	switch interface{}(*new(T)).(type) {
+	case Campus:
+		get = client.GetCampus
+	case Carrier:
+		get = client.GetCarrier
+	case CarrierFacility:
+		get = client.GetCarrierFacility
+	case Facility:
+		get = client.GetFacility
+	case InternetExchange:
+		get = client.GetInternetExchange
+	case InternetExchangeLAN:
+		get = client.GetInternetExchangeLAN
+	case InternetExchangePrefix:
+		get = client.GetInternetExchangePrefix
+	case InternetExchangeFacility:
+		get = client.GetInternetExchangeFacility
+	case Network:
+		get = client.GetNetwork
+	case NetworkContact:
+		get = client.GetNetworkContact
+	case NetworkFacility:
+		get = client.GetNetworkFacility
+	case NetworkInternetExchangeLAN:
+		get = client.GetNetworkInternetExchangeLAN
+	case Organization:
+		get = client.GetOrganization
+	}
+
+	return get.(func(map[string]interface{}) (*[]T, error))
+}
+
+// GetByIDs returns the objects of type T matching the given IDs, along with
+// the IDs which are missing or could not be looked up. The namespace to query
+// is the one of the type, objects are fetched in bulk with "id__in" queries:
+//
+//	result := peeringdb.GetByIDs[peeringdb.Facility](api, ids)
+//
+// Any client can be used, the live API as well as a mirror.
+func GetByIDs[T Object](client Client, ids []int) *ByIDsResult[T] {
+	return getByIDs(ids, getterFor[T](client))
+}
+
+// FetchByIDs is the same as GetByIDs but queries are made concurrently, by as
+// many workers as set in the options, each one looking up a batch of IDs. It
+// suits large lists of IDs, such as the ones of the objects referenced by a
+// full namespace. Errors of the failing queries are given by the Err method
+// of the result:
+//
+//	result := peeringdb.FetchByIDs[peeringdb.Network](api, ids, peeringdb.FetchOptions{Workers: 4})
+//	if err := result.Err(); err != nil {
+//		log.Print(err)
+//	}
+//
+// Workers share the rate limit and the quota of the client, if any.
+func FetchByIDs[T Object](client Client, ids []int, options FetchOptions) *ByIDsResult[T] {
+	return fetchByIDs(ids, getterFor[T](client), options)
+}
+
+// GetCampusesByIDs returns the Campus objects matching the given IDs, along
+// with the IDs which are missing or could not be looked up. Objects are fetched
+// in bulk.
+func (api *API) GetCampusesByIDs(ids []int) *ByIDsResult[Campus] {
+	return GetByIDs[Campus](api, ids)
+}
+
+// GetCarriersByIDs returns the Carrier objects matching the given IDs, along
+// with the IDs which are missing or could not be looked up. Objects are fetched
+// in bulk.
+func (api *API) GetCarriersByIDs(ids []int) *ByIDsResult[Carrier] {
+	return GetByIDs[Carrier](api, ids)
+}
+
+// GetCarrierFacilitiesByIDs returns the CarrierFacility objects matching the
+// given IDs, along with the IDs which are missing or could not be looked up.
+// Objects are fetched in bulk.
+func (api *API) GetCarrierFacilitiesByIDs(ids []int) *ByIDsResult[CarrierFacility] {
+	return GetByIDs[CarrierFacility](api, ids)
+}
+
+// GetFacilitiesByIDs returns the Facility objects matching the given IDs, along
+// with the IDs which are missing or could not be looked up. Objects are fetched
+// in bulk.
+func (api *API) GetFacilitiesByIDs(ids []int) *ByIDsResult[Facility] {
+	return GetByIDs[Facility](api, ids)
+}
+
+// GetInternetExchangesByIDs returns the InternetExchange objects matching the
+// given IDs, along with the IDs which are missing or could not be looked up.
+// Objects are fetched in bulk.
+func (api *API) GetInternetExchangesByIDs(ids []int) *ByIDsResult[InternetExchange] {
+	return GetByIDs[InternetExchange](api, ids)
+}
+
+// GetInternetExchangeLANsByIDs returns the InternetExchangeLAN objects matching
+// the given IDs, along with the IDs which are missing or could not be looked
+// up. Objects are fetched in bulk.
+func (api *API) GetInternetExchangeLANsByIDs(ids []int) *ByIDsResult[InternetExchangeLAN] {
+	return GetByIDs[InternetExchangeLAN](api, ids)
+}
+
+// GetInternetExchangePrefixesByIDs returns the InternetExchangePrefix objects
+// matching the given IDs, along with the IDs which are missing or could not be
+// looked up. Objects are fetched in bulk.
+func (api *API) GetInternetExchangePrefixesByIDs(ids []int) *ByIDsResult[InternetExchangePrefix] {
+	return GetByIDs[InternetExchangePrefix](api, ids)
+}
+
+// GetInternetExchangeFacilitiesByIDs returns the InternetExchangeFacility
+// objects matching the given IDs, along with the IDs which are missing or could
+// not be looked up. Objects are fetched in bulk.
+func (api *API) GetInternetExchangeFacilitiesByIDs(ids []int) *ByIDsResult[InternetExchangeFacility] {
+	return GetByIDs[InternetExchangeFacility](api, ids)
+}
+
+// GetNetworksByIDs returns the Network objects matching the given IDs, along
+// with the IDs which are missing or could not be looked up. Objects are fetched
+// in bulk.
+func (api *API) GetNetworksByIDs(ids []int) *ByIDsResult[Network] {
+	return GetByIDs[Network](api, ids)
+}
+
+// GetNetworkContactsByIDs returns the NetworkContact objects matching the given
+// IDs, along with the IDs which are missing or could not be looked up. Objects
+// are fetched in bulk.
+func (api *API) GetNetworkContactsByIDs(ids []int) *ByIDsResult[NetworkContact] {
+	return GetByIDs[NetworkContact](api, ids)
+}
+
+// GetNetworkFacilitiesByIDs returns the NetworkFacility objects matching the
+// given IDs, along with the IDs which are missing or could not be looked up.
+// Objects are fetched in bulk.
+func (api *API) GetNetworkFacilitiesByIDs(ids []int) *ByIDsResult[NetworkFacility] {
+	return GetByIDs[NetworkFacility](api, ids)
+}
+
+// GetNetworkInternetExchangeLANsByIDs returns the NetworkInternetExchangeLAN
+// objects matching the given IDs, along with the IDs which are missing or could
+// not be looked up. Objects are fetched in bulk.
+func (api *API) GetNetworkInternetExchangeLANsByIDs(ids []int) *ByIDsResult[NetworkInternetExchangeLAN] {
+	return GetByIDs[NetworkInternetExchangeLAN](api, ids)
+}
+
+// GetOrganizationsByIDs returns the Organization objects matching the given
+// IDs, along with the IDs which are missing or could not be looked up. Objects
+// are fetched in bulk.
+func (api *API) GetOrganizationsByIDs(ids []int) *ByIDsResult[Organization] {
+	return GetByIDs[Organization](api, ids)
+}
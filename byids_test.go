@@ -0,0 +1,97 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetByIDs(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+			{"id": 2, "asn": 64501},
+		},
+	})
+	api := server.api()
+
+	result := api.GetNetworksByIDs([]int{2, 3, 1, 2})
+	if result.Complete() || len(result.Objects) != 2 || result.Objects[0].ID != 2 || result.Objects[1].ID != 1 {
+		t.Errorf("GetNetworksByIDs, unexpected objects: %+v", result.Objects)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != 3 || len(result.Failed) != 0 {
+		t.Errorf("GetNetworksByIDs, unexpected missing or failed IDs: %v %v", result.Missing, result.Failed)
+	}
+
+	// The second chunk fails
+	ids := make([]int, maxIDsPerQuery+1)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	server.setQuota(1)
+	result = api.GetNetworksByIDs(ids)
+	if len(result.Objects) != 2 || len(result.Missing) != maxIDsPerQuery-2 || len(result.Failed) != 1 {
+		t.Fatalf("GetNetworksByIDs, unexpected result: %d found, %d missing, %d failed",
+			len(result.Objects), len(result.Missing), len(result.Failed))
+	}
+	if result.Failed[0].ID != maxIDsPerQuery+1 || !errors.Is(result.Failed[0].Err, ErrRateLimitExceeded) {
+		t.Errorf("GetNetworksByIDs, unexpected failure: %+v", result.Failed[0])
+	}
+}
+
+func TestGenericGetByIDs(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		facilityNamespace: {
+			{"id": 1, "name": "Facility A"},
+			{"id": 2, "name": "Facility B"},
+		},
+	})
+	api := server.api()
+	mirror, err := NewMirror(api, t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{facilityNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, client := range map[string]Client{"api": api, "mirror": mirror} {
+		result := GetByIDs[Facility](client, []int{2, 3})
+		if len(result.Objects) != 1 || result.Objects[0].Name != "Facility B" || len(result.Missing) != 1 {
+			t.Errorf("GetByIDs from %s, unexpected result %+v", name, result)
+		}
+	}
+}
+
+func TestFetchByIDs(t *testing.T) {
+	networks := make([]map[string]interface{}, 25)
+	ids := make([]int, len(networks))
+	for i := range networks {
+		networks[i] = map[string]interface{}{"id": i + 1, "asn": 64500 + i}
+		ids[i] = i + 1
+	}
+	server := newTestServer(t, map[string][]map[string]interface{}{networkNamespace: networks})
+	api := server.api()
+
+	result := FetchByIDs[Network](api, append(ids, 26), FetchOptions{Workers: 3, BatchSize: 10})
+	if len(result.Objects) != 25 || len(result.Missing) != 1 || result.Err() != nil {
+		t.Fatalf("FetchByIDs, unexpected result: %d found, %d missing, error %v", len(result.Objects), len(result.Missing), result.Err())
+	}
+	for i, network := range result.Objects {
+		if network.ID != ids[i] {
+			t.Fatalf("FetchByIDs, expected network %d at %d, got %d", ids[i], i, network.ID)
+		}
+	}
+	if count := server.count(networkNamespace); count != 3 {
+		t.Errorf("FetchByIDs, expected 3 queries, got %d", count)
+	}
+
+	// Only the first query answered is successful
+	server.setQuota(1)
+	result = FetchByIDs[Network](api, ids, FetchOptions{Workers: 2, BatchSize: 10})
+	if len(result.Objects) != 10 && len(result.Objects) != 5 {
+		t.Errorf("FetchByIDs, unexpected number of objects: %d", len(result.Objects))
+	}
+	if len(result.Failed) != 25-len(result.Objects) || !errors.Is(result.Err(), ErrRateLimitExceeded) {
+		t.Errorf("FetchByIDs, unexpected failures: %d failed, error %v", len(result.Failed), result.Err())
+	}
+}
@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// campusResource is the top-level structure when parsing the JSON output
-// from the API. This structure is not used if the Campus JSON object is
-// included as a field in another JSON object. This structure is used only if
-// the proper namespace is queried.
-type campusResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []Campus `json:"data"`
-}
-
 // Campus is the representation of a site where facilities are.
 type Campus struct {
 	ID               int          `json:"id"`
@@ -34,59 +23,39 @@ type Campus struct {
 	Country          string       `json:"country"`
 	State            string       `json:"state"`
 	Zipcode          string       `json:"zipcode"`
-	FacilitySet      []int        `json:"fac_set"`
+	FacilitySet      IDSet        `json:"fac_set"`
 	SocialMedia      []struct {
 		Service    string `json:"service"`
 		Identifier string `json:"identifier"`
 	} `json:"social_media"`
 }
 
-// getCampusResource returns a pointer to a campusResource structure
-// corresponding to the API JSON response. An error can be returned if
-// something went wrong.
-func (api *API) getCampusResource(search map[string]interface{}) (*campusResource, error) {
-	// Get the CampusResource from the API
-	response, err := api.lookup(campusNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &campusResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetCampus returns a pointer to a slice of Campus structures that the
 // PeeringDB API can provide matching the given search parameters map. If an
-// error occurs, the returned error will be non-nil. The returned value can be
-// nil if no object could be found.
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetCampus(search map[string]interface{}) (*[]Campus, error) {
-	// Ask for the all Campus objects
-	campusResource, err := api.getCampusResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetCampusWithContext(context.Background(), search)
+}
 
-	// Return all Campus objects, will be nil if slice is empty
-	return &campusResource.Data, nil
+// GetCampusWithContext is the same as GetCampus but uses the given context for
+// the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetCampusWithContext(ctx context.Context, search map[string]interface{}) (*[]Campus, error) {
+	return getObjects[Campus](ctx, api, search)
 }
 
 // GetAllCampuses returns a pointer to a slice of Campus structures that the
 // PeeringDB API can provide. If an error occurs, the returned error will be
-// non-nil. The can be nil if no object could be found.
+// non-nil. The returned slice is empty, but never nil, if no object could be
+// found.
 func (api *API) GetAllCampuses() (*[]Campus, error) {
-	// Return all Campus objects
-	return api.GetCampus(nil)
+	return api.GetAllCampusesWithContext(context.Background())
+}
+
+// GetAllCampusesWithContext is the same as GetAllCampuses but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetAllCampusesWithContext(ctx context.Context) (*[]Campus, error) {
+	return getAllObjects[Campus](ctx, api)
 }
 
 // GetCampusByID returns a pointer to a Campus structure that matches the
@@ -96,29 +65,11 @@ func (api *API) GetAllCampuses() (*[]Campus, error) {
 // given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetCampusByID(id int) (*Campus, error) {
-	// No point of looking for the campus with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the Campus given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	campuses, err := api.GetCampus(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No Campus matching the ID
-	if len(*campuses) < 1 {
-		return nil, nil
-	}
+	return api.GetCampusByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*campuses)[0], nil
+// GetCampusByIDWithContext is the same as GetCampusByID but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetCampusByIDWithContext(ctx context.Context, id int) (*Campus, error) {
+	return getObjectByID[Campus](ctx, api, id)
 }
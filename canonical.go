@@ -0,0 +1,84 @@
+package peeringdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalCanonical returns the canonical JSON encoding of a value, such as
+// any structure of this package. Encodings of equal values are byte-for-byte
+// identical whatever the machine, its timezone and the order of the fields,
+// so that they can be compared, hashed or diffed:
+//
+//   - object keys are sorted, including the ones of structures
+//   - there is no insignificant whitespace
+//   - characters such as "<" or "&" are not escaped
+//   - non integer numbers use their shortest decimal representation
+//   - times, given as RFC 3339 strings, are converted to UTC
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	return marshalCanonical(v, "", "")
+}
+
+// MarshalCanonicalIndent is like MarshalCanonical but each JSON element
+// begins on a new line, starting with prefix followed by copies of indent
+// according to its nesting. It suits snapshots meant to be diffed line by
+// line.
+func MarshalCanonicalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	return marshalCanonical(v, prefix, indent)
+}
+
+// marshalCanonical encodes a value as JSON, decodes it generically and
+// encodes it again, maps being encoded with their keys sorted.
+func marshalCanonical(v interface{}, prefix, indent string) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(&buffer)
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	buffer.Reset()
+	encoder.SetIndent(prefix, indent)
+	if err := encoder.Encode(canonicalize(value)); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// canonicalize returns the canonical form of a generically decoded JSON
+// value.
+func canonicalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = canonicalize(element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = canonicalize(element)
+		}
+	case json.Number:
+		if strings.ContainsAny(string(v), ".eE") {
+			if f, err := v.Float64(); err == nil {
+				return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+			}
+		}
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+
+	return value
+}
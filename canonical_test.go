@@ -0,0 +1,68 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	paris := time.FixedZone("CET", 3600)
+	network := Network{
+		ID:      1,
+		Name:    "Network <A> & co",
+		ASN:     64500,
+		Created: time.Date(2020, 1, 1, 1, 0, 0, 0, paris),
+	}
+
+	data, err := MarshalCanonical(network)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decoding and encoding again gives the same bytes, whatever the order
+	// of the fields and the timezone of times
+	var decoded map[string]interface{}
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["created"] != "2020-01-01T00:00:00Z" || decoded["name"] != "Network <A> & co" {
+		t.Errorf("MarshalCanonical, unexpected values: %s", data)
+	}
+	again, err := MarshalCanonical(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("MarshalCanonical, want\n%s\ngot\n%s", data, again)
+	}
+
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{map[string]interface{}{"b": 1, "a": []int{2, 1}}, `{"a":[2,1],"b":1}`},
+		{struct {
+			B float64 `json:"b"`
+			A string  `json:"a"`
+		}{B: 1e21, A: "x"}, `{"a":"x","b":1000000000000000000000}`},
+		{json.RawMessage(`{"z": 0.50, "y": {"d": null, "c": true}}`), `{"y":{"c":true,"d":null},"z":0.5}`},
+	}
+	for _, test := range tests {
+		data, err := MarshalCanonical(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("MarshalCanonical(%v), want %s got %s", test.value, test.expected, data)
+		}
+	}
+
+	indented, err := MarshalCanonicalIndent(map[string]int{"b": 2, "a": 1}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "{\n  \"a\": 1,\n  \"b\": 2\n}"; string(indented) != expected {
+		t.Errorf("MarshalCanonicalIndent, want %q got %q", expected, indented)
+	}
+}
@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// carrierResource is the top-level structure when parsing the JSON output
-// from the API. This structure is not used if the Carrier JSON object is
-// included as a field in another JSON object. This structure is used only if
-// the proper namespace is queried.
-type carrierResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []Carrier `json:"data"`
-}
-
 // Carrier is the representation of a network able to provider transport from
 // one facility to another.
 type Carrier struct {
@@ -37,52 +26,32 @@ type Carrier struct {
 	} `json:"social_media"`
 }
 
-// getCarrierResource returns a pointer to a carrierResource structure
-// corresponding to the API JSON response. An error can be returned if
-// something went wrong.
-func (api *API) getCarrierResource(search map[string]interface{}) (*carrierResource, error) {
-	// Get the CarrierResource from the API
-	response, err := api.lookup(carrierNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &carrierResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetCarrier returns a pointer to a slice of Carrier structures that the
 // PeeringDB API can provide matching the given search parameters map. If an
-// error occurs, the returned error will be non-nil. The returned value can be
-// nil if no object could be found.
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetCarrier(search map[string]interface{}) (*[]Carrier, error) {
-	// Ask for the all Carrier objects
-	carrierResource, err := api.getCarrierResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetCarrierWithContext(context.Background(), search)
+}
 
-	// Return all Carrier objects, will be nil if slice is empty
-	return &carrierResource.Data, nil
+// GetCarrierWithContext is the same as GetCarrier but uses the given context
+// for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetCarrierWithContext(ctx context.Context, search map[string]interface{}) (*[]Carrier, error) {
+	return getObjects[Carrier](ctx, api, search)
 }
 
 // GetAllCarriers returns a pointer to a slice of Carrier structures that the
 // PeeringDB API can provide. If an error occurs, the returned error will be
-// non-nil. The can be nil if no object could be found.
+// non-nil. The returned slice is empty, but never nil, if no object could be
+// found.
 func (api *API) GetAllCarriers() (*[]Carrier, error) {
-	// Return all Carrier objects
-	return api.GetCarrier(nil)
+	return api.GetAllCarriersWithContext(context.Background())
+}
+
+// GetAllCarriersWithContext is the same as GetAllCarriers but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetAllCarriersWithContext(ctx context.Context) (*[]Carrier, error) {
+	return getAllObjects[Carrier](ctx, api)
 }
 
 // GetCarrierByID returns a pointer to a Carrier structure that matches the
@@ -92,42 +61,13 @@ func (api *API) GetAllCarriers() (*[]Carrier, error) {
 // given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetCarrierByID(id int) (*Carrier, error) {
-	// No point of looking for the carrier with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the Carrier given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	carriers, err := api.GetCarrier(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No Carrier matching the ID
-	if len(*carriers) < 1 {
-		return nil, nil
-	}
-
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*carriers)[0], nil
+	return api.GetCarrierByIDWithContext(context.Background(), id)
 }
 
-// carrierFacilityResource is the top-level structure when parsing the JSON
-// output from the API. This structure is not used if the CarrierFacility JSON
-// object is included as a field in another JSON object. This structure is
-// used only if the proper namespace is queried.
-type carrierFacilityResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []CarrierFacility `json:"data"`
+// GetCarrierByIDWithContext is the same as GetCarrierByID but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetCarrierByIDWithContext(ctx context.Context, id int) (*Carrier, error) {
+	return getObjectByID[Carrier](ctx, api, id)
 }
 
 // CarrierFacility is a structure used to link an Carrier structure with a
@@ -145,53 +85,34 @@ type CarrierFacility struct {
 	Status     string    `json:"status"`
 }
 
-// getCarrierFacilityResource returns a pointer to an carrierFacilityResource
-// structure corresponding to the API JSON response. An error can be returned
-// if something went wrong.
-func (api *API) getCarrierFacilityResource(search map[string]interface{}) (*carrierFacilityResource, error) {
-	// Get the CarrierFacilityResource from the API
-	response, err := api.lookup(carrierFacilityNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &carrierFacilityResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
-// GetCarrierFacility returns a pointer to a slice of CarrierFacility
-// structures that the PeeringDB API can provide matching the given search
-// parameters map. If an error occurs, the returned error will be non-nil. The
-// returned value can be nil if no object could be found.
+// GetCarrierFacility returns a pointer to a slice of CarrierFacility structures
+// that the PeeringDB API can provide matching the given search parameters map.
+// If an error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetCarrierFacility(search map[string]interface{}) (*[]CarrierFacility, error) {
-	// Ask for the all CarrierFacility objects
-	carrierFacilityResource, err := api.getCarrierFacilityResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetCarrierFacilityWithContext(context.Background(), search)
+}
 
-	// Return all InternetExchangeFacility objects, will be nil if slice is
-	// empty
-	return &carrierFacilityResource.Data, nil
+// GetCarrierFacilityWithContext is the same as GetCarrierFacility but uses the
+// given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetCarrierFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]CarrierFacility, error) {
+	return getObjects[CarrierFacility](ctx, api, search)
 }
 
 // GetAllCarrierFacilities returns a pointer to a slice of CarrierFacility
 // structures that the PeeringDB API can provide. If an error occurs, the
-// returned error will be non-nil. The can be nil if no object could be found.
+// returned error will be non-nil. The returned slice is empty, but never nil,
+// if no object could be found.
 func (api *API) GetAllCarrierFacilities() (*[]CarrierFacility, error) {
-	// Return all CarrierFacility objects
-	return api.GetCarrierFacility(nil)
+	return api.GetAllCarrierFacilitiesWithContext(context.Background())
+}
+
+// GetAllCarrierFacilitiesWithContext is the same as GetAllCarrierFacilities but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetAllCarrierFacilitiesWithContext(ctx context.Context) (*[]CarrierFacility, error) {
+	return getAllObjects[CarrierFacility](ctx, api)
 }
 
 // GetCarrierFacilityByID returns a pointer to a CarrierFacility structure
@@ -201,29 +122,12 @@ func (api *API) GetAllCarrierFacilities() (*[]CarrierFacility, error) {
 // the given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetCarrierFacilityByID(id int) (*CarrierFacility, error) {
-	// No point of looking for the carrier facility with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the CarrierFacility given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	carrierFacilities, err := api.GetCarrierFacility(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No CarrierFacility matching the ID
-	if len(*carrierFacilities) < 1 {
-		return nil, nil
-	}
+	return api.GetCarrierFacilityByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*carrierFacilities)[0], nil
+// GetCarrierFacilityByIDWithContext is the same as GetCarrierFacilityByID but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetCarrierFacilityByIDWithContext(ctx context.Context, id int) (*CarrierFacility, error) {
+	return getObjectByID[CarrierFacility](ctx, api, id)
 }
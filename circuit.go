@@ -0,0 +1,190 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCircuitFailureThreshold is the number of consecutive failures
+	// opening a circuit breaker if none is given.
+	defaultCircuitFailureThreshold = 5
+	// defaultCircuitCoolDown is the time a circuit breaker stays open if none
+	// is given.
+	defaultCircuitCoolDown = 30 * time.Second
+)
+
+// ErrCircuitOpen is the error that will be returned, along with
+// ErrQueryingAPI, by API calls not made because the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed is the state of a circuit breaker letting all calls
+	// through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen is the state of a circuit breaker failing all calls right
+	// away during the cool-down period.
+	CircuitOpen
+	// CircuitHalfOpen is the state of a circuit breaker letting a single
+	// call through, after the cool-down period, to probe the API.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerOptions is a structure used to tune a CircuitBreaker.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed calls opening
+	// the circuit, 5 if it is zero. Calls failing without a response, because
+	// of a network error or a timeout, or with a 5xx status are failures.
+	FailureThreshold int
+	// CoolDown is the time the circuit stays open before a call is let
+	// through to probe the API, 30 seconds if it is zero.
+	CoolDown time.Duration
+	// OnStateChange is called, if set, when the state of the circuit
+	// changes. It may be called concurrently.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker stops API calls for a cool-down period once the API failed
+// several times in a row, so that a client fails fast instead of hammering an
+// API having issues. Once the cool-down period is over, a single call is let
+// through: the circuit is closed again if it succeeds, and open for another
+// period if it fails. A CircuitBreaker is safe for concurrent use and can be
+// shared by several API structures.
+type CircuitBreaker struct {
+	options CircuitBreakerOptions
+	now     func() time.Time
+
+	mutex    sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns a pointer to a new closed CircuitBreaker tuned
+// with the given options.
+func NewCircuitBreaker(options CircuitBreakerOptions) *CircuitBreaker {
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = defaultCircuitFailureThreshold
+	}
+	if options.CoolDown <= 0 {
+		options.CoolDown = defaultCircuitCoolDown
+	}
+
+	return &CircuitBreaker{options: options, now: time.Now}
+}
+
+// WithCircuitBreaker returns an option guarding API calls with the given
+// circuit breaker. Calls made while the circuit is open fail right away with
+// ErrCircuitOpen, and are not retried.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(api *API) {
+		api.breaker = breaker
+		api.responseHooks = append(api.responseHooks, breaker.record)
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+// setState changes the state of the circuit, the mutex must be held. It
+// returns a function notifying the change, to call once the mutex is
+// released.
+func (b *CircuitBreaker) setState(state CircuitState) func() {
+	from := b.state
+	b.state = state
+	b.probing = false
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+	if state == CircuitClosed {
+		b.failures = 0
+	}
+
+	if b.options.OnStateChange == nil || from == state {
+		return func() {}
+	}
+	return func() { b.options.OnStateChange(from, state) }
+}
+
+// allow tells if a call can be made, returning an error wrapping
+// ErrCircuitOpen if it cannot.
+func (b *CircuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mutex.Lock()
+	notify := func() {}
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.options.CoolDown {
+		notify = b.setState(CircuitHalfOpen)
+	}
+	allowed := b.state == CircuitClosed || (b.state == CircuitHalfOpen && !b.probing)
+	if b.state == CircuitHalfOpen && allowed {
+		b.probing = true
+	}
+	b.mutex.Unlock()
+	notify()
+
+	if !allowed {
+		return fmt.Errorf("%w: %w", ErrQueryingAPI, ErrCircuitOpen)
+	}
+
+	return nil
+}
+
+// record updates the circuit given the outcome of a call.
+func (b *CircuitBreaker) record(info ResponseInfo) {
+	if b == nil {
+		return
+	}
+
+	// Calls canceled by their caller tell nothing about the API
+	canceled := errors.Is(info.Err, context.Canceled)
+	failed := info.Err != nil && !canceled && (info.StatusCode == 0 || info.StatusCode >= 500)
+
+	b.mutex.Lock()
+	notify := func() {}
+	switch {
+	case b.state == CircuitHalfOpen && canceled:
+		b.probing = false
+	case b.state == CircuitHalfOpen && failed:
+		notify = b.setState(CircuitOpen)
+	case b.state == CircuitHalfOpen:
+		notify = b.setState(CircuitClosed)
+	case b.state == CircuitClosed && failed:
+		b.failures++
+		if b.failures >= b.options.FailureThreshold {
+			notify = b.setState(CircuitOpen)
+		}
+	case b.state == CircuitClosed && !canceled:
+		b.failures = 0
+	}
+	b.mutex.Unlock()
+	notify()
+}
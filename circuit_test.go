@@ -0,0 +1,125 @@
+package peeringdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls, failing atomic.Int32
+	failing.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	var transitions []string
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{
+		FailureThreshold: 2,
+		CoolDown:         time.Minute,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+">"+to.String())
+		},
+	})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	api := NewAPIFromURL(server.URL+"/api/", WithCircuitBreaker(breaker))
+
+	// The circuit opens after consecutive failures, then fails fast
+	for i := 0; i < 2; i++ {
+		if _, err := api.GetNetworkByID(1); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("GetNetworkByID, want an API error got %v", err)
+		}
+	}
+	if breaker.State() != CircuitOpen {
+		t.Errorf("State, want %s got %s", CircuitOpen, breaker.State())
+	}
+	if _, err := api.GetNetworkByID(1); !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrQueryingAPI) {
+		t.Errorf("GetNetworkByID, want ErrCircuitOpen got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("GetNetworkByID, want 2 calls got %d", calls.Load())
+	}
+
+	// A failed probe opens the circuit for another cool-down period
+	now = now.Add(time.Minute)
+	if _, err := api.GetNetworkByID(1); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("GetNetworkByID, want an API error got %v", err)
+	}
+	if _, err := api.GetNetworkByID(1); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("GetNetworkByID, want ErrCircuitOpen got %v", err)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	failing.Store(0)
+	if network, err := api.GetNetworkByID(1); err != nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByID, unexpected result %+v: %v", network, err)
+	}
+	if breaker.State() != CircuitClosed {
+		t.Errorf("State, want %s got %s", CircuitClosed, breaker.State())
+	}
+
+	expected := []string{"closed>open", "open>half-open", "half-open>open", "open>half-open", "half-open>closed"}
+	if len(transitions) != len(expected) {
+		t.Fatalf("OnStateChange, want %v got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("OnStateChange, want %v got %v", expected, transitions)
+			break
+		}
+	}
+}
+
+func TestCircuitBreakerSigningFailure(t *testing.T) {
+	var failing, signing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 1, CoolDown: time.Minute})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	api := NewAPIFromURL(server.URL+"/api/", WithCircuitBreaker(breaker), WithRequestSigner(func(*http.Request) error {
+		if signing.Load() {
+			return errors.New("no signing key")
+		}
+		return nil
+	}))
+
+	if _, err := api.GetNetworkByID(1); err == nil || breaker.State() != CircuitOpen {
+		t.Fatalf("GetNetworkByID, want the circuit open got %s: %v", breaker.State(), err)
+	}
+
+	// A request failing to be signed once the cool-down period is over is
+	// not a probe, the next call still is
+	now = now.Add(time.Minute)
+	failing.Store(false)
+	signing.Store(true)
+	if _, err := api.GetNetworkByID(1); !errors.Is(err, ErrSigningRequest) {
+		t.Errorf("GetNetworkByID, want ErrSigningRequest got %v", err)
+	}
+	signing.Store(false)
+	if network, err := api.GetNetworkByID(1); err != nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByID, unexpected result %+v: %v", network, err)
+	}
+	if breaker.State() != CircuitClosed {
+		t.Errorf("State, want %s got %s", CircuitClosed, breaker.State())
+	}
+}
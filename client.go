@@ -0,0 +1,411 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Client is the interface implemented by all sources of PeeringDB objects:
+// the live API, an offline Mirror, etc. Code depending on it can switch from
+// a source to another without further changes, and be given a fake in unit
+// tests.
+type Client interface {
+	GetASN(asn ASN) (*Network, error)
+	GetCampus(search map[string]interface{}) (*[]Campus, error)
+	GetAllCampuses() (*[]Campus, error)
+	GetCampusByID(id int) (*Campus, error)
+	GetCarrier(search map[string]interface{}) (*[]Carrier, error)
+	GetAllCarriers() (*[]Carrier, error)
+	GetCarrierByID(id int) (*Carrier, error)
+	GetCarrierFacility(search map[string]interface{}) (*[]CarrierFacility, error)
+	GetAllCarrierFacilities() (*[]CarrierFacility, error)
+	GetCarrierFacilityByID(id int) (*CarrierFacility, error)
+	GetFacility(search map[string]interface{}) (*[]Facility, error)
+	GetAllFacilities() (*[]Facility, error)
+	GetFacilityByID(id int) (*Facility, error)
+	GetInternetExchange(search map[string]interface{}) (*[]InternetExchange, error)
+	GetAllInternetExchanges() (*[]InternetExchange, error)
+	GetInternetExchangeByID(id int) (*InternetExchange, error)
+	GetInternetExchangeFacility(search map[string]interface{}) (*[]InternetExchangeFacility, error)
+	GetAllInternetExchangeFacilities() (*[]InternetExchangeFacility, error)
+	GetInternetExchangeFacilityByID(id int) (*InternetExchangeFacility, error)
+	GetInternetExchangeLAN(search map[string]interface{}) (*[]InternetExchangeLAN, error)
+	GetAllInternetExchangeLANs() (*[]InternetExchangeLAN, error)
+	GetInternetExchangeLANByID(id int) (*InternetExchangeLAN, error)
+	GetInternetExchangePrefix(search map[string]interface{}) (*[]InternetExchangePrefix, error)
+	GetAllInternetExchangePrefixes() (*[]InternetExchangePrefix, error)
+	GetInternetExchangePrefixByID(id int) (*InternetExchangePrefix, error)
+	GetNetwork(search map[string]interface{}) (*[]Network, error)
+	GetAllNetworks() (*[]Network, error)
+	GetNetworkByID(id int) (*Network, error)
+	GetNetworkContact(search map[string]interface{}) (*[]NetworkContact, error)
+	GetAllNetworkContacts() (*[]NetworkContact, error)
+	GetNetworkContactByID(id int) (*NetworkContact, error)
+	GetNetworkFacility(search map[string]interface{}) (*[]NetworkFacility, error)
+	GetAllNetworkFacilities() (*[]NetworkFacility, error)
+	GetNetworkFacilityByID(id int) (*NetworkFacility, error)
+	GetNetworkInternetExchangeLAN(search map[string]interface{}) (*[]NetworkInternetExchangeLAN, error)
+	GetAllNetworkInternetExchangeLANs() (*[]NetworkInternetExchangeLAN, error)
+	GetNetworkInternetExchangeLANByID(id int) (*NetworkInternetExchangeLAN, error)
+	GetOrganization(search map[string]interface{}) (*[]Organization, error)
+	GetAllOrganizations() (*[]Organization, error)
+	GetOrganizationByID(id int) (*Organization, error)
+}
+
+// ContextClient is the interface of the Client methods taking a context, to
+// cancel lookups or to bound their duration. It is implemented by the API
+// structure, code depending on it can then be given a fake in unit tests,
+// without network access.
+type ContextClient interface {
+	Client
+	GetASNWithContext(ctx context.Context, asn ASN) (*Network, error)
+	GetCampusWithContext(ctx context.Context, search map[string]interface{}) (*[]Campus, error)
+	GetAllCampusesWithContext(ctx context.Context) (*[]Campus, error)
+	GetCampusByIDWithContext(ctx context.Context, id int) (*Campus, error)
+	GetCarrierWithContext(ctx context.Context, search map[string]interface{}) (*[]Carrier, error)
+	GetAllCarriersWithContext(ctx context.Context) (*[]Carrier, error)
+	GetCarrierByIDWithContext(ctx context.Context, id int) (*Carrier, error)
+	GetCarrierFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]CarrierFacility, error)
+	GetAllCarrierFacilitiesWithContext(ctx context.Context) (*[]CarrierFacility, error)
+	GetCarrierFacilityByIDWithContext(ctx context.Context, id int) (*CarrierFacility, error)
+	GetFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]Facility, error)
+	GetAllFacilitiesWithContext(ctx context.Context) (*[]Facility, error)
+	GetFacilityByIDWithContext(ctx context.Context, id int) (*Facility, error)
+	GetInternetExchangeWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchange, error)
+	GetAllInternetExchangesWithContext(ctx context.Context) (*[]InternetExchange, error)
+	GetInternetExchangeByIDWithContext(ctx context.Context, id int) (*InternetExchange, error)
+	GetInternetExchangeFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchangeFacility, error)
+	GetAllInternetExchangeFacilitiesWithContext(ctx context.Context) (*[]InternetExchangeFacility, error)
+	GetInternetExchangeFacilityByIDWithContext(ctx context.Context, id int) (*InternetExchangeFacility, error)
+	GetInternetExchangeLANWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchangeLAN, error)
+	GetAllInternetExchangeLANsWithContext(ctx context.Context) (*[]InternetExchangeLAN, error)
+	GetInternetExchangeLANByIDWithContext(ctx context.Context, id int) (*InternetExchangeLAN, error)
+	GetInternetExchangePrefixWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchangePrefix, error)
+	GetAllInternetExchangePrefixesWithContext(ctx context.Context) (*[]InternetExchangePrefix, error)
+	GetInternetExchangePrefixByIDWithContext(ctx context.Context, id int) (*InternetExchangePrefix, error)
+	GetNetworkWithContext(ctx context.Context, search map[string]interface{}) (*[]Network, error)
+	GetAllNetworksWithContext(ctx context.Context) (*[]Network, error)
+	GetNetworkByIDWithContext(ctx context.Context, id int) (*Network, error)
+	GetNetworkContactWithContext(ctx context.Context, search map[string]interface{}) (*[]NetworkContact, error)
+	GetAllNetworkContactsWithContext(ctx context.Context) (*[]NetworkContact, error)
+	GetNetworkContactByIDWithContext(ctx context.Context, id int) (*NetworkContact, error)
+	GetNetworkFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]NetworkFacility, error)
+	GetAllNetworkFacilitiesWithContext(ctx context.Context) (*[]NetworkFacility, error)
+	GetNetworkFacilityByIDWithContext(ctx context.Context, id int) (*NetworkFacility, error)
+	GetNetworkInternetExchangeLANWithContext(ctx context.Context, search map[string]interface{}) (*[]NetworkInternetExchangeLAN, error)
+	GetAllNetworkInternetExchangeLANsWithContext(ctx context.Context) (*[]NetworkInternetExchangeLAN, error)
+	GetNetworkInternetExchangeLANByIDWithContext(ctx context.Context, id int) (*NetworkInternetExchangeLAN, error)
+	GetOrganizationWithContext(ctx context.Context, search map[string]interface{}) (*[]Organization, error)
+	GetAllOrganizationsWithContext(ctx context.Context) (*[]Organization, error)
+	GetOrganizationByIDWithContext(ctx context.Context, id int) (*Organization, error)
+}
+
+var (
+	_ Client = (*API)(nil)
+	_ Client = (*Mirror)(nil)
+	_ Client = (*LayeredClient)(nil)
+
+	_ ContextClient = (*API)(nil)
+)
+
+// rawSource is implemented by sources of objects which are not typed. Objects
+// are returned as raw JSON, as they are found in API responses.
+type rawSource interface {
+	getRaw(namespace string, search map[string]interface{}) ([]json.RawMessage, error)
+}
+
+// getFromSource returns the objects of a namespace matching the given search
+// parameters, decoded into their structures.
+func getFromSource[T any](source rawSource, namespace string, search map[string]interface{}) (*[]T, error) {
+	objects, err := source.getRaw(namespace, search)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := make([]T, len(objects))
+	for i, object := range objects {
+		if err = json.Unmarshal(object, &decoded[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &decoded, nil
+}
+
+// getByIDFromSource returns the object of a namespace matching the given ID,
+// decoded into its structure. Nil is returned if there is no such object.
+func getByIDFromSource[T any](source rawSource, namespace string, id int) (*T, error) {
+	// No point of looking for an object with an ID < 0
+	if id < 0 {
+		return nil, nil
+	}
+
+	search := make(map[string]interface{})
+	search["id"] = id
+
+	objects, err := getFromSource[T](source, namespace, search)
+	if err != nil {
+		return nil, err
+	}
+	if len(*objects) < 1 {
+		return nil, nil
+	}
+
+	return &(*objects)[0], nil
+}
+
+// sourceClient implements the Client interface on top of a rawSource.
+type sourceClient struct {
+	source rawSource
+}
+
+// GetASN returns a pointer to the Network structure matching the given AS
+// number. An error is returned if it cannot be found.
+func (c sourceClient) GetASN(asn ASN) (*Network, error) {
+	search := make(map[string]interface{})
+	search["asn"] = asn
+
+	networks, err := c.GetNetwork(search)
+	if err != nil {
+		return nil, err
+	}
+	if len(*networks) == 0 {
+		return nil, fmt.Errorf("no network found for ASN %d", asn)
+	}
+
+	return &(*networks)[0], nil
+}
+
+// GetCampus returns a pointer to a slice of Campus structures matching the
+// given search parameters map.
+func (c sourceClient) GetCampus(search map[string]interface{}) (*[]Campus, error) {
+	return getFromSource[Campus](c.source, campusNamespace, search)
+}
+
+// GetAllCampuses returns a pointer to a slice of all Campus structures.
+func (c sourceClient) GetAllCampuses() (*[]Campus, error) {
+	return getFromSource[Campus](c.source, campusNamespace, nil)
+}
+
+// GetCampusByID returns a pointer to the Campus structure matching the given
+// ID, nil is returned if it cannot be found.
+func (c sourceClient) GetCampusByID(id int) (*Campus, error) {
+	return getByIDFromSource[Campus](c.source, campusNamespace, id)
+}
+
+// GetCarrier returns a pointer to a slice of Carrier structures matching the
+// given search parameters map.
+func (c sourceClient) GetCarrier(search map[string]interface{}) (*[]Carrier, error) {
+	return getFromSource[Carrier](c.source, carrierNamespace, search)
+}
+
+// GetAllCarriers returns a pointer to a slice of all Carrier structures.
+func (c sourceClient) GetAllCarriers() (*[]Carrier, error) {
+	return getFromSource[Carrier](c.source, carrierNamespace, nil)
+}
+
+// GetCarrierByID returns a pointer to the Carrier structure matching the given
+// ID, nil is returned if it cannot be found.
+func (c sourceClient) GetCarrierByID(id int) (*Carrier, error) {
+	return getByIDFromSource[Carrier](c.source, carrierNamespace, id)
+}
+
+// GetCarrierFacility returns a pointer to a slice of CarrierFacility structures
+// matching the given search parameters map.
+func (c sourceClient) GetCarrierFacility(search map[string]interface{}) (*[]CarrierFacility, error) {
+	return getFromSource[CarrierFacility](c.source, carrierFacilityNamespace, search)
+}
+
+// GetAllCarrierFacilities returns a pointer to a slice of all CarrierFacility
+// structures.
+func (c sourceClient) GetAllCarrierFacilities() (*[]CarrierFacility, error) {
+	return getFromSource[CarrierFacility](c.source, carrierFacilityNamespace, nil)
+}
+
+// GetCarrierFacilityByID returns a pointer to the CarrierFacility structure
+// matching the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetCarrierFacilityByID(id int) (*CarrierFacility, error) {
+	return getByIDFromSource[CarrierFacility](c.source, carrierFacilityNamespace, id)
+}
+
+// GetFacility returns a pointer to a slice of Facility structures matching the
+// given search parameters map.
+func (c sourceClient) GetFacility(search map[string]interface{}) (*[]Facility, error) {
+	return getFromSource[Facility](c.source, facilityNamespace, search)
+}
+
+// GetAllFacilities returns a pointer to a slice of all Facility structures.
+func (c sourceClient) GetAllFacilities() (*[]Facility, error) {
+	return getFromSource[Facility](c.source, facilityNamespace, nil)
+}
+
+// GetFacilityByID returns a pointer to the Facility structure matching the
+// given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetFacilityByID(id int) (*Facility, error) {
+	return getByIDFromSource[Facility](c.source, facilityNamespace, id)
+}
+
+// GetInternetExchange returns a pointer to a slice of InternetExchange
+// structures matching the given search parameters map.
+func (c sourceClient) GetInternetExchange(search map[string]interface{}) (*[]InternetExchange, error) {
+	return getFromSource[InternetExchange](c.source, internetExchangeNamespace, search)
+}
+
+// GetAllInternetExchanges returns a pointer to a slice of all InternetExchange
+// structures.
+func (c sourceClient) GetAllInternetExchanges() (*[]InternetExchange, error) {
+	return getFromSource[InternetExchange](c.source, internetExchangeNamespace, nil)
+}
+
+// GetInternetExchangeByID returns a pointer to the InternetExchange structure
+// matching the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetInternetExchangeByID(id int) (*InternetExchange, error) {
+	return getByIDFromSource[InternetExchange](c.source, internetExchangeNamespace, id)
+}
+
+// GetInternetExchangeFacility returns a pointer to a slice of
+// InternetExchangeFacility structures matching the given search parameters map.
+func (c sourceClient) GetInternetExchangeFacility(search map[string]interface{}) (*[]InternetExchangeFacility, error) {
+	return getFromSource[InternetExchangeFacility](c.source, internetExchangeFacilityNamespace, search)
+}
+
+// GetAllInternetExchangeFacilities returns a pointer to a slice of all
+// InternetExchangeFacility structures.
+func (c sourceClient) GetAllInternetExchangeFacilities() (*[]InternetExchangeFacility, error) {
+	return getFromSource[InternetExchangeFacility](c.source, internetExchangeFacilityNamespace, nil)
+}
+
+// GetInternetExchangeFacilityByID returns a pointer to the
+// InternetExchangeFacility structure matching the given ID, nil is returned if
+// it cannot be found.
+func (c sourceClient) GetInternetExchangeFacilityByID(id int) (*InternetExchangeFacility, error) {
+	return getByIDFromSource[InternetExchangeFacility](c.source, internetExchangeFacilityNamespace, id)
+}
+
+// GetInternetExchangeLAN returns a pointer to a slice of InternetExchangeLAN
+// structures matching the given search parameters map.
+func (c sourceClient) GetInternetExchangeLAN(search map[string]interface{}) (*[]InternetExchangeLAN, error) {
+	return getFromSource[InternetExchangeLAN](c.source, internetExchangeLANNamespace, search)
+}
+
+// GetAllInternetExchangeLANs returns a pointer to a slice of all
+// InternetExchangeLAN structures.
+func (c sourceClient) GetAllInternetExchangeLANs() (*[]InternetExchangeLAN, error) {
+	return getFromSource[InternetExchangeLAN](c.source, internetExchangeLANNamespace, nil)
+}
+
+// GetInternetExchangeLANByID returns a pointer to the InternetExchangeLAN
+// structure matching the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetInternetExchangeLANByID(id int) (*InternetExchangeLAN, error) {
+	return getByIDFromSource[InternetExchangeLAN](c.source, internetExchangeLANNamespace, id)
+}
+
+// GetInternetExchangePrefix returns a pointer to a slice of
+// InternetExchangePrefix structures matching the given search parameters map.
+func (c sourceClient) GetInternetExchangePrefix(search map[string]interface{}) (*[]InternetExchangePrefix, error) {
+	return getFromSource[InternetExchangePrefix](c.source, internetExchangePrefixNamespace, search)
+}
+
+// GetAllInternetExchangePrefixes returns a pointer to a slice of all
+// InternetExchangePrefix structures.
+func (c sourceClient) GetAllInternetExchangePrefixes() (*[]InternetExchangePrefix, error) {
+	return getFromSource[InternetExchangePrefix](c.source, internetExchangePrefixNamespace, nil)
+}
+
+// GetInternetExchangePrefixByID returns a pointer to the InternetExchangePrefix
+// structure matching the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetInternetExchangePrefixByID(id int) (*InternetExchangePrefix, error) {
+	return getByIDFromSource[InternetExchangePrefix](c.source, internetExchangePrefixNamespace, id)
+}
+
+// GetNetwork returns a pointer to a slice of Network structures matching the
+// given search parameters map.
+func (c sourceClient) GetNetwork(search map[string]interface{}) (*[]Network, error) {
+	return getFromSource[Network](c.source, networkNamespace, search)
+}
+
+// GetAllNetworks returns a pointer to a slice of all Network structures.
+func (c sourceClient) GetAllNetworks() (*[]Network, error) {
+	return getFromSource[Network](c.source, networkNamespace, nil)
+}
+
+// GetNetworkByID returns a pointer to the Network structure matching the given
+// ID, nil is returned if it cannot be found.
+func (c sourceClient) GetNetworkByID(id int) (*Network, error) {
+	return getByIDFromSource[Network](c.source, networkNamespace, id)
+}
+
+// GetNetworkContact returns a pointer to a slice of NetworkContact structures
+// matching the given search parameters map.
+func (c sourceClient) GetNetworkContact(search map[string]interface{}) (*[]NetworkContact, error) {
+	return getFromSource[NetworkContact](c.source, networkContactNamespace, search)
+}
+
+// GetAllNetworkContacts returns a pointer to a slice of all NetworkContact
+// structures.
+func (c sourceClient) GetAllNetworkContacts() (*[]NetworkContact, error) {
+	return getFromSource[NetworkContact](c.source, networkContactNamespace, nil)
+}
+
+// GetNetworkContactByID returns a pointer to the NetworkContact structure
+// matching the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetNetworkContactByID(id int) (*NetworkContact, error) {
+	return getByIDFromSource[NetworkContact](c.source, networkContactNamespace, id)
+}
+
+// GetNetworkFacility returns a pointer to a slice of NetworkFacility structures
+// matching the given search parameters map.
+func (c sourceClient) GetNetworkFacility(search map[string]interface{}) (*[]NetworkFacility, error) {
+	return getFromSource[NetworkFacility](c.source, networkFacilityNamespace, search)
+}
+
+// GetAllNetworkFacilities returns a pointer to a slice of all NetworkFacility
+// structures.
+func (c sourceClient) GetAllNetworkFacilities() (*[]NetworkFacility, error) {
+	return getFromSource[NetworkFacility](c.source, networkFacilityNamespace, nil)
+}
+
+// GetNetworkFacilityByID returns a pointer to the NetworkFacility structure
+// matching the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetNetworkFacilityByID(id int) (*NetworkFacility, error) {
+	return getByIDFromSource[NetworkFacility](c.source, networkFacilityNamespace, id)
+}
+
+// GetNetworkInternetExchangeLAN returns a pointer to a slice of
+// NetworkInternetExchangeLAN structures matching the given search parameters
+// map.
+func (c sourceClient) GetNetworkInternetExchangeLAN(search map[string]interface{}) (*[]NetworkInternetExchangeLAN, error) {
+	return getFromSource[NetworkInternetExchangeLAN](c.source, networkInternetExchangeLANNamespace, search)
+}
+
+// GetAllNetworkInternetExchangeLANs returns a pointer to a slice of all
+// NetworkInternetExchangeLAN structures.
+func (c sourceClient) GetAllNetworkInternetExchangeLANs() (*[]NetworkInternetExchangeLAN, error) {
+	return getFromSource[NetworkInternetExchangeLAN](c.source, networkInternetExchangeLANNamespace, nil)
+}
+
+// GetNetworkInternetExchangeLANByID returns a pointer to the
+// NetworkInternetExchangeLAN structure matching the given ID, nil is returned
+// if it cannot be found.
+func (c sourceClient) GetNetworkInternetExchangeLANByID(id int) (*NetworkInternetExchangeLAN, error) {
+	return getByIDFromSource[NetworkInternetExchangeLAN](c.source, networkInternetExchangeLANNamespace, id)
+}
+
+// GetOrganization returns a pointer to a slice of Organization structures
+// matching the given search parameters map.
+func (c sourceClient) GetOrganization(search map[string]interface{}) (*[]Organization, error) {
+	return getFromSource[Organization](c.source, organizationNamespace, search)
+}
+
+// GetAllOrganizations returns a pointer to a slice of all Organization
+// structures.
+func (c sourceClient) GetAllOrganizations() (*[]Organization, error) {
+	return getFromSource[Organization](c.source, organizationNamespace, nil)
+}
+
+// GetOrganizationByID returns a pointer to the Organization structure matching
+// the given ID, nil is returned if it cannot be found.
+func (c sourceClient) GetOrganizationByID(id int) (*Organization, error) {
+	return getByIDFromSource[Organization](c.source, organizationNamespace, id)
+}
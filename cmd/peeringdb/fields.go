@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// runFields prints the search parameters which can be used to filter the
+// objects of a namespace, one field per line.
+func runFields(api *peeringdb.API, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: peeringdb fields <namespace>")
+	}
+
+	fields, err := api.FilterableFields(args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		fmt.Printf("%-30s %s\n", field.Name, strings.Join(field.Parameters()[1:], " "))
+	}
+	return nil
+}
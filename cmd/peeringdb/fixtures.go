@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// runFixtures samples networks from the API, or from a mirror, and writes
+// them with their related objects as anonymized JSON fixtures.
+func runFixtures(api *peeringdb.API, args []string) error {
+	flags := flag.NewFlagSet("fixtures", flag.ExitOnError)
+	asns := flags.String("asn", "", "comma separated list of AS numbers to sample")
+	networks := flags.Int("networks", 0, "number of networks to sample if no AS number is given")
+	skip := flags.Int("skip", 0, "number of networks to skip before sampling")
+	directory := flags.String("dir", "", "mirror directory to sample instead of the API")
+	output := flags.String("o", "", "output file, standard output if empty")
+	flags.Parse(args)
+
+	options := peeringdb.FixtureOptions{Networks: *networks, Skip: *skip}
+	if *asns != "" {
+		for _, value := range strings.Split(*asns, ",") {
+			asn, err := peeringdb.ParseASN(value)
+			if err != nil {
+				return err
+			}
+			options.ASNs = append(options.ASNs, asn)
+		}
+	}
+
+	var source peeringdb.Client = api
+	if *directory != "" {
+		mirror, err := peeringdb.NewMirror(api, *directory, peeringdb.MirrorOptions{})
+		if err != nil {
+			return err
+		}
+		source = mirror
+	}
+
+	fixtures, err := peeringdb.GenerateFixtures(source, options)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return fixtures.Write(os.Stdout)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err = fixtures.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// runImport compares the objects of a network with their definitions read
+// from a YAML or CSV file and prints the changes needed to make them match.
+// Changes are applied only if asked to.
+func runImport(api *peeringdb.API, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	networkID := flags.Int("net", 0, "ID of the network whose objects are imported")
+	namespace := flags.String("namespace", "", "namespace of the objects of a CSV file: netixlan, netfac or poc")
+	apply := flags.Bool("apply", false, "apply the changes instead of only printing them")
+	flags.Parse(args)
+
+	if *networkID == 0 || flags.NArg() != 1 {
+		return errors.New("usage: peeringdb import -net <id> [-namespace <namespace>] [-apply] <file>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var definitions peeringdb.ImportDefinitions
+	if strings.EqualFold(filepath.Ext(file.Name()), ".csv") {
+		if *namespace == "" {
+			return errors.New("the namespace of the objects of a CSV file is required")
+		}
+		definitions, err = peeringdb.ReadImportCSV(file, *namespace)
+	} else {
+		definitions, err = peeringdb.ReadImportYAML(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	changes, err := api.PlanImport(*networkID, definitions)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) == 0 || !*apply {
+		return nil
+	}
+
+	return api.ApplyImport(context.Background(), changes)
+}
@@ -0,0 +1,91 @@
+// Command peeringdb is a command line tool built on top of the peeringdb
+// package. It gives access to the helpers of the package without having to
+// write Go code.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// command is a subcommand of the tool, it receives the arguments following
+// its name.
+type command struct {
+	description string
+	run         func(api *peeringdb.API, args []string) error
+}
+
+var commands = map[string]command{
+	"fields":    {"list the fields objects of a namespace can be filtered on", runFields},
+	"fixtures":  {"generate anonymized fixtures from sampled networks", runFixtures},
+	"import":    {"import objects of a network from a YAML or CSV file", runImport},
+	"quota":     {"estimate the API calls of an operation", runQuota},
+	"reconcile": {"converge an organization to its desired state", runReconcile},
+	"report":    {"run a named report", runReport},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: peeringdb [flags] <command> [arguments]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	url := flag.String("url", "", "PeeringDB API URL, $PEERINGDB_URL or the public API if empty")
+	apiKey := flag.String("api-key", "", "API key used to authenticate, $PEERINGDB_API_KEY if empty")
+	configPath := flag.String("config", "", "peeringdb-py configuration file, ~/.peeringdb/config.yaml if it exists when empty")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	command, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	// Flags take precedence over the environment, which takes precedence
+	// over the configuration file
+	config := &peeringdb.Config{}
+	loaded, err := peeringdb.LoadConfig(*configPath)
+	switch {
+	case err == nil:
+		config = loaded
+	case *configPath != "" || !errors.Is(err, fs.ErrNotExist):
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	config.LoadEnv()
+	if *url != "" {
+		config.URL = *url
+	}
+	if *apiKey != "" {
+		config.APIKey = *apiKey
+	}
+	api := config.NewAPI()
+	if err := command.run(api, flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
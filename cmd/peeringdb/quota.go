@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// estimators are the operations whose number of API calls can be estimated,
+// each one parsing its own flags.
+var estimators = map[string]struct {
+	description string
+	estimate    func(args []string) (peeringdb.CallEstimate, error)
+}{
+	"sync":             {"initial load of a mirror", estimateSync},
+	"asns":             {"lookup of AS numbers in bulk", estimateASNs},
+	"contacts":         {"fetch of the contacts of networks", estimateContacts},
+	"facility-tenants": {"networks and IXs present in a facility", estimateFacilityTenants},
+}
+
+// runQuota prints the estimated number of API calls of an operation, or lists
+// the operations if the operation name is "list". No API call is made.
+func runQuota(_ *peeringdb.API, args []string) error {
+	if len(args) < 1 || args[0] == "list" {
+		names := make([]string, 0, len(estimators))
+		for name := range estimators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-20s %s\n", name, estimators[name].description)
+		}
+		return nil
+	}
+
+	estimator, ok := estimators[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown operation '%s'", args[0])
+	}
+
+	estimate, err := estimator.estimate(args[1:])
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(estimate)
+	return nil
+}
+
+func estimateSync(args []string) (peeringdb.CallEstimate, error) {
+	flags := flag.NewFlagSet("quota sync", flag.ExitOnError)
+	directory := flags.String("dir", "", "mirror directory, to account for a partial load")
+	pageSize := flags.Int("page-size", 0, "number of objects per API call")
+	namespaces := flags.String("namespaces", "", "comma separated list of namespaces, all if empty")
+	flags.Parse(args)
+
+	options := peeringdb.MirrorOptions{PageSize: *pageSize}
+	if *namespaces != "" {
+		options.Namespaces = strings.Split(*namespaces, ",")
+	}
+
+	if *directory == "" {
+		return peeringdb.EstimateBootstrap(options, nil), nil
+	}
+
+	mirror, err := peeringdb.NewMirror(nil, *directory, options)
+	if err != nil {
+		return peeringdb.CallEstimate{}, err
+	}
+	return mirror.EstimateBootstrap(nil), nil
+}
+
+func estimateASNs(args []string) (peeringdb.CallEstimate, error) {
+	flags := flag.NewFlagSet("quota asns", flag.ExitOnError)
+	count := flags.Int("count", 0, "number of AS numbers")
+	flags.Parse(args)
+
+	return peeringdb.EstimateASNs(*count), nil
+}
+
+func estimateContacts(args []string) (peeringdb.CallEstimate, error) {
+	flags := flag.NewFlagSet("quota contacts", flag.ExitOnError)
+	count := flags.Int("networks", 0, "number of networks")
+	flags.Parse(args)
+
+	return peeringdb.EstimateNetworkContacts(*count), nil
+}
+
+func estimateFacilityTenants(args []string) (peeringdb.CallEstimate, error) {
+	flags := flag.NewFlagSet("quota facility-tenants", flag.ExitOnError)
+	networks := flags.Int("networks", 0, "number of networks in the facility")
+	ixs := flags.Int("ixs", 0, "number of IXs in the facility")
+	flags.Parse(args)
+
+	return peeringdb.EstimateFacilityTenants(*networks, *ixs), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// runReconcile compares an organization with its desired state read from a
+// YAML file and prints the drift. Changes are applied only if asked to.
+func runReconcile(api *peeringdb.API, args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	apply := flags.Bool("apply", false, "apply the changes instead of only printing them")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: peeringdb reconcile [-apply] <file>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	state, err := peeringdb.ReadOrganizationState(file)
+	if err != nil {
+		return err
+	}
+
+	plan, err := api.PlanReconcile(state)
+	if err != nil {
+		return err
+	}
+	fmt.Println(plan)
+	if plan.InSync() || !*apply {
+		return nil
+	}
+
+	return api.ApplyReconcile(context.Background(), plan)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// runReport runs a registered report, or lists them if the report name is
+// "list". Each report parameter is available as a flag.
+func runReport(api *peeringdb.API, args []string) error {
+	if len(args) < 1 || args[0] == "list" {
+		for _, report := range peeringdb.Reports() {
+			fmt.Printf("%-20s %s\n", report.Name, report.Description)
+		}
+		return nil
+	}
+
+	report, ok := peeringdb.LookupReport(args[0])
+	if !ok {
+		return fmt.Errorf("%w: %s", peeringdb.ErrUnknownReport, args[0])
+	}
+
+	flags := flag.NewFlagSet("report "+report.Name, flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text, csv or json")
+	templateFile := flags.String("template", "", "text/template file used to output the report")
+	values := make(map[string]*string, len(report.Parameters))
+	for _, parameter := range report.Parameters {
+		values[parameter] = flags.String(parameter, "", "report parameter")
+	}
+	flags.Parse(args[1:])
+
+	parameters := make(peeringdb.ReportParameters, len(values))
+	for name, value := range values {
+		parameters[name] = *value
+	}
+
+	table, err := api.RunReport(report.Name, parameters)
+	if err != nil {
+		return err
+	}
+
+	if *templateFile != "" {
+		text, err := os.ReadFile(*templateFile)
+		if err != nil {
+			return err
+		}
+		return table.WriteTemplate(os.Stdout, string(text))
+	}
+
+	return table.Write(os.Stdout, peeringdb.ReportFormat(*format))
+}
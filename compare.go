@@ -0,0 +1,145 @@
+package peeringdb
+
+import "sort"
+
+// InternetExchangeSummary is a structure giving key figures about an Internet
+// exchange point. It is used to compare several Internet exchange points.
+type InternetExchangeSummary struct {
+	InternetExchange InternetExchange
+	// MemberCount is the number of distinct networks connected to the IX.
+	MemberCount int
+	// ConnectionCount is the number of connections to the IX, a network can
+	// have more than one.
+	ConnectionCount int
+	// TotalCapacity is the sum of the speeds of all connections in Mbit/s.
+	TotalCapacity int
+	// RouteServerASNs are the AS numbers of the route servers of the IX LANs.
+	RouteServerASNs []ASN
+	// RouteServerPeerCount is the number of connections peering with the
+	// route servers.
+	RouteServerPeerCount int
+	// IPv6 tells if the IX supports IPv6.
+	IPv6 bool
+	// IPv6ConnectionCount is the number of connections with an IPv6 address.
+	IPv6ConnectionCount int
+	ServiceLevel        string
+	Terms               string
+	FacilityIDs         []int
+}
+
+// HasRouteServer tells if at least one route server is available on the IX.
+func (s InternetExchangeSummary) HasRouteServer() bool {
+	return len(s.RouteServerASNs) > 0 || s.RouteServerPeerCount > 0
+}
+
+// InternetExchangeComparison is a structure comparing several Internet
+// exchange points side by side.
+type InternetExchangeComparison struct {
+	// InternetExchanges are the summaries in the order of the requested IDs.
+	InternetExchanges []InternetExchangeSummary
+	// SharedFacilities are the facilities where all the compared IXs are
+	// present.
+	SharedFacilities []Facility
+}
+
+// CompareIXes returns a pointer to an InternetExchangeComparison structure
+// for the Internet exchange points matching the given IDs. IDs without a
+// matching IX are ignored. All objects are fetched in bulk.
+func (api *API) CompareIXes(ids ...int) (*InternetExchangeComparison, error) {
+	ixs, err := getChunked(ids, "id", api.GetInternetExchange)
+	if err != nil {
+		return nil, err
+	}
+	ixLANs, err := getChunked(ids, "ix_id", api.GetInternetExchangeLAN)
+	if err != nil {
+		return nil, err
+	}
+	networkIXLANs, err := getChunked(ids, "ix_id", api.GetNetworkInternetExchangeLAN)
+	if err != nil {
+		return nil, err
+	}
+	ixFacilities, err := getChunked(ids, "ix_id", api.GetInternetExchangeFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[int]*InternetExchangeSummary, len(ixs))
+	for _, ix := range ixs {
+		summaries[ix.ID] = &InternetExchangeSummary{
+			InternetExchange: ix,
+			IPv6:             ix.ProtoIPv6,
+			ServiceLevel:     ix.ServiceLevel,
+			Terms:            ix.Terms,
+		}
+	}
+
+	for _, ixLAN := range ixLANs {
+		summary, ok := summaries[ixLAN.InternetExchangeID]
+		if ok && ixLAN.RouteServerASN != 0 {
+			summary.RouteServerASNs = append(summary.RouteServerASNs, ixLAN.RouteServerASN)
+		}
+	}
+
+	members := make(map[int]map[int]bool, len(ixs))
+	for _, networkIXLAN := range networkIXLANs {
+		summary, ok := summaries[networkIXLAN.InternetExchangeID]
+		if !ok {
+			continue
+		}
+
+		if members[summary.InternetExchange.ID] == nil {
+			members[summary.InternetExchange.ID] = make(map[int]bool)
+		}
+		members[summary.InternetExchange.ID][networkIXLAN.NetworkID] = true
+
+		summary.ConnectionCount++
+		summary.TotalCapacity += networkIXLAN.Speed
+		if networkIXLAN.IsRSPeer {
+			summary.RouteServerPeerCount++
+		}
+		if networkIXLAN.IPAddr6 != "" {
+			summary.IPv6ConnectionCount++
+		}
+	}
+
+	// Count in how many of the compared IXs each facility is
+	facilityIXs := make(map[int]int)
+	for _, ixFacility := range ixFacilities {
+		summary, ok := summaries[ixFacility.InternetExchangeID]
+		if !ok {
+			continue
+		}
+		summary.FacilityIDs = append(summary.FacilityIDs, ixFacility.FacilityID)
+		facilityIXs[ixFacility.FacilityID]++
+	}
+
+	var sharedIDs []int
+	for id, count := range facilityIXs {
+		if count == len(summaries) {
+			sharedIDs = append(sharedIDs, id)
+		}
+	}
+	sharedFacilities, err := getChunked(sharedIDs, "id", api.GetFacility)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sharedFacilities, func(i, j int) bool {
+		return sharedFacilities[i].Name < sharedFacilities[j].Name
+	})
+
+	comparison := &InternetExchangeComparison{SharedFacilities: sharedFacilities}
+	for _, id := range ids {
+		summary, ok := summaries[id]
+		if !ok {
+			continue
+		}
+		// Avoid listing the same IX twice if its ID was given twice
+		delete(summaries, id)
+
+		summary.MemberCount = len(members[id])
+		sort.Ints(summary.FacilityIDs)
+		comparison.InternetExchanges = append(comparison.InternetExchanges, *summary)
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,57 @@
+package peeringdb
+
+import "testing"
+
+func TestCompareIXes(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX One", "proto_ipv6": true},
+			{"id": 2, "name": "IX Two", "proto_ipv6": false},
+		},
+		internetExchangeLANNamespace: {
+			{"id": 10, "ix_id": 1, "rs_asn": 64999},
+			{"id": 20, "ix_id": 2},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 100, "ix_id": 1, "net_id": 1, "speed": 10000, "is_rs_peer": true, "ipaddr6": "2001:db8::1"},
+			{"id": 101, "ix_id": 1, "net_id": 1, "speed": 10000},
+			{"id": 102, "ix_id": 1, "net_id": 2, "speed": 1000},
+			{"id": 200, "ix_id": 2, "net_id": 1, "speed": 100000},
+		},
+		internetExchangeFacilityNamespace: {
+			{"id": 1000, "ix_id": 1, "fac_id": 5},
+			{"id": 1001, "ix_id": 1, "fac_id": 6},
+			{"id": 2000, "ix_id": 2, "fac_id": 6},
+		},
+		facilityNamespace: {
+			{"id": 5, "name": "Facility Five"},
+			{"id": 6, "name": "Facility Six"},
+		},
+	})
+
+	comparison, err := server.api().CompareIXes(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(comparison.InternetExchanges) != 2 {
+		t.Fatalf("CompareIXes, want 2 summaries got %d", len(comparison.InternetExchanges))
+	}
+
+	two, one := comparison.InternetExchanges[0], comparison.InternetExchanges[1]
+	if two.InternetExchange.ID != 2 || one.InternetExchange.ID != 1 {
+		t.Errorf("CompareIXes, want IDs [2 1] got [%d %d]", two.InternetExchange.ID, one.InternetExchange.ID)
+	}
+	if one.MemberCount != 2 || one.ConnectionCount != 3 || one.TotalCapacity != 21000 {
+		t.Errorf("CompareIXes, unexpected IX One figures %+v", one)
+	}
+	if !one.HasRouteServer() || two.HasRouteServer() {
+		t.Errorf("CompareIXes, want route server only for IX One")
+	}
+	if one.IPv6ConnectionCount != 1 {
+		t.Errorf("CompareIXes, want 1 IPv6 connection got %d", one.IPv6ConnectionCount)
+	}
+	if len(comparison.SharedFacilities) != 1 || comparison.SharedFacilities[0].ID != 6 {
+		t.Errorf("CompareIXes, want shared facility 6 got %v", comparison.SharedFacilities)
+	}
+}
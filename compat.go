@@ -0,0 +1,122 @@
+package peeringdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrNamespaceNotSupported is the error that will be returned if the server
+// does not know about a namespace, for instance because it runs an older
+// version of PeeringDB without campuses or carriers.
+var ErrNamespaceNotSupported = errors.New("namespace not supported")
+
+// compatibility keeps track of the namespaces supported by the server when
+// the compatibility mode is enabled.
+type compatibility struct {
+	mutex     sync.Mutex
+	supported map[string]bool
+}
+
+// WithCompatibilityMode returns an option enabling the compatibility mode,
+// allowing to use servers which do not support every namespace, such as older
+// self-hosted django-peeringdb instances. In this mode, querying a namespace
+// the server does not know about returns no objects instead of an error, and
+// the server is not queried again for this namespace.
+func WithCompatibilityMode() Option {
+	return func(api *API) {
+		api.compatibility = &compatibility{supported: make(map[string]bool)}
+	}
+}
+
+// Supports tells if the server supports the given namespace. The server is
+// queried for a single object of the namespace, the result being remembered
+// in compatibility mode.
+func (api *API) Supports(namespace string) (bool, error) {
+	return api.SupportsWithContext(context.Background(), namespace)
+}
+
+// SupportsWithContext is the same as Supports but uses the given context for
+// the API call, allowing to cancel it or to set a deadline.
+func (api *API) SupportsWithContext(ctx context.Context, namespace string) (bool, error) {
+	if supported, known := api.compatibility.lookup(namespace); known {
+		return supported, nil
+	}
+
+	search := make(map[string]interface{})
+	search["limit"] = 1
+	search["depth"] = 0
+
+	response, err := api.lookupWithContext(ctx, namespace, search)
+	if errors.Is(err, ErrNamespaceNotSupported) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	response.Body.Close()
+
+	// The compatibility mode answers for unsupported namespaces
+	if supported, known := api.compatibility.lookup(namespace); known {
+		return supported, nil
+	}
+	api.compatibility.set(namespace, true)
+
+	return true, nil
+}
+
+// SupportedNamespaces returns the namespaces known by this package which are
+// supported by the server.
+func (api *API) SupportedNamespaces() ([]string, error) {
+	var supported []string
+	for _, namespace := range namespaces {
+		ok, err := api.Supports(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			supported = append(supported, namespace)
+		}
+	}
+
+	return supported, nil
+}
+
+// lookup tells if a namespace is known to be supported. The second value is
+// false if it is not known yet or if the compatibility mode is disabled.
+func (c *compatibility) lookup(namespace string) (bool, bool) {
+	if c == nil {
+		return false, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	supported, known := c.supported[namespace]
+	return supported, known
+}
+
+// set remembers if a namespace is supported.
+func (c *compatibility) set(namespace string, supported bool) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	c.supported[namespace] = supported
+	c.mutex.Unlock()
+}
+
+// emptyResponse returns a response holding no objects, used for namespaces
+// not supported by the server.
+func emptyResponse() *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"meta": {}, "data": []}`))),
+	}
+}
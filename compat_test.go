@@ -0,0 +1,43 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompatibilityMode(t *testing.T) {
+	// A server without campuses nor carriers
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {{"id": 1, "asn": 64500}},
+	})
+
+	if _, err := server.api().GetAllCampuses(); !errors.Is(err, ErrNamespaceNotSupported) {
+		t.Errorf("GetAllCampuses, want ErrNamespaceNotSupported got %v", err)
+	}
+
+	api := server.api(WithCompatibilityMode())
+	for i := 0; i < 2; i++ {
+		campuses, err := api.GetAllCampuses()
+		if err != nil || len(*campuses) != 0 {
+			t.Errorf("GetAllCampuses, want no campus got %v", err)
+		}
+	}
+	if count := server.count(campusNamespace); count != 2 {
+		t.Errorf("GetAllCampuses, want 2 calls got %d", count)
+	}
+
+	if supported, err := api.Supports(networkNamespace); err != nil || !supported {
+		t.Errorf("Supports(%s), want true got %v", networkNamespace, err)
+	}
+	if supported, err := api.Supports(carrierNamespace); err != nil || supported {
+		t.Errorf("Supports(%s), want false got %v", carrierNamespace, err)
+	}
+
+	supported, err := api.SupportedNamespaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(supported) != 1 || supported[0] != networkNamespace {
+		t.Errorf("SupportedNamespaces, want [%s] got %v", networkNamespace, supported)
+	}
+}
@@ -0,0 +1,125 @@
+package peeringdb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a structure holding the API settings of a configuration file
+// written in the format of peeringdb-py, the official Python client. Only its
+// "sync" section is used:
+//
+//	sync:
+//	  url: https://www.peeringdb.com/api
+//	  api_key: ''
+//	  user: ''
+//	  password: ''
+//	  timeout: 0
+type Config struct {
+	// URL is the URL of the API, the public API is used if it is empty.
+	URL string
+	// APIKey is the API key used for authentication.
+	APIKey string
+	// Username and Password are used for basic authentication if no API
+	// key is set.
+	Username string
+	Password string
+	// Timeout is the maximum duration of each API call, 0 meaning no
+	// timeout.
+	Timeout time.Duration
+}
+
+// configFile is the part of a peeringdb-py configuration file used by Config.
+type configFile struct {
+	Sync struct {
+		URL      string `yaml:"url"`
+		APIKey   string `yaml:"api_key"`
+		User     string `yaml:"user"`
+		Password string `yaml:"password"`
+		// Timeout is in seconds
+		Timeout float64 `yaml:"timeout"`
+	} `yaml:"sync"`
+}
+
+// DefaultConfigPath returns the path of the configuration file used by
+// peeringdb-py by default, ~/.peeringdb/config.yaml.
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".peeringdb", "config.yaml"), nil
+}
+
+// ReadConfig reads a configuration written in the format of peeringdb-py.
+func ReadConfig(r io.Reader) (*Config, error) {
+	file := configFile{}
+	if err := yaml.NewDecoder(r).Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &Config{
+		URL:      withTrailingSlash(file.Sync.URL),
+		APIKey:   file.Sync.APIKey,
+		Username: file.Sync.User,
+		Password: file.Sync.Password,
+		Timeout:  time.Duration(file.Sync.Timeout * float64(time.Second)),
+	}, nil
+}
+
+// LoadConfig reads the configuration file at the given path, or at the
+// default path of peeringdb-py if it is empty.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	config, err := ReadConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// NewAPI returns a pointer to a new API structure configured with the
+// settings of the configuration. The given options are applied after them.
+func (c *Config) NewAPI(options ...Option) *API {
+	var settings []Option
+	if c.Username != "" {
+		settings = append(settings, WithBasicAuth(c.Username, c.Password))
+	}
+	if c.Timeout > 0 {
+		settings = append(settings, WithTimeout(c.Timeout))
+	}
+
+	return NewAPIFromURLWithAPIKey(c.URL, c.APIKey, append(settings, options...)...)
+}
+
+// NewAPIFromConfig returns a pointer to a new API structure configured with
+// the configuration file at the given path, or at the default path of
+// peeringdb-py, ~/.peeringdb/config.yaml, if it is empty. The given options
+// are applied after the settings of the file.
+func NewAPIFromConfig(path string, options ...Option) (*API, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.NewAPI(options...), nil
+}
@@ -0,0 +1,70 @@
+package peeringdb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadConfig(t *testing.T) {
+	config, err := ReadConfig(strings.NewReader(`
+orm:
+  backend: django_peeringdb
+  database:
+    engine: sqlite3
+    name: peeringdb.sqlite3
+sync:
+  url: https://peeringdb.example/api
+  user: user
+  password: password
+  api_key: ''
+  only: []
+  strip_tz: 1
+  timeout: 1.5
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Config{
+		URL:      "https://peeringdb.example/api/",
+		Username: "user",
+		Password: "password",
+		Timeout:  1500 * time.Millisecond,
+	}
+	if *config != expected {
+		t.Errorf("ReadConfig, want %+v got %+v", expected, *config)
+	}
+
+	api := config.NewAPI()
+	if api.url != expected.URL || api.username != "user" || api.timeout != expected.Timeout {
+		t.Errorf("NewAPI, unexpected API %+v", api)
+	}
+
+	if _, err = ReadConfig(strings.NewReader("sync: [")); err == nil {
+		t.Error("ReadConfig, want error for invalid YAML")
+	}
+}
+
+func TestNewAPIFromConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".peeringdb"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".peeringdb", "config.yaml"), []byte("sync:\n  api_key: key\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	api, err := NewAPIFromConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.url != baseAPI || api.apiKey != "key" {
+		t.Errorf("NewAPIFromConfig, unexpected API %+v", api)
+	}
+	if _, err = NewAPIFromConfig(filepath.Join(home, "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("NewAPIFromConfig, want a not exist error got %v", err)
+	}
+}
@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// networkContactResource is the top-level structure when parsing the JSON
-// output from the API. This structure is not used if the NetworkContact JSON
-// object is included as a field in another JSON object. This structure is used
-// only if the proper namespace is queried.
-type networkContactResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []NetworkContact `json:"data"`
-}
-
 // NetworkContact represents a contact for a network.
 type NetworkContact struct {
 	ID        int       `json:"id"`
@@ -32,52 +21,34 @@ type NetworkContact struct {
 	Status    string    `json:"status"`
 }
 
-// getNetworkContactResource returns a pointer to an networkContactResource
-// structure corresponding to the API JSON response. An error can be returned
-// if something went wrong.
-func (api *API) getNetworkContactResource(search map[string]interface{}) (*networkContactResource, error) {
-	// Get the NetworkContactResource from the API
-	response, err := api.lookup(networkContactNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &networkContactResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetNetworkContact returns a pointer to a slice of NetworkContact structures
 // that the PeeringDB API can provide matching the given search parameters map.
-// If an error occurs, the returned error will be non-nil. The returned value
-// can be nil if no object could be found.
+// If an error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetNetworkContact(search map[string]interface{}) (*[]NetworkContact, error) {
-	// Ask for the all NetworkContact objects
-	networkContactResource, err := api.getNetworkContactResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetNetworkContactWithContext(context.Background(), search)
+}
 
-	// Return all NetworkContact objects, will be nil if slice is empty
-	return &networkContactResource.Data, nil
+// GetNetworkContactWithContext is the same as GetNetworkContact but uses the
+// given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetNetworkContactWithContext(ctx context.Context, search map[string]interface{}) (*[]NetworkContact, error) {
+	return getObjects[NetworkContact](ctx, api, search)
 }
 
 // GetAllNetworkContacts returns a pointer to a slice of NetworkContact
 // structures that the PeeringDB API can provide. If an error occurs, the
-// returned error will be non-nil. The can be nil if no object could be found.
+// returned error will be non-nil. The returned slice is empty, but never nil,
+// if no object could be found.
 func (api *API) GetAllNetworkContacts() (*[]NetworkContact, error) {
-	// Return all NetworkContact objects
-	return api.GetNetworkContact(nil)
+	return api.GetAllNetworkContactsWithContext(context.Background())
+}
+
+// GetAllNetworkContactsWithContext is the same as GetAllNetworkContacts but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetAllNetworkContactsWithContext(ctx context.Context) (*[]NetworkContact, error) {
+	return getAllObjects[NetworkContact](ctx, api)
 }
 
 // GetNetworkContactByID returns a pointer to a NetworkContact structure that
@@ -87,29 +58,12 @@ func (api *API) GetAllNetworkContacts() (*[]NetworkContact, error) {
 // given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetNetworkContactByID(id int) (*NetworkContact, error) {
-	// No point of looking for the network contact with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the NetworkContact given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	networkContacts, err := api.GetNetworkContact(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No NetworkContact matching the ID
-	if len(*networkContacts) < 1 {
-		return nil, nil
-	}
+	return api.GetNetworkContactByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*networkContacts)[0], nil
+// GetNetworkContactByIDWithContext is the same as GetNetworkContactByID but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetNetworkContactByIDWithContext(ctx context.Context, id int) (*NetworkContact, error) {
+	return getObjectByID[NetworkContact](ctx, api, id)
 }
@@ -0,0 +1,186 @@
+package peeringdb
+
+import "sort"
+
+// CrossConnectSite is a structure representing a site where two networks can
+// interconnect with a cross-connect, without the need of a carrier. A site is
+// either a single facility where both networks are present, or a campus in
+// which each network is present in at least one facility.
+type CrossConnectSite struct {
+	// Campus is set if the site is a campus, it is nil for a facility.
+	Campus *Campus
+	// Facilities are the facilities of the site where at least one of the
+	// networks is present.
+	Facilities []Facility
+	// FacilityIDsA and FacilityIDsB are the IDs of the facilities where each
+	// network is present.
+	FacilityIDsA []int
+	FacilityIDsB []int
+	// PortsA and PortsB are the numbers of IX ports each network has on the
+	// IXs available in the facilities of the site.
+	PortsA int
+	PortsB int
+}
+
+// GetCrossConnectSites returns the sites where the networks identified by the
+// given AS numbers can interconnect with a cross-connect. Shared facilities
+// are listed first, then shared campuses. Within each kind, sites are ranked
+// by the ports both networks have there, the best candidates coming first.
+func (api *API) GetCrossConnectSites(asnA, asnB ASN) ([]CrossConnectSite, error) {
+	facilitiesA, err := api.getFacilityIDsByASN(asnA)
+	if err != nil {
+		return nil, err
+	}
+	facilitiesB, err := api.getFacilityIDsByASN(asnB)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for id := range facilitiesA {
+		ids = append(ids, id)
+	}
+	for id := range facilitiesB {
+		ids = append(ids, id)
+	}
+	facilities, err := getChunked(ids, "id", api.GetFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build shared facilities and group the others by campus
+	var sites []*CrossConnectSite
+	campuses := make(map[int]*CrossConnectSite)
+	for _, facility := range facilities {
+		a, b := facilitiesA[facility.ID], facilitiesB[facility.ID]
+
+		if a && b {
+			sites = append(sites, &CrossConnectSite{
+				Facilities:   []Facility{facility},
+				FacilityIDsA: []int{facility.ID},
+				FacilityIDsB: []int{facility.ID},
+			})
+		}
+		if facility.CampusID == 0 {
+			continue
+		}
+
+		site, ok := campuses[facility.CampusID]
+		if !ok {
+			site = &CrossConnectSite{Campus: &Campus{ID: facility.CampusID}}
+			campuses[facility.CampusID] = site
+		}
+		site.Facilities = append(site.Facilities, facility)
+		if a {
+			site.FacilityIDsA = append(site.FacilityIDsA, facility.ID)
+		}
+		if b {
+			site.FacilityIDsB = append(site.FacilityIDsB, facility.ID)
+		}
+	}
+
+	// Only keep campuses where networks are in different facilities, being in
+	// the same one is already covered by the shared facility
+	var campusIDs []int
+	for id, site := range campuses {
+		if len(site.FacilityIDsA) == 0 || len(site.FacilityIDsB) == 0 || len(site.Facilities) < 2 {
+			continue
+		}
+		campusIDs = append(campusIDs, id)
+		sites = append(sites, site)
+	}
+	campusDetails, err := getChunked(campusIDs, "id", api.GetCampus)
+	if err != nil {
+		return nil, err
+	}
+	for _, campus := range campusDetails {
+		*campuses[campus.ID].Campus = campus
+	}
+
+	if err = api.countCrossConnectPorts(sites, asnA, asnB); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sites, func(i, j int) bool {
+		if (sites[i].Campus == nil) != (sites[j].Campus == nil) {
+			return sites[i].Campus == nil
+		}
+		if lowI, lowJ := min(sites[i].PortsA, sites[i].PortsB), min(sites[j].PortsA, sites[j].PortsB); lowI != lowJ {
+			return lowI > lowJ
+		}
+		if totalI, totalJ := sites[i].PortsA+sites[i].PortsB, sites[j].PortsA+sites[j].PortsB; totalI != totalJ {
+			return totalI > totalJ
+		}
+		return sites[i].Facilities[0].ID < sites[j].Facilities[0].ID
+	})
+
+	result := make([]CrossConnectSite, len(sites))
+	for i, site := range sites {
+		result[i] = *site
+	}
+
+	return result, nil
+}
+
+// getFacilityIDsByASN returns the set of facility IDs where the network
+// identified by the given AS number is present.
+func (api *API) getFacilityIDsByASN(asn ASN) (map[int]bool, error) {
+	search := make(map[string]interface{})
+	search["local_asn"] = asn
+
+	networkFacilities, err := api.GetNetworkFacility(search)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]bool, len(*networkFacilities))
+	for _, networkFacility := range *networkFacilities {
+		ids[networkFacility.FacilityID] = true
+	}
+
+	return ids, nil
+}
+
+// countCrossConnectPorts fills the port counts of the given sites, counting
+// the IX connections of each network on IXs available in the facilities of
+// each site.
+func (api *API) countCrossConnectPorts(sites []*CrossConnectSite, asnA, asnB ASN) error {
+	var facilityIDs []int
+	for _, site := range sites {
+		for _, facility := range site.Facilities {
+			facilityIDs = append(facilityIDs, facility.ID)
+		}
+	}
+	ixFacilities, err := getChunked(facilityIDs, "fac_id", api.GetInternetExchangeFacility)
+	if err != nil {
+		return err
+	}
+	facilityIXs := make(map[int][]int)
+	for _, ixFacility := range ixFacilities {
+		facilityIXs[ixFacility.FacilityID] = append(facilityIXs[ixFacility.FacilityID], ixFacility.InternetExchangeID)
+	}
+
+	ports, err := getChunked(asnInts([]ASN{asnA, asnB}), "asn", api.GetNetworkInternetExchangeLAN)
+	if err != nil {
+		return err
+	}
+	portsByIX := map[ASN]map[int]int{asnA: {}, asnB: {}}
+	for _, port := range ports {
+		portsByIX[port.ASN][port.InternetExchangeID]++
+	}
+
+	for _, site := range sites {
+		ixs := make(map[int]bool)
+		for _, facility := range site.Facilities {
+			for _, id := range facilityIXs[facility.ID] {
+				ixs[id] = true
+			}
+		}
+		for id := range ixs {
+			site.PortsA += portsByIX[asnA][id]
+			site.PortsB += portsByIX[asnB][id]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestGetCrossConnectSites(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkFacilityNamespace: {
+			{"id": 1, "net_id": 10, "local_asn": 64500, "fac_id": 1},
+			{"id": 2, "net_id": 10, "local_asn": 64500, "fac_id": 2},
+			{"id": 3, "net_id": 10, "local_asn": 64500, "fac_id": 3},
+			{"id": 4, "net_id": 10, "local_asn": 64500, "fac_id": 5},
+			{"id": 5, "net_id": 10, "local_asn": 64500, "fac_id": 7},
+			{"id": 6, "net_id": 10, "local_asn": 64500, "fac_id": 8},
+			{"id": 7, "net_id": 11, "local_asn": 64501, "fac_id": 1},
+			{"id": 8, "net_id": 11, "local_asn": 64501, "fac_id": 2},
+			{"id": 9, "net_id": 11, "local_asn": 64501, "fac_id": 4},
+			{"id": 10, "net_id": 11, "local_asn": 64501, "fac_id": 6},
+			{"id": 11, "net_id": 11, "local_asn": 64501, "fac_id": 7},
+			{"id": 12, "net_id": 11, "local_asn": 64501, "fac_id": 8},
+			// Another network, or the same one with another AS number,
+			// does not count
+			{"id": 13, "net_id": 12, "local_asn": 64502, "fac_id": 5},
+			{"id": 14, "net_id": 10, "local_asn": 64503, "fac_id": 6},
+		},
+		facilityNamespace: {
+			{"id": 1, "name": "Facility 1"},
+			{"id": 2, "name": "Facility 2"},
+			{"id": 3, "name": "Facility 3", "campus_id": 1},
+			{"id": 4, "name": "Facility 4", "campus_id": 1},
+			{"id": 5, "name": "Facility 5"},
+			{"id": 6, "name": "Facility 6"},
+			{"id": 7, "name": "Facility 7"},
+			{"id": 8, "name": "Facility 8"},
+		},
+		campusNamespace: {
+			{"id": 1, "name": "Campus A"},
+		},
+		internetExchangeFacilityNamespace: {
+			{"id": 1, "ix_id": 1, "fac_id": 1},
+			{"id": 2, "ix_id": 2, "fac_id": 2},
+			{"id": 3, "ix_id": 1, "fac_id": 7},
+			{"id": 4, "ix_id": 3, "fac_id": 8},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "asn": 64500, "ix_id": 1},
+			{"id": 2, "asn": 64501, "ix_id": 1},
+			{"id": 3, "asn": 64500, "ix_id": 2},
+			{"id": 4, "asn": 64500, "ix_id": 2},
+			{"id": 5, "asn": 64501, "ix_id": 2},
+			{"id": 6, "asn": 64501, "ix_id": 2},
+			{"id": 7, "asn": 64500, "ix_id": 3},
+			{"id": 8, "asn": 64500, "ix_id": 3},
+			{"id": 9, "asn": 64500, "ix_id": 3},
+			{"id": 10, "asn": 64501, "ix_id": 3},
+		},
+	})
+	api := server.api()
+
+	sites, err := api.GetCrossConnectSites(64500, 64501)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Facilities 5 and 6 have only one of the networks. Shared facilities
+	// come first, ranked by the ports of the network having the fewest, then
+	// by the ports of both, ties being ordered by ID
+	want := []struct {
+		campus      bool
+		campusName  string
+		facilities  []int
+		portsA      int
+		portsB      int
+		facilitiesA int
+		facilitiesB int
+	}{
+		{facilities: []int{2}, portsA: 2, portsB: 2, facilitiesA: 1, facilitiesB: 1},
+		{facilities: []int{8}, portsA: 3, portsB: 1, facilitiesA: 1, facilitiesB: 1},
+		{facilities: []int{1}, portsA: 1, portsB: 1, facilitiesA: 1, facilitiesB: 1},
+		{facilities: []int{7}, portsA: 1, portsB: 1, facilitiesA: 1, facilitiesB: 1},
+		{campus: true, facilities: []int{3, 4}, facilitiesA: 1, facilitiesB: 1, campusName: "Campus A"},
+	}
+	if len(sites) != len(want) {
+		t.Fatalf("GetCrossConnectSites, want %d sites got %+v", len(want), sites)
+	}
+	for i, w := range want {
+		site := sites[i]
+		if (site.Campus != nil) != w.campus || (w.campus && site.Campus.Name != w.campusName) {
+			t.Errorf("GetCrossConnectSites, site %d: unexpected campus %+v", i, site.Campus)
+		}
+		if len(site.Facilities) != len(w.facilities) {
+			t.Errorf("GetCrossConnectSites, site %d: want facilities %v got %+v", i, w.facilities, site.Facilities)
+			continue
+		}
+		for j, id := range w.facilities {
+			if site.Facilities[j].ID != id {
+				t.Errorf("GetCrossConnectSites, site %d: want facilities %v got %+v", i, w.facilities, site.Facilities)
+			}
+		}
+		if site.PortsA != w.portsA || site.PortsB != w.portsB {
+			t.Errorf("GetCrossConnectSites, site %d: want %d and %d ports got %d and %d", i, w.portsA, w.portsB, site.PortsA, site.PortsB)
+		}
+		if len(site.FacilityIDsA) != w.facilitiesA || len(site.FacilityIDsB) != w.facilitiesB {
+			t.Errorf("GetCrossConnectSites, site %d: unexpected facilities per network %v and %v", i, site.FacilityIDsA, site.FacilityIDsB)
+		}
+	}
+}
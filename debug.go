@@ -0,0 +1,64 @@
+package peeringdb
+
+import (
+	"sync"
+)
+
+// WithBodyHook returns an option registering a function called after each API
+// call answered by the API with the raw body of the response, as received
+// before being decoded. It helps diagnosing mismatches between the objects
+// returned by the API, whose schema evolves, and the structures of this
+// package. The body must not be modified, and must be copied to be kept. Hooks
+// are called synchronously, after the response hooks, and may be called
+// concurrently if the API structure is shared between goroutines.
+func WithBodyHook(hook func(info ResponseInfo, body []byte)) Option {
+	return func(api *API) {
+		api.bodyHooks = append(api.bodyHooks, hook)
+	}
+}
+
+// notifyBody calls all body hooks with the given information and body.
+func (api *API) notifyBody(info ResponseInfo, body []byte) {
+	for _, hook := range api.bodyHooks {
+		hook(info, body)
+	}
+}
+
+// LastResponse keeps a copy of the raw body of the last response received by
+// the API structures it is registered with:
+//
+//	last := &peeringdb.LastResponse{}
+//	api := peeringdb.NewAPI(peeringdb.WithBodyHook(last.Record))
+//	if _, err := api.GetNetworkByID(20); err != nil {
+//		info, body := last.Get()
+//		log.Printf("%s returned %s", info.URL, body)
+//	}
+//
+// It is safe for concurrent use, but the last response of an API structure
+// shared between goroutines is the one of any of them.
+type LastResponse struct {
+	mutex sync.Mutex
+	info  ResponseInfo
+	body  []byte
+}
+
+// Record keeps a copy of the given body, it is meant to be given to
+// WithBodyHook.
+func (l *LastResponse) Record(info ResponseInfo, body []byte) {
+	copied := append([]byte(nil), body...)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.info = info
+	l.body = copied
+}
+
+// Get returns the information and the raw body of the last response. The body
+// is nil if no response was received yet.
+func (l *LastResponse) Get() (ResponseInfo, []byte) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.info, l.body
+}
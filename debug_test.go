@@ -0,0 +1,29 @@
+package peeringdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLastResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": ["64500"]}]}`))
+	}))
+	defer server.Close()
+
+	last := &LastResponse{}
+	if _, body := last.Get(); body != nil {
+		t.Errorf("Get, want no body got %s", body)
+	}
+	api := NewAPIFromURL(server.URL+"/api/", WithBodyHook(last.Record))
+
+	// The body of a response which cannot be decoded can be looked at
+	if _, err := api.GetNetworkByID(1); err == nil {
+		t.Fatal("GetNetworkByID, want a decoding error")
+	}
+	info, body := last.Get()
+	if info.Namespace != networkNamespace || info.StatusCode != http.StatusOK || string(body) != `{"meta": {}, "data": [{"id": 1, "asn": ["64500"]}]}` {
+		t.Errorf("Get, unexpected response %+v: %s", info, body)
+	}
+}
@@ -0,0 +1,81 @@
+package peeringdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// sharedResponse is a response fetched once for several identical lookups.
+type sharedResponse struct {
+	response *http.Response
+	body     []byte
+}
+
+// copy returns a copy of the response, with its own body, for one of the
+// lookups sharing it.
+func (s *sharedResponse) copy() *http.Response {
+	response := *s.response
+	response.Header = s.response.Header.Clone()
+	response.Body = io.NopCloser(bytes.NewReader(s.body))
+
+	return &response
+}
+
+// flightKey returns the key identifying identical lookups: the URL and the
+// scope of the caller, as the objects returned depend on it. Lookups
+// authenticated with the keys of a key pool share the same scope.
+func (api *API) flightKey(ctx context.Context, url string) string {
+	apiKey := api.apiKey
+	if auth, ok := ctx.Value(authKey{}).(contextAuth); ok {
+		apiKey = auth.apiKey
+	} else if api.keyPool != nil {
+		return url + "\x00pool"
+	}
+
+	return url + "\x00" + api.authScope(ctx, apiKey)
+}
+
+// getShared is the same as get, but concurrent identical lookups share a
+// single call to the API, such as when many goroutines expand the sets of
+// networks present at the same facility.
+func (api *API) getShared(ctx context.Context, namespace, url string) (*http.Response, error) {
+	if api.flights == nil {
+		return api.get(ctx, namespace, url)
+	}
+
+	flight := api.flights.DoChan(api.flightKey(ctx, url), func() (interface{}, error) {
+		response, err := api.get(ctx, namespace, url)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return &sharedResponse{response: response, body: body}, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, &APIError{Namespace: namespace, Method: http.MethodGet, URL: url, Err: fmt.Errorf("%w: %w", ErrQueryingAPI, ctx.Err())}
+	case result := <-flight:
+		// The call was made with the context of another lookup, which was
+		// canceled, this one has to make its own call
+		if result.Shared && ctx.Err() == nil &&
+			(errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded)) {
+			return api.get(ctx, namespace, url)
+		}
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		return result.Val.(*sharedResponse).copy(), nil
+	}
+}
@@ -0,0 +1,59 @@
+package peeringdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetShared(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+	api := NewAPIFromURL(server.URL + "/api/")
+
+	// Identical lookups share a single call, lookups made on behalf of
+	// another account do not
+	var wg sync.WaitGroup
+	networks := make([]*Network, 6)
+	for i := range networks {
+		ctx := context.Background()
+		if i == len(networks)-1 {
+			ctx = WithAuth(ctx, "other-key")
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			networks[i], _ = api.GetNetworkByIDWithContext(ctx, 1)
+		}(i)
+	}
+	for calls.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 2 {
+		t.Errorf("GetNetworkByIDWithContext, want 2 calls got %d", calls.Load())
+	}
+	for i, network := range networks {
+		if network == nil || network.ASN != 64500 {
+			t.Errorf("GetNetworkByIDWithContext, lookup %d got %+v", i, network)
+		}
+	}
+
+	// Once done, the same lookup makes a new call
+	if _, err := api.GetNetworkByID(1); err != nil || calls.Load() != 3 {
+		t.Errorf("GetNetworkByID, want a new call got %d calls (%v)", calls.Load(), err)
+	}
+}
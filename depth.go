@@ -0,0 +1,89 @@
+package peeringdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// IDSet is a list of object IDs as found in the "*_set" fields of objects.
+// With a depth of 1, the API gives these sets as lists of IDs, with a depth of
+// 2 it gives them as lists of objects. Both shapes are decoded, only keeping
+// the IDs of the objects.
+type IDSet []int
+
+// UnmarshalJSON decodes a set given either as a list of IDs or as a list of
+// objects having an "id" field.
+func (s *IDSet) UnmarshalJSON(data []byte) error {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	if elements == nil {
+		*s = nil
+		return nil
+	}
+
+	ids := make(IDSet, 0, len(elements))
+	for _, element := range elements {
+		element = bytes.TrimSpace(element)
+		if len(element) > 0 && element[0] == '{' {
+			var object struct {
+				ID *int `json:"id"`
+			}
+			if err := json.Unmarshal(element, &object); err != nil {
+				return err
+			}
+			if object.ID == nil {
+				return fmt.Errorf("set object without id: %s", element)
+			}
+			ids = append(ids, *object.ID)
+			continue
+		}
+
+		var id int
+		if err := json.Unmarshal(element, &id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	*s = ids
+
+	return nil
+}
+
+// WithDepth returns an option setting the depth used by queries not giving
+// their own "depth" search parameter. With a depth of 2, the organization,
+// network and Internet exchange objects an object refers to are decoded into
+// its embedded structures, and functions combining objects use them instead of
+// looking them up by ID. A value of 0 keeps the default depth of 1.
+func WithDepth(depth int) Option {
+	return func(api *API) {
+		api.depth = depth
+	}
+}
+
+// depthSearch returns the search parameters to use for a query given the
+// depth set for the API.
+func (api *API) depthSearch(search map[string]interface{}) map[string]interface{} {
+	if api.depth <= 0 {
+		return search
+	}
+	if _, ok := search["depth"]; ok {
+		return search
+	}
+
+	deep := make(map[string]interface{}, len(search)+1)
+	for key, value := range search {
+		deep[key] = value
+	}
+	deep["depth"] = api.depth
+
+	return deep
+}
+
+// embedsObjects tells if the objects returned by the API embed the objects
+// they refer to.
+func (api *API) embedsObjects() bool {
+	return api.depth >= 2
+}
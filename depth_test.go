@@ -0,0 +1,132 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// depthObject returns an object of the given type, as given by the API with a
+// depth of 1 or 2. Sets hold the IDs 1 and 2, and embedded objects, only given
+// with a depth of 2, have the ID 7.
+func depthObject(t reflect.Type, depth int) map[string]interface{} {
+	object := map[string]interface{}{"id": 10}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch {
+		case field.Type == reflect.TypeOf(IDSet{}) && depth < 2:
+			object[name] = []int{1, 2}
+		case field.Type == reflect.TypeOf(IDSet{}):
+			object[name] = []interface{}{
+				map[string]interface{}{"id": 1, "name": "First"},
+				map[string]interface{}{"id": 2, "name": "Second"},
+			}
+		case field.Type.Kind() == reflect.Struct && depth >= 2:
+			if _, ok := field.Type.FieldByName("ID"); ok {
+				object[name] = map[string]interface{}{"id": 7, "name": "Embedded"}
+			}
+		}
+	}
+
+	return object
+}
+
+func TestDecodeDepth(t *testing.T) {
+	for _, namespace := range namespaces {
+		for _, depth := range []int{1, 2} {
+			typ := namespaceTypes[namespace]
+			data, err := json.Marshal(depthObject(typ, depth))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			value := reflect.New(typ)
+			if err = json.Unmarshal(data, value.Interface()); err != nil {
+				t.Errorf("%s, depth %d: %v", namespace, depth, err)
+				continue
+			}
+
+			for i := 0; i < typ.NumField(); i++ {
+				field := typ.Field(i)
+				got := value.Elem().Field(i)
+
+				switch {
+				case field.Type == reflect.TypeOf(IDSet{}):
+					if set := got.Interface().(IDSet); !reflect.DeepEqual(set, IDSet{1, 2}) {
+						t.Errorf("%s, depth %d: want %s [1 2] got %v", namespace, depth, field.Name, set)
+					}
+				case field.Type.Kind() == reflect.Struct:
+					id := got.FieldByName("ID")
+					if !id.IsValid() {
+						continue
+					}
+					want := 0
+					if depth >= 2 {
+						want = 7
+					}
+					if int(id.Int()) != want {
+						t.Errorf("%s, depth %d: want %s ID %d got %d", namespace, depth, field.Name, want, id.Int())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestIDSetInvalid(t *testing.T) {
+	var set IDSet
+	if err := json.Unmarshal([]byte(`[{"name": "no id"}]`), &set); err == nil {
+		t.Error("IDSet, want error for object without ID")
+	}
+	if err := json.Unmarshal([]byte(`null`), &set); err != nil || set != nil {
+		t.Errorf("IDSet, want nil set got %v (%v)", set, err)
+	}
+}
+
+func TestFacilityTenantsDepth(t *testing.T) {
+	for _, depth := range []int{0, 2} {
+		server := newTestServer(t, map[string][]map[string]interface{}{
+			facilityNamespace: {{"id": 1, "name": "Facility"}},
+			networkFacilityNamespace: {
+				{"id": 1, "fac_id": 1, "net_id": 10, "local_asn": 64500, "net": map[string]interface{}{"id": 10, "asn": 64500, "name": "Network"}},
+			},
+			internetExchangeFacilityNamespace: {
+				{"id": 1, "fac_id": 1, "ix_id": 20, "ix": map[string]interface{}{"id": 20, "name": "IX"}},
+			},
+			networkNamespace:          {{"id": 10, "asn": 64500, "name": "Network"}},
+			internetExchangeNamespace: {{"id": 20, "name": "IX"}},
+		})
+
+		var urls []string
+		api := server.api(WithDepth(depth), WithResponseHook(func(info ResponseInfo) {
+			urls = append(urls, info.URL)
+		}))
+		tenants, err := api.GetFacilityTenants(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tenants.Networks) != 1 || tenants.Networks[0].ASN != 64500 || tenants.Networks[0].LocalASN != 64500 {
+			t.Errorf("GetFacilityTenants, depth %d: unexpected networks %+v", depth, tenants.Networks)
+		}
+		if len(tenants.InternetExchanges) != 1 || tenants.InternetExchanges[0].Name != "IX" {
+			t.Errorf("GetFacilityTenants, depth %d: unexpected IXs %+v", depth, tenants.InternetExchanges)
+		}
+
+		// Embedded objects are used instead of looking them up
+		lookups := server.count(networkNamespace) + server.count(internetExchangeNamespace)
+		if depth >= 2 && lookups != 0 {
+			t.Errorf("GetFacilityTenants, depth %d: want no lookup got %d", depth, lookups)
+		}
+		if depth < 2 && lookups != 2 {
+			t.Errorf("GetFacilityTenants, depth %d: want 2 lookups got %d", depth, lookups)
+		}
+		for _, url := range urls {
+			if depth >= 2 && !strings.Contains(url, "depth=2") {
+				t.Errorf("GetFacilityTenants, depth %d: unexpected URL %s", depth, url)
+			}
+		}
+	}
+}
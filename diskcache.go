@@ -0,0 +1,180 @@
+package peeringdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrCacheMiss is the error that will be returned by lookups in replay mode if
+// the disk cache has no entry for the query.
+var ErrCacheMiss = errors.New("no cached response for query")
+
+// DiskCacheMode tells how the disk cache is used by lookups.
+type DiskCacheMode int
+
+const (
+	// DiskCacheReadThrough serves lookups from the cache when possible, and
+	// stores the responses of the API otherwise.
+	DiskCacheReadThrough DiskCacheMode = iota
+	// DiskCacheRecord always queries the API and stores its responses,
+	// refreshing the existing entries.
+	DiskCacheRecord
+	// DiskCacheReplay only serves lookups from the cache, never querying
+	// the API. Lookups without cached entry fail with ErrCacheMiss.
+	DiskCacheReplay
+)
+
+// diskCache stores API responses in a directory, one file per query.
+type diskCache struct {
+	directory string
+	mode      DiskCacheMode
+}
+
+// diskCacheEntry is the content of a cache file. The URL is kept to make the
+// files readable, and the body is kept as is to review changes easily when
+// the files are committed.
+type diskCacheEntry struct {
+	URL     string          `json:"url"`
+	Fetched time.Time       `json:"fetched"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// WithDiskCache returns an option caching the responses of lookups in the
+// given directory, according to the given mode. Each entry is a JSON file
+// named after the hash of the canonical query URL, with its parameters sorted
+// and without its host, so that the directory can be committed and replayed
+// in tests, whatever the API endpoint they use. Credentials are not part of
+// the URL, responses seen with an API key are then served to any caller of
+// the cache. Only successful lookups are cached, writes are never cached.
+func WithDiskCache(directory string, mode DiskCacheMode) Option {
+	return func(api *API) {
+		api.diskCache = &diskCache{directory: directory, mode: mode}
+	}
+}
+
+// canonicalURL returns the URL identifying a query in the cache, made of its
+// path and its sorted parameters.
+func canonicalURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	canonical := u.EscapedPath()
+	if query := u.Query().Encode(); query != "" {
+		canonical += "?" + query
+	}
+
+	return canonical
+}
+
+// path returns the path of the cache file of the given query URL.
+func (c *diskCache) path(rawURL string) (string, string) {
+	canonical := canonicalURL(rawURL)
+	hash := sha256.Sum256([]byte(canonical))
+
+	return filepath.Join(c.directory, hex.EncodeToString(hash[:])+".json"), canonical
+}
+
+// used tells if lookups are served from the cache when possible.
+func (c *diskCache) used() bool {
+	return c != nil && c.mode != DiskCacheRecord
+}
+
+// get returns a response built from the cache entry of the given query URL.
+// The response is nil if the cache must not be used or has no entry.
+func (c *diskCache) get(rawURL string) (*http.Response, error) {
+	if c == nil || c.mode == DiskCacheRecord {
+		return nil, nil
+	}
+
+	path, canonical := c.path(rawURL)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if c.mode == DiskCacheReplay {
+			return nil, fmt.Errorf("%w: %s", ErrCacheMiss, canonical)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry diskCacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid cache entry %s: %w", path, err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(fromCacheHeader, "1")
+	if !entry.Fetched.IsZero() {
+		header.Set("Date", entry.Fetched.UTC().Format(http.TimeFormat))
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+	}, nil
+}
+
+// put stores the body of the response to the given query URL. The response
+// body is kept readable.
+func (c *diskCache) put(rawURL string, response *http.Response) error {
+	if c == nil || c.mode == DiskCacheReplay {
+		return nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if !json.Valid(body) {
+		return nil
+	}
+
+	path, canonical := c.path(rawURL)
+	data, err := json.MarshalIndent(diskCacheEntry{URL: canonical, Fetched: time.Now().UTC().Truncate(time.Second), Body: body}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.directory, 0o755); err != nil {
+		return err
+	}
+
+	// Write the entry atomically so that concurrent lookups never read a
+	// partial file
+	file, err := os.CreateTemp(c.directory, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if err = file.Chmod(0o644); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err = file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,66 @@
+package peeringdb
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestDiskCache(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A"},
+		},
+	})
+	directory := t.TempDir()
+
+	// Read-through, the second lookup is served from the cache
+	api := server.api(WithDiskCache(directory, DiskCacheReadThrough))
+	for i := 0; i < 2; i++ {
+		network, err := api.GetNetworkByID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if network == nil || network.ASN != 64500 {
+			t.Errorf("GetNetworkByID, unexpected network: %+v", network)
+		}
+	}
+	if count := server.count(networkNamespace); count != 1 {
+		t.Errorf("DiskCacheReadThrough, want 1 call got %d", count)
+	}
+
+	// Record, the API is always queried
+	server.objects[networkNamespace][0]["name"] = "Network A renamed"
+	api = server.api(WithDiskCache(directory, DiskCacheRecord))
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if count := server.count(networkNamespace); count != 2 {
+		t.Errorf("DiskCacheRecord, want 2 calls got %d", count)
+	}
+	if entries, _ := os.ReadDir(directory); len(entries) != 1 {
+		t.Errorf("DiskCacheRecord, want 1 entry got %d", len(entries))
+	}
+
+	// Replay against an unreachable endpoint, the host is not part of the
+	// cache key
+	api = NewAPIFromURL("http://127.0.0.1:1/api/", WithDiskCache(directory, DiskCacheReplay))
+	network, err := api.GetNetworkByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.Name != "Network A renamed" {
+		t.Errorf("DiskCacheReplay, unexpected network: %+v", network)
+	}
+	if _, err = api.GetNetworkByID(2); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("DiskCacheReplay, want ErrCacheMiss got %v", err)
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	a := canonicalURL("https://www.peeringdb.com/api/net?depth=1&name=A&asn=64500")
+	b := canonicalURL("http://localhost:8000/api/net?depth=1&asn=64500&name=A")
+	if a != b || a != "/api/net?asn=64500&depth=1&name=A" {
+		t.Errorf("canonicalURL, want same URLs got %s and %s", a, b)
+	}
+}
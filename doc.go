@@ -13,18 +13,83 @@ the top level of the JSON returned by the API. These structures are named
 *Resource. They all have a Meta field containing metadata returned by the API,
 and a Data field which is an array of the second level structures.
 
-All calls to the PeeringDB API use the "depth=1" parameter. This means that
-sets are expanded as integer slices instead of slices of structures, which
-speeds up the API processing time. To get the structures for a given set, you
-just need to iterate over the set and call the appropriate function to retrieve
-structures from IDs.
+By default, all calls to the PeeringDB API use the "depth=1" parameter. This
+means that sets are expanded as integer slices instead of slices of structures,
+which speeds up the API processing time. To get the structures for a given set,
+you just need to iterate over the set and call the appropriate function to
+retrieve structures from IDs. The WithDepth option can be used to ask for a
+depth of 2, in which case the organization, network and Internet exchange
+objects an object refers to are decoded into its embedded structures, while
+sets still only hold IDs.
+
+Functions getting all objects of a namespace, such as GetAllNetworks, fetch
+them page by page using the "limit" and "skip" parameters of the API, instead
+of asking for the whole namespace in a single enormous response. The size of
+the pages can be tuned with the WithPageSize option. Other queries can ask for
+a single page by passing their search parameters through Paginate.
+
+Search parameters using the operators of the API, such as "asn__in" or
+"created__gte", can be built with Filter and added to a search parameters map
+with Where, which formats their values the way the API expects them. A slice
+given as the value of a search parameter is sent as a comma separated list for
+the "__in" operator, and as a repeated parameter otherwise.
+
+Concurrent identical lookups, made with the same URL and credentials, share a
+single call to the API, their callers each getting a copy of the response.
+
+With the WithETagCache option, responses are kept in memory along with their
+ETag and revalidated with conditional requests, so that polling the same
+objects costs an empty 304 Not Modified response when nothing changed.
+The WithMemoryCache option goes further, serving lookups made again within a
+given time from memory without calling the API at all.
+
+Calls can be guarded by a CircuitBreaker, given with the WithCircuitBreaker
+option: once the API failed several times in a row, calls fail right away with
+ErrCircuitOpen for a cool-down period, after which a single call probes the API
+to close the circuit again.
+
+API calls can be logged with a log/slog logger given with the WithLogger
+option, along with their namespace, parameters, duration, status code and
+number of retries.
+
+Responses of large queries can be shrunk by asking only for some fields of the
+objects with SelectFields. Fields which are not asked are not returned by the
+API and stay zero-valued in the structures objects are decoded into.
+
+Changes can be fetched incrementally by asking only for the objects updated
+after a point in time with UpdatedSince. Deleted objects are then returned too,
+with their status set to "deleted".
+
+Lookups made with a context, using GetNetworkWithContext for instance, can be
+canceled or bounded by a deadline set on the context. The timeout of each call
+given with WithTimeout can also be changed for some lookups with
+ContextWithCallTimeout, interactive and bulk lookups needing different limits.
+
+Endpoints and fields not covered by the structures of this package yet can be
+queried with GetRaw, which returns the JSON response of the API as is.
+
+Objects can also be written, given an API key allowed to do so. An object
+fetched with the package can be changed and sent back to update it, with
+UpdateNetwork for instance. Objects of any type can be created, updated and
+deleted with the generic Create, Update and Delete functions. Writes return the
+object as given back by the API, with its ID, creation time and status, so that
+further changes can be chained. Many changes can be applied concurrently,
+within the rate limit, with a BulkWriter. Objects are validated before being
+sent, so that mistakes such as an invalid IP address do not cost API calls, and
+can be checked beforehand with Validate. The changes a write would make can be
+reviewed first, without writing, with DiffUpdate for instance.
+
+AS numbers are represented by the ASN type, which can be parsed from the
+asplain and asdot formats with ParseASN, and tells whether an AS number is
+private, reserved or for documentation.
 
 For example, when requesting one or more objects from the PeeringDB API, the
 response is always formatted in the same way: first comes the metadata, then
 the data. The data is always in an array since it might contain more than one
-object. When asking the API for a network object (called Net and represented by
-the struct of the same name), this package parses the first level as a
-NetResource structure. This structure contains metadata in its Meta field (if
-there is any) and Net structures in the Data field (as an array).
+object. When asking the API for network objects (called net and represented by
+the Network structure), this package parses the first level as a resource
+structure, shared by all object types. This structure contains metadata in its
+Meta field (if there is any) and Network structures in the Data field (as an
+array).
 */
 package peeringdb
@@ -0,0 +1,209 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldDiff is a structure describing the change of the value of a field made
+// by a write. Values are the ones of the JSON representation of the object.
+type FieldDiff struct {
+	Field string
+	// Current is the value of the field in PeeringDB, nil if the object
+	// does not exist yet.
+	Current interface{}
+	// Intended is the value the field would have after the write, nil if
+	// the object would be deleted.
+	Intended interface{}
+}
+
+// String returns a human readable representation of the change.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Field, formatDiffValue(d.Current), formatDiffValue(d.Intended))
+}
+
+// WriteDiff is a structure describing the changes a write would make to an
+// object, computed without writing it. It allows the changes to be reviewed
+// before they are applied.
+type WriteDiff struct {
+	Action    ImportAction
+	Namespace string
+	// ID is the ID of the object, it is 0 for a creation.
+	ID int
+	// Fields are the fields whose value would change, sorted by name.
+	Fields []FieldDiff
+}
+
+// Changed tells if the write would change the object.
+func (d *WriteDiff) Changed() bool {
+	return d.Action != ImportUpdate || len(d.Fields) > 0
+}
+
+// String returns a human readable representation of the changes, one field
+// per line after a line telling which object is written.
+func (d *WriteDiff) String() string {
+	var b strings.Builder
+	if d.Action == ImportCreate {
+		fmt.Fprintf(&b, "%s %s\n", d.Action, d.Namespace)
+	} else {
+		fmt.Fprintf(&b, "%s %s %d\n", d.Action, d.Namespace, d.ID)
+	}
+	for _, field := range d.Fields {
+		fmt.Fprintf(&b, "  %s\n", field)
+	}
+
+	return b.String()
+}
+
+// DiffCreate returns the changes Create would make with the given object,
+// without writing it. The object is validated first, as done by Validate.
+func DiffCreate[T Object](object *T) (*WriteDiff, error) {
+	if err := Validate(object); err != nil {
+		return nil, err
+	}
+
+	intended, err := writableFields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffFields(ImportCreate, namespaceOf[T](), 0, nil, intended), nil
+}
+
+// DiffUpdate returns the changes Update would make with the given object,
+// without writing it: the object having its ID is fetched and compared with
+// it, field by field. As Update sends all fields of the object, fields set in
+// PeeringDB but not in the object would be cleared and are part of the
+// changes. The object is validated first, as done by Validate.
+func DiffUpdate[T Object](ctx context.Context, api *API, object *T) (*WriteDiff, error) {
+	if err := Validate(object); err != nil {
+		return nil, err
+	}
+
+	current, err := currentFields[T](ctx, api, idOf(object))
+	if err != nil {
+		return nil, err
+	}
+	intended, err := writableFields(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffFields(ImportUpdate, namespaceOf[T](), idOf(object), current, intended), nil
+}
+
+// DiffPatch returns the changes Patch would make with the given fields to the
+// object of type T having the given ID, without writing it. Only the given
+// fields are compared.
+func DiffPatch[T Object](ctx context.Context, api *API, id int, fields map[string]interface{}) (*WriteDiff, error) {
+	namespace := namespaceOf[T]()
+	if err := validateObject(namespace, id, fields, nil); err != nil {
+		return nil, err
+	}
+
+	current, err := currentFields[T](ctx, api, id)
+	if err != nil {
+		return nil, err
+	}
+	// Give values the types they have once decoded from JSON, as the
+	// current ones
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s object: %w", namespace, err)
+	}
+	intended := make(map[string]interface{})
+	if err = json.Unmarshal(encoded, &intended); err != nil {
+		return nil, err
+	}
+	for name := range current {
+		if _, ok := intended[name]; !ok {
+			delete(current, name)
+		}
+	}
+
+	return diffFields(ImportUpdate, namespace, id, current, intended), nil
+}
+
+// DiffDelete returns the changes Delete would make to the object of type T
+// having the given ID, without deleting it. All its fields are part of the
+// changes.
+func DiffDelete[T Object](ctx context.Context, api *API, id int) (*WriteDiff, error) {
+	current, err := currentFields[T](ctx, api, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffFields(ImportDelete, namespaceOf[T](), id, current, nil), nil
+}
+
+// currentFields returns the writable fields of the object of type T having
+// the given ID, as found in PeeringDB.
+func currentFields[T Object](ctx context.Context, api *API, id int) (map[string]interface{}, error) {
+	namespace := namespaceOf[T]()
+	if id <= 0 {
+		return nil, fmt.Errorf("cannot diff %s object: %w", namespace, ErrMissingID)
+	}
+
+	current, err := getObjectByID[T](ctx, api, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("no %s object found for ID %d", namespace, id)
+	}
+
+	return writableFields(current)
+}
+
+// diffFields compares the current and the intended fields of an object. Nil
+// maps stand for an object which does not exist.
+func diffFields(action ImportAction, namespace string, id int, current, intended map[string]interface{}) *WriteDiff {
+	diff := &WriteDiff{Action: action, Namespace: namespace, ID: id}
+
+	names := make(map[string]bool, len(current)+len(intended))
+	for name := range current {
+		names[name] = true
+	}
+	for name := range intended {
+		names[name] = true
+	}
+
+	for name := range names {
+		currentValue, intendedValue := current[name], intended[name]
+		if reflect.DeepEqual(currentValue, intendedValue) {
+			continue
+		}
+		// Missing, empty and false values are the same for creations and
+		// deletions
+		if (current == nil || intended == nil) && (isZeroValue(currentValue) || currentValue == false) &&
+			(isZeroValue(intendedValue) || intendedValue == false) {
+			continue
+		}
+		diff.Fields = append(diff.Fields, FieldDiff{Field: name, Current: currentValue, Intended: intendedValue})
+	}
+	sort.Slice(diff.Fields, func(i, j int) bool {
+		return diff.Fields[i].Field < diff.Fields[j].Field
+	})
+
+	return diff
+}
+
+// formatDiffValue formats a value of a field for humans.
+func formatDiffValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
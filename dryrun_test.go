@@ -0,0 +1,61 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDiffUpdate(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "net_id": 1, "ixlan_id": 1, "speed": 1000, "ipaddr4": "192.0.2.1", "is_rs_peer": true},
+		},
+	})
+	api := server.api()
+	ctx := context.Background()
+
+	diff, err := DiffUpdate(ctx, api, &NetworkInternetExchangeLAN{ID: 1, NetworkID: 1, InternetExchangeLANID: 1, Speed: 10000, IPAddr4: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "update netixlan 1\n  is_rs_peer: true -> false\n  speed: 1000 -> 10000\n"
+	if !diff.Changed() || diff.String() != expected {
+		t.Errorf("DiffUpdate, expected:\n%s\ngot:\n%s", expected, diff)
+	}
+
+	diff, err = DiffPatch[NetworkInternetExchangeLAN](ctx, api, 1, map[string]interface{}{"speed": 1000, "ipaddr6": "2001:db8::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].String() != `ipaddr6: "" -> "2001:db8::1"` {
+		t.Errorf("DiffPatch, unexpected changes %v", diff.Fields)
+	}
+	if diff, _ = DiffPatch[NetworkInternetExchangeLAN](ctx, api, 1, map[string]interface{}{"speed": 1000}); diff.Changed() {
+		t.Errorf("DiffPatch, unexpected changes %v", diff.Fields)
+	}
+
+	diff, err = DiffDelete[NetworkInternetExchangeLAN](ctx, api, 1)
+	if err != nil || !diff.Changed() || len(diff.Fields) != 5 {
+		t.Errorf("DiffDelete, unexpected result %v, %v", diff, err)
+	}
+	if _, err = DiffDelete[NetworkInternetExchangeLAN](ctx, api, 2); err == nil {
+		t.Error("DiffDelete, expected an error for a missing object")
+	}
+
+	diff, err = DiffCreate(&NetworkFacility{NetworkID: 1, FacilityID: 2})
+	if err != nil || diff.String() != "create netfac\n  fac_id: <none> -> 2\n  net_id: <none> -> 1\n" {
+		t.Errorf("DiffCreate, unexpected result %v, %v", diff, err)
+	}
+	if _, err = DiffCreate(&NetworkFacility{NetworkID: 1}); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("DiffCreate, expected ErrInvalidObject, got %v", err)
+	}
+
+	// Nothing was written
+	if count := server.count(networkInternetExchangeLANNamespace); count != 5 {
+		t.Errorf("expected 5 lookups, got %d", count)
+	}
+	if speed := server.objects[networkInternetExchangeLANNamespace][0]["speed"]; speed != 1000 {
+		t.Errorf("object written, speed is %v", speed)
+	}
+}
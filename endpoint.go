@@ -0,0 +1,98 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// betaAPI is the URL of the API of the beta instance of PeeringDB, running
+// the next release with a copy of the production data.
+const betaAPI = "https://beta.peeringdb.com/api/"
+
+// ErrInvalidURL is the error that will be returned if an API URL cannot be
+// used.
+var ErrInvalidURL = errors.New("invalid API URL")
+
+// NewBetaAPI returns a pointer to a new API structure using the API of the
+// beta instance of PeeringDB, to test against its next release. Writes made
+// there are not applied to the production instance.
+func NewBetaAPI(options ...Option) *API {
+	return newAPI(betaAPI, "", options)
+}
+
+// NewBetaAPIWithAPIKey returns a pointer to a new API structure using the API
+// of the beta instance of PeeringDB and the given API key, which must be one
+// created on the beta instance.
+func NewBetaAPIWithAPIKey(apiKey string, options ...Option) *API {
+	return newAPI(betaAPI, apiKey, options)
+}
+
+// ValidateURL checks that the given URL can be used as the URL of an API, such
+// as the one of a self-hosted instance, and returns it normalized. The URL
+// must be an absolute HTTP or HTTPS URL without query. "/api/" is used as its
+// path if it has none, and a trailing slash is added if needed:
+//
+//	ValidateURL("http://localhost:8000")  // http://localhost:8000/api/
+//	ValidateURL("https://pdb.example/api") // https://pdb.example/api/
+func ValidateURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: unsupported scheme '%s' in %s", ErrInvalidURL, u.Scheme, rawURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: no host in %s", ErrInvalidURL, rawURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w: unexpected query in %s", ErrInvalidURL, rawURL)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/api/"
+	}
+
+	return withTrailingSlash(u.String()), nil
+}
+
+// withTrailingSlash returns the given URL ending with a slash, so that
+// namespaces can be appended to it.
+func withTrailingSlash(url string) string {
+	if url != "" && !strings.HasSuffix(url, "/") {
+		return url + "/"
+	}
+
+	return url
+}
+
+// URL returns the URL of the API used by the API structure.
+func (api *API) URL() string {
+	return api.url
+}
+
+// SwitchURL returns a copy of the API structure making its calls to the API at
+// the given URL, validated with ValidateURL, with the same settings and
+// credentials. It helps switching between the production instance, the beta
+// one and a local one while testing. The namespaces supported by the instance
+// are detected again in compatibility mode. The API structure itself is left
+// unchanged, so that calls in progress are not affected. The disk cache, whose
+// entries do not depend on the host of the API, should not be shared by
+// instances holding different data.
+func (api *API) SwitchURL(rawURL string) (*API, error) {
+	validated, err := ValidateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switched := *api
+	switched.url = validated
+	// Namespaces supported by the previous instance may not be by this one
+	if api.compatibility != nil {
+		switched.compatibility = &compatibility{supported: make(map[string]bool)}
+	}
+
+	return &switched, nil
+}
@@ -0,0 +1,52 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	for rawURL, expected := range map[string]string{
+		"http://localhost:8000":          "http://localhost:8000/api/",
+		"http://localhost:8000/":         "http://localhost:8000/api/",
+		"https://pdb.example/api":        "https://pdb.example/api/",
+		"https://pdb.example/peeringdb/": "https://pdb.example/peeringdb/",
+	} {
+		if validated, err := ValidateURL(rawURL); err != nil || validated != expected {
+			t.Errorf("ValidateURL(%s), want '%s' got '%s' (%v)", rawURL, expected, validated, err)
+		}
+	}
+
+	for _, rawURL := range []string{"", "localhost:8000", "ftp://pdb.example/api/", "https:///api/", "https://pdb.example/api/?depth=2", "://"} {
+		if _, err := ValidateURL(rawURL); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("ValidateURL(%s), want ErrInvalidURL got %v", rawURL, err)
+		}
+	}
+}
+
+func TestSwitchURL(t *testing.T) {
+	api := NewBetaAPIWithAPIKey("key", WithCompatibilityMode())
+	if api.URL() != "https://beta.peeringdb.com/api/" {
+		t.Errorf("NewBetaAPI, unexpected URL '%s'", api.URL())
+	}
+	api.compatibility.set(campusNamespace, false)
+
+	switched, err := api.SwitchURL("http://localhost:8000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if switched.URL() != "http://localhost:8000/api/" || switched.apiKey != "key" || api.URL() != "https://beta.peeringdb.com/api/" {
+		t.Errorf("SwitchURL, unexpected URLs '%s' and '%s'", switched.URL(), api.URL())
+	}
+	if _, known := switched.compatibility.lookup(campusNamespace); known {
+		t.Error("SwitchURL, want the supported namespaces to be detected again")
+	}
+	if _, err = api.SwitchURL("localhost"); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("SwitchURL, want ErrInvalidURL got %v", err)
+	}
+
+	// URLs without trailing slash are fixed
+	if api = NewAPIFromURL("http://localhost:8000/api"); api.URL() != "http://localhost:8000/api/" {
+		t.Errorf("NewAPIFromURL, unexpected URL '%s'", api.URL())
+	}
+}
@@ -0,0 +1,52 @@
+package peeringdb
+
+import (
+	"os"
+)
+
+// Environment variables read by NewAPIFromEnv.
+const (
+	// EnvURL is the environment variable holding the URL of the API.
+	EnvURL = "PEERINGDB_URL"
+	// EnvAPIKey is the environment variable holding the API key.
+	EnvAPIKey = "PEERINGDB_API_KEY"
+	// EnvUsername is the environment variable holding the username used
+	// for basic authentication.
+	EnvUsername = "PEERINGDB_USERNAME"
+	// EnvPassword is the environment variable holding the password used
+	// for basic authentication.
+	EnvPassword = "PEERINGDB_PASSWORD"
+)
+
+// NewAPIFromEnv returns a pointer to a new API structure configured with
+// environment variables, so that tools and containers can be configured
+// without code changes or secrets in their code:
+//
+//   - PEERINGDB_URL is the URL of the API, the public API is used if it is
+//     not set;
+//   - PEERINGDB_API_KEY is the API key used for authentication;
+//   - PEERINGDB_USERNAME and PEERINGDB_PASSWORD are used for basic
+//     authentication if no API key is set.
+//
+// The given options are applied after the environment is read.
+func NewAPIFromEnv(options ...Option) *API {
+	config := &Config{}
+	config.LoadEnv()
+
+	return config.NewAPI(options...)
+}
+
+// LoadEnv overrides the settings of the configuration with the environment
+// variables read by NewAPIFromEnv which are set.
+func (c *Config) LoadEnv() {
+	if url := os.Getenv(EnvURL); url != "" {
+		c.URL = url
+	}
+	if apiKey := os.Getenv(EnvAPIKey); apiKey != "" {
+		c.APIKey = apiKey
+	}
+	if username := os.Getenv(EnvUsername); username != "" {
+		c.Username = username
+		c.Password = os.Getenv(EnvPassword)
+	}
+}
@@ -0,0 +1,108 @@
+package peeringdb
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// defaultETagCacheSize is the number of responses kept by an ETag cache if no
+// size is given.
+const defaultETagCacheSize = 1024
+
+// etagEntry is a response kept along with its ETag.
+type etagEntry struct {
+	key  string
+	etag string
+	body []byte
+}
+
+// etagCache keeps the last responses of lookups along with their ETag, to
+// revalidate them with conditional requests. The least recently used
+// responses are evicted once the cache is full.
+type etagCache struct {
+	size int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// WithETagCache returns an option keeping the responses of lookups in memory
+// along with their ETag. Lookups of a URL already seen are sent with the
+// If-None-Match header, and the kept response is decoded again if the API
+// answers that it did not change, which makes polling much cheaper for both
+// the client and the API. At most size responses are kept, 1024 if size is
+// zero. Responses are kept per API key or basic authentication username, so
+// that objects seen by an account are never served to another one.
+func WithETagCache(size int) Option {
+	return func(api *API) {
+		if size <= 0 {
+			size = defaultETagCacheSize
+		}
+		api.etags = &etagCache{size: size, entries: map[string]*list.Element{}, order: list.New()}
+	}
+}
+
+// etagKey returns the key of the response to the given URL fetched by a
+// caller of the given scope.
+func etagKey(scope, url string) string {
+	return scope + "\x00" + url
+}
+
+// conditional makes the given lookup request conditional if a response to it
+// is kept, and returns this response.
+func (c *etagCache) conditional(request *http.Request, key string) *etagEntry {
+	if c == nil || request.Method != http.MethodGet {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return nil
+	}
+	c.order.MoveToFront(element)
+	entry := element.Value.(*etagEntry)
+	request.Header.Set("If-None-Match", entry.etag)
+
+	return entry
+}
+
+// revalidated turns a 304 Not Modified response into the kept response it
+// revalidates, and returns its body.
+func (e *etagEntry) revalidated(response *http.Response) []byte {
+	response.StatusCode = http.StatusOK
+	response.Status = "200 OK"
+	response.Body = io.NopCloser(bytes.NewReader(e.body))
+
+	return e.body
+}
+
+// store keeps the successful response to the given lookup request if it has
+// an ETag.
+func (c *etagCache) store(request *http.Request, key string, response *http.Response, body []byte) {
+	etag := response.Header.Get("ETag")
+	if c == nil || request.Method != http.MethodGet || response.StatusCode != http.StatusOK || etag == "" {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, found := c.entries[key]; found {
+		element.Value = &etagEntry{key: key, etag: etag, body: body}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&etagEntry{key: key, etag: etag, body: body})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*etagEntry).key)
+	}
+}
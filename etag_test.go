@@ -0,0 +1,70 @@
+package peeringdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithETagCache(t *testing.T) {
+	var notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + r.URL.Query().Get("id") + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(`{"meta": {}, "data": [{"id": ` + r.URL.Query().Get("id") + `, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	var statuses []int
+	api := NewAPIFromURL(server.URL+"/api/", WithETagCache(1), WithResponseHook(func(info ResponseInfo) {
+		statuses = append(statuses, info.StatusCode)
+	}))
+
+	// The second lookup is revalidated and decoded from the kept response
+	for i := 0; i < 2; i++ {
+		network, err := api.GetNetworkByID(1)
+		if err != nil || network == nil || network.ASN != 64500 {
+			t.Fatalf("GetNetworkByID, unexpected result %+v: %v", network, err)
+		}
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("GetNetworkByID, want 1 revalidation got %d", notModified.Load())
+	}
+
+	// The least recently used response is evicted
+	if _, err := api.GetNetworkByID(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("GetNetworkByID, want 1 revalidation got %d", notModified.Load())
+	}
+
+	// Responses are not shared between accounts
+	if _, err := api.GetNetworkByIDWithContext(WithAuth(context.Background(), "other-key"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("GetNetworkByIDWithContext, want 1 revalidation got %d", notModified.Load())
+	}
+
+	expected := []int{200, 304, 200, 200, 200}
+	if len(statuses) != len(expected) {
+		t.Fatalf("WithResponseHook, want %v got %v", expected, statuses)
+	}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Errorf("WithResponseHook, want %v got %v", expected, statuses)
+			break
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Command configgen generates the BIRD configuration of the BGP sessions of a
+// network with its peers, on each Internet exchange point where both are
+// connected. The prefix limits of the sessions are taken from the number of
+// prefixes peers declare.
+//
+// It shows how to look up several networks and their connections with a
+// single query each, whatever the number of peers.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// session is a BGP session with a peer on an IX.
+type session struct {
+	peer    *peeringdb.Network
+	ix      int
+	family  int
+	local   string
+	address string
+}
+
+func main() {
+	url := flag.String("url", "", "PeeringDB API URL, the public API is used if empty")
+	local := flag.String("asn", "", "AS number of the local network")
+	peers := flag.String("peers", "", "comma separated list of the AS numbers of the peers")
+	flag.Parse()
+
+	asn, err := peeringdb.ParseASN(*local)
+	if *local != "" && err != nil {
+		fmt.Fprintf(os.Stderr, "invalid AS number '%s'\n", *local)
+		os.Exit(2)
+	}
+
+	var peerASNs []peeringdb.ASN
+	for _, value := range strings.Split(*peers, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		peer, err := peeringdb.ParseASN(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid AS number '%s'\n", value)
+			os.Exit(2)
+		}
+		peerASNs = append(peerASNs, peer)
+	}
+	if asn == 0 || len(peerASNs) == 0 {
+		fmt.Fprintln(os.Stderr, "an AS number and peers are required")
+		os.Exit(2)
+	}
+
+	api := peeringdb.NewAPIFromURL(*url)
+	if err := run(api, os.Stdout, asn, peerASNs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run writes the configuration of the sessions of the network identified by
+// the given AS number with the given peers.
+func run(api *peeringdb.API, w io.Writer, asn peeringdb.ASN, peers []peeringdb.ASN) error {
+	asns := append([]peeringdb.ASN{asn}, peers...)
+	networks, err := api.GetASNs(asns)
+	if err != nil {
+		return err
+	}
+	if networks[asn] == nil {
+		return fmt.Errorf("no network found for AS%s", asn)
+	}
+
+	// Connections of the local network and of the peers
+	local := make(map[int][]peeringdb.NetworkInternetExchangeLAN)
+	var remote []peeringdb.NetworkInternetExchangeLAN
+	for _, search := range peeringdb.InASNs("asn", asns).Chunks() {
+		connections, err := api.GetNetworkInternetExchangeLAN(search)
+		if err != nil {
+			return err
+		}
+		for _, connection := range *connections {
+			if connection.ASN == asn {
+				local[connection.InternetExchangeLANID] = append(local[connection.InternetExchangeLANID], connection)
+			} else {
+				remote = append(remote, connection)
+			}
+		}
+	}
+
+	var sessions []session
+	var ixIDs []int
+	for _, connection := range remote {
+		if networks[connection.ASN] == nil {
+			continue
+		}
+		for _, own := range local[connection.InternetExchangeLANID] {
+			for family, addresses := range map[int][2]string{
+				4: {own.IPAddr4, connection.IPAddr4},
+				6: {own.IPAddr6, connection.IPAddr6},
+			} {
+				if addresses[0] == "" || addresses[1] == "" {
+					continue
+				}
+				sessions = append(sessions, session{
+					peer:    networks[connection.ASN],
+					ix:      connection.InternetExchangeID,
+					family:  family,
+					local:   addresses[0],
+					address: addresses[1],
+				})
+				ixIDs = append(ixIDs, connection.InternetExchangeID)
+			}
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		a, b := sessions[i], sessions[j]
+		if a.peer.ASN != b.peer.ASN {
+			return a.peer.ASN < b.peer.ASN
+		}
+		if a.ix != b.ix {
+			return a.ix < b.ix
+		}
+		if a.family != b.family {
+			return a.family < b.family
+		}
+		return a.address < b.address
+	})
+
+	ixNames := make(map[int]string)
+	for _, search := range peeringdb.InInts("id", ixIDs).Chunks() {
+		ixs, err := api.GetInternetExchange(peeringdb.SelectFields(search, "id", "name"))
+		if err != nil {
+			return err
+		}
+		for _, ix := range *ixs {
+			ixNames[ix.ID] = ix.Name
+		}
+	}
+
+	names := make(map[string]int)
+	for _, s := range sessions {
+		name := fmt.Sprintf("AS%d_IX%d_v%d", s.peer.ASN, s.ix, s.family)
+		names[name]++
+		if names[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, names[name])
+		}
+
+		limit := s.peer.InfoPrefixes4
+		if s.family == 6 {
+			limit = s.peer.InfoPrefixes6
+		}
+
+		fmt.Fprintf(w, "protocol bgp %s {\n", name)
+		description := peeringdb.Sanitize(fmt.Sprintf("%s at %s", s.peer.Name, ixNames[s.ix]), peeringdb.SanitizeOptions{SingleLine: true})
+		fmt.Fprintf(w, "\tdescription \"%s\";\n", strings.ReplaceAll(description, `"`, `'`))
+		fmt.Fprintf(w, "\tlocal %s as %d;\n", s.local, asn)
+		fmt.Fprintf(w, "\tneighbor %s as %d;\n", s.address, s.peer.ASN)
+		fmt.Fprintf(w, "\tipv%d {\n", s.family)
+		if limit > 0 {
+			fmt.Fprintf(w, "\t\timport limit %d action restart;\n", limit)
+		}
+		fmt.Fprintf(w, "\t};\n}\n\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// newAPI returns an API serving the example fixtures.
+func newAPI(t *testing.T) *peeringdb.API {
+	file, err := os.Open("../testdata/fixtures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	fixtures, err := peeringdb.ReadFixtures(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(fixtures.Handler())
+	t.Cleanup(server.Close)
+
+	return peeringdb.NewAPIFromURL(server.URL + "/api/")
+}
+
+func TestRun(t *testing.T) {
+	var output strings.Builder
+	// There is no session with AS64503 which is only connected over IPv6
+	// where the local network is only connected over IPv4, and AS64999 is
+	// unknown
+	if err := run(newAPI(t), &output, 64500, []peeringdb.ASN{64501, 64503, 64999}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `protocol bgp AS64501_IX1_v4 {
+	description "Alpha Networks at Example-IX Paris";
+	local 192.0.2.10 as 64500;
+	neighbor 192.0.2.11 as 64501;
+	ipv4 {
+		import limit 100 action restart;
+	};
+}
+
+protocol bgp AS64501_IX1_v6 {
+	description "Alpha Networks at Example-IX Paris";
+	local 2001:db8:1::10 as 64500;
+	neighbor 2001:db8:1::11 as 64501;
+	ipv6 {
+		import limit 20 action restart;
+	};
+}
+
+protocol bgp AS64501_IX2_v4 {
+	description "Alpha Networks at Example-IX Lyon";
+	local 198.51.100.10 as 64500;
+	neighbor 198.51.100.11 as 64501;
+	ipv4 {
+		import limit 100 action restart;
+	};
+}
+
+`
+	if output.String() != expected {
+		t.Errorf("run, want\n%s\ngot\n%s", expected, output.String())
+	}
+}
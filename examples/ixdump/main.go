@@ -0,0 +1,91 @@
+// Command ixdump writes the participants of an Internet exchange point as CSV,
+// one line per connection with the AS number and the name of the network, its
+// IP addresses, its speed and whether it peers with the route servers.
+//
+// It shows how to decorate objects with related ones fetched in bulk, instead
+// of looking up each network on its own.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+func main() {
+	url := flag.String("url", "", "PeeringDB API URL, the public API is used if empty")
+	ixID := flag.Int("ix", 0, "ID of the IX")
+	flag.Parse()
+
+	if *ixID == 0 {
+		fmt.Fprintln(os.Stderr, "an IX ID is required")
+		os.Exit(2)
+	}
+
+	api := peeringdb.NewAPIFromURL(*url)
+	if err := run(api, os.Stdout, *ixID); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run writes the participants of the IX matching the given ID.
+func run(api *peeringdb.API, w io.Writer, ixID int) error {
+	ix, err := api.GetInternetExchangeByID(ixID)
+	if err != nil {
+		return err
+	}
+	if ix == nil {
+		return fmt.Errorf("no IX found for ID %d", ixID)
+	}
+
+	connections, err := api.GetNetworkInternetExchangeLAN(map[string]interface{}{"ix_id": ixID})
+	if err != nil {
+		return err
+	}
+	networkIDs := make([]int, 0, len(*connections))
+	for _, connection := range *connections {
+		networkIDs = append(networkIDs, connection.NetworkID)
+	}
+
+	names := make(map[int]string)
+	for _, search := range peeringdb.InInts("id", networkIDs).Chunks() {
+		networks, err := api.GetNetwork(peeringdb.SelectFields(search, "id", "name"))
+		if err != nil {
+			return err
+		}
+		for _, network := range *networks {
+			names[network.ID] = network.Name
+		}
+	}
+
+	sort.Slice(*connections, func(i, j int) bool {
+		a, b := (*connections)[i], (*connections)[j]
+		if a.ASN != b.ASN {
+			return a.ASN < b.ASN
+		}
+		return a.ID < b.ID
+	})
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"asn", "name", "ipaddr4", "ipaddr6", "speed", "is_rs_peer"})
+	for _, connection := range *connections {
+		cw.Write([]string{
+			connection.ASN.String(),
+			names[connection.NetworkID],
+			connection.IPAddr4,
+			connection.IPAddr6,
+			strconv.Itoa(connection.Speed),
+			strconv.FormatBool(connection.IsRSPeer),
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// newAPI returns an API serving the example fixtures.
+func newAPI(t *testing.T) *peeringdb.API {
+	file, err := os.Open("../testdata/fixtures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	fixtures, err := peeringdb.ReadFixtures(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(fixtures.Handler())
+	t.Cleanup(server.Close)
+
+	return peeringdb.NewAPIFromURL(server.URL + "/api/")
+}
+
+func TestRun(t *testing.T) {
+	api := newAPI(t)
+
+	var output strings.Builder
+	if err := run(api, &output, 1); err != nil {
+		t.Fatal(err)
+	}
+	expected := `asn,name,ipaddr4,ipaddr6,speed,is_rs_peer
+64500,Local Network,192.0.2.10,2001:db8:1::10,10000,true
+64501,Alpha Networks,192.0.2.11,2001:db8:1::11,100000,true
+64502,Beta Transit,192.0.2.12,,100000,false
+`
+	if output.String() != expected {
+		t.Errorf("run, want\n%s\ngot\n%s", expected, output.String())
+	}
+
+	if err := run(api, io.Discard, 99); err == nil {
+		t.Error("run, want error for an unknown IX")
+	}
+}
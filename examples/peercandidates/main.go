@@ -0,0 +1,130 @@
+// Command peercandidates lists the networks a network could peer with, the
+// ones connected to the same Internet exchange points, ranked by the number of
+// IXs in common.
+//
+// It shows how to fetch objects in bulk: the number of API calls does not
+// grow with the number of IXs and candidates, and only the fields needed are
+// asked for.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// candidate is a network connected to at least one IX in common.
+type candidate struct {
+	network *peeringdb.Network
+	ixs     []string
+}
+
+func main() {
+	url := flag.String("url", "", "PeeringDB API URL, the public API is used if empty")
+	value := flag.String("asn", "", "AS number of the network looking for peers")
+	policy := flag.String("policy", "", "only list networks with this general peering policy, such as Open")
+	flag.Parse()
+
+	asn, err := peeringdb.ParseASN(*value)
+	if err != nil || asn == 0 {
+		fmt.Fprintln(os.Stderr, "an AS number is required")
+		os.Exit(2)
+	}
+
+	api := peeringdb.NewAPIFromURL(*url)
+	if err := run(api, os.Stdout, asn, *policy); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run writes the peering candidates of the network identified by the given AS
+// number, having the given general peering policy if it is not empty.
+func run(api *peeringdb.API, w io.Writer, asn peeringdb.ASN, policy string) error {
+	// IXs the network is connected to
+	search := peeringdb.SelectFields(map[string]interface{}{"asn": asn}, "ix_id")
+	connections, err := api.GetNetworkInternetExchangeLAN(search)
+	if err != nil {
+		return err
+	}
+	var ixIDs []int
+	for _, connection := range *connections {
+		ixIDs = append(ixIDs, connection.InternetExchangeID)
+	}
+	if len(ixIDs) == 0 {
+		return fmt.Errorf("AS%s is not connected to any IX", asn)
+	}
+
+	ixNames := make(map[int]string)
+	for _, search := range peeringdb.InInts("id", ixIDs).Chunks() {
+		ixs, err := api.GetInternetExchange(peeringdb.SelectFields(search, "id", "name"))
+		if err != nil {
+			return err
+		}
+		for _, ix := range *ixs {
+			ixNames[ix.ID] = ix.Name
+		}
+	}
+
+	// Other networks connected to these IXs, a network can have several
+	// connections to the same IX
+	common := make(map[peeringdb.ASN]map[int]bool)
+	for _, search := range peeringdb.InInts("ix_id", ixIDs).Chunks() {
+		connections, err := api.GetNetworkInternetExchangeLAN(peeringdb.SelectFields(search, "asn", "ix_id"))
+		if err != nil {
+			return err
+		}
+		for _, connection := range *connections {
+			if connection.ASN == asn {
+				continue
+			}
+			if common[connection.ASN] == nil {
+				common[connection.ASN] = make(map[int]bool)
+			}
+			common[connection.ASN][connection.InternetExchangeID] = true
+		}
+	}
+
+	asns := make([]peeringdb.ASN, 0, len(common))
+	for peer := range common {
+		asns = append(asns, peer)
+	}
+	networks, err := api.GetASNs(asns)
+	if err != nil {
+		return err
+	}
+
+	var candidates []candidate
+	for peer, ixs := range common {
+		network, ok := networks[peer]
+		if !ok || (policy != "" && !strings.EqualFold(network.PolicyGeneral, policy)) {
+			continue
+		}
+		c := candidate{network: network}
+		for ixID := range ixs {
+			c.ixs = append(c.ixs, ixNames[ixID])
+		}
+		sort.Strings(c.ixs)
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if len(candidates[i].ixs) != len(candidates[j].ixs) {
+			return len(candidates[i].ixs) > len(candidates[j].ixs)
+		}
+		return candidates[i].network.ASN < candidates[j].network.ASN
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ASN\tNAME\tPOLICY\tCOMMON IXS")
+	for _, c := range candidates {
+		fmt.Fprintf(tw, "AS%d\t%s\t%s\t%s\n", c.network.ASN, c.network.Name, c.network.PolicyGeneral, strings.Join(c.ixs, ", "))
+	}
+
+	return tw.Flush()
+}
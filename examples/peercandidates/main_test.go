@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// newAPI returns an API serving the example fixtures.
+func newAPI(t *testing.T) *peeringdb.API {
+	file, err := os.Open("../testdata/fixtures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	fixtures, err := peeringdb.ReadFixtures(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(fixtures.Handler())
+	t.Cleanup(server.Close)
+
+	return peeringdb.NewAPIFromURL(server.URL + "/api/")
+}
+
+func TestRun(t *testing.T) {
+	api := newAPI(t)
+
+	tests := []struct {
+		policy   string
+		expected string
+	}{
+		{
+			expected: `ASN      NAME            POLICY       COMMON IXS
+AS64501  Alpha Networks  Open         Example-IX Lyon, Example-IX Paris
+AS64502  Beta Transit    Restrictive  Example-IX Paris
+AS64503  Gamma Cloud     Selective    Example-IX Lyon
+`,
+		},
+		{
+			policy: "open",
+			expected: `ASN      NAME            POLICY  COMMON IXS
+AS64501  Alpha Networks  Open    Example-IX Lyon, Example-IX Paris
+`,
+		},
+	}
+	for _, test := range tests {
+		var output strings.Builder
+		if err := run(api, &output, 64500, test.policy); err != nil {
+			t.Fatal(err)
+		}
+		if output.String() != test.expected {
+			t.Errorf("run with policy %q, want\n%s\ngot\n%s", test.policy, test.expected, output.String())
+		}
+	}
+
+	if err := run(api, io.Discard, 64999, ""); err == nil {
+		t.Error("run, want error for a network without IX")
+	}
+}
@@ -0,0 +1,205 @@
+package peeringdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlannedCall is a structure describing an API call an operation would make.
+type PlannedCall struct {
+	// Method is the HTTP method of the call, GET for lookups.
+	Method    string
+	Namespace string
+	Search    map[string]interface{}
+	URL       string
+	// EstimatedResults is the number of objects the call is expected to
+	// return, or -1 if it cannot be estimated from the filters.
+	EstimatedResults int
+}
+
+// String returns the call as a human readable text.
+func (c PlannedCall) String() string {
+	keys := make([]string, 0, len(c.Search))
+	for key := range c.Search {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]string, len(keys))
+	for i, key := range keys {
+		filters[i] = fmt.Sprintf("%s=%v", key, c.Search[key])
+	}
+
+	estimate := "unknown"
+	if c.EstimatedResults >= 0 {
+		estimate = fmt.Sprintf("~%d", c.EstimatedResults)
+	}
+
+	if c.Method != http.MethodGet {
+		return fmt.Sprintf("%-6s %s", c.Method, c.URL)
+	}
+
+	return fmt.Sprintf("%-6s %-8s %s (%s objects)", c.Method, c.Namespace, strings.Join(filters, " "), estimate)
+}
+
+// QueryPlan is the sequence of API calls an operation would make.
+type QueryPlan []PlannedCall
+
+// String returns the plan as a human readable text, one line per call.
+func (p QueryPlan) String() string {
+	var b strings.Builder
+	for i, call := range p {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, call)
+	}
+
+	return b.String()
+}
+
+// ExplainedQuery is a structure describing the request a lookup would send.
+type ExplainedQuery struct {
+	Method string
+	URL    string
+	// Header holds the headers of the request. Credentials are redacted.
+	Header http.Header
+}
+
+// String returns the request as a human readable text, the request line
+// followed by the headers sorted by name.
+func (q ExplainedQuery) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", q.Method, q.URL)
+
+	names := make([]string, 0, len(q.Header))
+	for name := range q.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range q.Header[name] {
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+
+	return b.String()
+}
+
+// ExplainQuery returns the request a lookup of the given namespace with the
+// given search parameters would send, without sending it. The URL is built
+// the way lookups build it, with the depth and the limits of the API
+// structure, and the headers are the ones set by the API structure and its
+// signers. It helps debugging filters and writing audit logs, credentials are
+// therefore redacted. The request ID is generated again by the actual lookup,
+// unless the context carries one.
+func (api *API) ExplainQuery(ctx context.Context, namespace string, search map[string]interface{}) (ExplainedQuery, error) {
+	search, _ = api.limitSearch(api.depthSearch(search))
+	url := api.urlBuilder.URL(api.url, namespace, search)
+	if url == "" {
+		return ExplainedQuery{}, ErrBuildingURL
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ExplainedQuery{}, ErrBuildingRequest
+	}
+	api.setHeaders(ctx, request)
+	if err = api.sign(request); err != nil {
+		return ExplainedQuery{}, err
+	}
+	if authorization := request.Header.Get("Authorization"); authorization != "" {
+		scheme, _, _ := strings.Cut(authorization, " ")
+		request.Header.Set("Authorization", scheme+" REDACTED")
+	}
+
+	return ExplainedQuery{Method: request.Method, URL: url, Header: request.Header}, nil
+}
+
+// queryRecorder records the calls of an explained operation.
+type queryRecorder struct {
+	mutex sync.Mutex
+	plan  QueryPlan
+}
+
+// Explain returns the API calls the given function would make, without making
+// them. The function is called with an API which records calls instead of
+// sending them, every call getting an empty result. Writes are recorded too.
+// It is meant to explain bulk and composite helpers:
+//
+//	plan, err := api.Explain(func(api *peeringdb.API) error {
+//		_, err := api.GetASNs(asns)
+//		return err
+//	})
+//
+// As results are empty, calls depending on the results of previous ones are
+// not part of the plan, the plan then stops where data is needed. The error
+// returned by the function is returned along with the plan.
+func (api *API) Explain(f func(api *API) error) (QueryPlan, error) {
+	explained := *api
+	explained.explain = &queryRecorder{}
+
+	err := f(&explained)
+
+	explained.explain.mutex.Lock()
+	defer explained.explain.mutex.Unlock()
+
+	return explained.explain.plan, err
+}
+
+// record records a call and returns an empty response for it.
+func (r *queryRecorder) record(method, namespace, url string, search map[string]interface{}) *http.Response {
+	copied := make(map[string]interface{}, len(search))
+	for key, value := range search {
+		copied[key] = value
+	}
+
+	r.mutex.Lock()
+	call := PlannedCall{Method: method, Namespace: namespace, Search: copied, URL: url, EstimatedResults: 1}
+	if method == http.MethodGet {
+		call.EstimatedResults = estimateResults(namespace, search)
+	}
+	r.plan = append(r.plan, call)
+	r.mutex.Unlock()
+
+	return emptyResponse()
+}
+
+// estimateResults estimates the number of objects a query returns given its
+// search parameters. Lookups by ID or AS number return at most one object per
+// value, queries without filters return the whole namespace. It returns -1 if
+// the filters do not allow to estimate it.
+func estimateResults(namespace string, search map[string]interface{}) int {
+	estimate := -1
+	filtered := false
+
+	for key, value := range search {
+		if nonFilterParameters[key] {
+			continue
+		}
+		filtered = true
+
+		field, operator, _ := strings.Cut(key, "__")
+		if field != "id" && field != "asn" {
+			continue
+		}
+		switch operator {
+		case "":
+			estimate = 1
+		case "in":
+			if count := len(strings.Split(fmt.Sprintf("%v", value), ",")); estimate < 0 || count < estimate {
+				estimate = count
+			}
+		}
+	}
+
+	if !filtered {
+		estimate = approximateObjectCounts[namespace]
+	}
+	if limit, ok := search["limit"].(int); ok && limit > 0 && (estimate < 0 || limit < estimate) {
+		estimate = limit
+	}
+
+	return estimate
+}
@@ -0,0 +1,70 @@
+package peeringdb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{})
+	api := server.api()
+
+	asns := make([]ASN, 150)
+	for i := range asns {
+		asns[i] = ASN(64500 + i)
+	}
+
+	plan, err := api.Explain(func(api *API) error {
+		if _, err := api.GetASNs(asns); err != nil {
+			return err
+		}
+		_, err := api.GetAllFacilities()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Facilities are fetched in 2 pages
+	if len(plan) != 4 {
+		t.Fatalf("Explain, want 4 calls got %d:\n%s", len(plan), plan)
+	}
+	if plan[0].Namespace != networkNamespace || plan[0].EstimatedResults != 100 || plan[1].EstimatedResults != 50 {
+		t.Errorf("Explain, unexpected ASNs calls:\n%s", plan)
+	}
+	if plan[2].Namespace != facilityNamespace || plan[2].EstimatedResults != DefaultPageSize || plan[3].Search["skip"] != DefaultPageSize {
+		t.Errorf("Explain, unexpected facilities calls:\n%s", plan)
+	}
+
+	// Nothing must have been sent
+	if server.count(networkNamespace) != 0 || server.count(facilityNamespace) != 0 {
+		t.Error("Explain, calls were made")
+	}
+}
+
+func TestExplainQuery(t *testing.T) {
+	api := NewAPIFromURLWithAPIKey("https://peeringdb.example/api/", "secret-key",
+		WithRequestSigner(func(request *http.Request) error {
+			request.Header.Set("X-Signature", "signed")
+			return nil
+		}),
+	)
+	ctx := ContextWithRequestID(context.Background(), "request-1")
+
+	query, err := api.ExplainQuery(ctx, networkNamespace, Where(nil, Filter("asn").In(64500, 64501)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "GET https://peeringdb.example/api/net?depth=1&asn__in=64500%2C64501\n" +
+		"Authorization: Api-Key REDACTED\n" +
+		"X-Request-Id: request-1\n" +
+		"X-Signature: signed\n"
+	if query.String() != expected {
+		t.Errorf("ExplainQuery, want %q got %q", expected, query.String())
+	}
+	if strings.Contains(query.String(), "secret-key") {
+		t.Errorf("ExplainQuery, API key not redacted in %q", query.String())
+	}
+}
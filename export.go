@@ -0,0 +1,307 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSQLBatchSize is the number of rows per INSERT statement if no other
+// value is given.
+const defaultSQLBatchSize = 100
+
+// ColumnType is the type of the values of a Column.
+type ColumnType int
+
+const (
+	// ColumnInteger holds int64 values.
+	ColumnInteger ColumnType = iota
+	// ColumnFloat holds float64 values.
+	ColumnFloat
+	// ColumnBoolean holds bool values.
+	ColumnBoolean
+	// ColumnText holds string values.
+	ColumnText
+	// ColumnTime holds time.Time values.
+	ColumnTime
+	// ColumnJSON holds string values containing JSON, used for sets and
+	// other nested values.
+	ColumnJSON
+)
+
+// SQLType returns a SQL type suitable to store values of the column type. It
+// uses types understood by most SQL databases.
+func (t ColumnType) SQLType() string {
+	switch t {
+	case ColumnInteger:
+		return "BIGINT"
+	case ColumnFloat:
+		return "DOUBLE PRECISION"
+	case ColumnBoolean:
+		return "BOOLEAN"
+	case ColumnTime:
+		return "TIMESTAMP"
+	default:
+		return "TEXT"
+	}
+}
+
+// Column is a structure describing a column of a Table.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Table is a structure holding the objects of a namespace as rows of values.
+// Values are int64, float64, bool, string or time.Time depending on the
+// column type, or nil if there is no value. Objects embedded in other ones
+// are not part of the columns, only their IDs are.
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// tableColumns returns the columns used to represent the given structure.
+func tableColumns(t reflect.Type) []Column {
+	var columns []Column
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		var columnType ColumnType
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			columnType = ColumnInteger
+		case reflect.Float32, reflect.Float64:
+			columnType = ColumnFloat
+		case reflect.Bool:
+			columnType = ColumnBoolean
+		case reflect.String:
+			columnType = ColumnText
+		case reflect.Slice, reflect.Map:
+			columnType = ColumnJSON
+		case reflect.Struct:
+			// Embedded objects are represented by their IDs
+			if field.Type != reflect.TypeOf(time.Time{}) {
+				continue
+			}
+			columnType = ColumnTime
+		default:
+			continue
+		}
+
+		columns = append(columns, Column{Name: name, Type: columnType})
+	}
+
+	return columns
+}
+
+// columnValue converts a value decoded from JSON to the Go type matching the
+// column type.
+func columnValue(value interface{}, columnType ColumnType) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch columnType {
+	case ColumnInteger:
+		if number, ok := value.(float64); ok {
+			return int64(number)
+		}
+	case ColumnFloat:
+		if number, ok := value.(float64); ok {
+			return number
+		}
+	case ColumnBoolean:
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	case ColumnText:
+		if s, ok := value.(string); ok {
+			return s
+		}
+		return formatValue(value)
+	case ColumnTime:
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t.UTC()
+			}
+		}
+	case ColumnJSON:
+		if data, err := json.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+
+	return nil
+}
+
+// Table returns the objects of a namespace stored in the mirror as a Table
+// named after the namespace. Deleted objects kept by the mirror are left out.
+func (m *Mirror) Table(namespace string) (*Table, error) {
+	t, ok := namespaceTypes[namespace]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNamespace, namespace)
+	}
+
+	loaded, err := m.load(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{
+		Name:    namespace,
+		Columns: tableColumns(t),
+		Rows:    make([][]interface{}, 0, len(loaded.decoded)),
+	}
+	for _, object := range loaded.decoded {
+		if !isLive(object) {
+			continue
+		}
+		row := make([]interface{}, len(table.Columns))
+		for j, column := range table.Columns {
+			row[j] = columnValue(object[column.Name], column.Type)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+// Tables returns the tables of all namespaces completely loaded in the
+// mirror.
+func (m *Mirror) Tables() ([]*Table, error) {
+	var tables []*Table
+
+	for _, namespace := range m.options.Namespaces {
+		if state, ok := m.State(namespace); !ok || !state.Complete {
+			continue
+		}
+
+		table, err := m.Table(namespace)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// SQLExportOptions is a structure used to tune SQL exports.
+type SQLExportOptions struct {
+	// TablePrefix is prepended to the namespace to name tables.
+	TablePrefix string
+	// BatchSize is the number of rows per INSERT statement, 100 is used if
+	// it is not set.
+	BatchSize int
+}
+
+// CreateTableSQL returns the SQL statement creating the table.
+func (t *Table) CreateTableSQL(prefix string) string {
+	definitions := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		definitions[i] = fmt.Sprintf("%s %s", quoteSQLIdentifier(column.Name), column.Type.SQLType())
+		if column.Name == "id" {
+			definitions[i] += " PRIMARY KEY"
+		}
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n);\n",
+		quoteSQLIdentifier(prefix+t.Name), strings.Join(definitions, ",\n  "))
+}
+
+// WriteSQL writes SQL statements creating the table and inserting its rows.
+func (t *Table) WriteSQL(w io.Writer, options SQLExportOptions) error {
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultSQLBatchSize
+	}
+
+	if _, err := io.WriteString(w, t.CreateTableSQL(options.TablePrefix)); err != nil {
+		return err
+	}
+
+	names := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		names[i] = quoteSQLIdentifier(column.Name)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES\n",
+		quoteSQLIdentifier(options.TablePrefix+t.Name), strings.Join(names, ", "))
+
+	for start := 0; start < len(t.Rows); start += options.BatchSize {
+		rows := t.Rows[start:min(start+options.BatchSize, len(t.Rows))]
+
+		var statement strings.Builder
+		statement.WriteString(insert)
+		for i, row := range rows {
+			values := make([]string, len(row))
+			for j, value := range row {
+				values[j] = sqlLiteral(value)
+			}
+			statement.WriteString("  (" + strings.Join(values, ", ") + ")")
+			if i < len(rows)-1 {
+				statement.WriteString(",\n")
+			}
+		}
+		statement.WriteString(";\n")
+
+		if _, err := io.WriteString(w, statement.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportSQL writes a SQL dump of all namespaces completely loaded in the
+// mirror. The dump creates one table per namespace and inserts all objects.
+func (m *Mirror) ExportSQL(w io.Writer, options SQLExportOptions) error {
+	tables, err := m.Tables()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err = table.WriteSQL(w, options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quoteSQLIdentifier quotes a table or column name.
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlLiteral formats a table value as a SQL literal.
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05") + "'"
+	default:
+		return "'" + strings.ReplaceAll(fmt.Sprintf("%v", v), "'", "''") + "'"
+	}
+}
@@ -0,0 +1,132 @@
+package peeringdb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMirrorExportSQL(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		organizationNamespace: {
+			{"id": 1, "name": "O'Reilly", "net_set": []int{1, 2}, "latitude": 1.5, "require_2fa": true, "created": "2020-01-02T03:04:05Z"},
+			{"id": 2, "name": "Other", "latitude": nil},
+		},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{organizationNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := mirror.Table(organizationNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]interface{})
+	for i, column := range table.Columns {
+		values[column.Name] = table.Rows[0][i]
+	}
+	if values["name"] != "O'Reilly" || values["net_set"] != "[1,2]" || values["require_2fa"] != true {
+		t.Errorf("Table, unexpected values %v", values)
+	}
+	if created := values["created"]; created != time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) {
+		t.Errorf("Table, unexpected created time %v", created)
+	}
+
+	var buffer bytes.Buffer
+	if err = mirror.ExportSQL(&buffer, SQLExportOptions{TablePrefix: "peeringdb_"}); err != nil {
+		t.Fatal(err)
+	}
+	dump := buffer.String()
+	for _, expected := range []string{
+		`CREATE TABLE "peeringdb_org" (`,
+		`"id" BIGINT PRIMARY KEY`,
+		`INSERT INTO "peeringdb_org" ("id", "name", `,
+		`(1, 'O''Reilly', `,
+		`'2020-01-02 03:04:05'`,
+	} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("ExportSQL, want '%s' in dump", expected)
+		}
+	}
+}
+
+func TestMirrorExportDeletion(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "status": "ok", "updated": "2020-01-01T00:00:00Z"},
+			{"id": 2, "asn": 64501, "status": "ok", "updated": "2020-01-01T00:00:00Z"},
+		},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	server.mutex.Lock()
+	server.objects[networkNamespace][1]["status"] = "deleted"
+	server.objects[networkNamespace][1]["updated"] = time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	server.mutex.Unlock()
+	if err = mirror.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The deleted network is kept by the mirror but not exported
+	if state, _ := mirror.State(networkNamespace); state.Count != 1 {
+		t.Errorf("State, want a count of 1 got %d", state.Count)
+	}
+	tables, err := mirror.Tables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || len(tables[0].Rows) != 1 {
+		t.Fatalf("Tables, want a single row got %+v", tables)
+	}
+	if id := tables[0].Rows[0][0]; id != int64(1) {
+		t.Errorf("Tables, want network 1 got %v", id)
+	}
+
+	var buffer bytes.Buffer
+	if err = mirror.ExportSQL(&buffer, SQLExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buffer.String(), "64501") {
+		t.Error("ExportSQL, deleted network exported")
+	}
+}
+
+func TestTableWriteParquet(t *testing.T) {
+	table := &Table{
+		Name:    "net",
+		Columns: []Column{{"id", ColumnInteger}, {"name", ColumnText}, {"info_ipv6", ColumnBoolean}},
+		Rows: [][]interface{}{
+			{int64(1), "Network A", true},
+			{int64(2), nil, false},
+		},
+	}
+
+	var buffer bytes.Buffer
+	if err := table.WriteParquet(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buffer.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("WriteParquet, missing magic bytes")
+	}
+
+	// The footer length must point to the metadata, which ends with the
+	// writer name
+	length := int(data[len(data)-8]) | int(data[len(data)-7])<<8 | int(data[len(data)-6])<<16 | int(data[len(data)-5])<<24
+	metadata := data[len(data)-8-length : len(data)-8]
+	if !bytes.Contains(metadata, []byte("github.com/gmazoyer/peeringdb")) {
+		t.Error("WriteParquet, invalid footer length")
+	}
+}
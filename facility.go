@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// facilityResource is the top-level structure when parsing the JSON output
-// from the API. This structure is not used if the Facility JSON object is
-// included as a field in another JSON object. This structure is used only if
-// the proper namespace is queried.
-type facilityResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []Facility `json:"data"`
-}
-
 // Facility is the representation of a location where network operators and
 // Internet exchange points are located. Most of the time you know a facility
 // as a datacenter.
@@ -64,52 +53,33 @@ type Facility struct {
 	} `json:"social_media"`
 }
 
-// getFacilityResource returns a pointer to a facilityResource structure
-// corresponding to the API JSON response. An error can be returned if
-// something went wrong.
-func (api *API) getFacilityResource(search map[string]interface{}) (*facilityResource, error) {
-	// Get the FacilityResource from the API
-	response, err := api.lookup(facilityNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &facilityResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetFacility returns a pointer to a slice of Facility structures that the
 // PeeringDB API can provide matching the given search parameters map. If an
-// error occurs, the returned error will be non-nil. The returned value can be
-// nil if no object could be found.
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetFacility(search map[string]interface{}) (*[]Facility, error) {
-	// Ask for the all Facility objects
-	facilyResource, err := api.getFacilityResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetFacilityWithContext(context.Background(), search)
+}
 
-	// Return all Facility objects, will be nil if slice is empty
-	return &facilyResource.Data, nil
+// GetFacilityWithContext is the same as GetFacility but uses the given context
+// for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]Facility, error) {
+	return getObjects[Facility](ctx, api, search)
 }
 
-// GetAllFacilities returns a pointer to a slice of Facility structures that
-// the PeeringDB API can provide. If an error occurs, the returned error will
-// be non-nil. The can be nil if no object could be found.
+// GetAllFacilities returns a pointer to a slice of Facility structures that the
+// PeeringDB API can provide. If an error occurs, the returned error will be
+// non-nil. The returned slice is empty, but never nil, if no object could be
+// found.
 func (api *API) GetAllFacilities() (*[]Facility, error) {
-	// Return all Facility objects
-	return api.GetFacility(nil)
+	return api.GetAllFacilitiesWithContext(context.Background())
+}
+
+// GetAllFacilitiesWithContext is the same as GetAllFacilities but uses the
+// given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetAllFacilitiesWithContext(ctx context.Context) (*[]Facility, error) {
+	return getAllObjects[Facility](ctx, api)
 }
 
 // GetFacilityByID returns a pointer to a Facility structure that matches the
@@ -118,29 +88,11 @@ func (api *API) GetAllFacilities() (*[]Facility, error) {
 // some reasons the API returns more than one object for the given ID (but it
 // must not) only the first will be used for the returned value.
 func (api *API) GetFacilityByID(id int) (*Facility, error) {
-	// No point of looking for the facility with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the Facility given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	facilities, err := api.GetFacility(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No Facility matching the ID
-	if len(*facilities) < 1 {
-		return nil, nil
-	}
+	return api.GetFacilityByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*facilities)[0], nil
+// GetFacilityByIDWithContext is the same as GetFacilityByID but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetFacilityByIDWithContext(ctx context.Context, id int) (*Facility, error) {
+	return getObjectByID[Facility](ctx, api, id)
 }
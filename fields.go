@@ -0,0 +1,72 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SelectFields returns a copy of the given search parameters map asking the
+// API to only return the given fields of objects, such as "id", "asn" and
+// "name", using the "fields" parameter of the API. It shrinks the responses of
+// large queries. Fields which are not returned keep their zero value in the
+// structures objects are decoded into.
+func SelectFields(search map[string]interface{}, fields ...string) map[string]interface{} {
+	selected := make(map[string]interface{}, len(search)+1)
+	for key, value := range search {
+		selected[key] = value
+	}
+	selected["fields"] = strings.Join(fields, ",")
+
+	return selected
+}
+
+// selectedFields returns the fields asked by the "fields" parameter of a
+// search parameters map, nil if all fields are asked.
+func selectedFields(search map[string]interface{}) []string {
+	value, ok := search["fields"]
+	if !ok {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(fmt.Sprintf("%v", value), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// projectObject returns an object, decoded from JSON, with only the given
+// fields, encoded as JSON.
+func projectObject(object map[string]interface{}, fields []string) (json.RawMessage, error) {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := object[field]; ok {
+			projected[field] = value
+		}
+	}
+
+	return json.Marshal(projected)
+}
+
+// validateFields checks that the fields asked by the "fields" parameter of a
+// search parameters map are fields of the given structure.
+func validateFields(t reflect.Type, namespace string, search map[string]interface{}) error {
+	known := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		known[name] = true
+	}
+
+	for _, field := range selectedFields(search) {
+		if !known[field] {
+			return fmt.Errorf("invalid field '%s' for %s", field, namespace)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestSelectFields(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A", "info_traffic": "1-5Gbps"},
+			{"id": 2, "asn": 64501, "name": "Network B", "info_traffic": "5-10Gbps"},
+		},
+	})
+	api := server.api()
+
+	search := SelectFields(map[string]interface{}{"asn": 64501}, "id", "asn", "name")
+	if err := api.ValidateSearch(networkNamespace, search); err != nil {
+		t.Errorf("ValidateSearch, unexpected error: %v", err)
+	}
+	if err := api.ValidateSearch(networkNamespace, SelectFields(nil, "id", "unknown")); err == nil {
+		t.Error("ValidateSearch, want error for an unknown field")
+	}
+
+	mirror, err := NewMirror(api, t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The API and a mirror only return the fields asked
+	for name, client := range map[string]Client{"api": api, "mirror": mirror} {
+		networks, err := client.GetNetwork(search)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(*networks) != 1 {
+			t.Fatalf("GetNetwork from %s, want 1 network got %d", name, len(*networks))
+		}
+		network := (*networks)[0]
+		if network.ID != 2 || network.ASN != 64501 || network.Name != "Network B" || network.InfoTraffic != "" {
+			t.Errorf("GetNetwork from %s, unexpected network: %+v", name, network)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownNamespace is the error that will be returned if a namespace is
+// not known by this package.
+var ErrUnknownNamespace = errors.New("unknown namespace")
+
+// filterOperators gives the operators supported to filter fields of each
+// column type. The empty operator is an exact match.
+var filterOperators = map[ColumnType][]string{
+	ColumnInteger: {"", "in", "lt", "lte", "gt", "gte"},
+	ColumnFloat:   {"", "lt", "lte", "gt", "gte"},
+	ColumnBoolean: {""},
+	ColumnText:    {"", "in", "contains", "startswith"},
+	ColumnTime:    {"", "lt", "lte", "gt", "gte"},
+}
+
+// FilterableField is a field of a namespace which can be used to filter
+// objects, with the operators it supports.
+type FilterableField struct {
+	Name      string
+	Type      ColumnType
+	Operators []string
+}
+
+// Parameters returns the search parameter names using the field, "asn" and
+// "asn__in" for instance.
+func (f FilterableField) Parameters() []string {
+	parameters := make([]string, len(f.Operators))
+	for i, operator := range f.Operators {
+		parameters[i] = f.Name
+		if operator != "" {
+			parameters[i] += "__" + operator
+		}
+	}
+
+	return parameters
+}
+
+// FilterableFields returns the fields of the given namespace which can be
+// used in search parameters maps, sorted by name. Fields are taken from the
+// structure representing the objects of the namespace, nested objects and
+// lists cannot be filtered.
+func (api *API) FilterableFields(namespace string) ([]FilterableField, error) {
+	return filterableFields(namespace)
+}
+
+// ValidateSearch checks that the given search parameters map only filters on
+// fields of the namespace, with supported operators, and that the fields
+// asked with SelectFields are fields of the namespace.
+func (api *API) ValidateSearch(namespace string, search map[string]interface{}) error {
+	fields, err := filterableFields(namespace)
+	if err != nil {
+		return err
+	}
+	if err = validateFields(namespaceTypes[namespace], namespace, search); err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for _, field := range fields {
+		for _, parameter := range field.Parameters() {
+			known[parameter] = true
+		}
+	}
+
+	keys := make([]string, 0, len(search))
+	for key := range search {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if nonFilterParameters[key] || known[key] {
+			continue
+		}
+		field, operator, _ := strings.Cut(key, "__")
+		if known[field] {
+			return fmt.Errorf("unsupported operator '%s' for field '%s' of %s", operator, field, namespace)
+		}
+		return fmt.Errorf("invalid filter field '%s' for %s", field, namespace)
+	}
+
+	return nil
+}
+
+// filterableFields returns the filterable fields of a namespace.
+func filterableFields(namespace string) ([]FilterableField, error) {
+	t, ok := namespaceTypes[namespace]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNamespace, namespace)
+	}
+
+	var fields []FilterableField
+	for _, column := range tableColumns(t) {
+		operators, ok := filterOperators[column.Type]
+		if !ok {
+			continue
+		}
+		fields = append(fields, FilterableField{Name: column.Name, Type: column.Type, Operators: operators})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	return fields, nil
+}
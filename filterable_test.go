@@ -0,0 +1,45 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFilterableFields(t *testing.T) {
+	api := NewAPI()
+
+	fields, err := api.FilterableFields(networkNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]FilterableField)
+	for _, field := range fields {
+		found[field.Name] = field
+	}
+	if asn, ok := found["asn"]; !ok || len(asn.Parameters()) != 6 || asn.Parameters()[1] != "asn__in" {
+		t.Errorf("FilterableFields, unexpected asn field: %+v", asn)
+	}
+	if _, ok := found["org"]; ok {
+		t.Error("FilterableFields, nested objects must not be filterable")
+	}
+
+	if _, err = api.FilterableFields("foo"); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("FilterableFields, want ErrUnknownNamespace got %v", err)
+	}
+
+	tests := []struct {
+		search map[string]interface{}
+		valid  bool
+	}{
+		{map[string]interface{}{"asn__in": "64500,64501", "depth": 0}, true},
+		{map[string]interface{}{"name__startswith": "Net", "info_ipv6": true}, true},
+		{map[string]interface{}{"asn__contains": "645"}, false},
+		{map[string]interface{}{"foo": 1}, false},
+	}
+	for _, test := range tests {
+		if err = api.ValidateSearch(networkNamespace, test.search); (err == nil) != test.valid {
+			t.Errorf("ValidateSearch(%v), unexpected result: %v", test.search, err)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// defaultFixtureNetworks is the number of networks sampled to generate
+// fixtures if no other value is given.
+const defaultFixtureNetworks = 5
+
+// ErrUnsupportedSource is the error that will be returned if a client cannot
+// give access to the raw objects it holds.
+var ErrUnsupportedSource = errors.New("unsupported source")
+
+// Fixtures is a small dataset of PeeringDB objects indexed by namespace.
+// Objects are kept as decoded JSON with all their fields, so fixtures follow
+// the schema of the API when they are generated again.
+type Fixtures map[string][]map[string]interface{}
+
+// FixtureOptions is a structure used to configure the generation of fixtures.
+type FixtureOptions struct {
+	// ASNs are the AS numbers of the networks to sample. If it is not set,
+	// the first networks are sampled.
+	ASNs []ASN
+	// Networks is the number of networks to sample if no AS numbers are
+	// given, 5 is used if it is not set.
+	Networks int
+	// Skip is the number of networks to skip before sampling if no AS
+	// numbers are given.
+	Skip int
+}
+
+// GenerateFixtures samples networks from the given source, the live API or a
+// Mirror for instance, and returns them with the objects they relate to:
+// organizations, contacts, facilities, Internet exchange points, their LANs
+// and prefixes, and the connections between them. Contact details, such as
+// names, e-mail addresses and phone numbers, are anonymized.
+func GenerateFixtures(source Client, options FixtureOptions) (Fixtures, error) {
+	raw, ok := source.(rawSource)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedSource, source)
+	}
+	if options.Networks <= 0 {
+		options.Networks = defaultFixtureNetworks
+	}
+
+	fixtures := make(Fixtures)
+
+	var (
+		networks []map[string]interface{}
+		err      error
+	)
+	if len(options.ASNs) > 0 {
+		networks, err = getFixtureObjects(raw, networkNamespace, "asn", asnInts(options.ASNs))
+	} else {
+		search := make(map[string]interface{})
+		search["limit"] = options.Networks
+		search["skip"] = options.Skip
+		networks, err = decodeFixtureObjects(raw.getRaw(networkNamespace, search))
+	}
+	if err != nil {
+		return nil, err
+	}
+	fixtures.add(networkNamespace, networks)
+	networkIDs := fixtureIDs(networks, "id")
+
+	// Objects attached to the networks
+	for _, namespace := range []string{networkContactNamespace, networkFacilityNamespace, networkInternetExchangeLANNamespace} {
+		objects, err := getFixtureObjects(raw, namespace, "net_id", networkIDs)
+		if err != nil {
+			return nil, err
+		}
+		fixtures.add(namespace, objects)
+	}
+
+	// Objects the networks are connected to, then their own objects
+	related := []struct {
+		namespace string
+		field     string
+		from      string
+		fromField string
+	}{
+		{internetExchangeLANNamespace, "id", networkInternetExchangeLANNamespace, "ixlan_id"},
+		{internetExchangeNamespace, "id", internetExchangeLANNamespace, "ix_id"},
+		{internetExchangePrefixNamespace, "ixlan_id", internetExchangeLANNamespace, "id"},
+		{facilityNamespace, "id", networkFacilityNamespace, "fac_id"},
+		{organizationNamespace, "id", networkNamespace, "org_id"},
+		{organizationNamespace, "id", internetExchangeNamespace, "org_id"},
+		{organizationNamespace, "id", facilityNamespace, "org_id"},
+	}
+	for _, r := range related {
+		objects, err := getFixtureObjects(raw, r.namespace, r.field, fixtureIDs(fixtures[r.from], r.fromField))
+		if err != nil {
+			return nil, err
+		}
+		fixtures.add(r.namespace, objects)
+	}
+
+	fixtures.anonymize()
+
+	return fixtures, nil
+}
+
+// ReadFixtures reads fixtures written as JSON.
+func ReadFixtures(r io.Reader) (Fixtures, error) {
+	fixtures := make(Fixtures)
+	if err := json.NewDecoder(r).Decode(&fixtures); err != nil {
+		return nil, err
+	}
+
+	return fixtures, nil
+}
+
+// Write writes the fixtures as canonical indented JSON, so that fixtures of
+// the same objects are identical and their changes can be reviewed as diffs.
+func (f Fixtures) Write(w io.Writer) error {
+	data, err := MarshalCanonicalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+
+	return err
+}
+
+// Handler returns an HTTP handler serving the fixtures the way the PeeringDB
+// API does, for instance with an httptest server, so that code using the API
+// can be tested offline. Objects are looked up under "/api/<namespace>" or
+// "/api/<namespace>/<id>", with the filters supported by a Mirror and the
+// "limit", "skip", "since" and "fields" parameters. Only lookups are
+// supported.
+func (f Fixtures) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError := func(status int, message string) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta": map[string]interface{}{"error": message},
+			})
+		}
+
+		if r.Method != http.MethodGet {
+			writeError(http.StatusMethodNotAllowed, "only lookups are supported")
+			return
+		}
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+		namespace, id, _ := strings.Cut(path, "/")
+		if _, ok := namespaceTypes[namespace]; !ok {
+			writeError(http.StatusNotFound, fmt.Sprintf("unknown namespace '%s'", namespace))
+			return
+		}
+
+		search := make(map[string]interface{})
+		for key, values := range r.URL.Query() {
+			search[key] = values[0]
+		}
+		if id != "" {
+			search["id"] = id
+		}
+
+		indexes, err := filterObjects(f[namespace], search)
+		if err != nil {
+			writeError(http.StatusBadRequest, err.Error())
+			return
+		}
+		fields := selectedFields(search)
+		data := make([]json.RawMessage, len(indexes))
+		for i, index := range indexes {
+			if fields != nil {
+				data[i], err = projectObject(f[namespace][index], fields)
+			} else {
+				data[i], err = json.Marshal(f[namespace][index])
+			}
+			if err != nil {
+				writeError(http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"meta": map[string]interface{}{},
+			"data": data,
+		})
+	})
+}
+
+// Client returns a Client serving the fixtures from memory, with the filters
+// supported by a Mirror and the "limit", "skip", "since" and "fields"
+// parameters, so that code depending on the Client interface can be tested
+// without network access.
+func (f Fixtures) Client() Client {
+	return sourceClient{fixtureSource(f)}
+}
+
+// fixtureSource is a source of the objects of fixtures.
+type fixtureSource Fixtures
+
+// getRaw returns the objects of a namespace matching the given search
+// parameters map.
+func (s fixtureSource) getRaw(namespace string, search map[string]interface{}) ([]json.RawMessage, error) {
+	if _, ok := namespaceTypes[namespace]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceNotSupported, namespace)
+	}
+
+	indexes, err := filterObjects(s[namespace], search)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := selectedFields(search)
+	objects := make([]json.RawMessage, len(indexes))
+	for i, index := range indexes {
+		if fields != nil {
+			objects[i], err = projectObject(s[namespace][index], fields)
+		} else {
+			objects[i], err = json.Marshal(s[namespace][index])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+// add adds objects to a namespace, ignoring objects already there and keeping
+// them sorted by ID.
+func (f Fixtures) add(namespace string, objects []map[string]interface{}) {
+	known := make(map[int]bool, len(f[namespace]))
+	for _, id := range fixtureIDs(f[namespace], "id") {
+		known[id] = true
+	}
+
+	for _, object := range objects {
+		id, _ := fixtureInt(object["id"])
+		if !known[id] {
+			known[id] = true
+			f[namespace] = append(f[namespace], object)
+		}
+	}
+
+	sort.Slice(f[namespace], func(i, j int) bool {
+		a, _ := fixtureInt(f[namespace][i]["id"])
+		b, _ := fixtureInt(f[namespace][j]["id"])
+		return a < b
+	})
+}
+
+// anonymize replaces contact details with example values. Names of contacts,
+// e-mail addresses and phone numbers are replaced in every object.
+func (f Fixtures) anonymize() {
+	for namespace, objects := range f {
+		for _, object := range objects {
+			id, _ := fixtureInt(object["id"])
+			for key, value := range object {
+				if s, ok := value.(string); !ok || s == "" {
+					continue
+				}
+				switch {
+				case strings.HasSuffix(key, "email"):
+					object[key] = fmt.Sprintf("%s-%d@example.com", namespace, id)
+				case strings.HasSuffix(key, "phone"):
+					object[key] = "+1 555 0100"
+				}
+			}
+			if namespace == networkContactNamespace {
+				object["name"] = fmt.Sprintf("Contact %d", id)
+				object["url"] = ""
+			}
+		}
+	}
+}
+
+// getFixtureObjects returns the objects of a namespace whose field value is
+// in the given list, querying them in chunks.
+func getFixtureObjects(source rawSource, namespace, field string, ids []int) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+
+	for _, chunk := range chunkIDs(ids, maxIDsPerQuery) {
+		search := make(map[string]interface{})
+		search[field+"__in"] = joinIDs(chunk)
+
+		found, err := decodeFixtureObjects(source.getRaw(namespace, search))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, found...)
+	}
+
+	return objects, nil
+}
+
+// decodeFixtureObjects decodes raw objects returned by a source.
+func decodeFixtureObjects(raw []json.RawMessage, err error) ([]map[string]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]map[string]interface{}, len(raw))
+	for i, object := range raw {
+		if err = json.Unmarshal(object, &objects[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+// fixtureIDs returns the distinct non-zero integer values of a field of the
+// given objects.
+func fixtureIDs(objects []map[string]interface{}, field string) []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, object := range objects {
+		id, ok := fixtureInt(object[field])
+		if ok && id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// fixtureInt returns a value decoded from JSON, or given in Go code, as an
+// integer.
+func fixtureInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
+}
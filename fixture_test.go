@@ -0,0 +1,128 @@
+package peeringdb
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateFixtures(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "org_id": 1},
+			{"id": 2, "asn": 64501, "org_id": 2},
+		},
+		organizationNamespace: {
+			{"id": 1, "name": "Organization A"},
+			{"id": 2, "name": "Organization B"},
+			{"id": 3, "name": "IX Organization"},
+		},
+		networkContactNamespace: {
+			{"id": 1, "net_id": 1, "name": "Jane Doe", "email": "jane@doe.net", "phone": "+33 1 23 45 67 89"},
+			{"id": 2, "net_id": 2, "name": "John Doe", "email": "john@doe.net", "phone": ""},
+		},
+		networkFacilityNamespace:            {},
+		facilityNamespace:                   {},
+		networkInternetExchangeLANNamespace: {{"id": 1, "net_id": 1, "ixlan_id": 1}},
+		internetExchangeLANNamespace:        {{"id": 1, "ix_id": 1}, {"id": 2, "ix_id": 2}},
+		internetExchangeNamespace:           {{"id": 1, "org_id": 3, "tech_email": "noc@ix.net"}},
+		internetExchangePrefixNamespace:     {{"id": 1, "ixlan_id": 1}, {"id": 2, "ixlan_id": 2}},
+	})
+
+	fixtures, err := GenerateFixtures(server.api(), FixtureOptions{ASNs: []ASN{64500}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{
+		networkNamespace:                1,
+		networkContactNamespace:         1,
+		internetExchangeLANNamespace:    1,
+		internetExchangeNamespace:       1,
+		internetExchangePrefixNamespace: 1,
+		organizationNamespace:           2,
+	}
+	for namespace, count := range counts {
+		if len(fixtures[namespace]) != count {
+			t.Errorf("GenerateFixtures, want %d %s objects got %d", count, namespace, len(fixtures[namespace]))
+		}
+	}
+
+	contact := fixtures[networkContactNamespace][0]
+	if contact["name"] != "Contact 1" || contact["email"] != "poc-1@example.com" || contact["phone"] != "+1 555 0100" {
+		t.Errorf("GenerateFixtures, contact not anonymized: %v", contact)
+	}
+	if ix := fixtures[internetExchangeNamespace][0]; ix["tech_email"] != "ix-1@example.com" {
+		t.Errorf("GenerateFixtures, IX not anonymized: %v", ix)
+	}
+
+	// Fixtures must be readable back
+	var buffer bytes.Buffer
+	if err = fixtures.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadFixtures(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read[organizationNamespace]) != 2 {
+		t.Errorf("ReadFixtures, want 2 organizations got %d", len(read[organizationNamespace]))
+	}
+}
+
+func TestFixturesHandler(t *testing.T) {
+	fixtures := Fixtures{
+		networkNamespace: {
+			{"id": float64(1), "asn": float64(64500), "name": "Network A"},
+			{"id": float64(2), "asn": float64(64501), "name": "Network B"},
+		},
+	}
+	server := httptest.NewServer(fixtures.Handler())
+	defer server.Close()
+	api := NewAPIFromURL(server.URL + "/api/")
+
+	networks, err := api.GetNetwork(SelectFields(map[string]interface{}{"asn__in": "64501,64502"}, "id", "asn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*networks) != 1 || (*networks)[0].ASN != 64501 || (*networks)[0].Name != "" {
+		t.Errorf("GetNetwork, unexpected networks: %+v", *networks)
+	}
+	if network, err := api.GetNetworkByID(1); err != nil || network == nil || network.Name != "Network A" {
+		t.Errorf("GetNetworkByID, unexpected network %+v: %v", network, err)
+	}
+	if campuses, err := api.GetAllCampuses(); err != nil || len(*campuses) != 0 {
+		t.Errorf("GetAllCampuses, want no campus got %v, %v", campuses, err)
+	}
+	if _, err = api.getRaw("unknown", nil); !errors.Is(err, ErrNamespaceNotSupported) {
+		t.Errorf("getRaw, want ErrNamespaceNotSupported got %v", err)
+	}
+	if _, err = api.GetNetwork(map[string]interface{}{"unknown": 1}); err == nil {
+		t.Error("GetNetwork, want error for an invalid filter")
+	}
+}
+
+func TestFixturesClient(t *testing.T) {
+	fixtures := Fixtures{
+		networkNamespace: {
+			{"id": float64(1), "asn": float64(64500), "name": "Network A"},
+			{"id": float64(2), "asn": float64(64501), "name": "Network B"},
+		},
+	}
+	client := fixtures.Client()
+
+	if network, err := client.GetASN(64501); err != nil || network.Name != "Network B" {
+		t.Errorf("GetASN, unexpected network %+v: %v", network, err)
+	}
+	networks, err := client.GetNetwork(SelectFields(map[string]interface{}{"name__contains": "Network"}, "id"))
+	if err != nil || len(*networks) != 2 || (*networks)[0].Name != "" {
+		t.Errorf("GetNetwork, unexpected networks %+v: %v", networks, err)
+	}
+	if organizations, err := client.GetAllOrganizations(); err != nil || len(*organizations) != 0 {
+		t.Errorf("GetAllOrganizations, want no organization got %v, %v", organizations, err)
+	}
+	if _, err = fixtureSource(fixtures).getRaw("unknown", nil); !errors.Is(err, ErrNamespaceNotSupported) {
+		t.Errorf("getRaw, want ErrNamespaceNotSupported got %v", err)
+	}
+}
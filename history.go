@@ -0,0 +1,141 @@
+package peeringdb
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// mirrorHistoryDirectory is the name of the directory where a mirror keeps the
+// versions of objects replaced by newer ones.
+const mirrorHistoryDirectory = "history"
+
+// ObjectVersion is a structure describing a version of a PeeringDB object
+// known by a mirror.
+type ObjectVersion struct {
+	// Updated is the time at which the object was updated to this version,
+	// as reported by PeeringDB.
+	Updated time.Time `json:"updated"`
+	// Replaced is the time of the synchronization which brought a newer
+	// version of the object. It is zero for the current version.
+	Replaced time.Time `json:"replaced"`
+	// Object is the object as returned by the API.
+	Object json.RawMessage `json:"object"`
+}
+
+// historyEntry is a line of a history file.
+type historyEntry struct {
+	ID       int             `json:"id"`
+	Replaced time.Time       `json:"replaced"`
+	Object   json.RawMessage `json:"object"`
+}
+
+// historyPath returns the path of the file storing the history of a
+// namespace.
+func (m *Mirror) historyPath(namespace string) string {
+	return filepath.Join(m.directory, mirrorHistoryDirectory, namespace+".jsonl")
+}
+
+// appendHistory stores versions of objects replaced during a synchronization.
+func (m *Mirror) appendHistory(namespace string, objects []json.RawMessage, replaced time.Time) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(m.directory, mirrorHistoryDirectory), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(m.historyPath(namespace), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, object := range objects {
+		id, err := objectID(object)
+		if err != nil {
+			return err
+		}
+		if err = encoder.Encode(historyEntry{ID: id, Replaced: replaced, Object: object}); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// GetObjectHistory returns the versions of the object of the given namespace
+// matching the given ID, the oldest first and the current one last.
+//
+// PeeringDB does not expose the history of objects through its API, the
+// history is built by the mirror from the versions it has seen: the one
+// fetched by the initial load and the ones fetched by each call to Sync. If no
+// object matches the ID, nil is returned.
+func (m *Mirror) GetObjectHistory(namespace string, id int) ([]ObjectVersion, error) {
+	loaded, err := m.load(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []ObjectVersion
+
+	file, err := os.Open(m.historyPath(namespace))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, 64*1024*1024)
+		for scanner.Scan() {
+			var entry historyEntry
+			if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				return nil, err
+			}
+			if entry.ID != id {
+				continue
+			}
+			versions = append(versions, ObjectVersion{
+				Updated:  objectUpdated(entry.Object),
+				Replaced: entry.Replaced,
+				Object:   entry.Object,
+			})
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Objects are sorted by ID
+	index := sort.Search(len(loaded.decoded), func(i int) bool {
+		value, _ := loaded.decoded[i]["id"].(float64)
+		return int(value) >= id
+	})
+	if index < len(loaded.decoded) {
+		if value, _ := loaded.decoded[index]["id"].(float64); int(value) == id {
+			versions = append(versions, ObjectVersion{
+				Updated: objectUpdated(loaded.raw[index]),
+				Object:  loaded.raw[index],
+			})
+		}
+	}
+
+	return versions, nil
+}
+
+// objectUpdated returns the update time of a PeeringDB object given as raw
+// JSON, or a zero time if it is unknown.
+func objectUpdated(object json.RawMessage) time.Time {
+	var updated struct {
+		Updated time.Time `json:"updated"`
+	}
+	json.Unmarshal(object, &updated)
+
+	return updated.Updated
+}
@@ -0,0 +1,63 @@
+package peeringdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMirrorObjectHistory(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A", "updated": "2020-01-01T00:00:00Z"},
+			{"id": 2, "asn": 64501, "name": "Network B", "updated": "2020-01-01T00:00:00Z"},
+		},
+	})
+	options := MirrorOptions{PageSize: 10, Pacing: -1, Namespaces: []string{networkNamespace}}
+
+	mirror, err := NewMirror(server.api(), t.TempDir(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rename a network and synchronize twice, the second time nothing
+	// changes
+	server.objects[networkNamespace][0]["name"] = "Network A renamed"
+	server.objects[networkNamespace][0]["updated"] = time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	for i := 0; i < 2; i++ {
+		if err = mirror.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	network, err := mirror.GetNetworkByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.Name != "Network A renamed" {
+		t.Errorf("Sync, network not updated: %+v", network)
+	}
+
+	versions, err := mirror.GetObjectHistory(networkNamespace, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("GetObjectHistory, want 2 versions got %d", len(versions))
+	}
+	if !versions[0].Updated.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) || versions[0].Replaced.IsZero() {
+		t.Errorf("GetObjectHistory, unexpected first version: %+v", versions[0])
+	}
+	if !versions[1].Replaced.IsZero() {
+		t.Errorf("GetObjectHistory, current version must not be replaced: %+v", versions[1])
+	}
+
+	if versions, _ = mirror.GetObjectHistory(networkNamespace, 2); len(versions) != 1 {
+		t.Errorf("GetObjectHistory, want 1 version got %d", len(versions))
+	}
+	if versions, _ = mirror.GetObjectHistory(networkNamespace, 3); versions != nil {
+		t.Errorf("GetObjectHistory, want no version got %d", len(versions))
+	}
+}
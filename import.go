@@ -0,0 +1,388 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// importKeys are the sets of fields identifying an object of an importable
+// namespace among the objects of a network, when its ID is not given. Sets are
+// tried in order, the first one whose fields are all defined and match an
+// object of the network being used.
+var importKeys = map[string][][]string{
+	networkInternetExchangeLANNamespace: {{"ixlan_id", "ipaddr4"}, {"ixlan_id", "ipaddr6"}},
+	networkFacilityNamespace:            {{"fac_id"}},
+	networkContactNamespace:             {{"role", "name"}},
+}
+
+// ImportDefinitions holds the objects a network should have in PeeringDB,
+// indexed by namespace. Objects are given as field names, as used by the API,
+// and their values. Only the netixlan, netfac and poc namespaces can be
+// imported.
+type ImportDefinitions map[string][]map[string]interface{}
+
+// ReadImportYAML reads import definitions from a YAML document whose top-level
+// keys are namespaces, each one holding a list of objects.
+//
+//	netfac:
+//	  - fac_id: 1
+//	    local_asn: 64500
+func ReadImportYAML(r io.Reader) (ImportDefinitions, error) {
+	definitions := make(ImportDefinitions)
+	if err := yaml.NewDecoder(r).Decode(&definitions); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid yaml import: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// ReadImportCSV reads import definitions of a namespace from a CSV document.
+// The first line gives the field names, each following line being an object.
+// Empty values are ignored.
+func ReadImportCSV(r io.Reader, namespace string) (ImportDefinitions, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv import: %w", err)
+	}
+
+	definitions := ImportDefinitions{namespace: {}}
+	if len(records) == 0 {
+		return definitions, nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		object := make(map[string]interface{}, len(header))
+		for i, value := range record {
+			if i < len(header) && value != "" {
+				object[strings.TrimSpace(header[i])] = value
+			}
+		}
+		definitions[namespace] = append(definitions[namespace], object)
+	}
+
+	return definitions, nil
+}
+
+// ImportAction is the kind of change made by an import.
+type ImportAction string
+
+const (
+	// ImportCreate creates an object missing from PeeringDB.
+	ImportCreate ImportAction = "create"
+	// ImportUpdate updates an object whose fields differ from their
+	// definition.
+	ImportUpdate ImportAction = "update"
+	// ImportDelete deletes an object not part of the definitions.
+	ImportDelete ImportAction = "delete"
+)
+
+// importMethods are the HTTP methods of the requests applying each action.
+var importMethods = map[ImportAction]string{
+	ImportCreate: http.MethodPost,
+	ImportUpdate: http.MethodPut,
+	ImportDelete: http.MethodDelete,
+}
+
+// ImportChange is a structure describing a change to apply to PeeringDB to
+// make the objects of a network match their definitions.
+type ImportChange struct {
+	Action    ImportAction
+	Namespace string
+	// ID is the ID of the changed object, it is 0 for a creation.
+	ID int
+	// Object holds the fields sent to the API. It is nil for a deletion.
+	Object map[string]interface{}
+	// Changed are the names of the fields changed by an update.
+	Changed []string
+}
+
+// String returns a human readable description of the change.
+func (c ImportChange) String() string {
+	switch c.Action {
+	case ImportCreate:
+		return fmt.Sprintf("create %s %s", c.Namespace, formatImportObject(c.Object))
+	case ImportUpdate:
+		return fmt.Sprintf("update %s %d: %s", c.Namespace, c.ID, strings.Join(c.Changed, ", "))
+	default:
+		return fmt.Sprintf("delete %s %d", c.Namespace, c.ID)
+	}
+}
+
+// formatImportObject formats the fields of an object, sorted by name.
+func formatImportObject(object map[string]interface{}) string {
+	fields := make([]string, 0, len(object))
+	for name, value := range object {
+		fields = append(fields, fmt.Sprintf("%s=%v", name, value))
+	}
+	sort.Strings(fields)
+
+	return strings.Join(fields, " ")
+}
+
+// PlanImport compares the definitions with the objects of the network
+// matching the given ID and returns the changes needed to make them match.
+// Objects are matched by ID if it is given, or by their key fields otherwise:
+// IX LAN and IPv4 or IPv6 address for netixlan, facility for netfac, role and name for
+// poc. Only the fields given in the definitions are compared. If the network
+// ID is 0, the network does not exist yet and all objects are created.
+//
+// Objects of a namespace not part of the definitions are left untouched, all
+// other objects of the network not matching a definition are deleted. Changes
+// are ordered to delete objects first, then to update and create them.
+func (api *API) PlanImport(networkID int, definitions ImportDefinitions) ([]ImportChange, error) {
+	var deletions, updates, creations []ImportChange
+
+	namespaces := make([]string, 0, len(definitions))
+	for namespace := range definitions {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		var err error
+		keys, ok := importKeys[namespace]
+		if !ok {
+			return nil, fmt.Errorf("namespace '%s' cannot be imported", namespace)
+		}
+		columns := importColumns(namespace)
+
+		// Objects of the network, indexed by ID and by key
+		var raw []json.RawMessage
+		if networkID != 0 {
+			search := make(map[string]interface{})
+			search["net_id"] = networkID
+			if raw, err = api.getRaw(namespace, search); err != nil {
+				return nil, err
+			}
+		}
+		current := make(map[int]map[string]interface{}, len(raw))
+		currentKeys := make(map[string]int, len(raw))
+		for _, object := range raw {
+			typed, err := importObject(object, columns)
+			if err != nil {
+				return nil, err
+			}
+
+			id, _ := typed["id"].(int64)
+			current[int(id)] = typed
+			for _, key := range keys {
+				if value, ok := importKey(key, typed); ok {
+					currentKeys[value] = int(id)
+				}
+			}
+		}
+
+		matched := make(map[int]bool)
+		for i, definition := range definitions[namespace] {
+			object, err := parseImportObject(definition, columns)
+			if err != nil {
+				return nil, fmt.Errorf("%s definition %d: %w", namespace, i+1, err)
+			}
+			if value, ok := object["net_id"]; ok && value != int64(networkID) {
+				return nil, fmt.Errorf("%s definition %d: net_id is not %d", namespace, i+1, networkID)
+			}
+			object["net_id"] = int64(networkID)
+
+			var id int
+			found := false
+			if value, given := object["id"]; given {
+				id = int(value.(int64))
+				if _, found = current[id]; !found {
+					return nil, fmt.Errorf("%s definition %d: no %s %d for the network", namespace, i+1, namespace, id)
+				}
+			}
+			for _, key := range keys {
+				if found {
+					break
+				}
+				if value, defined := importKey(key, object); defined {
+					id, found = currentKeys[value]
+				}
+			}
+			if !found {
+				creations = append(creations, ImportChange{
+					Action:    ImportCreate,
+					Namespace: namespace,
+					Object:    object,
+				})
+				continue
+			}
+			if matched[id] {
+				return nil, fmt.Errorf("%s definition %d: %s %d defined twice", namespace, i+1, namespace, id)
+			}
+			matched[id] = true
+
+			if change, ok := importUpdate(namespace, id, current[id], object); ok {
+				updates = append(updates, change)
+			}
+		}
+
+		ids := make([]int, 0, len(current))
+		for id := range current {
+			if !matched[id] {
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			deletions = append(deletions, ImportChange{Action: ImportDelete, Namespace: namespace, ID: id})
+		}
+	}
+
+	return append(append(deletions, updates...), creations...), nil
+}
+
+// ApplyImport applies changes to PeeringDB, in order, using the given context
+// for the API calls. It stops at the first change which cannot be applied.
+// Applying changes requires an API key allowed to write objects of the
+// network.
+func (api *API) ApplyImport(ctx context.Context, changes []ImportChange) error {
+	for _, change := range changes {
+		var object interface{}
+		if change.Object != nil {
+			object = change.Object
+		}
+		if _, err := api.write(ctx, importMethods[change.Action], change.Namespace, change.ID, object, nil); err != nil {
+			return fmt.Errorf("%s: %w", change, err)
+		}
+	}
+
+	return nil
+}
+
+// importColumns returns the types of the fields of a namespace, indexed by
+// name.
+func importColumns(namespace string) map[string]ColumnType {
+	columns := make(map[string]ColumnType)
+	for _, column := range tableColumns(namespaceTypes[namespace]) {
+		columns[column.Name] = column.Type
+	}
+
+	return columns
+}
+
+// importObject decodes an object returned by the API, keeping its writable
+// fields with the same types as the ones of definitions.
+func importObject(object json.RawMessage, columns map[string]ColumnType) (map[string]interface{}, error) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(object, &decoded); err != nil {
+		return nil, err
+	}
+
+	typed := make(map[string]interface{}, len(columns))
+	for name, columnType := range columns {
+		if columnType == ColumnTime || columnType == ColumnJSON {
+			continue
+		}
+		if value := columnValue(decoded[name], columnType); value != nil {
+			if text, ok := value.(string); ok {
+				value = normalizeImportText(name, text)
+			}
+			typed[name] = value
+		}
+	}
+
+	return typed, nil
+}
+
+// importUpdate returns the change updating an object if some of the defined
+// fields differ from the current ones. The object sent to the API is made of
+// the current writable fields overridden by the defined ones.
+func importUpdate(namespace string, id int, current, defined map[string]interface{}) (ImportChange, bool) {
+	change := ImportChange{
+		Action:    ImportUpdate,
+		Namespace: namespace,
+		ID:        id,
+		Object:    make(map[string]interface{}, len(current)),
+	}
+
+	for name, value := range current {
+		change.Object[name] = value
+	}
+	for name, value := range defined {
+		if current[name] != value {
+			change.Changed = append(change.Changed, name)
+		}
+		change.Object[name] = value
+	}
+	sort.Strings(change.Changed)
+
+	return change, len(change.Changed) > 0
+}
+
+// importKey returns the key identifying an object among the objects of a
+// network, made of the given fields, and whether all of them are defined.
+func importKey(fields []string, object map[string]interface{}) (string, bool) {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value, ok := object[field]
+		if !ok || value == "" {
+			return "", false
+		}
+		values[i] = fmt.Sprintf("%v", value)
+	}
+
+	return strings.Join(fields, ",") + "=" + strings.Join(values, "|"), true
+}
+
+// normalizeImportText normalizes a text value so that different notations of
+// the same value are equal. Only IP addresses are normalized.
+func normalizeImportText(name, text string) string {
+	if strings.HasPrefix(name, "ipaddr") {
+		if address, err := netip.ParseAddr(strings.TrimSpace(text)); err == nil {
+			return address.String()
+		}
+	}
+
+	return text
+}
+
+// parseImportObject converts the values of a defined object to the types of
+// the fields of its namespace: int64, float64, bool or string. Values read
+// from CSV are strings and are parsed.
+func parseImportObject(definition map[string]interface{}, columns map[string]ColumnType) (map[string]interface{}, error) {
+	object := make(map[string]interface{}, len(definition))
+
+	for name, value := range definition {
+		columnType, ok := columns[name]
+		if !ok || columnType == ColumnTime || columnType == ColumnJSON {
+			return nil, fmt.Errorf("field '%s' cannot be imported", name)
+		}
+
+		text := fmt.Sprintf("%v", value)
+		var err error
+		switch columnType {
+		case ColumnInteger:
+			object[name], err = strconv.ParseInt(text, 10, 64)
+		case ColumnFloat:
+			object[name], err = strconv.ParseFloat(text, 64)
+		case ColumnBoolean:
+			switch strings.ToLower(text) {
+			case "1", "true", "t", "yes":
+				object[name] = true
+			case "0", "false", "f", "no":
+				object[name] = false
+			default:
+				err = fmt.Errorf("invalid boolean")
+			}
+		default:
+			object[name] = normalizeImportText(name, text)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for field '%s'", text, name)
+		}
+	}
+
+	return object, nil
+}
@@ -0,0 +1,100 @@
+package peeringdb
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkFacilityNamespace: {
+			{"id": 1, "net_id": 10, "fac_id": 100, "local_asn": 64500},
+			{"id": 2, "net_id": 10, "fac_id": 101, "local_asn": 64500},
+			{"id": 3, "net_id": 10, "fac_id": 102, "local_asn": 64500},
+			{"id": 4, "net_id": 11, "fac_id": 100, "local_asn": 64501},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "net_id": 10, "ixlan_id": 5, "ipaddr4": "192.0.2.1", "ipaddr6": "2001:db8::1", "speed": 10000},
+			{"id": 2, "net_id": 10, "ixlan_id": 6, "ipaddr4": "192.0.2.2", "ipaddr6": "2001:db8::2", "speed": 10000},
+		},
+	})
+	api := server.api()
+
+	definitions, err := ReadImportYAML(strings.NewReader(`
+netfac:
+  - fac_id: 100
+    local_asn: 64500
+  - fac_id: 101
+    local_asn: 64510
+  - fac_id: 103
+    local_asn: 64500
+netixlan:
+  - ixlan_id: 5
+    ipaddr6: "2001:DB8:0::1"
+    speed: 10000
+  # Matched by its IPv6 address as its IPv4 one changes
+  - ixlan_id: 6
+    ipaddr4: "192.0.2.3"
+    ipaddr6: "2001:db8::2"
+    speed: 10000
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := api.PlanImport(10, definitions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"delete netfac 3",
+		"update netfac 2: local_asn",
+		"update netixlan 2: ipaddr4",
+		"create netfac fac_id=103 local_asn=64500 net_id=10",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("PlanImport, want %d changes got %v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("PlanImport, want '%s' got '%s'", expected[i], change)
+		}
+	}
+
+	if err = api.ApplyImport(context.Background(), changes); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing left to do once applied
+	if changes, err = api.PlanImport(10, definitions); err != nil || len(changes) != 0 {
+		t.Errorf("PlanImport, want no change got %v (%v)", changes, err)
+	}
+
+	// Objects of other networks are untouched
+	if facilities, _ := api.GetNetworkFacility(map[string]interface{}{"net_id": 11}); len(*facilities) != 1 {
+		t.Errorf("ApplyImport, objects of another network changed")
+	}
+}
+
+func TestReadImportCSV(t *testing.T) {
+	definitions, err := ReadImportCSV(strings.NewReader("role,name,email,visible\nNOC,Ops,noc@example.net,Public\nAbuse,Abuse,,Public\n"),
+		networkContactNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contacts := definitions[networkContactNamespace]
+	if len(contacts) != 2 || contacts[0]["email"] != "noc@example.net" {
+		t.Errorf("ReadImportCSV, unexpected definitions: %v", definitions)
+	}
+	if _, ok := contacts[1]["email"]; ok {
+		t.Errorf("ReadImportCSV, empty values must be ignored: %v", contacts[1])
+	}
+
+	if _, err = parseImportObject(map[string]interface{}{"created": "2020-01-01"},
+		map[string]ColumnType{"created": ColumnTime}); err == nil {
+		t.Error("parseImportObject, want error for read-only field got nil")
+	}
+}
@@ -0,0 +1,97 @@
+package peeringdb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InFilter is a filter matching objects whose field value is one of several
+// values, using the "__in" operator of the API. Values are joined with commas,
+// so string values cannot contain commas.
+type InFilter struct {
+	// Field is the name of the filtered field, such as "id" or "asn".
+	Field string
+	// Values are the values of the filter, without duplicates.
+	Values []string
+}
+
+// InInts returns a filter matching objects whose field value is one of the
+// given integers, such as IDs or AS numbers:
+//
+//	networks, err := api.GetNetwork(peeringdb.InInts("id", ids).Search())
+//
+// Duplicated values are only kept once.
+func InInts(field string, values []int) InFilter {
+	filter := InFilter{Field: field, Values: make([]string, 0, len(values))}
+	seen := make(map[int]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			filter.Values = append(filter.Values, strconv.Itoa(value))
+		}
+	}
+
+	return filter
+}
+
+// InASNs returns a filter matching objects whose field value is one of the
+// given AS numbers, such as "asn" or "local_asn". Duplicated values are only
+// kept once.
+func InASNs(field string, values []ASN) InFilter {
+	return InInts(field, asnInts(values))
+}
+
+// InStrings returns a filter matching objects whose field value is one of the
+// given strings, such as country codes. Values are trimmed, empty and
+// duplicated values are ignored.
+func InStrings(field string, values []string) InFilter {
+	filter := InFilter{Field: field, Values: make([]string, 0, len(values))}
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !seen[value] {
+			seen[value] = true
+			filter.Values = append(filter.Values, value)
+		}
+	}
+
+	return filter
+}
+
+// Parameter returns the name of the search parameter of the filter, such as
+// "id__in".
+func (f InFilter) Parameter() string {
+	return f.Field + "__in"
+}
+
+// Value returns the value of the search parameter of the filter, the comma
+// separated list of values.
+func (f InFilter) Value() string {
+	return strings.Join(f.Values, ",")
+}
+
+// Search returns a search parameters map holding the filter only. If the
+// filter has too many values to fit in a single query, as told by
+// NeedsChunks, Chunks must be used instead.
+func (f InFilter) Search() map[string]interface{} {
+	return map[string]interface{}{f.Parameter(): f.Value()}
+}
+
+// NeedsChunks tells if the filter has too many values to be used in a single
+// query without hitting URL length limits.
+func (f InFilter) NeedsChunks() bool {
+	return len(f.Values) > maxIDsPerQuery
+}
+
+// Chunks returns search parameters maps splitting the values of the filter in
+// several queries small enough to be sent. The results of all queries must be
+// concatenated. There is no query if the filter has no values.
+func (f InFilter) Chunks() []map[string]interface{} {
+	var chunks []map[string]interface{}
+	for start := 0; start < len(f.Values); start += maxIDsPerQuery {
+		chunk := InFilter{Field: f.Field, Values: f.Values[start:min(start+maxIDsPerQuery, len(f.Values))]}
+		chunks = append(chunks, chunk.Search())
+	}
+
+	return chunks
+}
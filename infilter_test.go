@@ -0,0 +1,36 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestInFilter(t *testing.T) {
+	filter := InInts("id", []int{3, 1, 3, 2})
+	if search := filter.Search(); len(search) != 1 || search["id__in"] != "3,1,2" {
+		t.Errorf("InInts, unexpected search: %v", search)
+	}
+	if filter.NeedsChunks() {
+		t.Error("InInts, want no chunks for 3 values")
+	}
+
+	filter = InStrings("country", []string{" FR", "", "DE", "FR"})
+	if filter.Parameter() != "country__in" || filter.Value() != "FR,DE" {
+		t.Errorf("InStrings, unexpected filter: %s=%s", filter.Parameter(), filter.Value())
+	}
+
+	ids := make([]int, 250)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	filter = InInts("net_id", ids)
+	chunks := filter.Chunks()
+	if !filter.NeedsChunks() || len(chunks) != 3 {
+		t.Fatalf("Chunks, want 3 chunks got %d", len(chunks))
+	}
+	if chunks[2]["net_id__in"] != "201,202,203,204,205,206,207,208,209,210,211,212,213,214,215,216,217,218,219,220,221,222,223,224,225,226,227,228,229,230,231,232,233,234,235,236,237,238,239,240,241,242,243,244,245,246,247,248,249,250" {
+		t.Errorf("Chunks, unexpected last chunk: %v", chunks[2])
+	}
+	if chunks := InInts("id", nil).Chunks(); len(chunks) != 0 {
+		t.Errorf("Chunks, want no chunk without values got %v", chunks)
+	}
+}
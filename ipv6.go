@@ -0,0 +1,104 @@
+package peeringdb
+
+import "sort"
+
+// IPv6AdoptionRow gives the IPv6 adoption of the participants of an Internet
+// exchange point.
+type IPv6AdoptionRow struct {
+	InternetExchangeID int
+	Name               string
+	// Participants is the number of distinct networks connected to the IX.
+	Participants int
+	// IPv6Participants is the number of networks with at least one
+	// operational connection having an IPv6 address.
+	IPv6Participants int
+}
+
+// Ratio returns the fraction of participants using IPv6, between 0 and 1.
+func (r IPv6AdoptionRow) Ratio() float64 {
+	if r.Participants == 0 {
+		return 0
+	}
+
+	return float64(r.IPv6Participants) / float64(r.Participants)
+}
+
+// IPv6AdoptionRows is a list of IPv6 adoption rows. It implements
+// sort.Interface, rows being ordered by ratio, then by number of participants
+// and then by IX name.
+type IPv6AdoptionRows []IPv6AdoptionRow
+
+func (r IPv6AdoptionRows) Len() int      { return len(r) }
+func (r IPv6AdoptionRows) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r IPv6AdoptionRows) Less(i, j int) bool {
+	if r[i].Ratio() != r[j].Ratio() {
+		return r[i].Ratio() < r[j].Ratio()
+	}
+	if r[i].Participants != r[j].Participants {
+		return r[i].Participants < r[j].Participants
+	}
+	return r[i].Name < r[j].Name
+}
+
+// GetIPv6Adoption returns the IPv6 adoption of the Internet exchange points
+// matching the given IDs, all IXs being used if no ID is given. Rows are
+// sorted with the highest adoption first, use sort.Sort on the result to sort
+// them the other way around. IXs without participants are ignored.
+func (api *API) GetIPv6Adoption(ids ...int) (IPv6AdoptionRows, error) {
+	var (
+		ixs           []InternetExchange
+		networkIXLANs []NetworkInternetExchangeLAN
+	)
+	if len(ids) == 0 {
+		all, err := api.GetAllInternetExchanges()
+		if err != nil {
+			return nil, err
+		}
+		ixs = *all
+
+		allNetworkIXLANs, err := api.GetAllNetworkInternetExchangeLANs()
+		if err != nil {
+			return nil, err
+		}
+		networkIXLANs = *allNetworkIXLANs
+	} else {
+		var err error
+		if ixs, err = getChunked(ids, "id", api.GetInternetExchange); err != nil {
+			return nil, err
+		}
+		if networkIXLANs, err = getChunked(ids, "ix_id", api.GetNetworkInternetExchangeLAN); err != nil {
+			return nil, err
+		}
+	}
+
+	// Networks connected to each IX, true if they use IPv6
+	participants := make(map[int]map[int]bool, len(ixs))
+	for _, networkIXLAN := range networkIXLANs {
+		networks, ok := participants[networkIXLAN.InternetExchangeID]
+		if !ok {
+			networks = make(map[int]bool)
+			participants[networkIXLAN.InternetExchangeID] = networks
+		}
+		networks[networkIXLAN.NetworkID] = networks[networkIXLAN.NetworkID] ||
+			(networkIXLAN.IPAddr6 != "" && networkIXLAN.Operational)
+	}
+
+	rows := make(IPv6AdoptionRows, 0, len(ixs))
+	for _, ix := range ixs {
+		networks := participants[ix.ID]
+		if len(networks) == 0 {
+			continue
+		}
+
+		row := IPv6AdoptionRow{InternetExchangeID: ix.ID, Name: ix.Name, Participants: len(networks)}
+		for _, ipv6 := range networks {
+			if ipv6 {
+				row.IPv6Participants++
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Sort(sort.Reverse(rows))
+
+	return rows, nil
+}
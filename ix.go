@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// internetExchangeResource is the top-level structure when parsing the JSON
-// output from the API. This structure is not used if the InternetExchange JSON
-// object is included as a field in another JSON object. This structure is used
-// only if the proper namespace is queried.
-type internetExchangeResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []InternetExchange `json:"data"`
-}
-
 // InternetExchange is a structure representing an Internet exchange point. It
 // is directly linked to the Organization that manage the IX.
 type InternetExchange struct {
@@ -41,8 +30,8 @@ type InternetExchange struct {
 	PolicyPhone            string       `json:"policy_phone"`
 	SalesPhone             string       `json:"sales_phone"`
 	SalesEmail             string       `json:"sales_email"`
-	FacilitySet            []int        `json:"fac_set"`
-	InternetExchangeLANSet []int        `json:"ixlan_set"`
+	FacilitySet            IDSet        `json:"fac_set"`
+	InternetExchangeLANSet IDSet        `json:"ixlan_set"`
 	NetworkCount           int          `json:"net_count"`
 	FacilityCount          int          `json:"fac_count"`
 	IxfNetCount            int          `json:"ixf_net_count"`
@@ -61,52 +50,34 @@ type InternetExchange struct {
 	} `json:"social_media"`
 }
 
-// getInternetExchangeResource returns a pointer to an internetExchangeResource
-// structure corresponding to the API JSON response. An error can be returned
-// if something went wrong.
-func (api *API) getInternetExchangeResource(search map[string]interface{}) (*internetExchangeResource, error) {
-	// Get the InternetExchangeResource from the API
-	response, err := api.lookup(internetExchangeNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &internetExchangeResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetInternetExchange returns a pointer to a slice of InternetExchange
 // structures that the PeeringDB API can provide matching the given search
 // parameters map. If an error occurs, the returned error will be non-nil. The
-// returned value can be nil if no object could be found.
+// returned slice is empty, but never nil, if no object could be found.
 func (api *API) GetInternetExchange(search map[string]interface{}) (*[]InternetExchange, error) {
-	// Ask for the all InternetExchange objects
-	internetExchangeResource, err := api.getInternetExchangeResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetInternetExchangeWithContext(context.Background(), search)
+}
 
-	// Return all InternetExchange objects, will be nil if slice is empty
-	return &internetExchangeResource.Data, nil
+// GetInternetExchangeWithContext is the same as GetInternetExchange but uses
+// the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetInternetExchangeWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchange, error) {
+	return getObjects[InternetExchange](ctx, api, search)
 }
 
 // GetAllInternetExchanges returns a pointer to a slice of InternetExchange
 // structures that the PeeringDB API can provide. If an error occurs, the
-// returned error will be non-nil. The can be nil if no object could be found.
+// returned error will be non-nil. The returned slice is empty, but never nil,
+// if no object could be found.
 func (api *API) GetAllInternetExchanges() (*[]InternetExchange, error) {
-	// Return all InternetExchange objects
-	return api.GetInternetExchange(nil)
+	return api.GetAllInternetExchangesWithContext(context.Background())
+}
+
+// GetAllInternetExchangesWithContext is the same as GetAllInternetExchanges but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetAllInternetExchangesWithContext(ctx context.Context) (*[]InternetExchange, error) {
+	return getAllObjects[InternetExchange](ctx, api)
 }
 
 // GetInternetExchangeByID returns a pointer to a InternetExchange structure
@@ -116,42 +87,14 @@ func (api *API) GetAllInternetExchanges() (*[]InternetExchange, error) {
 // the given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetInternetExchangeByID(id int) (*InternetExchange, error) {
-	// No point of looking for the Internet exchange with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the InternetExchange given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	internetExchanges, err := api.GetInternetExchange(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No InternetExchange matching the ID
-	if len(*internetExchanges) < 1 {
-		return nil, nil
-	}
-
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*internetExchanges)[0], nil
+	return api.GetInternetExchangeByIDWithContext(context.Background(), id)
 }
 
-// internetExchangeLANResource is the top-level structure when parsing the JSON
-// output from the API. This structure is not used if the InternetExchangeLAN
-// JSON object is included as a field in another JSON object. This structure is
-// used only if the proper namespace is queried.
-type internetExchangeLANResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []InternetExchangeLAN `json:"data"`
+// GetInternetExchangeByIDWithContext is the same as GetInternetExchangeByID but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetInternetExchangeByIDWithContext(ctx context.Context, id int) (*InternetExchange, error) {
+	return getObjectByID[InternetExchange](ctx, api, id)
 }
 
 // InternetExchangeLAN is a structure representing the one of the network (LAN)
@@ -165,10 +108,10 @@ type InternetExchangeLAN struct {
 	Description                string           `json:"descr"`
 	MTU                        int              `json:"mtu"`
 	Dot1QSupport               bool             `json:"dot1q_support"`
-	RouteServerASN             int              `json:"rs_asn"`
+	RouteServerASN             ASN              `json:"rs_asn"`
 	ARPSponge                  string           `json:"arp_sponge"`
-	NetworkSet                 []int            `json:"net_set"`
-	InternetExchangePrefixSet  []int            `json:"ixpfx_set"`
+	NetworkSet                 IDSet            `json:"net_set"`
+	InternetExchangePrefixSet  IDSet            `json:"ixpfx_set"`
 	IXFIXPMemberListURL        string           `json:"ixf_ixp_member_list_url"`
 	IXFIXPMemberListURLVisible string           `json:"ixf_ixp_member_list_url_visible"`
 	IXFIXPImportEnabled        bool             `json:"ixf_ixp_import_enabled"`
@@ -177,53 +120,34 @@ type InternetExchangeLAN struct {
 	Status                     string           `json:"status"`
 }
 
-// getInternetExchangeLANResource returns a pointer to an
-// internetExchangeLANResource structure corresponding to the API JSON
-// response. An error can be returned if  something went wrong.
-func (api *API) getInternetExchangeLANResource(search map[string]interface{}) (*internetExchangeLANResource, error) {
-	// Get the InternetExchangeLANResource from the API
-	response, err := api.lookup(internetExchangeLANNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &internetExchangeLANResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetInternetExchangeLAN returns a pointer to a slice of InternetExchangeLAN
 // structures that the PeeringDB API can provide matching the given search
 // parameters map. If an error occurs, the returned error will be non-nil. The
-// returned value can be nil if no object could be found.
+// returned slice is empty, but never nil, if no object could be found.
 func (api *API) GetInternetExchangeLAN(search map[string]interface{}) (*[]InternetExchangeLAN, error) {
-	// Ask for the all InternetExchangeLAN objects
-	internetExchangeLANResource, err := api.getInternetExchangeLANResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetInternetExchangeLANWithContext(context.Background(), search)
+}
 
-	// Return all InternetExchangeLAN objects, will be nil if slice is empty
-	return &internetExchangeLANResource.Data, nil
+// GetInternetExchangeLANWithContext is the same as GetInternetExchangeLAN but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetInternetExchangeLANWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchangeLAN, error) {
+	return getObjects[InternetExchangeLAN](ctx, api, search)
 }
 
 // GetAllInternetExchangeLANs returns a pointer to a slice of
 // InternetExchangeLAN structures that the PeeringDB API can provide. If an
-// error occurs, the returned error will be non-nil. The can be nil if no
-// object could be found.
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetAllInternetExchangeLANs() (*[]InternetExchangeLAN, error) {
-	// Return all InternetExchangeLAN objects
-	return api.GetInternetExchangeLAN(nil)
+	return api.GetAllInternetExchangeLANsWithContext(context.Background())
+}
+
+// GetAllInternetExchangeLANsWithContext is the same as
+// GetAllInternetExchangeLANs but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetAllInternetExchangeLANsWithContext(ctx context.Context) (*[]InternetExchangeLAN, error) {
+	return getAllObjects[InternetExchangeLAN](ctx, api)
 }
 
 // GetInternetExchangeLANByID returns a pointer to a InternetExchangeLAN
@@ -233,42 +157,14 @@ func (api *API) GetAllInternetExchangeLANs() (*[]InternetExchangeLAN, error) {
 // object for the given ID (but it must not) only the first will be used for
 // the returned value.
 func (api *API) GetInternetExchangeLANByID(id int) (*InternetExchangeLAN, error) {
-	// No point of looking for the Internet exchange LAN with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the InternetExchangeLAN given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	ixLANs, err := api.GetInternetExchangeLAN(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No InternetExchangeLAN matching the ID
-	if len(*ixLANs) < 1 {
-		return nil, nil
-	}
-
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*ixLANs)[0], nil
+	return api.GetInternetExchangeLANByIDWithContext(context.Background(), id)
 }
 
-// internetExchangePrefixResource is the top-level structure when parsing the
-// JSON output from the API. This structure is not used if the
-// InternetExchangePrefix JSON object is included as a field in another JSON
-// object. This structure is used only if the proper namespace is queried.
-type internetExchangePrefixResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []InternetExchangePrefix `json:"data"`
+// GetInternetExchangeLANByIDWithContext is the same as
+// GetInternetExchangeLANByID but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetInternetExchangeLANByIDWithContext(ctx context.Context, id int) (*InternetExchangeLAN, error) {
+	return getObjectByID[InternetExchangeLAN](ctx, api, id)
 }
 
 // InternetExchangePrefix is a structure representing the prefix used by an
@@ -285,54 +181,35 @@ type InternetExchangePrefix struct {
 	Status                string              `json:"status"`
 }
 
-// getInternetExchangePrefixResource returns a pointer to an
-// internetExchangePrefixResource structure corresponding to the API JSON
-// response. An error can be returned if something went wrong.
-func (api *API) getInternetExchangePrefixResource(search map[string]interface{}) (*internetExchangePrefixResource, error) {
-	// Get the InternetExchangePrefixResource from the API
-	response, err := api.lookup(internetExchangePrefixNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &internetExchangePrefixResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetInternetExchangePrefix returns a pointer to a slice of
-// InternetExchangePrefix structures that the PeeringDB API can provide
-// matching the given search parameters map. If an error occurs, the returned
-// error will be non-nil. The returned value can be nil if no object could be
+// InternetExchangePrefix structures that the PeeringDB API can provide matching
+// the given search parameters map. If an error occurs, the returned error will
+// be non-nil. The returned slice is empty, but never nil, if no object could be
 // found.
 func (api *API) GetInternetExchangePrefix(search map[string]interface{}) (*[]InternetExchangePrefix, error) {
-	// Ask for the all InternetExchangePrefix objects
-	internetExchangePrefixResource, err := api.getInternetExchangePrefixResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetInternetExchangePrefixWithContext(context.Background(), search)
+}
 
-	// Return all InternetExchangePrefix objects, will be nil if slice is empty
-	return &internetExchangePrefixResource.Data, nil
+// GetInternetExchangePrefixWithContext is the same as GetInternetExchangePrefix
+// but uses the given context for the API calls, allowing to cancel them or to
+// set a deadline.
+func (api *API) GetInternetExchangePrefixWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchangePrefix, error) {
+	return getObjects[InternetExchangePrefix](ctx, api, search)
 }
 
 // GetAllInternetExchangePrefixes returns a pointer to a slice of
 // InternetExchangePrefix structures that the PeeringDB API can provide. If an
-// error occurs, the returned error will be non-nil. The can be nil if no
-// object could be found.
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetAllInternetExchangePrefixes() (*[]InternetExchangePrefix, error) {
-	// Return all InternetExchangePrefix objects
-	return api.GetInternetExchangePrefix(nil)
+	return api.GetAllInternetExchangePrefixesWithContext(context.Background())
+}
+
+// GetAllInternetExchangePrefixesWithContext is the same as
+// GetAllInternetExchangePrefixes but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetAllInternetExchangePrefixesWithContext(ctx context.Context) (*[]InternetExchangePrefix, error) {
+	return getAllObjects[InternetExchangePrefix](ctx, api)
 }
 
 // GetInternetExchangePrefixByID returns a pointer to a InternetExchangePrefix
@@ -342,42 +219,14 @@ func (api *API) GetAllInternetExchangePrefixes() (*[]InternetExchangePrefix, err
 // object for the given ID (but it must not) only the first will be used for
 // the returned value.
 func (api *API) GetInternetExchangePrefixByID(id int) (*InternetExchangePrefix, error) {
-	// No point of looking for the Internet exchange prefix with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the InternetExchangePrefix given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	ixPrefixes, err := api.GetInternetExchangePrefix(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No InternetExchangePrefix matching the ID
-	if len(*ixPrefixes) < 1 {
-		return nil, nil
-	}
-
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*ixPrefixes)[0], nil
+	return api.GetInternetExchangePrefixByIDWithContext(context.Background(), id)
 }
 
-// internetExchangeFacilityResource is the top-level structure when parsing the
-// JSON output from the API. This structure is not used if the
-// InternetExchangeFacility JSON object is included as a field in another JSON
-// object. This structure is used only if the proper namespace is queried.
-type internetExchangeFacilityResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []InternetExchangeFacility `json:"data"`
+// GetInternetExchangePrefixByIDWithContext is the same as
+// GetInternetExchangePrefixByID but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetInternetExchangePrefixByIDWithContext(ctx context.Context, id int) (*InternetExchangePrefix, error) {
+	return getObjectByID[InternetExchangePrefix](ctx, api, id)
 }
 
 // InternetExchangeFacility is a structure used to link an InternetExchange
@@ -398,55 +247,35 @@ type InternetExchangeFacility struct {
 	Status             string           `json:"status"`
 }
 
-// getInternetExchangeFacilityResource returns a pointer to an
-// internetExchangeFacilityResource structure corresponding to the API JSON
-// response. An error can be returned if something went wrong.
-func (api *API) getInternetExchangeFacilityResource(search map[string]interface{}) (*internetExchangeFacilityResource, error) {
-	// Get the InternetExchangeFacilityResource from the API
-	response, err := api.lookup(internetExchangeFacilityNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &internetExchangeFacilityResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetInternetExchangeFacility returns a pointer to a slice of
 // InternetExchangeFacility structures that the PeeringDB API can provide
 // matching the given search parameters map. If an error occurs, the returned
-// error will be non-nil. The returned value can be nil if no object could be
-// found.
+// error will be non-nil. The returned slice is empty, but never nil, if no
+// object could be found.
 func (api *API) GetInternetExchangeFacility(search map[string]interface{}) (*[]InternetExchangeFacility, error) {
-	// Ask for the all InternetExchangeFacility objects
-	internetExchangeFacilityResource, err := api.getInternetExchangeFacilityResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetInternetExchangeFacilityWithContext(context.Background(), search)
+}
 
-	// Return all InternetExchangeFacility objects, will be nil if slice is
-	// empty
-	return &internetExchangeFacilityResource.Data, nil
+// GetInternetExchangeFacilityWithContext is the same as
+// GetInternetExchangeFacility but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetInternetExchangeFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]InternetExchangeFacility, error) {
+	return getObjects[InternetExchangeFacility](ctx, api, search)
 }
 
 // GetAllInternetExchangeFacilities returns a pointer to a slice of
-// InternetExchangeFacility structures that the PeeringDB API can provide. If
-// an error occurs, the returned error will be non-nil. The can be nil if no
-// object could be found.
+// InternetExchangeFacility structures that the PeeringDB API can provide. If an
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetAllInternetExchangeFacilities() (*[]InternetExchangeFacility, error) {
-	// Return all InternetExchangeFacility objects
-	return api.GetInternetExchangeFacility(nil)
+	return api.GetAllInternetExchangeFacilitiesWithContext(context.Background())
+}
+
+// GetAllInternetExchangeFacilitiesWithContext is the same as
+// GetAllInternetExchangeFacilities but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) GetAllInternetExchangeFacilitiesWithContext(ctx context.Context) (*[]InternetExchangeFacility, error) {
+	return getAllObjects[InternetExchangeFacility](ctx, api)
 }
 
 // GetInternetExchangeFacilityByID returns a pointer to a
@@ -456,29 +285,12 @@ func (api *API) GetAllInternetExchangeFacilities() (*[]InternetExchangeFacility,
 // returns more than one object for the given ID (but it must not) only the
 // first will be used for the returned value.
 func (api *API) GetInternetExchangeFacilityByID(id int) (*InternetExchangeFacility, error) {
-	// No point of looking for the Internet exchange facility with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the InternetExchangeFacility given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	ixFacilities, err := api.GetInternetExchangeFacility(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No InternetExchangeFacility matching the ID
-	if len(*ixFacilities) < 1 {
-		return nil, nil
-	}
+	return api.GetInternetExchangeFacilityByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*ixFacilities)[0], nil
+// GetInternetExchangeFacilityByIDWithContext is the same as
+// GetInternetExchangeFacilityByID but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetInternetExchangeFacilityByIDWithContext(ctx context.Context, id int) (*InternetExchangeFacility, error) {
+	return getObjectByID[InternetExchangeFacility](ctx, api, id)
 }
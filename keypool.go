@@ -0,0 +1,268 @@
+package peeringdb
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultKeyBudgetWindow is the window of the budget of the keys of a
+	// pool if none is given.
+	defaultKeyBudgetWindow = time.Minute
+	// defaultKeyThrottle is the time a throttled key of a pool is skipped if
+	// the API does not tell how long to wait.
+	defaultKeyThrottle = time.Minute
+)
+
+// APIKeyPoolOptions is a structure used to tune an APIKeyPool.
+type APIKeyPoolOptions struct {
+	// KeyType is the type of all the keys of the pool.
+	KeyType APIKeyType
+	// Budget is the maximum number of calls made with each key within the
+	// window. Keys having used their budget are skipped while others are
+	// available. There is no budget if it is zero.
+	Budget int
+	// Window is the duration over which the budget of each key applies, a
+	// minute if it is zero.
+	Window time.Duration
+	// Throttle is the time a key is skipped once the API rate limit is
+	// exceeded with it, when the API does not tell how long to wait. It is a
+	// minute if it is zero.
+	Throttle time.Duration
+}
+
+// APIKeyStats is a structure describing the use of a key of an APIKeyPool.
+type APIKeyStats struct {
+	// Key is the API key, masked so that it can be logged.
+	Key string
+	// Calls is the number of calls made with the key within the budget
+	// window.
+	Calls int
+	// ThrottledUntil is the time until which the key is skipped because the
+	// API rate limit was exceeded with it, it is zero if it is not
+	// throttled.
+	ThrottledUntil time.Time
+	// Rejected tells if the API rejected the key as invalid, in which case it
+	// is not used anymore.
+	Rejected bool
+}
+
+// pooledAPIKey is a key of an APIKeyPool along with its use.
+type pooledAPIKey struct {
+	key            string
+	calls          []time.Time
+	throttledUntil time.Time
+	rejected       bool
+}
+
+// APIKeyPool is a pool of API keys, such as the ones of several integration
+// accounts of an organization, used in turn to authenticate API calls. A key
+// is skipped while the API rate limit is exceeded with it, or while it used
+// its budget, and is not used anymore if the API rejects it. An APIKeyPool is
+// safe for concurrent use.
+type APIKeyPool struct {
+	options APIKeyPoolOptions
+	now     func() time.Time
+
+	mutex sync.Mutex
+	keys  []*pooledAPIKey
+	next  int
+}
+
+// NewAPIKeyPool returns a pointer to a new APIKeyPool holding the given keys,
+// tuned with the given options. Empty and duplicated keys are ignored.
+func NewAPIKeyPool(keys []string, options APIKeyPoolOptions) *APIKeyPool {
+	if options.Window <= 0 {
+		options.Window = defaultKeyBudgetWindow
+	}
+	if options.Throttle <= 0 {
+		options.Throttle = defaultKeyThrottle
+	}
+
+	pool := &APIKeyPool{options: options, now: time.Now}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			pool.keys = append(pool.keys, &pooledAPIKey{key: key})
+		}
+	}
+
+	return pool
+}
+
+// WithAPIKeyPool returns an option authenticating API calls with the keys of
+// the given pool, in turn. Lookups failing because the rate limit is exceeded,
+// or because the key is rejected, are retried right away with another key of
+// the pool if one is available. A key given with WithAuth still takes
+// precedence for calls made with its context.
+func WithAPIKeyPool(pool *APIKeyPool) Option {
+	return func(api *API) {
+		api.keyPool = pool
+	}
+}
+
+// Stats returns the use of each key of the pool, in the order they were
+// given.
+func (p *APIKeyPool) Stats() []APIKeyStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	stats := make([]APIKeyStats, len(p.keys))
+	for i, key := range p.keys {
+		p.expire(key, now)
+		stats[i] = APIKeyStats{
+			Key:      maskAPIKey(key.key),
+			Calls:    len(key.calls),
+			Rejected: key.rejected,
+		}
+		if key.throttledUntil.After(now) {
+			stats[i].ThrottledUntil = key.throttledUntil
+		}
+	}
+
+	return stats
+}
+
+// expire forgets the calls made with a key before the budget window.
+func (p *APIKeyPool) expire(key *pooledAPIKey, now time.Time) {
+	kept := key.calls[:0]
+	for _, call := range key.calls {
+		if now.Sub(call) < p.options.Window {
+			kept = append(kept, call)
+		}
+	}
+	key.calls = kept
+}
+
+// usable tells if a key can be used now.
+func (p *APIKeyPool) usable(key *pooledAPIKey, now time.Time) bool {
+	p.expire(key, now)
+	return !key.rejected && !key.throttledUntil.After(now) &&
+		(p.options.Budget <= 0 || len(key.calls) < p.options.Budget)
+}
+
+// pick returns the index of the key to use for the next call. Keys are tried
+// in turn, if none is usable the one throttled for the shortest time is used.
+// It returns -1 if all keys are rejected.
+func (p *APIKeyPool) pick(now time.Time) int {
+	for i := range p.keys {
+		index := (p.next + i) % len(p.keys)
+		if p.usable(p.keys[index], now) {
+			return index
+		}
+	}
+
+	best := -1
+	for i, key := range p.keys {
+		if !key.rejected && (best < 0 || key.throttledUntil.Before(p.keys[best].throttledUntil)) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// credentials returns the key to use for the next call, and its type. It
+// returns an empty key if the pool has no usable key.
+func (p *APIKeyPool) credentials() (string, APIKeyType) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if index := p.pick(p.now()); index >= 0 {
+		return p.keys[index].key, p.options.KeyType
+	}
+
+	return "", p.options.KeyType
+}
+
+// used records a call made with the given key, the next call then uses the
+// following key.
+func (p *APIKeyPool) used(apiKey string) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i, key := range p.keys {
+		if key.key == apiKey {
+			key.calls = append(key.calls, p.now())
+			p.next = (i + 1) % len(p.keys)
+			return
+		}
+	}
+}
+
+// report records the outcome of a call made with the given key, throttling
+// the key if the rate limit is exceeded and rejecting it if it is invalid.
+func (p *APIKeyPool) report(apiKey string, err error) {
+	if p == nil || err == nil {
+		return
+	}
+
+	var rateLimit *RateLimitError
+	var apiError *APIError
+	rateLimited := errors.As(err, &rateLimit)
+	rejected := errors.As(err, &apiError) && apiError.StatusCode == http.StatusUnauthorized
+	if !rateLimited && !rejected {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, key := range p.keys {
+		if key.key != apiKey {
+			continue
+		}
+		if rejected {
+			key.rejected = true
+			continue
+		}
+		wait := rateLimit.RetryAfter
+		if wait <= 0 {
+			wait = p.options.Throttle
+		}
+		key.throttledUntil = p.now().Add(wait)
+	}
+}
+
+// rotate tells if a lookup failing with the given error can be retried right
+// away with another key.
+func (p *APIKeyPool) rotate(err error) bool {
+	if p == nil {
+		return false
+	}
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || (!apiError.RateLimited() && apiError.StatusCode != http.StatusUnauthorized) {
+		return false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	for _, key := range p.keys {
+		if p.usable(key, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maskAPIKey returns an API key with all but its first characters hidden.
+func maskAPIKey(key string) string {
+	const visible = 4
+	if len(key) <= visible {
+		return "****"
+	}
+
+	return key[:visible] + "****"
+}
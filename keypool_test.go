@@ -0,0 +1,117 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyPool(t *testing.T) {
+	var mutex sync.Mutex
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Api-Key ")
+		mutex.Lock()
+		used = append(used, key)
+		mutex.Unlock()
+
+		switch key {
+		case "throttled":
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, `{"meta": {"error": "Request was throttled."}}`, http.StatusTooManyRequests)
+		case "invalid":
+			http.Error(w, `{"meta": {"error": "Invalid API key."}}`, http.StatusUnauthorized)
+		default:
+			w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+		}
+	}))
+	defer server.Close()
+	usedKeys := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		keys := used
+		used = nil
+		return keys
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewAPIKeyPool([]string{"throttled", "invalid", "valid-key", ""}, APIKeyPoolOptions{})
+	pool.now = func() time.Time { return now }
+	api := NewAPIFromURL(server.URL+"/api/", WithAPIKeyPool(pool))
+
+	// The throttled and the invalid keys are skipped for the valid one
+	if network, err := api.GetNetworkByID(1); err != nil || network == nil {
+		t.Fatalf("GetNetworkByID, want a network got %v", err)
+	}
+	if keys := strings.Join(usedKeys(), ","); keys != "throttled,invalid,valid-key" {
+		t.Errorf("GetNetworkByID, unexpected keys used: %s", keys)
+	}
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if keys := strings.Join(usedKeys(), ","); keys != "valid-key" {
+		t.Errorf("GetNetworkByID, want only the valid key used got %s", keys)
+	}
+
+	stats := pool.Stats()
+	if len(stats) != 3 {
+		t.Fatalf("Stats, want 3 keys got %d", len(stats))
+	}
+	if stats[0].Key != "thro****" || !stats[0].ThrottledUntil.Equal(now.Add(30*time.Second)) {
+		t.Errorf("Stats, want throttled key got %+v", stats[0])
+	}
+	if !stats[1].Rejected || stats[2].Calls != 2 || stats[2].Rejected {
+		t.Errorf("Stats, unexpected stats %+v", stats)
+	}
+
+	// Once the rate limit is lifted, keys are used in turn
+	now = now.Add(time.Minute)
+	pool = NewAPIKeyPool([]string{"a", "b"}, APIKeyPoolOptions{Budget: 2})
+	pool.now = func() time.Time { return now }
+	api = NewAPIFromURL(server.URL+"/api/", WithAPIKeyPool(pool))
+	for i := 0; i < 3; i++ {
+		if _, err := api.GetNetworkByID(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if keys := strings.Join(usedKeys(), ","); keys != "a,b,a" {
+		t.Errorf("GetNetworkByID, want keys used in turn got %s", keys)
+	}
+
+	// A key carried by the context takes precedence and is not rotated
+	pool = NewAPIKeyPool([]string{"valid-key"}, APIKeyPoolOptions{})
+	api = NewAPIFromURL(server.URL+"/api/", WithAPIKeyPool(pool))
+	_, err := api.GetNetworkByIDWithContext(WithAuth(context.Background(), "throttled"), 1)
+	if !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("GetNetworkByIDWithContext, want ErrRateLimitExceeded got %v", err)
+	}
+	if keys := strings.Join(usedKeys(), ","); keys != "throttled" {
+		t.Errorf("GetNetworkByIDWithContext, want only the context key used got %s", keys)
+	}
+}
+
+func TestAPIKeyPoolBudget(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewAPIKeyPool([]string{"a", "b"}, APIKeyPoolOptions{Budget: 1, Window: time.Minute})
+	pool.now = func() time.Time { return now }
+
+	// The first key used its budget within the window, while the calls of
+	// the second one expired
+	pool.used("a")
+	pool.used("b")
+	now = now.Add(30 * time.Second)
+	pool.used("a")
+	pool.used("a")
+	now = now.Add(45 * time.Second)
+	if key, _ := pool.credentials(); key != "b" {
+		t.Errorf("credentials, want key b got %s", key)
+	}
+	if stats := pool.Stats(); stats[0].Calls != 2 || stats[1].Calls != 0 {
+		t.Errorf("Stats, want calls expired got %+v", stats)
+	}
+}
@@ -0,0 +1,212 @@
+package peeringdb
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultLayeredCacheSize is the number of query results kept in memory by a
+// LayeredClient if no size is set.
+const defaultLayeredCacheSize = 1024
+
+// FreshnessPolicy is a structure telling a LayeredClient how fresh the data of
+// a namespace must be.
+type FreshnessPolicy struct {
+	// CacheTTL is the duration for which query results are kept in memory.
+	// Results are not cached if it is zero.
+	CacheTTL time.Duration
+	// MirrorMaxAge is the maximum duration since the last synchronization of
+	// the mirror for it to be used. The mirror is used whatever its age if it
+	// is zero.
+	MirrorMaxAge time.Duration
+	// SkipMirror disables the use of the mirror for the namespace.
+	SkipMirror bool
+	// MirrorOnError allows to use the mirror, even if it is too old, when the
+	// live API returns an error.
+	MirrorOnError bool
+}
+
+// LayeredClient is a Client combining the available sources of PeeringDB
+// objects. For each query, it first looks in its in-memory cache, then in the
+// mirror and finally asks the live API, according to the freshness policy of
+// the queried namespace. The least recently used query results are evicted
+// from the cache once it is full.
+type LayeredClient struct {
+	sourceClient
+
+	api    *API
+	mirror *Mirror
+	now    func() time.Time
+
+	mutex         sync.Mutex
+	defaultPolicy FreshnessPolicy
+	policies      map[string]FreshnessPolicy
+	cacheSize     int
+	cache         map[string]*list.Element
+	order         *list.List
+}
+
+// layeredEntry is a query result kept in memory.
+type layeredEntry struct {
+	key        string
+	objects    []json.RawMessage
+	provenance Provenance
+	expires    time.Time
+}
+
+// NewLayeredClient returns a pointer to a new LayeredClient using the given
+// API and mirror. The mirror can be nil if there is none. The given policy
+// applies to all namespaces unless a specific one is set with SetPolicy.
+func NewLayeredClient(api *API, mirror *Mirror, policy FreshnessPolicy) *LayeredClient {
+	l := &LayeredClient{
+		api:           api,
+		mirror:        mirror,
+		now:           time.Now,
+		defaultPolicy: policy,
+		policies:      make(map[string]FreshnessPolicy),
+		cacheSize:     defaultLayeredCacheSize,
+		cache:         make(map[string]*list.Element),
+		order:         list.New(),
+	}
+	l.sourceClient = sourceClient{l}
+
+	return l
+}
+
+// SetPolicy sets the freshness policy to use for the given namespace.
+func (l *LayeredClient) SetPolicy(namespace string, policy FreshnessPolicy) {
+	l.mutex.Lock()
+	l.policies[namespace] = policy
+	l.mutex.Unlock()
+}
+
+// SetCacheSize sets the number of query results kept in memory, 1024 by
+// default or if size is zero. The least recently used results are evicted if
+// more are kept.
+func (l *LayeredClient) SetCacheSize(size int) {
+	if size <= 0 {
+		size = defaultLayeredCacheSize
+	}
+
+	l.mutex.Lock()
+	l.cacheSize = size
+	l.evict()
+	l.mutex.Unlock()
+}
+
+// Invalidate removes all query results kept in memory.
+func (l *LayeredClient) Invalidate() {
+	l.mutex.Lock()
+	l.cache = make(map[string]*list.Element)
+	l.order.Init()
+	l.mutex.Unlock()
+}
+
+// evict removes the least recently used query results kept in memory until
+// there are no more than the size of the cache. The mutex must be held.
+func (l *LayeredClient) evict() {
+	for l.order.Len() > l.cacheSize {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.cache, oldest.Value.(*layeredEntry).key)
+	}
+}
+
+// policy returns the freshness policy of a namespace.
+func (l *LayeredClient) policy(namespace string) FreshnessPolicy {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	policy, ok := l.policies[namespace]
+	if !ok {
+		return l.defaultPolicy
+	}
+
+	return policy
+}
+
+// getRaw returns the objects of a namespace matching the given search
+// parameters map, from the first source able to provide them.
+func (l *LayeredClient) getRaw(namespace string, search map[string]interface{}) ([]json.RawMessage, error) {
+	objects, _, err := l.getRawWithProvenance(namespace, search)
+	return objects, err
+}
+
+// getRawWithProvenance returns the objects of a namespace matching the given
+// search parameters map, from the first source able to provide them, along
+// with their provenance.
+func (l *LayeredClient) getRawWithProvenance(namespace string, search map[string]interface{}) ([]json.RawMessage, Provenance, error) {
+	policy := l.policy(namespace)
+	key := namespace + "?" + formatSearchParameters(search)
+	now := l.now()
+
+	// Look in the cache first
+	if policy.CacheTTL > 0 {
+		var entry *layeredEntry
+		l.mutex.Lock()
+		if element, ok := l.cache[key]; ok {
+			entry = element.Value.(*layeredEntry)
+			if now.After(entry.expires) {
+				l.order.Remove(element)
+				delete(l.cache, key)
+				entry = nil
+			} else {
+				l.order.MoveToFront(element)
+			}
+		}
+		l.mutex.Unlock()
+
+		if entry != nil {
+			provenance := entry.provenance
+			provenance.Source = SourceMemoryCache
+			return entry.objects, provenance, nil
+		}
+	}
+
+	objects, provenance, err := l.getUncached(namespace, search, policy, now)
+	if err != nil {
+		return nil, provenance, err
+	}
+
+	if policy.CacheTTL > 0 {
+		entry := &layeredEntry{key: key, objects: objects, provenance: provenance, expires: now.Add(policy.CacheTTL)}
+		l.mutex.Lock()
+		if element, ok := l.cache[key]; ok {
+			element.Value = entry
+			l.order.MoveToFront(element)
+		} else {
+			l.cache[key] = l.order.PushFront(entry)
+			l.evict()
+		}
+		l.mutex.Unlock()
+	}
+
+	return objects, provenance, nil
+}
+
+// getUncached returns the objects of a namespace from the mirror if it is
+// fresh enough, from the live API otherwise.
+func (l *LayeredClient) getUncached(namespace string, search map[string]interface{}, policy FreshnessPolicy, now time.Time) ([]json.RawMessage, Provenance, error) {
+	mirrored := false
+	if l.mirror != nil && !policy.SkipMirror {
+		state, ok := l.mirror.State(namespace)
+		mirrored = ok && state.Complete
+
+		if mirrored && (policy.MirrorMaxAge == 0 || now.Sub(state.LastSync) <= policy.MirrorMaxAge) {
+			objects, provenance, err := l.mirror.getRawWithProvenance(namespace, search)
+			if !errors.Is(err, ErrNamespaceNotMirrored) {
+				return objects, provenance, err
+			}
+		}
+	}
+
+	objects, provenance, err := l.api.getRawWithProvenance(namespace, search)
+	if err != nil && mirrored && policy.MirrorOnError {
+		return l.mirror.getRawWithProvenance(namespace, search)
+	}
+
+	return objects, provenance, err
+}
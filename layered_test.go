@@ -0,0 +1,98 @@
+package peeringdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayeredClient(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace:  {{"id": 1, "asn": 64500, "name": "Network A"}},
+		facilityNamespace: {{"id": 1, "name": "Facility A"}},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewLayeredClient(server.api(), mirror, FreshnessPolicy{MirrorMaxAge: time.Hour})
+	client.SetPolicy(facilityNamespace, FreshnessPolicy{CacheTTL: time.Minute})
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	// Networks come from the mirror while it is fresh enough
+	before := server.count(networkNamespace)
+	if _, err = client.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if count := server.count(networkNamespace); count != before {
+		t.Errorf("GetNetworkByID, want no API call got %d", count-before)
+	}
+
+	// And from the API once it is too old
+	now = now.Add(2 * time.Hour)
+	if _, err = client.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if count := server.count(networkNamespace); count != before+1 {
+		t.Errorf("GetNetworkByID, want 1 API call got %d", count-before)
+	}
+
+	// Facilities are not mirrored, but cached
+	for i := 0; i < 3; i++ {
+		facility, err := client.GetFacilityByID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if facility.Name != "Facility A" {
+			t.Errorf("GetFacilityByID, want 'Facility A' got '%s'", facility.Name)
+		}
+	}
+	if count := server.count(facilityNamespace); count != 1 {
+		t.Errorf("GetFacilityByID, want 1 API call got %d", count)
+	}
+
+	now = now.Add(2 * time.Minute)
+	client.GetFacilityByID(1)
+	if count := server.count(facilityNamespace); count != 2 {
+		t.Errorf("GetFacilityByID, want 2 API calls got %d", count)
+	}
+}
+
+func TestLayeredClientCacheSize(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		facilityNamespace: {
+			{"id": 1, "name": "Facility A"},
+			{"id": 2, "name": "Facility B"},
+			{"id": 3, "name": "Facility C"},
+		},
+	})
+	client := NewLayeredClient(server.api(), nil, FreshnessPolicy{CacheTTL: time.Hour})
+	client.SetCacheSize(2)
+
+	// Facility 2 is the least recently used once facility 1 is looked up
+	// again, it is evicted to keep facility 3
+	for _, id := range []int{1, 2, 1, 3} {
+		if _, err := client.GetFacilityByID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(client.cache) != 2 || client.order.Len() != 2 {
+		t.Errorf("LayeredClient, want 2 cached results got %d", len(client.cache))
+	}
+
+	before := server.count(facilityNamespace)
+	for _, id := range []int{1, 3} {
+		client.GetFacilityByID(id)
+	}
+	if count := server.count(facilityNamespace); count != before {
+		t.Errorf("GetFacilityByID, want no API call got %d", count-before)
+	}
+	client.GetFacilityByID(2)
+	if count := server.count(facilityNamespace); count != before+1 {
+		t.Errorf("GetFacilityByID, want 1 API call got %d", count-before)
+	}
+}
@@ -0,0 +1,99 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrServiceClosed is the error that will be returned when running a
+	// Service which was closed.
+	ErrServiceClosed = errors.New("service closed")
+	// ErrServiceRunning is the error that will be returned when running a
+	// Service which is already running.
+	ErrServiceRunning = errors.New("service already running")
+)
+
+// Service is the interface implemented by the long-running components of
+// this package, such as MirrorSyncer, so that embedders can manage all of
+// them the same way.
+//
+// Run does the work of the service until the given context is done or Close
+// is called. Work in progress is then drained, finished rather than
+// abandoned, before Run returns nil. Close stops the service and waits for Run
+// to return, a closed service cannot be run again.
+type Service interface {
+	Run(ctx context.Context) error
+	Close() error
+}
+
+// lifecycle implements the Run and Close mechanics shared by services.
+type lifecycle struct {
+	mutex   sync.Mutex
+	running bool
+	closed  bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// init creates the channel closed by Close, the mutex must be held.
+func (l *lifecycle) init() {
+	if l.stop == nil {
+		l.stop = make(chan struct{})
+	}
+}
+
+// start marks the service as running. The returned context is done once the
+// given one is or once Close is called. The returned function must be called
+// when Run returns.
+func (l *lifecycle) start(ctx context.Context) (context.Context, func(), error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return nil, nil, ErrServiceClosed
+	}
+	if l.running {
+		return nil, nil, ErrServiceRunning
+	}
+	l.init()
+	l.running = true
+	l.done = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop, done := l.stop, l.done
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		cancel()
+		l.mutex.Lock()
+		l.running = false
+		l.mutex.Unlock()
+		close(done)
+	}, nil
+}
+
+// close stops the service and waits for Run to return if it is running.
+func (l *lifecycle) close() {
+	l.mutex.Lock()
+	if l.closed {
+		l.mutex.Unlock()
+		return
+	}
+	l.init()
+	l.closed = true
+	close(l.stop)
+	running, done := l.running, l.done
+	l.mutex.Unlock()
+
+	if running {
+		<-done
+	}
+}
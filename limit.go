@@ -0,0 +1,89 @@
+package peeringdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrTruncated is the error that will be returned if a query matches more
+// objects than the maximum number of results allowed. Errors returned in such
+// a case are TruncatedError values which can be checked with errors.Is.
+var ErrTruncated = errors.New("results truncated")
+
+// TruncatedError is the error returned when a query matches more objects than
+// the maximum number of results allowed, instead of returning partial data.
+type TruncatedError struct {
+	// Namespace is the namespace of the queried objects.
+	Namespace string
+	// Max is the maximum number of results allowed.
+	Max int
+	// Count is the number of objects actually fetched, more than the
+	// maximum. Fetching stops once the maximum is exceeded, so more objects
+	// may match the query.
+	Count int
+}
+
+// Error returns a message telling how many objects were fetched.
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("%s: %d %s objects fetched, more than %d", ErrTruncated, e.Count, e.Namespace, e.Max)
+}
+
+// Is tells if the target is ErrTruncated.
+func (e *TruncatedError) Is(target error) bool {
+	return target == ErrTruncated
+}
+
+// WithMaxResults returns an option setting the maximum number of objects a
+// query can return. Queries matching more objects fail with a TruncatedError.
+// Queries giving their own "limit" search parameter, such as paginated ones,
+// are not affected. A value of 0 means no maximum.
+func WithMaxResults(max int) Option {
+	return func(api *API) {
+		api.maxResults = max
+	}
+}
+
+// limitSearch returns the search parameters to use for a query given the
+// maximum number of results, and whether the results must be checked. One
+// more object than the maximum is asked to know if there are more.
+func (api *API) limitSearch(search map[string]interface{}) (map[string]interface{}, bool) {
+	if api.maxResults <= 0 {
+		return search, false
+	}
+	if _, ok := search["limit"]; ok {
+		return search, false
+	}
+
+	limited := make(map[string]interface{}, len(search)+1)
+	for key, value := range search {
+		limited[key] = value
+	}
+	limited["limit"] = api.maxResults + 1
+
+	return limited, true
+}
+
+// checkTruncated returns a TruncatedError if the response holds more objects
+// than the maximum number of results. The response body is kept readable.
+func (api *API) checkTruncated(namespace string, response *http.Response) error {
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	resource := &rawResource{}
+	if err = json.Unmarshal(body, resource); err != nil {
+		// Let the caller report decoding errors
+		return nil
+	}
+	if len(resource.Data) > api.maxResults {
+		return &TruncatedError{Namespace: namespace, Max: api.maxResults, Count: len(resource.Data)}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithMaxResults(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+			{"id": 2, "asn": 64501},
+			{"id": 3, "asn": 64502},
+		},
+	})
+
+	api := server.api(WithMaxResults(2))
+	_, err := api.GetAllNetworks()
+	var truncated *TruncatedError
+	if !errors.Is(err, ErrTruncated) || !errors.As(err, &truncated) || truncated.Max != 2 || truncated.Count != 3 {
+		t.Fatalf("GetAllNetworks, want ErrTruncated got %v", err)
+	}
+
+	// Queries within the maximum and paginated queries are not affected
+	if networks, err := api.GetNetwork(map[string]interface{}{"asn__in": "64500,64501"}); err != nil || len(*networks) != 2 {
+		t.Errorf("GetNetwork, want 2 networks got %v", err)
+	}
+	if networks, err := api.GetNetwork(map[string]interface{}{"limit": 3}); err != nil || len(*networks) != 3 {
+		t.Errorf("GetNetwork, want 3 networks got %v", err)
+	}
+
+	api = server.api(WithMaxResults(3))
+	if networks, err := api.GetAllNetworks(); err != nil || len(*networks) != 3 {
+		t.Errorf("GetAllNetworks, want 3 networks got %v", err)
+	}
+}
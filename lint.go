@@ -0,0 +1,172 @@
+package peeringdb
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LintSeverity tells how important a LintFinding is.
+type LintSeverity string
+
+const (
+	// LintWarning is used for findings that should be looked at.
+	LintWarning LintSeverity = "warning"
+	// LintError is used for findings that are most likely mistakes.
+	LintError LintSeverity = "error"
+
+	// defaultContactMaxAge is the age after which contacts are considered
+	// stale if no other value is given.
+	defaultContactMaxAge = 365 * 24 * time.Hour
+)
+
+// irrASSetItem matches a valid item of an IRR AS-SET field: an optional
+// source followed by an AS number or an AS-SET name. Hierarchical names, as
+// defined by RFC 2622, are made of AS numbers and AS-SET names separated by
+// colons, at least one of them being an AS-SET name.
+var irrASSetItem = regexp.MustCompile(`(?i)^([a-z0-9-]+::)?(as[0-9]+|(as[0-9]+:)*as-[a-z0-9_-]+(:(as[0-9]+|as-[a-z0-9_-]+))*)$`)
+
+// LintFinding is a structure describing an issue found in a PeeringDB object
+// while auditing it.
+type LintFinding struct {
+	Namespace string
+	ID        int
+	Field     string
+	Severity  LintSeverity
+	Message   string
+}
+
+// String returns a human readable representation of the finding.
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s/%d %s: %s", f.Severity, f.Namespace, f.ID, f.Field, f.Message)
+}
+
+// LintOptions is a structure used to tune the checks made by
+// LintOrganization.
+type LintOptions struct {
+	// ContactMaxAge is the age after which the contacts of a network are
+	// considered stale. A year is used if it is not set.
+	ContactMaxAge time.Duration
+	// Now is the time used as reference to compute ages. The current time
+	// is used if it is not set.
+	Now time.Time
+}
+
+// LintOrganization audits the organization matching the given ID and the
+// objects it owns. It returns the list of findings, sorted by namespace and
+// ID, which is empty if nothing suspicious has been found. Contacts are only
+// visible to authenticated users, so NOC contacts can only be checked
+// reliably if an API key is used.
+func (api *API) LintOrganization(id int, options LintOptions) ([]LintFinding, error) {
+	if options.ContactMaxAge == 0 {
+		options.ContactMaxAge = defaultContactMaxAge
+	}
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+
+	organization, err := api.GetOrganizationByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, fmt.Errorf("no organization found for ID %d", id)
+	}
+
+	findings := []LintFinding{}
+	if organization.Website == "" {
+		findings = append(findings, LintFinding{
+			Namespace: organizationNamespace,
+			ID:        organization.ID,
+			Field:     "website",
+			Severity:  LintWarning,
+			Message:   "no website set",
+		})
+	}
+
+	networks, err := getChunked([]int{organization.ID}, "org_id", api.GetNetwork)
+	if err != nil {
+		return nil, err
+	}
+	networkIDs := make([]int, len(networks))
+	for i, network := range networks {
+		networkIDs[i] = network.ID
+	}
+
+	networkIXLANs, err := getChunked(networkIDs, "net_id", api.GetNetworkInternetExchangeLAN)
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := getChunked(networkIDs, "net_id", api.GetNetworkContact)
+	if err != nil {
+		return nil, err
+	}
+	nocs := make(map[int]bool)
+	for _, contact := range contacts {
+		if strings.EqualFold(contact.Role, "NOC") {
+			nocs[contact.NetworkID] = true
+		}
+	}
+
+	for _, network := range networks {
+		findings = append(findings, lintNetwork(network, nocs[network.ID], options)...)
+	}
+	for _, networkIXLAN := range networkIXLANs {
+		if networkIXLAN.IPAddr6 == "" {
+			findings = append(findings, LintFinding{
+				Namespace: networkInternetExchangeLANNamespace,
+				ID:        networkIXLAN.ID,
+				Field:     "ipaddr6",
+				Severity:  LintWarning,
+				Message:   fmt.Sprintf("no IPv6 address on %s", networkIXLAN.Name),
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Namespace != findings[j].Namespace {
+			return findings[i].Namespace < findings[j].Namespace
+		}
+		return findings[i].ID < findings[j].ID
+	})
+
+	return findings, nil
+}
+
+// lintNetwork audits a single network.
+func lintNetwork(network Network, hasNOC bool, options LintOptions) []LintFinding {
+	var findings []LintFinding
+
+	finding := func(field string, severity LintSeverity, message string) {
+		findings = append(findings, LintFinding{
+			Namespace: networkNamespace,
+			ID:        network.ID,
+			Field:     field,
+			Severity:  severity,
+			Message:   message,
+		})
+	}
+
+	if network.Website == "" {
+		finding("website", LintWarning, "no website set")
+	}
+	if !hasNOC {
+		finding("poc_set", LintWarning, "no NOC contact found")
+	}
+	if !network.NetworkContactUpdated.IsZero() && options.Now.Sub(network.NetworkContactUpdated) > options.ContactMaxAge {
+		finding("poc_updated", LintWarning,
+			fmt.Sprintf("contacts not updated since %s", network.NetworkContactUpdated.Format("2006-01-02")))
+	}
+	if network.ASNLapsed() {
+		finding("rir_status", LintError, fmt.Sprintf("AS%d is not registered anymore (RIR status '%s')", network.ASN, network.RIRStatus))
+	}
+	for _, item := range strings.Fields(network.IRRASSet) {
+		if !irrASSetItem.MatchString(item) {
+			finding("irr_as_set", LintError, fmt.Sprintf("'%s' is not a valid AS-SET or AS number", item))
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,66 @@
+package peeringdb
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIRRASSetItem(t *testing.T) {
+	valid := []string{
+		"AS-FOO", "RIPE::AS-FOO", "AS65536", "as-foo-bar", "AS65536:AS-CUSTOMERS", "ARIN::AS65536:AS-FOO",
+		"AS-FOO:AS-BAR", "AS65000:AS-CUSTOMERS:AS65001", "RIPE::AS-FOO:AS65000",
+	}
+	for _, item := range valid {
+		if !irrASSetItem.MatchString(item) {
+			t.Errorf("irrASSetItem, want '%s' to be valid", item)
+		}
+	}
+
+	invalid := []string{"FOO", "AS-", "RIPE:AS-FOO", "AS 65536", "AS-FOO,AS-BAR", "AS65000:AS65001", "AS-FOO:", "AS-FOO:BAR"}
+	for _, item := range invalid {
+		if irrASSetItem.MatchString(item) {
+			t.Errorf("irrASSetItem, want '%s' to be invalid", item)
+		}
+	}
+}
+
+func TestLintOrganization(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		organizationNamespace: {{"id": 1, "name": "Org", "website": "https://example.com"}},
+		networkNamespace: {
+			{"id": 10, "org_id": 1, "asn": 64500, "irr_as_set": "RIPE::AS-FOO", "website": "https://example.com", "poc_updated": "2020-01-01T00:00:00Z"},
+			{"id": 11, "org_id": 1, "asn": 64501, "irr_as_set": "AS-BAR,AS-BAZ", "rir_status": "available"},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 100, "net_id": 10, "ipaddr4": "192.0.2.1", "ipaddr6": "2001:db8::1"},
+			{"id": 101, "net_id": 11, "ipaddr4": "192.0.2.2"},
+		},
+		networkContactNamespace: {{"id": 1000, "net_id": 10, "role": "NOC"}},
+	})
+
+	findings, err := server.api().LintOrganization(1, LintOptions{
+		Now: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]bool{
+		"net/10/poc_updated":   true,
+		"net/11/website":       true,
+		"net/11/poc_set":       true,
+		"net/11/irr_as_set":    true,
+		"net/11/rir_status":    true,
+		"netixlan/101/ipaddr6": true,
+	}
+	if len(findings) != len(expected) {
+		t.Errorf("LintOrganization, want %d findings got %d: %v", len(expected), len(findings), findings)
+	}
+	for _, finding := range findings {
+		key := finding.Namespace + "/" + strconv.Itoa(finding.ID) + "/" + finding.Field
+		if !expected[key] {
+			t.Errorf("LintOrganization, unexpected finding %s", finding)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package peeringdb
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+)
+
+// WithLogger returns an option logging each API call with the given logger.
+// Successful calls are logged at the debug level and failed ones at the warn
+// level, with the namespace, the parameters of the query, the duration, the
+// status code and the number of retries of the call, which helps tracking
+// rate limit issues and slow queries. API keys are never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(api *API) {
+		api.responseHooks = append(api.responseHooks, func(info ResponseInfo) {
+			logResponse(logger, info)
+		})
+	}
+}
+
+// logResponse logs an API call described by the given information.
+func logResponse(logger *slog.Logger, info ResponseInfo) {
+	level := slog.LevelDebug
+	if info.Err != nil {
+		level = slog.LevelWarn
+	}
+	if logger == nil || !logger.Enabled(context.Background(), level) {
+		return
+	}
+
+	parameters := ""
+	if u, err := url.Parse(info.URL); err == nil {
+		parameters = u.RawQuery
+		if unescaped, err := url.QueryUnescape(parameters); err == nil {
+			parameters = unescaped
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("namespace", info.Namespace),
+		slog.String("parameters", parameters),
+		slog.Duration("duration", info.Duration),
+		slog.Int("status", info.StatusCode),
+		slog.Int("retry", info.Retry),
+		slog.Int64("size", info.Size),
+	}
+	if info.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", info.RequestID))
+	}
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", info.Err.Error()))
+	}
+
+	logger.LogAttrs(context.Background(), level, "peeringdb api call", attrs...)
+}
@@ -0,0 +1,61 @@
+package peeringdb
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	api := NewAPIFromURLWithAPIKey(server.URL+"/api/", "secret-key",
+		WithLogger(logger),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+		WithRequestIDHeader(""),
+	)
+	if _, err := api.GetNetwork(map[string]interface{}{"asn": 64500}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("WithLogger, want 2 lines got %q", lines)
+	}
+	for i, expected := range [][]string{
+		{"level=WARN", "namespace=net", `parameters="depth=1&asn=64500"`, "status=502", "retry=0", "error="},
+		{"level=DEBUG", "namespace=net", `parameters="depth=1&asn=64500"`, "status=200", "retry=1", "duration="},
+	} {
+		for _, attr := range expected {
+			if !strings.Contains(lines[i], attr) {
+				t.Errorf("WithLogger, want %s in %q", attr, lines[i])
+			}
+		}
+	}
+	if strings.Contains(output.String(), "secret-key") {
+		t.Errorf("WithLogger, API key logged in %q", output.String())
+	}
+
+	// Nothing is done if the level is not enabled
+	output.Reset()
+	logger = slog.New(slog.NewTextHandler(&output, nil))
+	logResponse(logger, ResponseInfo{Namespace: networkNamespace, StatusCode: 200})
+	if output.Len() != 0 {
+		t.Errorf("logResponse, want nothing logged got %q", output.String())
+	}
+}
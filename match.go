@@ -0,0 +1,239 @@
+package peeringdb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nonFilterParameters are the search parameters which do not filter objects
+// on their fields.
+var nonFilterParameters = map[string]bool{
+	"depth":  true,
+	"limit":  true,
+	"skip":   true,
+	"fields": true,
+	"since":  true,
+}
+
+// filterObjects returns the objects matching the given search parameters
+// map, the way the PeeringDB API would filter them. Objects are expected to
+// be sorted by ID. The "limit", "skip" and "since" parameters are honored,
+// the "fields" one must be applied by the caller. Like the API, objects whose
+// status is not "ok", deleted ones for instance, are only returned when
+// asking for the objects updated since a given time.
+func filterObjects(objects []map[string]interface{}, search map[string]interface{}) ([]int, error) {
+	var since time.Time
+	if value, ok := search["since"]; ok {
+		timestamp, err := strconv.ParseInt(fmt.Sprintf("%v", value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since value '%v'", value)
+		}
+		since = time.Unix(timestamp, 0)
+	}
+
+	var indexes []int
+	for i, object := range objects {
+		if since.IsZero() && !isLive(object) {
+			continue
+		}
+		if !since.IsZero() {
+			updated, ok := parseTime(object["updated"])
+			if !ok || !updated.After(since) {
+				continue
+			}
+		}
+
+		ok, err := matchObject(object, search)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if value, ok := search["skip"]; ok {
+		skip, err := strconv.Atoi(fmt.Sprintf("%v", value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid skip value '%v'", value)
+		}
+		indexes = indexes[min(skip, len(indexes)):]
+	}
+	if value, ok := search["limit"]; ok {
+		limit, err := strconv.Atoi(fmt.Sprintf("%v", value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit value '%v'", value)
+		}
+		if limit > 0 {
+			indexes = indexes[:min(limit, len(indexes))]
+		}
+	}
+
+	return indexes, nil
+}
+
+// isLive tells if an object, decoded from JSON, is not deleted or pending.
+// Objects without a status are considered live.
+func isLive(object map[string]interface{}) bool {
+	status, ok := object["status"]
+	return !ok || status == nil || status == "" || status == "ok"
+}
+
+// matchObject tells if an object, decoded from JSON, matches all filters of
+// the given search parameters map. Filters are made of a field name followed
+// by an optional operator ("asn__in" for instance). Supported operators are
+// in, contains, startswith, lt, lte, gt and gte.
+func matchObject(object map[string]interface{}, search map[string]interface{}) (bool, error) {
+	// Sort keys to report errors consistently
+	keys := make([]string, 0, len(search))
+	for key := range search {
+		if !nonFilterParameters[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, operator, _ := strings.Cut(key, "__")
+		value, ok := object[field]
+		if !ok {
+			return false, fmt.Errorf("invalid filter field '%s'", field)
+		}
+
+		// Like the API, the last value of a repeated parameter is used
+		var wanted string
+		if values := parameterValues(key, search[key]); len(values) > 0 {
+			wanted = values[len(values)-1]
+		}
+		match, err := matchValue(value, operator, wanted)
+		if err != nil {
+			return false, fmt.Errorf("invalid filter '%s': %w", key, err)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// matchValue tells if a field value matches the wanted value given an
+// operator.
+func matchValue(value interface{}, operator, wanted string) (bool, error) {
+	actual := formatValue(value)
+
+	switch operator {
+	case "":
+		if _, ok := value.(bool); ok {
+			return actual == normalizeBool(wanted), nil
+		}
+		return actual == wanted, nil
+	case "in":
+		for _, candidate := range strings.Split(wanted, ",") {
+			if actual == strings.TrimSpace(candidate) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "contains":
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(wanted)), nil
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(actual), strings.ToLower(wanted)), nil
+	case "lt", "lte", "gt", "gte":
+		comparison, err := compareValues(value, wanted)
+		if err != nil {
+			return false, err
+		}
+		switch operator {
+		case "lt":
+			return comparison < 0, nil
+		case "lte":
+			return comparison <= 0, nil
+		case "gt":
+			return comparison > 0, nil
+		default:
+			return comparison >= 0, nil
+		}
+	default:
+		return false, fmt.Errorf("unsupported operator '%s'", operator)
+	}
+}
+
+// compareValues compares a field value with a wanted value. Numbers and
+// times are compared as such, other values are compared as strings.
+func compareValues(value interface{}, wanted string) (int, error) {
+	if number, ok := value.(float64); ok {
+		other, err := strconv.ParseFloat(wanted, 64)
+		if err != nil {
+			return 0, fmt.Errorf("'%s' is not a number", wanted)
+		}
+		switch {
+		case number < other:
+			return -1, nil
+		case number > other:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	}
+
+	if t, ok := parseTime(value); ok {
+		other, ok := parseTime(wanted)
+		if !ok {
+			return 0, fmt.Errorf("'%s' is not a time", wanted)
+		}
+		return t.Compare(other), nil
+	}
+
+	return strings.Compare(formatValue(value), wanted), nil
+}
+
+// formatValue formats a value decoded from JSON the way it would appear in a
+// query string.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// normalizeBool turns the various ways of writing a boolean in a query string
+// into "true" or "false".
+func normalizeBool(value string) string {
+	switch strings.ToLower(value) {
+	case "1", "true", "t", "yes":
+		return "true"
+	default:
+		return "false"
+	}
+}
+
+// parseTime parses a time found in an object or given as filter value. Dates,
+// RFC 3339 times and UNIX timestamps are supported.
+func parseTime(value interface{}) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true
+	}
+	if timestamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(timestamp, 0), true
+	}
+
+	return time.Time{}, false
+}
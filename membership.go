@@ -0,0 +1,120 @@
+package peeringdb
+
+import (
+	"context"
+)
+
+// addLink creates an object of type T linking two objects, such as a network
+// and a facility, unless an object matching the given search parameters, and
+// so linking the same objects, already exists. It returns the object linking
+// them and whether it was created.
+func addLink[T Object](ctx context.Context, api *API, link *T, search map[string]interface{}) (*T, bool, error) {
+	existing, err := getObjects[T](ctx, api, search)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(*existing) > 0 {
+		return &(*existing)[0], false, nil
+	}
+
+	created, err := Create(ctx, api, link)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return created, true, nil
+}
+
+// removeLink deletes the objects of type T matching the given search
+// parameters, linking two objects. It returns whether there was such an
+// object.
+func removeLink[T Object](ctx context.Context, api *API, search map[string]interface{}) (bool, error) {
+	existing, err := getObjects[T](ctx, api, search)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range *existing {
+		if err = Delete[T](ctx, api, idOf(&(*existing)[i])); err != nil {
+			return false, err
+		}
+	}
+
+	return len(*existing) > 0, nil
+}
+
+// AddNetworkToFacility records the presence of the network with the given ID
+// in the facility with the given ID, creating the NetworkFacility object
+// linking them unless it already exists. Presence records can so be driven
+// from an inventory without creating duplicates. It returns the
+// NetworkFacility object and whether it was created.
+func (api *API) AddNetworkToFacility(networkID, facilityID int) (*NetworkFacility, bool, error) {
+	return api.AddNetworkToFacilityWithContext(context.Background(), networkID, facilityID)
+}
+
+// AddNetworkToFacilityWithContext is the same as AddNetworkToFacility but uses
+// the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) AddNetworkToFacilityWithContext(ctx context.Context, networkID, facilityID int) (*NetworkFacility, bool, error) {
+	search := make(map[string]interface{})
+	search["net_id"] = networkID
+	search["fac_id"] = facilityID
+
+	return addLink(ctx, api, &NetworkFacility{NetworkID: networkID, FacilityID: facilityID}, search)
+}
+
+// RemoveNetworkFromFacility removes the presence of the network with the given
+// ID from the facility with the given ID, deleting the NetworkFacility object
+// linking them. It returns whether the network was present in the facility.
+func (api *API) RemoveNetworkFromFacility(networkID, facilityID int) (bool, error) {
+	return api.RemoveNetworkFromFacilityWithContext(context.Background(), networkID, facilityID)
+}
+
+// RemoveNetworkFromFacilityWithContext is the same as
+// RemoveNetworkFromFacility but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) RemoveNetworkFromFacilityWithContext(ctx context.Context, networkID, facilityID int) (bool, error) {
+	search := make(map[string]interface{})
+	search["net_id"] = networkID
+	search["fac_id"] = facilityID
+
+	return removeLink[NetworkFacility](ctx, api, search)
+}
+
+// AddInternetExchangeToFacility records the presence of the Internet exchange
+// point with the given ID in the facility with the given ID, creating the
+// InternetExchangeFacility object linking them unless it already exists. It
+// returns the InternetExchangeFacility object and whether it was created.
+func (api *API) AddInternetExchangeToFacility(ixID, facilityID int) (*InternetExchangeFacility, bool, error) {
+	return api.AddInternetExchangeToFacilityWithContext(context.Background(), ixID, facilityID)
+}
+
+// AddInternetExchangeToFacilityWithContext is the same as
+// AddInternetExchangeToFacility but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) AddInternetExchangeToFacilityWithContext(ctx context.Context, ixID, facilityID int) (*InternetExchangeFacility, bool, error) {
+	search := make(map[string]interface{})
+	search["ix_id"] = ixID
+	search["fac_id"] = facilityID
+
+	return addLink(ctx, api, &InternetExchangeFacility{InternetExchangeID: ixID, FacilityID: facilityID}, search)
+}
+
+// RemoveInternetExchangeFromFacility removes the presence of the Internet
+// exchange point with the given ID from the facility with the given ID,
+// deleting the InternetExchangeFacility object linking them. It returns
+// whether the IX was present in the facility.
+func (api *API) RemoveInternetExchangeFromFacility(ixID, facilityID int) (bool, error) {
+	return api.RemoveInternetExchangeFromFacilityWithContext(context.Background(), ixID, facilityID)
+}
+
+// RemoveInternetExchangeFromFacilityWithContext is the same as
+// RemoveInternetExchangeFromFacility but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) RemoveInternetExchangeFromFacilityWithContext(ctx context.Context, ixID, facilityID int) (bool, error) {
+	search := make(map[string]interface{})
+	search["ix_id"] = ixID
+	search["fac_id"] = facilityID
+
+	return removeLink[InternetExchangeFacility](ctx, api, search)
+}
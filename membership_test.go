@@ -0,0 +1,53 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestNetworkFacilityMembership(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkFacilityNamespace: {{"id": 1, "net_id": 10, "fac_id": 1}},
+	})
+	api := server.api()
+
+	// Already present, nothing is created
+	netfac, created, err := api.AddNetworkToFacility(10, 1)
+	if err != nil || created || netfac == nil || netfac.ID != 1 {
+		t.Errorf("AddNetworkToFacility, unexpected result %v, %v, %v", netfac, created, err)
+	}
+	netfac, created, err = api.AddNetworkToFacility(10, 2)
+	if err != nil || !created || netfac == nil || netfac.ID != 2 || netfac.FacilityID != 2 {
+		t.Errorf("AddNetworkToFacility, unexpected result %v, %v, %v", netfac, created, err)
+	}
+	if count := len(server.objects[networkFacilityNamespace]); count != 2 {
+		t.Errorf("AddNetworkToFacility, expected 2 objects, got %d", count)
+	}
+
+	removed, err := api.RemoveNetworkFromFacility(10, 1)
+	if err != nil || !removed {
+		t.Errorf("RemoveNetworkFromFacility, unexpected result %v, %v", removed, err)
+	}
+	if removed, err = api.RemoveNetworkFromFacility(10, 1); err != nil || removed {
+		t.Errorf("RemoveNetworkFromFacility, unexpected result %v, %v", removed, err)
+	}
+}
+
+func TestInternetExchangeFacilityMembership(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		internetExchangeFacilityNamespace: {},
+	})
+	api := server.api()
+
+	ixfac, created, err := api.AddInternetExchangeToFacility(1, 2)
+	if err != nil || !created || ixfac == nil || ixfac.ID != 1 {
+		t.Fatalf("AddInternetExchangeToFacility, unexpected result %v, %v, %v", ixfac, created, err)
+	}
+	if ixfac, created, err = api.AddInternetExchangeToFacility(1, 2); err != nil || created || ixfac == nil || ixfac.ID != 1 {
+		t.Errorf("AddInternetExchangeToFacility, unexpected result %v, %v, %v", ixfac, created, err)
+	}
+
+	removed, err := api.RemoveInternetExchangeFromFacility(1, 2)
+	if err != nil || !removed || len(server.objects[internetExchangeFacilityNamespace]) != 0 {
+		t.Errorf("RemoveInternetExchangeFromFacility, unexpected result %v, %v", removed, err)
+	}
+}
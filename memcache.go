@@ -0,0 +1,124 @@
+package peeringdb
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultMemoryCacheSize is the number of responses kept by a memory cache if
+// no size is given.
+const defaultMemoryCacheSize = 1024
+
+// memoryCacheEntry is a response kept until it expires.
+type memoryCacheEntry struct {
+	key      string
+	response *sharedResponse
+	expires  time.Time
+}
+
+// memoryCache keeps the responses of lookups in memory for a while. The least
+// recently used responses are evicted once the cache is full.
+type memoryCache struct {
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// WithMemoryCache returns an option serving lookups from memory if the same
+// lookup was made less than ttl ago, instead of querying the API again. This
+// suits the expansion of sets of objects referencing the same ones, such as
+// the exchanges of many netixlan objects. At most size responses are kept,
+// 1024 if size is zero. Responses are kept per API key or basic
+// authentication username, so that objects seen by an account are never
+// served to another one, and only successful lookups are kept.
+func WithMemoryCache(ttl time.Duration, size int) Option {
+	return func(api *API) {
+		if size <= 0 {
+			size = defaultMemoryCacheSize
+		}
+		api.memoryCache = &memoryCache{
+			ttl:     ttl,
+			size:    size,
+			now:     time.Now,
+			entries: map[string]*list.Element{},
+			order:   list.New(),
+		}
+	}
+}
+
+// get returns a copy of the response kept for the lookup of the given key, as
+// returned by flightKey, or nil if there is none or if it expired.
+func (c *memoryCache) get(key string) *http.Response {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return nil
+	}
+	entry := element.Value.(*memoryCacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil
+	}
+	c.order.MoveToFront(element)
+
+	return entry.response.copy()
+}
+
+// put keeps the response to the lookup of the given key, which is then read
+// from memory. The kept response is tagged with the time it was fetched at,
+// so that its provenance can be told.
+func (c *memoryCache) put(key string, response *http.Response) error {
+	if c == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	kept := *response
+	kept.Header = response.Header.Clone()
+	fetched := c.now()
+	if response.Header.Get(fromCacheHeader) != "" {
+		if date, err := http.ParseTime(response.Header.Get("Date")); err == nil {
+			fetched = date
+		}
+	}
+	kept.Header.Set(fromMemoryCacheHeader, fetched.UTC().Format(time.RFC3339Nano))
+	shared := &sharedResponse{response: &kept, body: body}
+
+	entry := &memoryCacheEntry{key: key, response: shared, expires: c.now().Add(c.ttl)}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, found := c.entries[key]; found {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package peeringdb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithMemoryCache(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {{"id": 1, "asn": 64500}, {"id": 2, "asn": 64501}},
+	})
+	hits := 0
+	api := server.api()
+	WithMemoryCache(time.Minute, 1)(api)
+	WithCacheHook(func(info CacheInfo) {
+		if info.Hit {
+			hits++
+		}
+	})(api)
+	now := time.Now()
+	api.memoryCache.now = func() time.Time { return now }
+
+	// Repeated lookups are served from memory
+	for i := 0; i < 3; i++ {
+		network, err := api.GetNetworkByID(1)
+		if err != nil || network == nil || network.ASN != 64500 {
+			t.Fatalf("GetNetworkByID, unexpected result %+v: %v", network, err)
+		}
+	}
+	if server.count(networkNamespace) != 1 || hits != 2 {
+		t.Errorf("GetNetworkByID, want 1 call and 2 hits got %d and %d", server.count(networkNamespace), hits)
+	}
+
+	// Lookups made on behalf of another account are not
+	if _, err := api.GetNetworkByIDWithContext(WithAuth(context.Background(), "other-key"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if server.count(networkNamespace) != 2 {
+		t.Errorf("GetNetworkByIDWithContext, want 2 calls got %d", server.count(networkNamespace))
+	}
+
+	// Responses expire, and the least recently used ones are evicted
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if server.count(networkNamespace) != 4 {
+		t.Errorf("GetNetworkByID, want 4 calls got %d", server.count(networkNamespace))
+	}
+}
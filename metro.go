@@ -0,0 +1,161 @@
+package peeringdb
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MetroSite is a group of facilities where an Internet exchange point is
+// available. Facilities belonging to a campus are grouped by campus, others
+// are grouped by city.
+type MetroSite struct {
+	// Name is the name of the campus, or the city and the country of the
+	// facilities if they do not belong to a campus.
+	Name string
+	// Campus is the campus of the facilities, its ID is 0 if they do not
+	// belong to one.
+	Campus Campus
+	// Facilities are the facilities of the site, sorted by name.
+	Facilities []Facility
+	// Prospects are the networks present in at least one facility of the
+	// site but not connected to the IX, sorted by AS number.
+	Prospects []Network
+}
+
+// MetroRing is a structure listing the sites of an Internet exchange point
+// along with the networks which could be connected to it there. It helps IX
+// operators targeting their outreach.
+type MetroRing struct {
+	InternetExchange InternetExchange
+	// Sites are sorted by number of prospects, the largest first, then by
+	// name.
+	Sites []MetroSite
+}
+
+// GetMetroRing returns a pointer to a MetroRing structure for the Internet
+// exchange point matching the given ID. All objects are fetched in bulk, so
+// the number of API calls does not grow linearly with the number of sites.
+func (api *API) GetMetroRing(ixID int) (*MetroRing, error) {
+	ix, err := api.GetInternetExchangeByID(ixID)
+	if err != nil {
+		return nil, err
+	}
+	if ix == nil {
+		return nil, fmt.Errorf("no IX found for ID %d", ixID)
+	}
+
+	ixFacilities, err := getChunked([]int{ixID}, "ix_id", api.GetInternetExchangeFacility)
+	if err != nil {
+		return nil, err
+	}
+	facilityIDs := make([]int, 0, len(ixFacilities))
+	for _, ixFacility := range ixFacilities {
+		facilityIDs = append(facilityIDs, ixFacility.FacilityID)
+	}
+	facilities, err := getChunked(facilityIDs, "id", api.GetFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	// Networks already connected to the IX are not prospects
+	networkIXLANs, err := getChunked([]int{ixID}, "ix_id", api.GetNetworkInternetExchangeLAN)
+	if err != nil {
+		return nil, err
+	}
+	connected := make(map[int]bool, len(networkIXLANs))
+	for _, networkIXLAN := range networkIXLANs {
+		connected[networkIXLAN.NetworkID] = true
+	}
+
+	networkFacilities, err := getChunked(facilityIDs, "fac_id", api.GetNetworkFacility)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[int][]int, len(facilities))
+	var networkIDs []int
+	seen := make(map[int]bool)
+	for _, networkFacility := range networkFacilities {
+		if connected[networkFacility.NetworkID] {
+			continue
+		}
+		present[networkFacility.FacilityID] = append(present[networkFacility.FacilityID], networkFacility.NetworkID)
+		if !seen[networkFacility.NetworkID] {
+			seen[networkFacility.NetworkID] = true
+			networkIDs = append(networkIDs, networkFacility.NetworkID)
+		}
+	}
+	networks, err := getChunked(networkIDs, "id", api.GetNetwork)
+	if err != nil {
+		return nil, err
+	}
+	prospects := make(map[int]Network, len(networks))
+	for _, network := range networks {
+		prospects[network.ID] = network
+	}
+
+	// Group facilities by campus, or by city when they are not in a campus
+	var campusIDs []int
+	sites := make(map[string]*MetroSite)
+	var keys []string
+	for _, facility := range facilities {
+		key := fmt.Sprintf("%s, %s", facility.City, facility.Country)
+		if facility.CampusID != 0 {
+			key = fmt.Sprintf("campus %d", facility.CampusID)
+		}
+
+		site, ok := sites[key]
+		if !ok {
+			site = &MetroSite{Name: fmt.Sprintf("%s, %s", facility.City, facility.Country)}
+			if facility.CampusID != 0 {
+				site.Campus.ID = facility.CampusID
+				campusIDs = append(campusIDs, facility.CampusID)
+			}
+			sites[key] = site
+			keys = append(keys, key)
+		}
+		site.Facilities = append(site.Facilities, facility)
+	}
+
+	campuses, err := getChunked(campusIDs, "id", api.GetCampus)
+	if err != nil {
+		return nil, err
+	}
+	for _, campus := range campuses {
+		if site, ok := sites[fmt.Sprintf("campus %d", campus.ID)]; ok {
+			site.Name = campus.Name
+			site.Campus = campus
+		}
+	}
+
+	ring := &MetroRing{InternetExchange: *ix, Sites: make([]MetroSite, 0, len(sites))}
+	for _, key := range keys {
+		site := sites[key]
+		sort.Slice(site.Facilities, func(i, j int) bool {
+			return site.Facilities[i].Name < site.Facilities[j].Name
+		})
+
+		added := make(map[int]bool)
+		for _, facility := range site.Facilities {
+			for _, networkID := range present[facility.ID] {
+				network, ok := prospects[networkID]
+				if ok && !added[networkID] {
+					added[networkID] = true
+					site.Prospects = append(site.Prospects, network)
+				}
+			}
+		}
+		sort.Slice(site.Prospects, func(i, j int) bool {
+			return site.Prospects[i].ASN < site.Prospects[j].ASN
+		})
+
+		ring.Sites = append(ring.Sites, *site)
+	}
+	sort.SliceStable(ring.Sites, func(i, j int) bool {
+		if len(ring.Sites[i].Prospects) != len(ring.Sites[j].Prospects) {
+			return len(ring.Sites[i].Prospects) > len(ring.Sites[j].Prospects)
+		}
+		return ring.Sites[i].Name < ring.Sites[j].Name
+	})
+
+	return ring, nil
+}
@@ -0,0 +1,62 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestGetMetroRing(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		internetExchangeNamespace: {{"id": 1, "name": "IX"}},
+		internetExchangeFacilityNamespace: {
+			{"id": 1, "ix_id": 1, "fac_id": 10},
+			{"id": 2, "ix_id": 1, "fac_id": 11},
+			{"id": 3, "ix_id": 1, "fac_id": 12},
+		},
+		facilityNamespace: {
+			{"id": 10, "name": "Facility B", "campus_id": 5, "city": "Paris", "country": "FR"},
+			{"id": 11, "name": "Facility A", "campus_id": 5, "city": "Paris", "country": "FR"},
+			{"id": 12, "name": "Facility C", "city": "Lyon", "country": "FR"},
+		},
+		campusNamespace: {{"id": 5, "name": "Campus"}},
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "ix_id": 1, "net_id": 100},
+		},
+		networkFacilityNamespace: {
+			{"id": 1, "fac_id": 10, "net_id": 100},
+			{"id": 2, "fac_id": 10, "net_id": 102},
+			{"id": 3, "fac_id": 11, "net_id": 101},
+			{"id": 4, "fac_id": 11, "net_id": 102},
+			{"id": 5, "fac_id": 12, "net_id": 101},
+		},
+		networkNamespace: {
+			{"id": 100, "asn": 64500},
+			{"id": 101, "asn": 64501},
+			{"id": 102, "asn": 64502},
+		},
+	})
+
+	ring, err := server.api().GetMetroRing(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ring.InternetExchange.Name != "IX" || len(ring.Sites) != 2 {
+		t.Fatalf("GetMetroRing, unexpected ring: %+v", ring)
+	}
+
+	campus := ring.Sites[0]
+	if campus.Name != "Campus" || campus.Campus.ID != 5 || len(campus.Facilities) != 2 || campus.Facilities[0].Name != "Facility A" {
+		t.Errorf("GetMetroRing, unexpected campus site: %+v", campus)
+	}
+	if len(campus.Prospects) != 2 || campus.Prospects[0].ASN != 64501 || campus.Prospects[1].ASN != 64502 {
+		t.Errorf("GetMetroRing, unexpected campus prospects: %+v", campus.Prospects)
+	}
+
+	city := ring.Sites[1]
+	if city.Name != "Lyon, FR" || city.Campus.ID != 0 || len(city.Prospects) != 1 || city.Prospects[0].ASN != 64501 {
+		t.Errorf("GetMetroRing, unexpected city site: %+v", city)
+	}
+
+	if _, err = server.api().GetMetroRing(2); err == nil {
+		t.Error("GetMetroRing, want error for unknown IX")
+	}
+}
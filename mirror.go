@@ -0,0 +1,762 @@
+package peeringdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMirrorPageSize is the number of objects requested per API call
+	// when bootstrapping a mirror if no other value is given.
+	defaultMirrorPageSize = 250
+	// defaultMirrorPacing is the minimum delay between two API calls made
+	// by a mirror if no other value is given.
+	defaultMirrorPacing = time.Second
+	// mirrorStateFile is the name of the file where the mirror keeps track
+	// of its progress.
+	mirrorStateFile = "state.json"
+)
+
+// ErrNamespaceNotMirrored is the error that will be returned when reading
+// objects of a namespace whose initial load is not done.
+var ErrNamespaceNotMirrored = errors.New("namespace not mirrored")
+
+// MirrorOptions is a structure used to configure a Mirror.
+type MirrorOptions struct {
+	// PageSize is the number of objects requested per API call, 250 is used
+	// if it is not set.
+	PageSize int
+	// Pacing is the minimum delay between two API calls, one second is used
+	// if it is not set. Use a negative value to disable pacing.
+	Pacing time.Duration
+	// Namespaces are the namespaces to mirror, all of them are mirrored if
+	// it is not set.
+	Namespaces []string
+	// ConsistencyRetries is the number of times the initial load of a
+	// namespace is started again if objects changed while paginating. The
+	// namespace is flagged as inconsistent if they still change.
+	ConsistencyRetries int
+	// Workers is the number of namespaces loaded or synchronized
+	// concurrently, 1 is used if it is not set.
+	Workers int
+	// PageWorkers is the number of pages of a namespace fetched
+	// concurrently, indexed by namespace. 1 is used for namespaces not
+	// listed. Large namespaces benefit the most from it.
+	//
+	// Whatever the number of workers, API calls are spread according to
+	// Pacing which acts as a global rate budget.
+	PageWorkers map[string]int
+}
+
+// MirrorNamespaceState is a structure describing the progress of a mirror for
+// a given namespace.
+type MirrorNamespaceState struct {
+	// Complete tells if the initial load of the namespace is done.
+	Complete bool `json:"complete"`
+	// Fetched is the number of objects fetched so far by the initial load.
+	Fetched int `json:"fetched"`
+	// PartialSize is the size of the file holding the objects fetched so
+	// far by the initial load, used to resume it.
+	PartialSize int64 `json:"partial_size,omitempty"`
+	// Count is the number of live objects in the mirror once the initial
+	// load is done, deleted objects kept by the mirror not being counted.
+	Count int `json:"count"`
+	// Inconsistent tells if objects changed while paginating during the
+	// initial load, some of them may then be missing. Objects updated during
+	// the load are fetched by the next Sync, but objects missed because
+	// others were deleted are not: the namespace should be loaded again.
+	Inconsistent bool `json:"inconsistent,omitempty"`
+	// Started is the time at which the initial load started.
+	Started time.Time `json:"started"`
+	// LastSync is the time up to which the mirror is known to be in sync
+	// with PeeringDB.
+	LastSync time.Time `json:"last_sync"`
+	// LastAttempt is the time at which the last synchronization started,
+	// whether it succeeded or not.
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	// LastError is the error of the last synchronization, it is empty if
+	// it succeeded.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Mirror is a local copy of PeeringDB objects stored in a directory. Each
+// namespace is stored in its own file, using the same JSON format as the
+// API responses.
+//
+// The initial load paginates through every namespace, pacing API calls. Its
+// progress is saved after each page so that it can be resumed after an
+// interruption, for instance when the API rate limit is exceeded. Once
+// loaded, Sync keeps the mirror up to date by fetching the objects updated
+// since the last synchronization.
+//
+// A Mirror implements the Client interface, objects are then read from the
+// local copy instead of the API. Search parameters are applied locally and
+// mimic the filters of the API.
+type Mirror struct {
+	sourceClient
+
+	api       *API
+	directory string
+	options   MirrorOptions
+	sleep     func(time.Duration)
+	now       func() time.Time
+
+	mutex    sync.Mutex
+	save     sync.Mutex
+	state    map[string]*MirrorNamespaceState
+	lastCall time.Time
+	loaded   map[string]*mirrorNamespace
+}
+
+// mirrorNamespace holds the objects of a namespace loaded in memory. They are
+// kept both raw and decoded, to filter them without decoding them each time.
+type mirrorNamespace struct {
+	modified time.Time
+	raw      []json.RawMessage
+	decoded  []map[string]interface{}
+}
+
+// NewMirror returns a pointer to a new Mirror storing its data in the given
+// directory and using the given API to fetch objects. The directory is
+// created if it does not exist. If it contains the state of a previous
+// mirror, it is loaded so that an interrupted initial load is resumed.
+func NewMirror(api *API, directory string, options MirrorOptions) (*Mirror, error) {
+	if options.PageSize <= 0 {
+		options.PageSize = defaultMirrorPageSize
+	}
+	if options.Pacing == 0 {
+		options.Pacing = defaultMirrorPacing
+	}
+	if len(options.Namespaces) == 0 {
+		options.Namespaces = namespaces
+	}
+	if options.Workers <= 0 {
+		options.Workers = 1
+	}
+
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	m := &Mirror{
+		api:       api,
+		directory: directory,
+		options:   options,
+		sleep:     time.Sleep,
+		now:       time.Now,
+		state:     make(map[string]*MirrorNamespaceState),
+		loaded:    make(map[string]*mirrorNamespace),
+	}
+	m.sourceClient = sourceClient{m}
+
+	data, err := os.ReadFile(filepath.Join(directory, mirrorStateFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err = json.Unmarshal(data, &m.state); err != nil {
+			return nil, fmt.Errorf("invalid mirror state: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// State returns the state of the mirror for the given namespace. The boolean
+// is false if nothing has been mirrored yet for the namespace.
+func (m *Mirror) State(namespace string) (MirrorNamespaceState, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	state, ok := m.state[namespace]
+	if !ok {
+		return MirrorNamespaceState{}, false
+	}
+
+	return *state, true
+}
+
+// Bootstrap loads all objects of the mirrored namespaces. Namespaces already
+// loaded are skipped and a namespace partially loaded is resumed where it
+// stopped. If an error occurs, for instance because the rate limit of the API
+// is exceeded, the progress is kept and Bootstrap can be called again later.
+func (m *Mirror) Bootstrap() error {
+	return m.forEachNamespace(m.bootstrapNamespace)
+}
+
+// forEachNamespace calls the given function for each mirrored namespace,
+// running as many of them concurrently as there are workers. The error of the
+// first namespace failing, in the order of the options, is returned once all
+// calls are done.
+func (m *Mirror) forEachNamespace(f func(namespace string) error) error {
+	errs := make([]error, len(m.options.Namespaces))
+	workers := make(chan struct{}, m.options.Workers)
+
+	var wg sync.WaitGroup
+	for i, namespace := range m.options.Namespaces {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, namespace string) {
+			defer wg.Done()
+			errs[i] = f(namespace)
+			<-workers
+		}(i, namespace)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchBatch fetches pages of a namespace concurrently, as many as the page
+// workers of the namespace, starting at the given number of objects to skip.
+// Search parameters of each page are built with the given function. Pages are
+// returned in order, up to the first one which is not full.
+func (m *Mirror) fetchBatch(namespace string, skip int, search func(skip int) map[string]interface{}) ([][]json.RawMessage, error) {
+	count := max(m.options.PageWorkers[namespace], 1)
+	pages := make([][]json.RawMessage, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.pace()
+			resource, err := m.api.getRawResource(namespace, search(skip+i*m.options.PageSize))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			pages[i] = resource.Data
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range pages {
+		if errs[i] != nil {
+			// Pages fetched before the failing one can still be used
+			return pages[:i], errs[i]
+		}
+		if len(pages[i]) < m.options.PageSize {
+			return pages[:i+1], nil
+		}
+	}
+
+	return pages, nil
+}
+
+// bootstrapNamespace loads all objects of a namespace, page by page. Objects
+// are appended to a partial file which becomes the namespace file once all
+// pages have been fetched. If the objects changed while paginating, the load
+// is restarted as many times as allowed by the options, the namespace being
+// flagged as inconsistent if the objects still change.
+func (m *Mirror) bootstrapNamespace(namespace string) error {
+	for retries := m.options.ConsistencyRetries; ; retries-- {
+		m.mutex.Lock()
+		state, ok := m.state[namespace]
+		if !ok {
+			state = &MirrorNamespaceState{}
+			m.state[namespace] = state
+		}
+		complete, started, size := state.Complete, state.Started, state.PartialSize
+		if !complete && started.IsZero() {
+			state.Started = m.now()
+			state.Fetched = 0
+			state.PartialSize = 0
+		}
+		m.mutex.Unlock()
+
+		if complete {
+			return nil
+		}
+
+		// Forget about objects written after the progress was last saved,
+		// they will be fetched again
+		partial := m.path(namespace) + ".part"
+		if started.IsZero() {
+			if err := os.Remove(partial); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		} else if err := os.Truncate(partial, size); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		if err := m.fetchPages(namespace, partial, state); err != nil {
+			return err
+		}
+
+		objects, consistent, err := m.readPartial(namespace, partial, state)
+		if err != nil {
+			return err
+		}
+		if consistent || retries <= 0 {
+			return m.finishBootstrap(namespace, partial, state, objects, !consistent)
+		}
+
+		// Start again from scratch
+		m.mutex.Lock()
+		state.Started = time.Time{}
+		m.mutex.Unlock()
+	}
+}
+
+// fetchPages fetches the pages of a namespace not fetched yet and appends
+// their objects to the partial file. The progress is saved after each page.
+func (m *Mirror) fetchPages(namespace, partial string, state *MirrorNamespaceState) error {
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for {
+		m.mutex.Lock()
+		fetched := state.Fetched
+		m.mutex.Unlock()
+
+		pages, fetchErr := m.fetchBatch(namespace, fetched, func(skip int) map[string]interface{} {
+			search := make(map[string]interface{})
+			search["limit"] = m.options.PageSize
+			search["skip"] = skip
+			return search
+		})
+
+		for _, page := range pages {
+			writer := bufio.NewWriter(file)
+			for _, object := range page {
+				writer.Write(object)
+				writer.WriteByte('\n')
+			}
+			if err = writer.Flush(); err != nil {
+				return err
+			}
+			info, err := file.Stat()
+			if err != nil {
+				return err
+			}
+
+			m.mutex.Lock()
+			state.Fetched += len(page)
+			state.PartialSize = info.Size()
+			fetched = state.Fetched
+			m.mutex.Unlock()
+			if err = m.saveState(); err != nil {
+				return err
+			}
+
+			if len(page) < m.options.PageSize {
+				return nil
+			}
+		}
+
+		if fetchErr != nil {
+			// Progress is saved after each page, nothing else to keep
+			return fmt.Errorf("mirror bootstrap of %s stopped after %d objects: %w",
+				namespace, fetched, fetchErr)
+		}
+	}
+}
+
+// readPartial reads the objects of the partial file of a namespace and tells
+// if they are consistent. As pages are fetched by position, objects deleted or
+// restored while paginating shift the following ones: some objects are then
+// fetched twice, or not at all. The first case is detected with duplicated
+// IDs, the second one by looking for objects deleted since the load started.
+func (m *Mirror) readPartial(namespace, partial string, state *MirrorNamespaceState) (map[int]json.RawMessage, bool, error) {
+	file, err := os.Open(partial)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	objects := make(map[int]json.RawMessage)
+	consistent := true
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		object := json.RawMessage(append([]byte(nil), scanner.Bytes()...))
+		id, err := objectID(object)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, ok := objects[id]; ok {
+			consistent = false
+		}
+		objects[id] = object
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, false, err
+	}
+	if !consistent {
+		return objects, false, nil
+	}
+
+	m.mutex.Lock()
+	started := state.Started
+	m.mutex.Unlock()
+
+	// Objects updated since the load started, including deleted ones
+	search := UpdatedSince(nil, started.Add(-time.Second))
+
+	m.pace()
+	resource, err := m.api.getRawResource(namespace, search)
+	if err != nil {
+		return nil, false, fmt.Errorf("mirror bootstrap of %s cannot be checked: %w", namespace, err)
+	}
+	for _, object := range resource.Data {
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err = json.Unmarshal(object, &status); err != nil {
+			return nil, false, err
+		}
+		if status.Status != "" && status.Status != "ok" {
+			return objects, false, nil
+		}
+	}
+
+	return objects, true, nil
+}
+
+// finishBootstrap turns the objects read from the partial file of a
+// namespace into its namespace file.
+func (m *Mirror) finishBootstrap(namespace, partial string, state *MirrorNamespaceState, objects map[int]json.RawMessage, inconsistent bool) error {
+	if err := m.writeObjects(namespace, objects); err != nil {
+		return err
+	}
+	count, err := liveCount(objects)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	state.Complete = true
+	state.Inconsistent = inconsistent
+	state.Count = count
+	state.PartialSize = 0
+	state.LastSync = state.Started
+	m.mutex.Unlock()
+	if err := m.saveState(); err != nil {
+		return err
+	}
+
+	return os.Remove(partial)
+}
+
+// Sync fetches the objects updated since the last synchronization of each
+// loaded namespace and stores them in the mirror. Namespaces whose initial
+// load is not done are skipped. Objects deleted from PeeringDB are kept, with
+// a "deleted" status, as returned by the API. Versions of objects replaced by
+// newer ones are kept in the history of the mirror.
+func (m *Mirror) Sync() error {
+	return m.forEachNamespace(m.syncNamespace)
+}
+
+// syncNamespace fetches the objects of a namespace updated since its last
+// synchronization, page by page, and merges them into the namespace file.
+func (m *Mirror) syncNamespace(namespace string) error {
+	state, ok := m.State(namespace)
+	if !ok || !state.Complete {
+		return nil
+	}
+
+	// Record the outcome of the attempt, so that a synchronization failing
+	// repeatedly can be noticed
+	started := m.now()
+	err := m.syncUpdated(namespace, state, started)
+	m.mutex.Lock()
+	m.state[namespace].LastAttempt = started
+	m.state[namespace].LastError = ""
+	if err != nil {
+		m.state[namespace].LastError = err.Error()
+	}
+	m.mutex.Unlock()
+
+	if saveErr := m.saveState(); err == nil {
+		err = saveErr
+	}
+
+	return err
+}
+
+// syncUpdated fetches and merges the objects of a namespace updated since its
+// last synchronization. Deleted objects are kept with their status, so that
+// they are only returned when asking for the objects updated since a given
+// time, as done by the API.
+func (m *Mirror) syncUpdated(namespace string, state MirrorNamespaceState, started time.Time) error {
+	var updated []json.RawMessage
+	for done := false; !done; {
+		pages, err := m.fetchBatch(namespace, len(updated), func(skip int) map[string]interface{} {
+			// One second earlier to not miss objects updated during
+			// the second of the last synchronization
+			since := UpdatedSince(nil, state.LastSync.Add(-time.Second))
+			return Paginate(since, m.options.PageSize, skip)
+		})
+		if err != nil {
+			return fmt.Errorf("mirror sync of %s failed: %w", namespace, err)
+		}
+
+		for _, page := range pages {
+			updated = append(updated, page...)
+			done = len(page) < m.options.PageSize
+		}
+	}
+
+	if len(updated) > 0 {
+		objects, err := m.readObjects(namespace)
+		if err != nil {
+			return err
+		}
+
+		var replaced []json.RawMessage
+		for _, object := range updated {
+			id, err := objectID(object)
+			if err != nil {
+				return err
+			}
+			if previous, ok := objects[id]; ok {
+				if bytes.Equal(previous, object) {
+					continue
+				}
+				replaced = append(replaced, previous)
+			}
+			objects[id] = object
+		}
+
+		if err = m.appendHistory(namespace, replaced, started); err != nil {
+			return err
+		}
+		if err = m.writeObjects(namespace, objects); err != nil {
+			return err
+		}
+		count, err := liveCount(objects)
+		if err != nil {
+			return err
+		}
+
+		m.mutex.Lock()
+		m.state[namespace].Count = count
+		m.mutex.Unlock()
+	}
+
+	m.mutex.Lock()
+	m.state[namespace].LastSync = started
+	m.mutex.Unlock()
+
+	return nil
+}
+
+// getRaw returns the objects of a namespace matching the given search
+// parameters map. The namespace file is loaded in memory the first time and
+// reloaded each time it changes.
+func (m *Mirror) getRaw(namespace string, search map[string]interface{}) ([]json.RawMessage, error) {
+	loaded, err := m.load(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := filterObjects(loaded.decoded, search)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only keep the fields asked, as the API does
+	fields := selectedFields(search)
+	objects := make([]json.RawMessage, len(indexes))
+	for i, index := range indexes {
+		objects[i] = loaded.raw[index]
+		if fields != nil {
+			if objects[i], err = projectObject(loaded.decoded[index], fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// load returns the objects of a namespace, loading them from the namespace
+// file if they are not in memory or if the file has changed.
+func (m *Mirror) load(namespace string) (*mirrorNamespace, error) {
+	if state, ok := m.State(namespace); !ok || !state.Complete {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceNotMirrored, namespace)
+	}
+
+	info, err := os.Stat(m.path(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	loaded, ok := m.loaded[namespace]
+	m.mutex.Unlock()
+	if ok && loaded.modified.Equal(info.ModTime()) {
+		return loaded, nil
+	}
+
+	data, err := os.ReadFile(m.path(namespace))
+	if err != nil {
+		return nil, err
+	}
+	resource := &rawResource{}
+	if err = json.Unmarshal(data, resource); err != nil {
+		return nil, err
+	}
+
+	loaded = &mirrorNamespace{
+		modified: info.ModTime(),
+		raw:      resource.Data,
+		decoded:  make([]map[string]interface{}, len(resource.Data)),
+	}
+	for i, object := range resource.Data {
+		if err = json.Unmarshal(object, &loaded.decoded[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mutex.Lock()
+	m.loaded[namespace] = loaded
+	m.mutex.Unlock()
+
+	return loaded, nil
+}
+
+// pace waits long enough to respect the minimum delay between API calls. The
+// time of the call is reserved before waiting, so concurrent workers share
+// the same budget.
+func (m *Mirror) pace() {
+	m.mutex.Lock()
+	now := m.now()
+	next := m.lastCall.Add(m.options.Pacing)
+	if next.Before(now) {
+		next = now
+	}
+	m.lastCall = next
+	m.mutex.Unlock()
+
+	if wait := next.Sub(now); wait > 0 {
+		m.sleep(wait)
+	}
+}
+
+// path returns the path of the file storing the objects of a namespace.
+func (m *Mirror) path(namespace string) string {
+	return filepath.Join(m.directory, namespace+".json")
+}
+
+// readObjects returns the objects of a namespace stored in the mirror,
+// indexed by ID.
+func (m *Mirror) readObjects(namespace string) (map[int]json.RawMessage, error) {
+	data, err := os.ReadFile(m.path(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &rawResource{}
+	if err = json.Unmarshal(data, resource); err != nil {
+		return nil, err
+	}
+
+	objects := make(map[int]json.RawMessage, len(resource.Data))
+	for _, object := range resource.Data {
+		id, err := objectID(object)
+		if err != nil {
+			return nil, err
+		}
+		objects[id] = object
+	}
+
+	return objects, nil
+}
+
+// liveCount returns the number of objects which are not deleted.
+func liveCount(objects map[int]json.RawMessage) (int, error) {
+	count := 0
+	for _, object := range objects {
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(object, &status); err != nil {
+			return 0, err
+		}
+		if status.Status == "" || status.Status == "ok" {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// writeObjects stores the objects of a namespace, sorted by ID.
+func (m *Mirror) writeObjects(namespace string, objects map[int]json.RawMessage) error {
+	ids := make([]int, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	resource := &rawResource{Data: make([]json.RawMessage, len(ids))}
+	for i, id := range ids {
+		resource.Data[i] = objects[id]
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(m.path(namespace), data)
+}
+
+// saveState stores the progress of the mirror. Concurrent workers save it one
+// at a time.
+func (m *Mirror) saveState() error {
+	m.save.Lock()
+	defer m.save.Unlock()
+
+	m.mutex.Lock()
+	data, err := json.MarshalIndent(m.state, "", "  ")
+	m.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(m.directory, mirrorStateFile), data)
+}
+
+// objectID returns the ID of a PeeringDB object given as raw JSON.
+func objectID(object json.RawMessage) (int, error) {
+	var identified struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(object, &identified); err != nil {
+		return 0, err
+	}
+
+	return identified.ID, nil
+}
+
+// writeFileAtomic writes data to a temporary file which is then renamed, so
+// that readers never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(temporary, path)
+}
@@ -0,0 +1,272 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMirrorBootstrapResume(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+			{"id": 2, "asn": 64501},
+			{"id": 3, "asn": 64502},
+			{"id": 4, "asn": 64503},
+			{"id": 5, "asn": 64504},
+		},
+	})
+	directory := t.TempDir()
+	options := MirrorOptions{PageSize: 2, Pacing: -1, Namespaces: []string{networkNamespace}}
+
+	// Stop after the first page as if the quota was exhausted
+	server.setQuota(1)
+	mirror, err := NewMirror(server.api(), directory, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("Bootstrap, want ErrRateLimitExceeded got %v", err)
+	}
+	if state, _ := mirror.State(networkNamespace); state.Complete || state.Fetched != 2 {
+		t.Errorf("Bootstrap, want 2 objects fetched got %+v", state)
+	}
+
+	// Resume with a new mirror using the same directory
+	server.setQuota(-1)
+	mirror, err = NewMirror(server.api(), directory, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := mirror.State(networkNamespace)
+	if !state.Complete || state.Count != 5 {
+		t.Errorf("Bootstrap, want 5 objects got %+v", state)
+	}
+	// 1 page before the interruption, 1 rejected, 2 after and a consistency
+	// check
+	if count := server.count(networkNamespace); count != 5 {
+		t.Errorf("Bootstrap, want 5 API calls got %d", count)
+	}
+
+	objects, err := mirror.readObjects(networkNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 5 {
+		t.Errorf("Bootstrap, want 5 objects stored got %d", len(objects))
+	}
+}
+
+func TestMirrorClient(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Alpha Networks", "info_ipv6": true},
+			{"id": 2, "asn": 64501, "name": "Beta Networks", "info_ipv6": false},
+			{"id": 3, "asn": 64502, "name": "Gamma", "info_ipv6": true},
+		},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing can be read before the initial load
+	if _, err = mirror.GetAllNetworks(); !errors.Is(err, ErrNamespaceNotMirrored) {
+		t.Errorf("GetAllNetworks, want ErrNamespaceNotMirrored got %v", err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The mirror can be used where a client is expected
+	var client Client = mirror
+	network, err := client.GetASN(64501)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Name != "Beta Networks" {
+		t.Errorf("GetASN, want 'Beta Networks' got '%s'", network.Name)
+	}
+
+	search := map[string]interface{}{"name__contains": "networks", "info_ipv6": 1}
+	networks, err := client.GetNetwork(search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*networks) != 1 || (*networks)[0].ID != 1 {
+		t.Errorf("GetNetwork, want network 1 got %v", *networks)
+	}
+
+	network, err = client.GetNetworkByID(4)
+	if err != nil || network != nil {
+		t.Errorf("GetNetworkByID, want nil got %v, %v", network, err)
+	}
+
+	if _, err = client.GetNetwork(map[string]interface{}{"foo": 1}); err == nil {
+		t.Error("GetNetwork, want error for unknown field got nil")
+	}
+}
+
+func TestMirrorBootstrapConsistency(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+			{"id": 2, "asn": 64501},
+			{"id": 3, "asn": 64502},
+			{"id": 4, "asn": 64503},
+			{"id": 5, "asn": 64504},
+		},
+	})
+
+	// Delete the first network once the first page is fetched, the
+	// following objects are then shifted and one of them is missed
+	// Each reading of the clock is an hour later, so that the network is
+	// seen as deleted during the first load only
+	clock := time.Now()
+	deleted := false
+	api := server.api(WithResponseHook(func(info ResponseInfo) {
+		if !deleted {
+			deleted = true
+			server.mutex.Lock()
+			server.objects[networkNamespace][0]["status"] = "deleted"
+			server.objects[networkNamespace][0]["updated"] = clock.Add(time.Minute).Format(time.RFC3339)
+			server.mutex.Unlock()
+		}
+	}))
+
+	for _, retries := range []int{0, 1} {
+		deleted = false
+		options := MirrorOptions{PageSize: 2, Pacing: -1, Namespaces: []string{networkNamespace}, ConsistencyRetries: retries}
+		mirror, err := NewMirror(api, t.TempDir(), options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mirror.now = func() time.Time {
+			clock = clock.Add(time.Hour)
+			return clock
+		}
+		if err = mirror.Bootstrap(); err != nil {
+			t.Fatal(err)
+		}
+
+		state, _ := mirror.State(networkNamespace)
+		if state.Inconsistent != (retries == 0) {
+			t.Errorf("Bootstrap with %d retries, unexpected inconsistent flag: %+v", retries, state)
+		}
+		if retries > 0 && state.Count != 4 {
+			t.Errorf("Bootstrap with %d retries, want 4 objects got %d", retries, state.Count)
+		}
+
+		// Restore the network for the next run
+		server.objects[networkNamespace][0]["status"] = "ok"
+	}
+}
+
+func TestMirrorConcurrentWorkers(t *testing.T) {
+	objects := make(map[string][]map[string]interface{})
+	for _, namespace := range []string{networkNamespace, organizationNamespace, facilityNamespace} {
+		for id := 1; id <= 7; id++ {
+			objects[namespace] = append(objects[namespace], map[string]interface{}{
+				"id": id, "name": fmt.Sprintf("%s %d", namespace, id), "updated": "2020-01-01T00:00:00Z",
+			})
+		}
+	}
+	server := newTestServer(t, objects)
+
+	options := MirrorOptions{
+		PageSize:    2,
+		Pacing:      -1,
+		Namespaces:  []string{networkNamespace, organizationNamespace, facilityNamespace},
+		Workers:     2,
+		PageWorkers: map[string]int{networkNamespace: 3},
+	}
+	counts := map[string]int{networkNamespace: 7, organizationNamespace: 7, facilityNamespace: 7}
+
+	mirror, err := NewMirror(server.api(), t.TempDir(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimate := mirror.EstimateBootstrap(counts)
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, namespace := range options.Namespaces {
+		state, _ := mirror.State(namespace)
+		if !state.Complete || state.Count != 7 || state.Inconsistent {
+			t.Errorf("Bootstrap, unexpected state for %s: %+v", namespace, state)
+		}
+		if made := server.count(namespace); made != estimate.Calls[namespace] {
+			t.Errorf("Bootstrap, estimated %d calls for %s but made %d", estimate.Calls[namespace], namespace, made)
+		}
+	}
+
+	networks, err := mirror.GetAllNetworks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, network := range *networks {
+		if network.ID != i+1 {
+			t.Fatalf("GetAllNetworks, unexpected networks order: %+v", *networks)
+		}
+	}
+
+	server.mutex.Lock()
+	server.objects[networkNamespace][6]["name"] = "renamed"
+	server.objects[networkNamespace][6]["updated"] = time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	server.mutex.Unlock()
+	if err = mirror.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if network, _ := mirror.GetNetworkByID(7); network == nil || network.Name != "renamed" {
+		t.Errorf("Sync, network not updated: %+v", network)
+	}
+}
+
+func TestMirrorSyncDeletion(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "status": "ok", "updated": "2020-01-01T00:00:00Z"},
+			{"id": 2, "asn": 64501, "status": "ok", "updated": "2020-01-01T00:00:00Z"},
+		},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	server.mutex.Lock()
+	server.objects[networkNamespace][1]["status"] = "deleted"
+	server.objects[networkNamespace][1]["updated"] = time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	server.mutex.Unlock()
+	if err = mirror.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	networks, err := mirror.GetAllNetworks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*networks) != 1 || (*networks)[0].ID != 1 {
+		t.Errorf("GetAllNetworks, want network 1 only got %+v", *networks)
+	}
+	if network, err := mirror.GetASN(64501); err == nil {
+		t.Errorf("GetASN, want error for deleted network got %+v", network)
+	}
+
+	// Deleted objects are still returned when asking for updated ones
+	networks, err = mirror.GetNetwork(UpdatedSince(nil, time.Now().Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*networks) != 1 || (*networks)[0].Status != "deleted" {
+		t.Errorf("GetNetwork since, want deleted network 2 got %+v", *networks)
+	}
+}
@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// networkResource is the top-level structure when parsing the JSON output from
-// the API. This structure is not used if the Network JSON object is included
-// as a field in another JSON object. This structure is used only if the proper
-// namespace is queried.
-type networkResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []Network `json:"data"`
-}
-
 // Network is a structure representing a network. Basically, a network is an
 // Autonomous System identified by an AS number and other details. It belongs
 // to an Organization, contains one or more NetworkContact, and is part of
@@ -28,7 +17,7 @@ type Network struct {
 	AKA                               string       `json:"aka"`
 	NameLong                          string       `json:"name_long"`
 	Website                           string       `json:"website"`
-	ASN                               int          `json:"asn"`
+	ASN                               ASN          `json:"asn"`
 	LookingGlass                      string       `json:"looking_glass"`
 	RouteServer                       string       `json:"route_server"`
 	IRRASSet                          string       `json:"irr_as_set"`
@@ -54,9 +43,9 @@ type Network struct {
 	PolicyLocations                   string       `json:"policy_locations"`
 	PolicyRatio                       bool         `json:"policy_ratio"`
 	PolicyContracts                   string       `json:"policy_contracts"`
-	NetworkFacilitySet                []int        `json:"netfac_set"`
-	NetworkInternetExchangeLANSet     []int        `json:"netixlan_set"`
-	NetworkContactSet                 []int        `json:"poc_set"`
+	NetworkFacilitySet                IDSet        `json:"netfac_set"`
+	NetworkInternetExchangeLANSet     IDSet        `json:"netixlan_set"`
+	NetworkContactSet                 IDSet        `json:"poc_set"`
 	AllowIXPUpdate                    bool         `json:"allow_ixp_update"`
 	StatusDashboard                   string       `json:"status_dashboard"`
 	RIRStatus                         string       `json:"rir_status"`
@@ -70,52 +59,32 @@ type Network struct {
 	} `json:"social_media"`
 }
 
-// getNetworkResource returns a pointer to an networkResource structure
-// corresponding to the API JSON response. An error can be returned if
-// something went wrong.
-func (api *API) getNetworkResource(search map[string]interface{}) (*networkResource, error) {
-	// Get the NetworkResource from the API
-	response, err := api.lookup(networkNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &networkResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetNetwork returns a pointer to a slice of Network structures that the
 // PeeringDB API can provide matching the given search parameters map. If an
-// error occurs, the returned error will be non-nil. The returned value can be
-// nil if no object could be found.
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetNetwork(search map[string]interface{}) (*[]Network, error) {
-	// Ask for the all Network objects
-	networkResource, err := api.getNetworkResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetNetworkWithContext(context.Background(), search)
+}
 
-	// Return all Network objects, will be nil if slice is empty
-	return &networkResource.Data, nil
+// GetNetworkWithContext is the same as GetNetwork but uses the given context
+// for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetNetworkWithContext(ctx context.Context, search map[string]interface{}) (*[]Network, error) {
+	return getObjects[Network](ctx, api, search)
 }
 
 // GetAllNetworks returns a pointer to a slice of Network structures that the
 // PeeringDB API can provide. If an error occurs, the returned error will be
-// non-nil. The can be nil if no object could be found.
+// non-nil. The returned slice is empty, but never nil, if no object could be
+// found.
 func (api *API) GetAllNetworks() (*[]Network, error) {
-	// Return all Network objects
-	return api.GetNetwork(nil)
+	return api.GetAllNetworksWithContext(context.Background())
+}
+
+// GetAllNetworksWithContext is the same as GetAllNetworks but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetAllNetworksWithContext(ctx context.Context) (*[]Network, error) {
+	return getAllObjects[Network](ctx, api)
 }
 
 // GetNetworkByID returns a pointer to a Network structure that matches the
@@ -124,42 +93,13 @@ func (api *API) GetAllNetworks() (*[]Network, error) {
 // some reasons the API returns more than one object for the given ID (but it
 // must not) only the first will be used for the returned value.
 func (api *API) GetNetworkByID(id int) (*Network, error) {
-	// No point of looking for the network with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the Network given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	networks, err := api.GetNetwork(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No Network matching the ID
-	if len(*networks) < 1 {
-		return nil, nil
-	}
-
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*networks)[0], nil
+	return api.GetNetworkByIDWithContext(context.Background(), id)
 }
 
-// networkFacilityResource is the top-level structure when parsing the JSON
-// output from the API. This structure is not used if the NetFacility JSON
-// object is included as a field in another JSON object. This structure is used
-// only if the proper namespace is queried.
-type networkFacilityResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []NetworkFacility `json:"data"`
+// GetNetworkByIDWithContext is the same as GetNetworkByID but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetNetworkByIDWithContext(ctx context.Context, id int) (*Network, error) {
+	return getObjectByID[Network](ctx, api, id)
 }
 
 // NetworkFacility is a structure used to link a Network with a Facility. It
@@ -175,58 +115,40 @@ type NetworkFacility struct {
 	Network    Network   `json:"net,omitempty"`
 	FacilityID int       `json:"fac_id"`
 	Facility   Facility  `json:"fac,omitempty"`
-	LocalASN   int       `json:"local_asn"`
+	LocalASN   ASN       `json:"local_asn"`
 	Created    time.Time `json:"created"`
 	Updated    time.Time `json:"updated"`
 	Status     string    `json:"status"`
 }
 
-// getNetworkFacilityResource returns a pointer to an networkFacilityResource
-// structure corresponding to the API JSON response. An error can be returned
-// if something went wrong.
-func (api *API) getNetworkFacilityResource(search map[string]interface{}) (*networkFacilityResource, error) {
-	// Get the NetworkFacilityResource from the API
-	response, err := api.lookup(networkFacilityNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &networkFacilityResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
-// GetNetworkFacility returns a pointer to a slice of NetworkFacility
-// structures that the PeeringDB API can provide matching the given search
-// parameters map. If an error occurs, the returned error will be non-nil. The
-// returned value can be nil if no object could be found.
+// GetNetworkFacility returns a pointer to a slice of NetworkFacility structures
+// that the PeeringDB API can provide matching the given search parameters map.
+// If an error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetNetworkFacility(search map[string]interface{}) (*[]NetworkFacility, error) {
-	// Ask for the all NetworkFacility objects
-	networkFacilityResource, err := api.getNetworkFacilityResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetNetworkFacilityWithContext(context.Background(), search)
+}
 
-	// Return all NetworkFacility objects, will be nil if slice is empty
-	return &networkFacilityResource.Data, nil
+// GetNetworkFacilityWithContext is the same as GetNetworkFacility but uses the
+// given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetNetworkFacilityWithContext(ctx context.Context, search map[string]interface{}) (*[]NetworkFacility, error) {
+	return getObjects[NetworkFacility](ctx, api, search)
 }
 
 // GetAllNetworkFacilities returns a pointer to a slice of NetworkFacility
 // structures that the PeeringDB API can provide. If an error occurs, the
-// returned error will be non-nil. The can be nil if no object could be found.
+// returned error will be non-nil. The returned slice is empty, but never nil,
+// if no object could be found.
 func (api *API) GetAllNetworkFacilities() (*[]NetworkFacility, error) {
-	// Return all NetFacility objects
-	return api.GetNetworkFacility(nil)
+	return api.GetAllNetworkFacilitiesWithContext(context.Background())
+}
+
+// GetAllNetworkFacilitiesWithContext is the same as GetAllNetworkFacilities but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetAllNetworkFacilitiesWithContext(ctx context.Context) (*[]NetworkFacility, error) {
+	return getAllObjects[NetworkFacility](ctx, api)
 }
 
 // GetNetworkFacilityByID returns a pointer to a NetworkFacility structure that
@@ -236,42 +158,48 @@ func (api *API) GetAllNetworkFacilities() (*[]NetworkFacility, error) {
 // given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetNetworkFacilityByID(id int) (*NetworkFacility, error) {
-	// No point of looking for the network facility with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the NetworkFacility given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
+	return api.GetNetworkFacilityByIDWithContext(context.Background(), id)
+}
 
-	// Actually ask for it
-	networkFacilities, err := api.GetNetworkFacility(search)
+// GetNetworkFacilityByIDWithContext is the same as GetNetworkFacilityByID but
+// uses the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetNetworkFacilityByIDWithContext(ctx context.Context, id int) (*NetworkFacility, error) {
+	return getObjectByID[NetworkFacility](ctx, api, id)
+}
 
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+// GetNetworkFacilitiesByFacilityIDs returns the NetworkFacility structures of
+// all networks present in the facilities matching the given IDs. They are
+// fetched with "fac_id__in" queries, splitting the IDs in as many queries as
+// needed to keep URLs short enough.
+func (api *API) GetNetworkFacilitiesByFacilityIDs(ids []int) ([]NetworkFacility, error) {
+	return api.GetNetworkFacilitiesByFacilityIDsWithContext(context.Background(), ids)
+}
 
-	// No NetworkFacility matching the ID
-	if len(*networkFacilities) < 1 {
-		return nil, nil
-	}
+// GetNetworkFacilitiesByFacilityIDsWithContext is the same as
+// GetNetworkFacilitiesByFacilityIDs but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) GetNetworkFacilitiesByFacilityIDsWithContext(ctx context.Context, ids []int) ([]NetworkFacility, error) {
+	return getChunked(ids, "fac_id", func(search map[string]interface{}) (*[]NetworkFacility, error) {
+		return api.GetNetworkFacilityWithContext(ctx, search)
+	})
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*networkFacilities)[0], nil
+// GetNetworkFacilitiesByNetworkIDs returns the NetworkFacility structures of
+// the networks matching the given IDs, telling in which facilities they are
+// present. They are fetched with "net_id__in" queries, splitting the IDs in as
+// many queries as needed to keep URLs short enough.
+func (api *API) GetNetworkFacilitiesByNetworkIDs(ids []int) ([]NetworkFacility, error) {
+	return api.GetNetworkFacilitiesByNetworkIDsWithContext(context.Background(), ids)
 }
 
-// networkInternetExchangeLANResource is the top-level structure when parsing
-// the JSON output from the API. This structure is not used if the
-// NetworkInternetExchangeLAN JSON object is included as a field in another
-// JSON object. This structure is used only if the proper namespace is queried.
-type networkInternetExchangeLANResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []NetworkInternetExchangeLAN `json:"data"`
+// GetNetworkFacilitiesByNetworkIDsWithContext is the same as
+// GetNetworkFacilitiesByNetworkIDs but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) GetNetworkFacilitiesByNetworkIDsWithContext(ctx context.Context, ids []int) ([]NetworkFacility, error) {
+	return getChunked(ids, "net_id", func(search map[string]interface{}) (*[]NetworkFacility, error) {
+		return api.GetNetworkFacilityWithContext(ctx, search)
+	})
 }
 
 // NetworkInternetExchangeLAN is a structure allowing to know to which
@@ -288,7 +216,7 @@ type NetworkInternetExchangeLAN struct {
 	InternetExchangeLAN    InternetExchangeLAN `json:"ixlan,omitempty"`
 	Notes                  string              `json:"notes"`
 	Speed                  int                 `json:"speed"`
-	ASN                    int                 `json:"asn"`
+	ASN                    ASN                 `json:"asn"`
 	IPAddr4                string              `json:"ipaddr4"`
 	IPAddr6                string              `json:"ipaddr6"`
 	IsRSPeer               bool                `json:"is_rs_peer"`
@@ -301,54 +229,35 @@ type NetworkInternetExchangeLAN struct {
 	Status                 string              `json:"status"`
 }
 
-// getNetworkInternetExchangeLANResource returns a pointer to an
-// networkInternetExchangeLANResource structure corresponding to the API JSON
-// response. An error can be returned if something went wrong.
-func (api *API) getNetworkInternetExchangeLANResource(search map[string]interface{}) (*networkInternetExchangeLANResource, error) {
-	// Get the NetworkInternetExchangeLANResource from the API
-	response, err := api.lookup(networkInternetExchangeLANNamepsace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &networkInternetExchangeLANResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetNetworkInternetExchangeLAN returns a pointer to a slice of
 // NetworkInternetExchangeLAN structures that the PeeringDB API can provide
 // matching the given search parameters map. If an error occurs, the returned
-// error will be non-nil. The returned value can be nil if no object could be
-// found.
+// error will be non-nil. The returned slice is empty, but never nil, if no
+// object could be found.
 func (api *API) GetNetworkInternetExchangeLAN(search map[string]interface{}) (*[]NetworkInternetExchangeLAN, error) {
-	// Ask for the all NetInternetExchangeLAN objects
-	networkInternetExchangeLANResource, err := api.getNetworkInternetExchangeLANResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetNetworkInternetExchangeLANWithContext(context.Background(), search)
+}
 
-	// Return all NetInternetExchangeLAN objects, will be nil if slice is empty
-	return &networkInternetExchangeLANResource.Data, nil
+// GetNetworkInternetExchangeLANWithContext is the same as
+// GetNetworkInternetExchangeLAN but uses the given context for the API calls,
+// allowing to cancel them or to set a deadline.
+func (api *API) GetNetworkInternetExchangeLANWithContext(ctx context.Context, search map[string]interface{}) (*[]NetworkInternetExchangeLAN, error) {
+	return getObjects[NetworkInternetExchangeLAN](ctx, api, search)
 }
 
 // GetAllNetworkInternetExchangeLANs returns a pointer to a slice of
 // NetworkInternetExchangeLAN structures that the PeeringDB API can provide. If
-// an error occurs, the returned error will be non-nil. The can be nil if no
-// object could be found.
+// an error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetAllNetworkInternetExchangeLANs() (*[]NetworkInternetExchangeLAN, error) {
-	// Return all NetworkInternetExchangeLAN objects
-	return api.GetNetworkInternetExchangeLAN(nil)
+	return api.GetAllNetworkInternetExchangeLANsWithContext(context.Background())
+}
+
+// GetAllNetworkInternetExchangeLANsWithContext is the same as
+// GetAllNetworkInternetExchangeLANs but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) GetAllNetworkInternetExchangeLANsWithContext(ctx context.Context) (*[]NetworkInternetExchangeLAN, error) {
+	return getAllObjects[NetworkInternetExchangeLAN](ctx, api)
 }
 
 // GetNetworkInternetExchangeLANByID returns a pointer to a
@@ -358,29 +267,12 @@ func (api *API) GetAllNetworkInternetExchangeLANs() (*[]NetworkInternetExchangeL
 // returns more than one object for the given ID (but it must not) only the
 // first will be used for the returned value.
 func (api *API) GetNetworkInternetExchangeLANByID(id int) (*NetworkInternetExchangeLAN, error) {
-	// No point of looking for the Internet exchange LAN with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the NetworkInternetExchangeLAN given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	networkInternetExchangeLANs, err := api.GetNetworkInternetExchangeLAN(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No NetworkInternetExchangeLAN matching the ID
-	if len(*networkInternetExchangeLANs) < 1 {
-		return nil, nil
-	}
+	return api.GetNetworkInternetExchangeLANByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*networkInternetExchangeLANs)[0], nil
+// GetNetworkInternetExchangeLANByIDWithContext is the same as
+// GetNetworkInternetExchangeLANByID but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) GetNetworkInternetExchangeLANByIDWithContext(ctx context.Context, id int) (*NetworkInternetExchangeLAN, error) {
+	return getObjectByID[NetworkInternetExchangeLAN](ctx, api, id)
 }
@@ -0,0 +1,39 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestGetNetworkFacilitiesByIDs(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkFacilityNamespace: {
+			{"id": 1, "net_id": 10, "fac_id": 1, "local_asn": 64500},
+			{"id": 2, "net_id": 11, "fac_id": 1, "local_asn": 64501},
+			{"id": 3, "net_id": 10, "fac_id": 150, "local_asn": 64500},
+			{"id": 4, "net_id": 12, "fac_id": 2, "local_asn": 64502},
+		},
+	})
+	api := server.api()
+
+	// IDs are split in several queries
+	ids := make([]int, 150)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	networkFacilities, err := api.GetNetworkFacilitiesByFacilityIDs(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networkFacilities) != 4 || server.count(networkFacilityNamespace) != 2 {
+		t.Errorf("GetNetworkFacilitiesByFacilityIDs, want 4 objects in 2 calls got %d in %d",
+			len(networkFacilities), server.count(networkFacilityNamespace))
+	}
+
+	networkFacilities, err = api.GetNetworkFacilitiesByNetworkIDs([]int{10, 12, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networkFacilities) != 3 || networkFacilities[0].ID != 1 || networkFacilities[2].LocalASN != 64502 {
+		t.Errorf("GetNetworkFacilitiesByNetworkIDs, unexpected objects %+v", networkFacilities)
+	}
+}
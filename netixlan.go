@@ -0,0 +1,292 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrInternetExchangeLANNotFound is the error that will be returned if
+	// the IX LAN of a network IX LAN connection cannot be found.
+	ErrInternetExchangeLANNotFound = errors.New("ix lan not found")
+	// ErrAddressNotInPrefixes is the error that will be returned if an IP
+	// address of a network IX LAN connection is not part of the prefixes of
+	// the IX LAN.
+	ErrAddressNotInPrefixes = errors.New("ip address not in ix lan prefixes")
+	// ErrAmbiguousNetworkInternetExchangeLAN is the error that will be
+	// returned if the IPv4 and IPv6 addresses of a network IX LAN connection
+	// to upsert belong to two different existing connections.
+	ErrAmbiguousNetworkInternetExchangeLAN = errors.New("ip addresses used by different netixlan objects")
+)
+
+// PrepareNetworkInternetExchangeLAN checks and completes a network IX LAN
+// connection before submitting it to PeeringDB, preventing the most common
+// submission errors.
+//
+// If the IX LAN ID is not set, the IX LAN is found using the IX ID or, if it
+// is not set either, the IX name given in the Name field. The network ID and
+// the AS number are completed from each other. IP addresses are normalized and
+// must be part of the IPv4 and IPv6 prefixes of the IX LAN, at least one of
+// them being required.
+func (api *API) PrepareNetworkInternetExchangeLAN(netixlan *NetworkInternetExchangeLAN) error {
+	return api.PrepareNetworkInternetExchangeLANWithContext(context.Background(), netixlan)
+}
+
+// PrepareNetworkInternetExchangeLANWithContext is the same as
+// PrepareNetworkInternetExchangeLAN but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) PrepareNetworkInternetExchangeLANWithContext(ctx context.Context, netixlan *NetworkInternetExchangeLAN) error {
+	ixlan, err := api.findInternetExchangeLAN(ctx, netixlan)
+	if err != nil {
+		return err
+	}
+	netixlan.InternetExchangeLANID = ixlan.ID
+	netixlan.InternetExchangeID = ixlan.InternetExchangeID
+
+	if err = api.completeNetwork(ctx, netixlan); err != nil {
+		return err
+	}
+
+	if netixlan.IPAddr4 == "" && netixlan.IPAddr6 == "" {
+		return errors.New("at least one ip address is required")
+	}
+
+	search := make(map[string]interface{})
+	search["ixlan_id"] = ixlan.ID
+	prefixes, err := api.GetInternetExchangePrefixWithContext(ctx, search)
+	if err != nil {
+		return err
+	}
+
+	if netixlan.IPAddr4, err = checkAddress(netixlan.IPAddr4, "ipv4", *prefixes); err != nil {
+		return err
+	}
+	if netixlan.IPAddr6, err = checkAddress(netixlan.IPAddr6, "ipv6", *prefixes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertNetworkInternetExchangeLAN creates the given network IX LAN
+// connection, or updates the existing one if there is one, so that the IX
+// presence of a network can be synced from an IPAM. The existing connection is
+// the one of the same AS number on the same IX LAN with the same IPv4 or IPv6
+// address. The connection is first checked and completed with
+// PrepareNetworkInternetExchangeLAN. Only its fields which are set and differ
+// from the ones of the existing connection are changed, with a PATCH request,
+// the others being left as they are. The given structure is then replaced by
+// the connection as returned by the API, with its ID. It returns whether the
+// connection was created.
+func (api *API) UpsertNetworkInternetExchangeLAN(netixlan *NetworkInternetExchangeLAN) (bool, error) {
+	return api.UpsertNetworkInternetExchangeLANWithContext(context.Background(), netixlan)
+}
+
+// UpsertNetworkInternetExchangeLANWithContext is the same as
+// UpsertNetworkInternetExchangeLAN but uses the given context for the API
+// calls, allowing to cancel them or to set a deadline.
+func (api *API) UpsertNetworkInternetExchangeLANWithContext(ctx context.Context, netixlan *NetworkInternetExchangeLAN) (bool, error) {
+	if err := api.PrepareNetworkInternetExchangeLANWithContext(ctx, netixlan); err != nil {
+		return false, err
+	}
+
+	existing, err := api.findNetworkInternetExchangeLAN(ctx, netixlan)
+	if err != nil {
+		return false, err
+	}
+
+	var written *NetworkInternetExchangeLAN
+	if existing == nil {
+		written, err = Create(ctx, api, netixlan)
+	} else {
+		var changes map[string]interface{}
+		if changes, err = changedFields(existing, netixlan); err == nil {
+			written, err = Patch[NetworkInternetExchangeLAN](ctx, api, existing.ID, changes)
+		}
+		// Nothing to change
+		if err == nil && written == nil {
+			written = existing
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	if written != nil {
+		*netixlan = *written
+	}
+
+	return existing == nil, nil
+}
+
+// changedFields returns the writable fields of a network IX LAN connection
+// which are set and differ from the ones of the existing connection. Fields
+// with a zero value, including false booleans, are considered as not set.
+func changedFields(existing, netixlan *NetworkInternetExchangeLAN) (map[string]interface{}, error) {
+	current, err := writableFields(existing)
+	if err != nil {
+		return nil, err
+	}
+	intended, err := writableFields(netixlan)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]interface{})
+	for name, value := range intended {
+		if isZeroValue(value) || value == false || reflect.DeepEqual(current[name], value) {
+			continue
+		}
+		changes[name] = value
+	}
+
+	return changes, nil
+}
+
+// findNetworkInternetExchangeLAN returns the existing network IX LAN
+// connection of the same AS number on the same IX LAN with the same IPv4 or
+// IPv6 address as the given one, or nil if there is none.
+func (api *API) findNetworkInternetExchangeLAN(ctx context.Context, netixlan *NetworkInternetExchangeLAN) (*NetworkInternetExchangeLAN, error) {
+	search := make(map[string]interface{})
+	search["asn"] = netixlan.ASN
+	search["ixlan_id"] = netixlan.InternetExchangeLANID
+	candidates, err := api.GetNetworkInternetExchangeLANWithContext(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *NetworkInternetExchangeLAN
+	for i, candidate := range *candidates {
+		if !sameAddress(candidate.IPAddr4, netixlan.IPAddr4) && !sameAddress(candidate.IPAddr6, netixlan.IPAddr6) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: %d and %d", ErrAmbiguousNetworkInternetExchangeLAN, found.ID, candidate.ID)
+		}
+		found = &(*candidates)[i]
+	}
+
+	return found, nil
+}
+
+// sameAddress tells if two IP addresses, which may be written differently,
+// are the same. Empty addresses are never the same.
+func sameAddress(a, b string) bool {
+	first, err := netip.ParseAddr(strings.TrimSpace(a))
+	if err != nil {
+		return false
+	}
+	second, err := netip.ParseAddr(strings.TrimSpace(b))
+	if err != nil {
+		return false
+	}
+
+	return first == second
+}
+
+// findInternetExchangeLAN returns the IX LAN of a network IX LAN connection.
+func (api *API) findInternetExchangeLAN(ctx context.Context, netixlan *NetworkInternetExchangeLAN) (*InternetExchangeLAN, error) {
+	if netixlan.InternetExchangeLANID != 0 {
+		ixlan, err := api.GetInternetExchangeLANByIDWithContext(ctx, netixlan.InternetExchangeLANID)
+		if err != nil {
+			return nil, err
+		}
+		if ixlan == nil {
+			return nil, fmt.Errorf("%w: no ix lan with ID %d", ErrInternetExchangeLANNotFound,
+				netixlan.InternetExchangeLANID)
+		}
+		return ixlan, nil
+	}
+
+	ixID := netixlan.InternetExchangeID
+	if ixID == 0 {
+		if netixlan.Name == "" {
+			return nil, fmt.Errorf("%w: ix lan, ix ID or ix name required", ErrInternetExchangeLANNotFound)
+		}
+
+		search := make(map[string]interface{})
+		search["name"] = netixlan.Name
+		ixs, err := api.GetInternetExchangeWithContext(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		if len(*ixs) != 1 {
+			return nil, fmt.Errorf("%w: %d ixs named '%s'", ErrInternetExchangeLANNotFound,
+				len(*ixs), netixlan.Name)
+		}
+		ixID = (*ixs)[0].ID
+	}
+
+	search := make(map[string]interface{})
+	search["ix_id"] = ixID
+	ixlans, err := api.GetInternetExchangeLANWithContext(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	if len(*ixlans) != 1 {
+		return nil, fmt.Errorf("%w: %d ix lans for ix ID %d", ErrInternetExchangeLANNotFound,
+			len(*ixlans), ixID)
+	}
+
+	return &(*ixlans)[0], nil
+}
+
+// completeNetwork sets the network ID or the AS number of a network IX LAN
+// connection if only one of them is known.
+func (api *API) completeNetwork(ctx context.Context, netixlan *NetworkInternetExchangeLAN) error {
+	switch {
+	case netixlan.NetworkID == 0 && netixlan.ASN == 0:
+		return errors.New("network ID or asn required")
+	case netixlan.NetworkID == 0:
+		network, err := api.GetASNWithContext(ctx, netixlan.ASN)
+		if err != nil {
+			return err
+		}
+		if network == nil {
+			return fmt.Errorf("no network found for AS%d", netixlan.ASN)
+		}
+		netixlan.NetworkID = network.ID
+	case netixlan.ASN == 0:
+		network, err := api.GetNetworkByIDWithContext(ctx, netixlan.NetworkID)
+		if err != nil {
+			return err
+		}
+		if network == nil {
+			return fmt.Errorf("no network found for ID %d", netixlan.NetworkID)
+		}
+		netixlan.ASN = network.ASN
+	}
+
+	return nil
+}
+
+// checkAddress normalizes an IP address and checks that it is part of one of
+// the prefixes of the given protocol. An empty address is left as is.
+func checkAddress(address, protocol string, prefixes []InternetExchangePrefix) (string, error) {
+	if address == "" {
+		return "", nil
+	}
+
+	ip, err := netip.ParseAddr(strings.TrimSpace(address))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s address '%s'", protocol, address)
+	}
+	if (protocol == "ipv4") != ip.Is4() {
+		return "", fmt.Errorf("'%s' is not an %s address", address, protocol)
+	}
+
+	for _, prefix := range prefixes {
+		if !strings.EqualFold(prefix.Protocol, protocol) {
+			continue
+		}
+		network, err := netip.ParsePrefix(prefix.Prefix)
+		if err == nil && network.Contains(ip) {
+			return ip.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrAddressNotInPrefixes, ip)
+}
@@ -0,0 +1,111 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPrepareNetworkInternetExchangeLAN(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 10, "asn": 64500, "name": "Network A"},
+		},
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX-A"},
+		},
+		internetExchangeLANNamespace: {
+			{"id": 2, "ix_id": 1},
+		},
+		internetExchangePrefixNamespace: {
+			{"id": 3, "ixlan_id": 2, "protocol": "IPv4", "prefix": "192.0.2.0/24"},
+			{"id": 4, "ixlan_id": 2, "protocol": "IPv6", "prefix": "2001:db8::/64"},
+		},
+	})
+	api := server.api()
+
+	netixlan := &NetworkInternetExchangeLAN{
+		Name:    "IX-A",
+		ASN:     64500,
+		IPAddr4: "192.0.2.10",
+		IPAddr6: "2001:DB8:0::a",
+	}
+	if err := api.PrepareNetworkInternetExchangeLAN(netixlan); err != nil {
+		t.Fatal(err)
+	}
+	if netixlan.InternetExchangeLANID != 2 || netixlan.InternetExchangeID != 1 || netixlan.NetworkID != 10 {
+		t.Errorf("PrepareNetworkInternetExchangeLAN, IDs not filled: %+v", netixlan)
+	}
+	if netixlan.IPAddr6 != "2001:db8::a" {
+		t.Errorf("PrepareNetworkInternetExchangeLAN, want normalized address got %s", netixlan.IPAddr6)
+	}
+
+	netixlan = &NetworkInternetExchangeLAN{
+		InternetExchangeID: 1,
+		NetworkID:          10,
+		IPAddr4:            "198.51.100.1",
+	}
+	if err := api.PrepareNetworkInternetExchangeLAN(netixlan); !errors.Is(err, ErrAddressNotInPrefixes) {
+		t.Errorf("PrepareNetworkInternetExchangeLAN, want ErrAddressNotInPrefixes got %v", err)
+	}
+
+	netixlan = &NetworkInternetExchangeLAN{Name: "IX-B", ASN: 64500, IPAddr4: "192.0.2.10"}
+	if err := api.PrepareNetworkInternetExchangeLAN(netixlan); !errors.Is(err, ErrInternetExchangeLANNotFound) {
+		t.Errorf("PrepareNetworkInternetExchangeLAN, want ErrInternetExchangeLANNotFound got %v", err)
+	}
+}
+
+func TestUpsertNetworkInternetExchangeLAN(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 10, "asn": 64500, "name": "Network A"},
+		},
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX-A"},
+		},
+		internetExchangeLANNamespace: {
+			{"id": 2, "ix_id": 1},
+		},
+		internetExchangePrefixNamespace: {
+			{"id": 3, "ixlan_id": 2, "protocol": "IPv4", "prefix": "192.0.2.0/24"},
+			{"id": 4, "ixlan_id": 2, "protocol": "IPv6", "prefix": "2001:db8::/64"},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 5, "net_id": 10, "asn": 64500, "ixlan_id": 2, "ix_id": 1, "speed": 1000, "ipaddr4": "192.0.2.10", "ipaddr6": "2001:db8::a", "is_rs_peer": true, "operational": true, "notes": "Notes"},
+			{"id": 6, "net_id": 10, "asn": 64500, "ixlan_id": 2, "ix_id": 1, "speed": 1000, "ipaddr4": "192.0.2.11"},
+		},
+	})
+	api := server.api()
+
+	// Matched by its IPv6 address, written differently
+	netixlan := &NetworkInternetExchangeLAN{InternetExchangeLANID: 2, ASN: 64500, Speed: 10000, IPAddr6: "2001:DB8:0::a"}
+	created, err := api.UpsertNetworkInternetExchangeLAN(netixlan)
+	if err != nil || created || netixlan.ID != 5 {
+		t.Fatalf("UpsertNetworkInternetExchangeLAN, expected an update of 5, got %v, %v, %d", created, err, netixlan.ID)
+	}
+	if updated, _ := api.GetNetworkInternetExchangeLANByID(5); updated == nil || updated.Speed != 10000 {
+		t.Errorf("UpsertNetworkInternetExchangeLAN, object not updated: %v", updated)
+	}
+	// Fields which are not set are left as they are
+	if netixlan.IPAddr4 != "192.0.2.10" || !netixlan.IsRSPeer || !netixlan.Operational || netixlan.Notes != "Notes" {
+		t.Errorf("UpsertNetworkInternetExchangeLAN, unset fields overwritten: %+v", netixlan)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	netixlan = &NetworkInternetExchangeLAN{InternetExchangeLANID: 2, ASN: 64500, Speed: 100000, IPAddr4: "192.0.2.10"}
+	if _, err = api.UpsertNetworkInternetExchangeLANWithContext(ctx, netixlan); !errors.Is(err, context.Canceled) {
+		t.Errorf("UpsertNetworkInternetExchangeLANWithContext, want context.Canceled got %v", err)
+	}
+
+	netixlan = &NetworkInternetExchangeLAN{InternetExchangeLANID: 2, ASN: 64500, Speed: 10000, IPAddr4: "192.0.2.12"}
+	created, err = api.UpsertNetworkInternetExchangeLAN(netixlan)
+	if err != nil || !created || netixlan.ID != 7 {
+		t.Fatalf("UpsertNetworkInternetExchangeLAN, expected a creation, got %v, %v, %d", created, err, netixlan.ID)
+	}
+
+	netixlan = &NetworkInternetExchangeLAN{InternetExchangeLANID: 2, ASN: 64500, Speed: 10000, IPAddr4: "192.0.2.11", IPAddr6: "2001:db8::a"}
+	if _, err = api.UpsertNetworkInternetExchangeLAN(netixlan); !errors.Is(err, ErrAmbiguousNetworkInternetExchangeLAN) {
+		t.Errorf("UpsertNetworkInternetExchangeLAN, expected ErrAmbiguousNetworkInternetExchangeLAN, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package peeringdb
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option is a function used to tune an API structure when creating it. Options
+// are given to the NewAPI family of functions.
+type Option func(*API)
+
+// ResponseInfo is a structure describing an API call once it is done. It is
+// given to the hooks registered with WithResponseHook.
+type ResponseInfo struct {
+	// Namespace is the namespace of the objects queried by the call.
+	Namespace string
+	// URL is the URL used to make the call.
+	URL string
+	// RequestID is the ID sent with the request, if any.
+	RequestID string
+	// StatusCode is the HTTP status code of the response, it is 0 if no
+	// response was received.
+	StatusCode int
+	// Duration is the time spent between sending the request and reading the
+	// whole response body.
+	Duration time.Duration
+	// Size is the size of the response body in bytes.
+	Size int64
+	// Retry is the number of times the call was retried before this attempt,
+	// 0 for a first attempt.
+	Retry int
+	// Err is the error returned to the caller, if any.
+	Err error
+}
+
+// WithResponseHook returns an option registering a function called after each
+// API call. It can be used to feed metric systems with the duration, size and
+// status of the responses. Hooks are called synchronously, in the order they
+// are registered, and may be called concurrently if the API structure is
+// shared between goroutines.
+func WithResponseHook(hook func(ResponseInfo)) Option {
+	return func(api *API) {
+		api.responseHooks = append(api.responseHooks, hook)
+	}
+}
+
+// notifyResponse calls all response hooks with the given information.
+func (api *API) notifyResponse(info ResponseInfo) {
+	for _, hook := range api.responseHooks {
+		hook(info)
+	}
+}
+
+// CacheInfo is a structure describing a lookup checked against a cache. It is
+// given to the hooks registered with WithCacheHook.
+type CacheInfo struct {
+	// Namespace is the namespace of the objects looked up.
+	Namespace string
+	// URL is the URL of the lookup.
+	URL string
+	// Hit tells if the lookup was served from the cache, without calling
+	// the API.
+	Hit bool
+}
+
+// WithCacheHook returns an option registering a function called each time a
+// lookup is checked against a cache, such as the one set with WithDiskCache.
+// It can be used to measure how many API calls the cache saves. Hooks are
+// called synchronously, in the order they are registered, and may be called
+// concurrently if the API structure is shared between goroutines.
+func WithCacheHook(hook func(CacheInfo)) Option {
+	return func(api *API) {
+		api.cacheHooks = append(api.cacheHooks, hook)
+	}
+}
+
+// notifyCache calls all cache hooks with the given information.
+func (api *API) notifyCache(info CacheInfo) {
+	for _, hook := range api.cacheHooks {
+		hook(info)
+	}
+}
+
+// WithHTTPClient returns an option setting the HTTP client used to make API
+// calls. The client is shared by all calls so that connections are reused. A
+// client with default settings is used if this option is not given.
+func WithHTTPClient(client *http.Client) Option {
+	return func(api *API) {
+		api.client = client
+	}
+}
+
+// WithTimeout returns an option setting the maximum duration of each API call,
+// including the reading of the response body. Calls taking longer fail with an
+// error matching both ErrQueryingAPI and context.DeadlineExceeded. The timeout
+// applies on top of the deadline of the context given to the call, if any. A
+// value of 0 means no timeout, which is the default. The timeout can be changed
+// for some calls with ContextWithCallTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(api *API) {
+		api.timeout = timeout
+	}
+}
@@ -0,0 +1,120 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithResponseHook(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A"},
+		},
+	})
+
+	var infos []ResponseInfo
+	api := server.api(WithResponseHook(func(info ResponseInfo) {
+		infos = append(infos, info)
+	}))
+
+	network, err := api.GetASN(64500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.Name != "Network A" {
+		t.Errorf("GetASN, unexpected network: %+v", network)
+	}
+
+	if len(infos) != 1 {
+		t.Fatalf("hook, want 1 call got: %d", len(infos))
+	}
+	info := infos[0]
+	if info.Namespace != networkNamespace || info.StatusCode != http.StatusOK || info.Err != nil {
+		t.Errorf("hook, unexpected info: %+v", info)
+	}
+	if info.Size == 0 || info.Duration <= 0 {
+		t.Errorf("hook, size and duration must be measured: %+v", info)
+	}
+
+	// Errors are reported to hooks as well
+	server.setQuota(0)
+	if _, err = api.GetASN(64500); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("GetASN, want rate limit error got: %v", err)
+	}
+	if len(infos) != 2 || infos[1].StatusCode != http.StatusTooManyRequests || !errors.Is(infos[1].Err, ErrRateLimitExceeded) {
+		t.Errorf("hook, unexpected info: %+v", infos[len(infos)-1])
+	}
+}
+
+// roundTripCounter is an http.RoundTripper counting the requests it sends.
+type roundTripCounter struct {
+	count int
+}
+
+func (c *roundTripCounter) RoundTrip(request *http.Request) (*http.Response, error) {
+	c.count++
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestSharedHTTPClient(t *testing.T) {
+	// Connections must be reused between calls
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	api := NewAPIFromURL(server.URL + "/api/")
+	for i := 0; i < 3; i++ {
+		if _, err := api.GetNetworkByID(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count := connections.Load(); count != 1 {
+		t.Errorf("GetNetworkByID, want 1 connection got %d", count)
+	}
+
+	counter := &roundTripCounter{}
+	api = NewAPIFromURL(server.URL+"/api/", WithHTTPClient(&http.Client{Transport: counter}))
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if counter.count != 1 {
+		t.Errorf("WithHTTPClient, want 1 request got %d", counter.count)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	api := NewAPIFromURL(server.URL+"/api/", WithTimeout(50*time.Millisecond))
+	start := time.Now()
+	_, err := api.GetNetworkByID(1)
+	if !errors.Is(err, ErrQueryingAPI) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WithTimeout, want deadline exceeded error got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WithTimeout, call took %s", elapsed)
+	}
+}
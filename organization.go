@@ -1,21 +1,10 @@
 package peeringdb
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 )
 
-// organizationResource is the top-level structure when parsing the JSON output
-// from the API. This structure is not used if the Organization JSON object is
-// included as a field in another JSON object. This structure is used only if
-// the proper namespace is queried.
-type organizationResource struct {
-	Meta struct {
-		Generated float64 `json:"generated,omitempty"`
-	} `json:"meta"`
-	Data []Organization `json:"data"`
-}
-
 // Organization is a structure representing an Organization. An organization
 // can be seen as an enterprise linked to networks, facilities and internet
 // exchange points.
@@ -27,11 +16,11 @@ type Organization struct {
 	Website             string    `json:"website"`
 	Notes               string    `json:"notes"`
 	Require2FA          bool      `json:"require_2fa"`
-	NetworkSet          []int     `json:"net_set"`
-	FacilitySet         []int     `json:"fac_set"`
-	InternetExchangeSet []int     `json:"ix_set"`
-	CarrierSet          []int     `json:"carrier_set"`
-	CampusSet           []int     `json:"campus_set"`
+	NetworkSet          IDSet     `json:"net_set"`
+	FacilitySet         IDSet     `json:"fac_set"`
+	InternetExchangeSet IDSet     `json:"ix_set"`
+	CarrierSet          IDSet     `json:"carrier_set"`
+	CampusSet           IDSet     `json:"campus_set"`
 	Address1            string    `json:"address1"`
 	Address2            string    `json:"address2"`
 	City                string    `json:"city"`
@@ -51,52 +40,33 @@ type Organization struct {
 	} `json:"social_media"`
 }
 
-// getOrganizationResource returns a pointer to an organizationResource
-// structure corresponding to the API JSON response. An error can be returned
-// if something went wrong.
-func (api *API) getOrganizationResource(search map[string]interface{}) (*organizationResource, error) {
-	// Get the OrganizationResource from the API
-	response, err := api.lookup(organizationNamespace, search)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ask for cleanup once we are done
-	defer response.Body.Close()
-
-	// Decode what the API has given to us
-	resource := &organizationResource{}
-	err = json.NewDecoder(response.Body).Decode(&resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return resource, nil
-}
-
 // GetOrganization returns a pointer to a slice of Organization structures that
-// the PeeringDB API can provide matching the given search parameters map. If
-// an error occurs, the returned error will be non-nil. The returned value can
-// be nil if no object could be found.
+// the PeeringDB API can provide matching the given search parameters map. If an
+// error occurs, the returned error will be non-nil. The returned slice is
+// empty, but never nil, if no object could be found.
 func (api *API) GetOrganization(search map[string]interface{}) (*[]Organization, error) {
-	// Ask for the all Organization objects
-	organizationResource, err := api.getOrganizationResource(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
+	return api.GetOrganizationWithContext(context.Background(), search)
+}
 
-	// Return all Organization objects, will be nil if slice is empty
-	return &organizationResource.Data, nil
+// GetOrganizationWithContext is the same as GetOrganization but uses the given
+// context for the API calls, allowing to cancel them or to set a deadline.
+func (api *API) GetOrganizationWithContext(ctx context.Context, search map[string]interface{}) (*[]Organization, error) {
+	return getObjects[Organization](ctx, api, search)
 }
 
 // GetAllOrganizations returns a pointer to a slice of Organization structures
 // that the PeeringDB API can provide. If an error occurs, the returned error
-// will be non-nil. The can be nil if no object could be found.
+// will be non-nil. The returned slice is empty, but never nil, if no object
+// could be found.
 func (api *API) GetAllOrganizations() (*[]Organization, error) {
-	// Return all Organization objects
-	return api.GetOrganization(nil)
+	return api.GetAllOrganizationsWithContext(context.Background())
+}
+
+// GetAllOrganizationsWithContext is the same as GetAllOrganizations but uses
+// the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetAllOrganizationsWithContext(ctx context.Context) (*[]Organization, error) {
+	return getAllObjects[Organization](ctx, api)
 }
 
 // GetOrganizationByID returns a pointer to a Organization structure that
@@ -106,29 +76,12 @@ func (api *API) GetAllOrganizations() (*[]Organization, error) {
 // given ID (but it must not) only the first will be used for the returned
 // value.
 func (api *API) GetOrganizationByID(id int) (*Organization, error) {
-	// No point of looking for the organization with an ID < 0
-	if id < 0 {
-		return nil, nil
-	}
-
-	// Ask for the Organization given it ID
-	search := make(map[string]interface{})
-	search["id"] = id
-
-	// Actually ask for it
-	organizations, err := api.GetOrganization(search)
-
-	// Error as occurred while querying the API
-	if err != nil {
-		return nil, err
-	}
-
-	// No Organization matching the ID
-	if len(*organizations) < 1 {
-		return nil, nil
-	}
+	return api.GetOrganizationByIDWithContext(context.Background(), id)
+}
 
-	// Only return the first match, they must be only one match (ID being
-	// unique)
-	return &(*organizations)[0], nil
+// GetOrganizationByIDWithContext is the same as GetOrganizationByID but uses
+// the given context for the API calls, allowing to cancel them or to set a
+// deadline.
+func (api *API) GetOrganizationByIDWithContext(ctx context.Context, id int) (*Organization, error) {
+	return getObjectByID[Organization](ctx, api, id)
 }
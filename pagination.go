@@ -0,0 +1,70 @@
+package peeringdb
+
+import (
+	"context"
+)
+
+// DefaultPageSize is the number of objects asked per API call by the functions
+// getting all objects of a namespace, such as GetAllNetworks. Large pages keep
+// the number of calls, which count against the API rate limit, low.
+const DefaultPageSize = 5000
+
+// WithPageSize returns an option setting the number of objects asked per API
+// call by the functions getting all objects of a namespace, such as
+// GetAllNetworks. They go through the pages until one is not full. A value of
+// 0 makes them ask for the whole namespace in a single call.
+func WithPageSize(size int) Option {
+	return func(api *API) {
+		api.pageSize = size
+	}
+}
+
+// Paginate returns a copy of the given search parameters map asking for a
+// page of objects, using the "limit" and "skip" parameters of the API. At most
+// limit objects are returned, after skipping the given number of objects.
+func Paginate(search map[string]interface{}, limit, skip int) map[string]interface{} {
+	paginated := make(map[string]interface{}, len(search)+2)
+	for key, value := range search {
+		paginated[key] = value
+	}
+	paginated["limit"] = limit
+	paginated["skip"] = skip
+
+	return paginated
+}
+
+// getAllPages returns all objects of a namespace, fetching them page by page
+// with the given function. The maximum number of results, if set, applies to
+// the objects of all pages.
+func getAllPages[T any](ctx context.Context, api *API, namespace string, get func(context.Context, map[string]interface{}) (*[]T, error)) (*[]T, error) {
+	if api.pageSize <= 0 {
+		return get(ctx, nil)
+	}
+
+	var objects []T
+	for skip := 0; ; skip += api.pageSize {
+		page, err := get(ctx, Paginate(nil, api.pageSize, skip))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, *page...)
+
+		if api.maxResults > 0 && len(objects) > api.maxResults {
+			return nil, &TruncatedError{Namespace: namespace, Max: api.maxResults, Count: len(objects)}
+		}
+
+		// Pages of explained calls are empty, plan the ones expected given
+		// the size of the namespace
+		if api.explain != nil {
+			if skip+api.pageSize >= approximateObjectCounts[namespace] {
+				break
+			}
+			continue
+		}
+		if len(*page) < api.pageSize {
+			break
+		}
+	}
+
+	return nonNilSlice(&objects), nil
+}
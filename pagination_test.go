@@ -0,0 +1,68 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetAllPaginated(t *testing.T) {
+	var networks []map[string]interface{}
+	for i := 1; i <= 5; i++ {
+		networks = append(networks, map[string]interface{}{"id": i, "asn": 64499 + i})
+	}
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: networks,
+	})
+
+	tests := []struct {
+		pageSize int
+		calls    int
+	}{
+		// 3 pages, the last one not full
+		{pageSize: 2, calls: 3},
+		// 5 full pages then an empty one
+		{pageSize: 1, calls: 6},
+		// Whole namespace at once
+		{pageSize: 0, calls: 1},
+		{pageSize: DefaultPageSize, calls: 1},
+	}
+	for _, test := range tests {
+		before := server.count(networkNamespace)
+		found, err := server.api(WithPageSize(test.pageSize)).GetAllNetworks()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(*found) != 5 {
+			t.Errorf("GetAllNetworks with pages of %d, want 5 networks got %d", test.pageSize, len(*found))
+		}
+		for i, network := range *found {
+			if network.ID != i+1 {
+				t.Errorf("GetAllNetworks with pages of %d, unexpected networks order: %+v", test.pageSize, *found)
+				break
+			}
+		}
+		if calls := server.count(networkNamespace) - before; calls != test.calls {
+			t.Errorf("GetAllNetworks with pages of %d, want %d calls got %d", test.pageSize, test.calls, calls)
+		}
+	}
+
+	// The maximum number of results applies to all pages, the last one
+	// overshooting it
+	_, err := server.api(WithPageSize(2), WithMaxResults(3)).GetAllNetworks()
+	var truncated *TruncatedError
+	if !errors.As(err, &truncated) || truncated.Max != 3 || truncated.Count != 4 {
+		t.Errorf("GetAllNetworks, want ErrTruncated after 4 objects got %v", err)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	search := map[string]interface{}{"asn__in": "64500,64501"}
+	paginated := Paginate(search, 10, 20)
+
+	if paginated["limit"] != 10 || paginated["skip"] != 20 || paginated["asn__in"] != "64500,64501" {
+		t.Errorf("Paginate, unexpected parameters: %v", paginated)
+	}
+	if _, ok := search["limit"]; ok {
+		t.Error("Paginate, search parameters modified")
+	}
+}
@@ -0,0 +1,306 @@
+package peeringdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// This file implements a minimal Parquet writer, enough to export tables
+// without depending on a third party library. Files contain a single row
+// group with a single uncompressed, PLAIN encoded, data page per column. All
+// columns are optional. File metadata is encoded with the Thrift compact
+// protocol as required by the Parquet format.
+
+const (
+	parquetMagic = "PAR1"
+
+	// Physical types
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	// Converted types
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	// Encodings
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetOptional = 1
+	parquetDataPage = 0
+	parquetVersion  = 1
+)
+
+// Thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes values with the Thrift compact protocol.
+type thriftWriter struct {
+	buffer bytes.Buffer
+	last   []int16
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	w.buffer.Write(binary.AppendUvarint(nil, v))
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+// field writes a field header.
+func (w *thriftWriter) field(id int16, fieldType byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buffer.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buffer.WriteByte(fieldType)
+		w.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) beginStruct() {
+	w.last = append(w.last, 0)
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buffer.WriteByte(0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) binary(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.varint(uint64(len(v)))
+	w.buffer.WriteString(v)
+}
+
+// list writes a list header, elements must be written right after.
+func (w *thriftWriter) list(id int16, elementType byte, size int) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.buffer.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		w.buffer.WriteByte(0xf0 | elementType)
+		w.varint(uint64(size))
+	}
+}
+
+// parquetColumn is the encoded data page of a column.
+type parquetColumn struct {
+	physicalType int32
+	page         []byte
+	header       []byte
+	offset       int64
+}
+
+// parquetTypes returns the physical and converted types used for a column
+// type, the converted type being -1 if there is none.
+func parquetTypes(columnType ColumnType) (int32, int32) {
+	switch columnType {
+	case ColumnInteger:
+		return parquetInt64, -1
+	case ColumnFloat:
+		return parquetDouble, -1
+	case ColumnBoolean:
+		return parquetBoolean, -1
+	case ColumnTime:
+		return parquetInt64, parquetTimestampMicros
+	default:
+		return parquetByteArray, parquetUTF8
+	}
+}
+
+// encodeParquetPage encodes the values of a column as a data page: the
+// definition levels, RLE encoded, followed by the non-null values.
+func encodeParquetPage(t *Table, index int) []byte {
+	var levels, values bytes.Buffer
+	var bits, bitCount byte
+
+	// Definition levels are encoded as runs of identical values
+	run, current := 0, byte(0)
+	flush := func() {
+		if run > 0 {
+			levels.Write(binary.AppendUvarint(nil, uint64(run)<<1))
+			levels.WriteByte(current)
+		}
+	}
+
+	for _, row := range t.Rows {
+		value := row[index]
+
+		level := byte(1)
+		if value == nil {
+			level = 0
+		}
+		if level != current {
+			flush()
+			run, current = 0, level
+		}
+		run++
+
+		switch v := value.(type) {
+		case int64:
+			values.Write(binary.LittleEndian.AppendUint64(nil, uint64(v)))
+		case float64:
+			values.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+		case time.Time:
+			values.Write(binary.LittleEndian.AppendUint64(nil, uint64(v.UnixMicro())))
+		case string:
+			values.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+			values.WriteString(v)
+		case bool:
+			// Booleans are bit-packed, least significant bit first
+			if v {
+				bits |= 1 << bitCount
+			}
+			bitCount++
+			if bitCount == 8 {
+				values.WriteByte(bits)
+				bits, bitCount = 0, 0
+			}
+		}
+	}
+	flush()
+	if bitCount > 0 {
+		values.WriteByte(bits)
+	}
+
+	page := binary.LittleEndian.AppendUint32(nil, uint32(levels.Len()))
+	page = append(page, levels.Bytes()...)
+
+	return append(page, values.Bytes()...)
+}
+
+// WriteParquet writes the table as a Parquet file.
+func (t *Table) WriteParquet(w io.Writer) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	columns := make([]parquetColumn, len(t.Columns))
+	for i, column := range t.Columns {
+		physicalType, _ := parquetTypes(column.Type)
+		page := encodeParquetPage(t, i)
+
+		header := &thriftWriter{}
+		header.beginStruct()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.field(5, thriftStruct)
+		header.beginStruct()
+		header.i32(1, int32(len(t.Rows)))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.endStruct()
+		header.endStruct()
+
+		columns[i] = parquetColumn{
+			physicalType: physicalType,
+			page:         page,
+			header:       header.buffer.Bytes(),
+			offset:       int64(file.Len()),
+		}
+		file.Write(columns[i].header)
+		file.Write(page)
+	}
+
+	metadata := &thriftWriter{}
+	metadata.beginStruct()
+	metadata.i32(1, parquetVersion)
+
+	// Schema, made of a root element followed by the columns
+	metadata.list(2, thriftStruct, len(t.Columns)+1)
+	metadata.beginStruct()
+	metadata.binary(4, "schema")
+	metadata.i32(5, int32(len(t.Columns)))
+	metadata.endStruct()
+	for _, column := range t.Columns {
+		physicalType, convertedType := parquetTypes(column.Type)
+		metadata.beginStruct()
+		metadata.i32(1, physicalType)
+		metadata.i32(3, parquetOptional)
+		metadata.binary(4, column.Name)
+		if convertedType >= 0 {
+			metadata.i32(6, convertedType)
+		}
+		metadata.endStruct()
+	}
+	metadata.i64(3, int64(len(t.Rows)))
+
+	// A single row group containing all columns
+	var totalSize int64
+	for _, column := range columns {
+		totalSize += int64(len(column.header) + len(column.page))
+	}
+	metadata.list(4, thriftStruct, 1)
+	metadata.beginStruct()
+	metadata.list(1, thriftStruct, len(columns))
+	for i, column := range columns {
+		size := int64(len(column.header) + len(column.page))
+
+		metadata.beginStruct()
+		metadata.i64(2, column.offset)
+		metadata.field(3, thriftStruct)
+		metadata.beginStruct()
+		metadata.i32(1, column.physicalType)
+		metadata.list(2, thriftI32, 2)
+		metadata.zigzag(parquetPlain)
+		metadata.zigzag(parquetRLE)
+		metadata.list(3, thriftBinary, 1)
+		metadata.varint(uint64(len(t.Columns[i].Name)))
+		metadata.buffer.WriteString(t.Columns[i].Name)
+		metadata.i32(4, 0)
+		metadata.i64(5, int64(len(t.Rows)))
+		metadata.i64(6, size)
+		metadata.i64(7, size)
+		metadata.i64(9, column.offset)
+		metadata.endStruct()
+		metadata.endStruct()
+	}
+	metadata.i64(2, totalSize)
+	metadata.i64(3, int64(len(t.Rows)))
+	metadata.endStruct()
+	metadata.binary(6, "github.com/gmazoyer/peeringdb")
+	metadata.endStruct()
+
+	file.Write(metadata.buffer.Bytes())
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(metadata.buffer.Len())))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// ExportParquet writes the objects of a namespace stored in the mirror as a
+// Parquet file.
+func (m *Mirror) ExportParquet(w io.Writer, namespace string) error {
+	table, err := m.Table(namespace)
+	if err != nil {
+		return err
+	}
+
+	return table.WriteParquet(w)
+}
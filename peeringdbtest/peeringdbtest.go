@@ -0,0 +1,77 @@
+// Package peeringdbtest provides helpers to test code using PeeringDB
+// deterministically, without network access. Objects are given as fixtures,
+// written by hand or generated with the "peeringdb fixtures" command, and are
+// either served by an HTTP server speaking the PeeringDB API format:
+//
+//	server := peeringdbtest.NewServer(t, peeringdbtest.LoadFixtures(t, "testdata/fixtures.json"))
+//	api := server.API()
+//
+// or, for code depending on the peeringdb.Client interface, directly from
+// memory with NewClient.
+package peeringdbtest
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// Server is an HTTP server serving fixtures the way the PeeringDB API does.
+// Only lookups are supported.
+type Server struct {
+	*httptest.Server
+
+	// Fixtures are the objects served.
+	Fixtures peeringdb.Fixtures
+}
+
+// NewServer starts and returns a Server serving the given fixtures. The
+// server is closed when the test and all its subtests are done.
+func NewServer(tb testing.TB, fixtures peeringdb.Fixtures) *Server {
+	tb.Helper()
+
+	server := &Server{Server: httptest.NewServer(fixtures.Handler()), Fixtures: fixtures}
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+// APIURL returns the URL of the API served, to give to NewAPIFromURL for
+// instance.
+func (s *Server) APIURL() string {
+	return s.URL + "/api/"
+}
+
+// API returns a pointer to a new API structure querying the server, tuned
+// with the given options.
+func (s *Server) API(options ...peeringdb.Option) *peeringdb.API {
+	return peeringdb.NewAPIFromURL(s.APIURL(), options...)
+}
+
+// NewClient returns a peeringdb.Client serving the given fixtures from
+// memory.
+func NewClient(fixtures peeringdb.Fixtures) peeringdb.Client {
+	return fixtures.Client()
+}
+
+// LoadFixtures reads fixtures from the given JSON file, as written by the
+// "peeringdb fixtures" command. The test fails right away if they cannot be
+// read.
+func LoadFixtures(tb testing.TB, path string) peeringdb.Fixtures {
+	tb.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer file.Close()
+
+	fixtures, err := peeringdb.ReadFixtures(file)
+	if err != nil {
+		tb.Fatalf("invalid fixtures %s: %v", path, err)
+	}
+
+	return fixtures
+}
@@ -0,0 +1,31 @@
+package peeringdbtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+func TestServerAndClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	fixtures := `{"net": [{"id": 1, "asn": 64500, "name": "Network A"}]}`
+	if err := os.WriteFile(path, []byte(fixtures), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := LoadFixtures(t, path)
+	for name, client := range map[string]peeringdb.Client{
+		"server": NewServer(t, loaded).API(),
+		"memory": NewClient(loaded),
+	} {
+		network, err := client.GetASN(64500)
+		if err != nil || network == nil || network.Name != "Network A" {
+			t.Errorf("%s: GetASN, unexpected network %+v: %v", name, network, err)
+		}
+		if network, err = client.GetNetworkByID(2); err != nil || network != nil {
+			t.Errorf("%s: GetNetworkByID, want no network got %+v: %v", name, network, err)
+		}
+	}
+}
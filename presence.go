@@ -0,0 +1,112 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInternetExchangeNotFound is the error that will be returned if no
+// Internet exchange point matches a given name.
+var ErrInternetExchangeNotFound = errors.New("ix not found")
+
+// GetPresenceAtIX returns the network IX LAN connections of the network with
+// the given AS number at the Internet exchange point matching the given name.
+// The name does not need to be exact, case, punctuation and spaces are
+// ignored, and the IX with the closest name or long name is used. It returns
+// nil if the network is not present at the IX.
+func (api *API) GetPresenceAtIX(asn int, ixName string) ([]NetworkInternetExchangeLAN, error) {
+	ix, err := api.findInternetExchangeByName(ixName)
+	if err != nil {
+		return nil, err
+	}
+
+	search := make(map[string]interface{})
+	search["asn"] = asn
+	search["ix_id"] = ix.ID
+
+	networkIXLANs, err := api.GetNetworkInternetExchangeLAN(search)
+	if err != nil {
+		return nil, err
+	}
+	if len(*networkIXLANs) == 0 {
+		return nil, nil
+	}
+
+	return *networkIXLANs, nil
+}
+
+// findInternetExchangeByName returns the Internet exchange point with the
+// name closest to the given one. IXs with a name containing the given one are
+// looked up first, all IXs are considered if none is found.
+func (api *API) findInternetExchangeByName(name string) (*InternetExchange, error) {
+	wanted := normalizeName(name)
+	if wanted == "" {
+		return nil, fmt.Errorf("%w: empty name", ErrInternetExchangeNotFound)
+	}
+
+	search := make(map[string]interface{})
+	search["name__contains"] = strings.TrimSpace(name)
+
+	ixs, err := api.GetInternetExchange(search)
+	if err != nil {
+		return nil, err
+	}
+	if len(*ixs) == 0 {
+		if ixs, err = api.GetAllInternetExchanges(); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		best      *InternetExchange
+		bestScore int
+	)
+	for i, ix := range *ixs {
+		score := max(nameScore(wanted, ix.Name), nameScore(wanted, ix.NameLong))
+		if score == 0 {
+			continue
+		}
+		// Prefer the best score, then the shortest name which is the
+		// closest to the wanted one
+		if best == nil || score > bestScore || (score == bestScore && len(ix.Name) < len(best.Name)) {
+			best, bestScore = &(*ixs)[i], score
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: no ix named '%s'", ErrInternetExchangeNotFound, name)
+	}
+
+	return best, nil
+}
+
+// nameScore tells how close a name is to the wanted normalized name, the
+// higher the closer. It returns 0 if the name does not match at all.
+func nameScore(wanted, name string) int {
+	normalized := normalizeName(name)
+	switch {
+	case normalized == "":
+		return 0
+	case normalized == wanted:
+		return 3
+	case strings.HasPrefix(normalized, wanted):
+		return 2
+	case strings.Contains(normalized, wanted):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// normalizeName returns the name in lower case without any character other
+// than letters and digits, so "DE-CIX Frankfurt" and "de cix frankfurt" are
+// the same.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
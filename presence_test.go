@@ -0,0 +1,53 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetPresenceAtIX(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		internetExchangeNamespace: {
+			{"id": 1, "name": "DE-CIX Frankfurt", "name_long": "DE-CIX Frankfurt/Main"},
+			{"id": 2, "name": "DE-CIX Frankfurt Metro", "name_long": ""},
+			{"id": 3, "name": "AMS-IX", "name_long": "Amsterdam Internet Exchange"},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "asn": 64500, "ix_id": 1, "ipaddr4": "192.0.2.1"},
+			{"id": 2, "asn": 64500, "ix_id": 1, "ipaddr4": "192.0.2.2"},
+			{"id": 3, "asn": 64500, "ix_id": 2, "ipaddr4": "198.51.100.1"},
+			{"id": 4, "asn": 64501, "ix_id": 3, "ipaddr4": "203.0.113.1"},
+		},
+	})
+	api := server.api()
+
+	tests := []struct {
+		name     string
+		asn      int
+		expected int
+	}{
+		{"DE-CIX Frankfurt", 64500, 2},
+		{"de cix frankfurt", 64500, 2},
+		{"decix frankfurt metro", 64500, 1},
+		{"amsterdam internet exchange", 64501, 1},
+		{"AMS-IX", 64500, 0},
+	}
+
+	for _, test := range tests {
+		networkIXLANs, err := api.GetPresenceAtIX(test.asn, test.name)
+		if err != nil {
+			t.Fatalf("GetPresenceAtIX(%d, %q): %v", test.asn, test.name, err)
+		}
+		if len(networkIXLANs) != test.expected {
+			t.Errorf("GetPresenceAtIX(%d, %q), want %d connections got %d", test.asn, test.name,
+				test.expected, len(networkIXLANs))
+		}
+		if test.expected == 0 && networkIXLANs != nil {
+			t.Errorf("GetPresenceAtIX(%d, %q), want nil", test.asn, test.name)
+		}
+	}
+
+	if _, err := api.GetPresenceAtIX(64500, "LINX"); !errors.Is(err, ErrInternetExchangeNotFound) {
+		t.Errorf("GetPresenceAtIX, want ErrInternetExchangeNotFound got %v", err)
+	}
+}
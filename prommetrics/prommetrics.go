@@ -0,0 +1,130 @@
+// Package prommetrics exposes the usage of the PeeringDB API as Prometheus
+// metrics, so that services embedding the client can alert on rate limiting
+// before their automation breaks. A Collector is registered like any other
+// collector and plugged into an API structure with its options:
+//
+//	collector := prommetrics.NewCollector(prommetrics.Options{})
+//	prometheus.MustRegister(collector)
+//	api := peeringdb.NewAPI(collector.Options()...)
+//
+// The following metrics are exposed, prefixed with "peeringdb_" by default:
+//
+//	requests_total{namespace,status}     API calls by status code
+//	request_duration_seconds{namespace}  duration of API calls
+//	rate_limited_total{namespace}        API calls rejected by the rate limit
+//	cache_hits_total{namespace}          lookups served from a cache
+//	cache_misses_total{namespace}        lookups not found in a cache
+package prommetrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gmazoyer/peeringdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Options is a structure used to tune the metrics of a Collector.
+type Options struct {
+	// Namespace is prepended to the name of the metrics, "peeringdb" if it
+	// is empty.
+	Namespace string
+	// ConstLabels are labels with fixed values added to all metrics, to
+	// tell apart several API structures for instance.
+	ConstLabels prometheus.Labels
+	// Buckets are the buckets of the request duration histogram, in
+	// seconds. The default Prometheus buckets are used if it is empty.
+	Buckets []float64
+}
+
+// Collector is a Prometheus collector measuring the API calls made by the API
+// structures it is plugged into. It is safe for concurrent use.
+type Collector struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	rateLimited *prometheus.CounterVec
+	cacheHits   *prometheus.CounterVec
+	cacheMisses *prometheus.CounterVec
+}
+
+// NewCollector returns a pointer to a new Collector tuned with the given
+// options.
+func NewCollector(options Options) *Collector {
+	if options.Namespace == "" {
+		options.Namespace = "peeringdb"
+	}
+	if len(options.Buckets) == 0 {
+		options.Buckets = prometheus.DefBuckets
+	}
+
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        name,
+			Help:        help,
+			ConstLabels: options.ConstLabels,
+		}, labels)
+	}
+
+	return &Collector{
+		requests: counter("requests_total", "Number of PeeringDB API calls by namespace and status code, 0 if no response was received.", "namespace", "status"),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   options.Namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of PeeringDB API calls by namespace.",
+			ConstLabels: options.ConstLabels,
+			Buckets:     options.Buckets,
+		}, []string{"namespace"}),
+		rateLimited: counter("rate_limited_total", "Number of PeeringDB API calls rejected by the rate limit.", "namespace"),
+		cacheHits:   counter("cache_hits_total", "Number of PeeringDB lookups served from a cache.", "namespace"),
+		cacheMisses: counter("cache_misses_total", "Number of PeeringDB lookups not found in a cache.", "namespace"),
+	}
+}
+
+// Options returns the options to give to the NewAPI family of functions to
+// measure the calls of an API structure. Several API structures can be
+// measured by the same collector.
+func (c *Collector) Options() []peeringdb.Option {
+	return []peeringdb.Option{
+		peeringdb.WithResponseHook(c.observeResponse),
+		peeringdb.WithCacheHook(c.observeCache),
+	}
+}
+
+// observeResponse updates the metrics with an API call.
+func (c *Collector) observeResponse(info peeringdb.ResponseInfo) {
+	c.requests.WithLabelValues(info.Namespace, strconv.Itoa(info.StatusCode)).Inc()
+	c.duration.WithLabelValues(info.Namespace).Observe(info.Duration.Seconds())
+	if info.StatusCode == http.StatusTooManyRequests {
+		c.rateLimited.WithLabelValues(info.Namespace).Inc()
+	}
+}
+
+// observeCache updates the metrics with a lookup checked against a cache.
+func (c *Collector) observeCache(info peeringdb.CacheInfo) {
+	if info.Hit {
+		c.cacheHits.WithLabelValues(info.Namespace).Inc()
+	} else {
+		c.cacheMisses.WithLabelValues(info.Namespace).Inc()
+	}
+}
+
+// Describe sends the descriptors of the metrics to the given channel. It
+// implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.rateLimited.Describe(ch)
+	c.cacheHits.Describe(ch)
+	c.cacheMisses.Describe(ch)
+}
+
+// Collect sends the metrics to the given channel. It implements
+// prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.rateLimited.Collect(ch)
+	c.cacheHits.Collect(ch)
+	c.cacheMisses.Collect(ch)
+}
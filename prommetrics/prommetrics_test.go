@@ -0,0 +1,56 @@
+package prommetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/net" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "name": "Facility"}]}`))
+	}))
+	defer server.Close()
+
+	collector := NewCollector(Options{})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	options := append(collector.Options(), peeringdb.WithDiskCache(t.TempDir(), peeringdb.DiskCacheReadThrough))
+	api := peeringdb.NewAPIFromURL(server.URL+"/api/", options...)
+
+	if _, err := api.GetNetworkByID(1); err == nil {
+		t.Error("GetNetworkByID, want an error")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := api.GetFacilityByID(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		collector prometheus.Collector
+		expected  float64
+	}{
+		{collector.requests.WithLabelValues("net", "429"), 1},
+		{collector.requests.WithLabelValues("fac", "200"), 1},
+		{collector.rateLimited.WithLabelValues("net"), 1},
+		{collector.cacheHits.WithLabelValues("fac"), 1},
+		{collector.cacheMisses.WithLabelValues("fac"), 1},
+		{collector.cacheMisses.WithLabelValues("net"), 1},
+	} {
+		if value := testutil.ToFloat64(tc.collector); value != tc.expected {
+			t.Errorf("Collector, want %v got %v", tc.expected, value)
+		}
+	}
+	if count := testutil.CollectAndCount(collector, "peeringdb_request_duration_seconds"); count != 2 {
+		t.Errorf("CollectAndCount, want 2 duration series got %d", count)
+	}
+}
@@ -0,0 +1,176 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+const (
+	// fromCacheHeader is the header set on responses served from the disk
+	// cache.
+	fromCacheHeader = "X-From-Cache"
+	// fromMemoryCacheHeader is the header set on responses served from the
+	// memory cache, holding the time at which they were fetched.
+	fromMemoryCacheHeader = "X-From-Memory-Cache"
+)
+
+// ProvenanceSource is the kind of source objects were read from.
+type ProvenanceSource int
+
+const (
+	// SourceAPI is used for objects fetched from the live API.
+	SourceAPI ProvenanceSource = iota
+	// SourceDiskCache is used for objects read from the disk cache of the
+	// API, see WithDiskCache.
+	SourceDiskCache
+	// SourceMemoryCache is used for objects kept in memory by a
+	// LayeredClient, or by the API, see WithMemoryCache.
+	SourceMemoryCache
+	// SourceMirror is used for objects read from a Mirror.
+	SourceMirror
+)
+
+// String returns the name of the source.
+func (s ProvenanceSource) String() string {
+	switch s {
+	case SourceAPI:
+		return "api"
+	case SourceDiskCache:
+		return "disk-cache"
+	case SourceMemoryCache:
+		return "memory-cache"
+	case SourceMirror:
+		return "mirror"
+	default:
+		return fmt.Sprintf("source(%d)", int(s))
+	}
+}
+
+// Provenance is a structure describing where objects come from, so that
+// callers can apply their own trust and freshness policies.
+type Provenance struct {
+	// Source is the kind of source the objects were read from.
+	Source ProvenanceSource
+	// FetchedAt is the time at which the objects were fetched from the live
+	// API. For a mirror, it is the time of its last synchronization.
+	FetchedAt time.Time
+	// Authenticated tells if the objects were fetched with an API key, in
+	// which case they may include data not visible to anonymous users.
+	Authenticated bool
+}
+
+// Age returns the time elapsed since the objects were fetched from the live
+// API.
+func (p Provenance) Age(now time.Time) time.Duration {
+	return now.Sub(p.FetchedAt)
+}
+
+// Annotated is an object along with its provenance.
+type Annotated[T any] struct {
+	Object     T
+	Provenance Provenance
+}
+
+// provenanceSource is implemented by sources of objects able to tell where
+// the objects they return come from.
+type provenanceSource interface {
+	getRawWithProvenance(namespace string, search map[string]interface{}) ([]json.RawMessage, Provenance, error)
+}
+
+var (
+	_ provenanceSource = (*API)(nil)
+	_ provenanceSource = (*Mirror)(nil)
+	_ provenanceSource = (*LayeredClient)(nil)
+)
+
+// GetAnnotated returns the objects matching the given search parameters map,
+// along with their provenance. The namespace is the one of the type of the
+// objects, which must be one of the structures of this package, such as
+// Network. The client must be an API, a Mirror or a LayeredClient.
+func GetAnnotated[T any](client Client, search map[string]interface{}) ([]Annotated[T], error) {
+	source, ok := client.(provenanceSource)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedSource, client)
+	}
+
+	var namespace string
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for name, candidate := range namespaceTypes {
+		if candidate == t {
+			namespace = name
+		}
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("%w: no namespace for %s", ErrUnknownNamespace, t)
+	}
+
+	objects, provenance, err := source.getRawWithProvenance(namespace, search)
+	if err != nil {
+		return nil, err
+	}
+
+	annotated := make([]Annotated[T], len(objects))
+	for i, object := range objects {
+		if err = json.Unmarshal(object, &annotated[i].Object); err != nil {
+			return nil, err
+		}
+		annotated[i].Provenance = provenance
+	}
+
+	return annotated, nil
+}
+
+// getRawWithProvenance returns the objects of a namespace matching the given
+// search parameters map as raw JSON, along with their provenance.
+func (api *API) getRawWithProvenance(namespace string, search map[string]interface{}) ([]json.RawMessage, Provenance, error) {
+	apiKey, _ := api.credentials(context.Background())
+	provenance := Provenance{Source: SourceAPI, FetchedAt: time.Now(), Authenticated: apiKey != ""}
+
+	response, err := api.lookup(namespace, search)
+	if err != nil {
+		return nil, provenance, err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.Header.Get(fromMemoryCacheHeader) != "":
+		provenance.Source = SourceMemoryCache
+		if fetched, err := time.Parse(time.RFC3339Nano, response.Header.Get(fromMemoryCacheHeader)); err == nil {
+			provenance.FetchedAt = fetched
+		}
+	case response.Header.Get(fromCacheHeader) != "":
+		provenance.Source = SourceDiskCache
+		if fetched, err := http.ParseTime(response.Header.Get("Date")); err == nil {
+			provenance.FetchedAt = fetched
+		}
+	}
+
+	resource := &rawResource{}
+	if err = json.NewDecoder(response.Body).Decode(resource); err != nil {
+		return nil, provenance, err
+	}
+
+	return resource.Data, provenance, nil
+}
+
+// getRawWithProvenance returns the objects of a namespace matching the given
+// search parameters map as raw JSON, along with their provenance.
+func (m *Mirror) getRawWithProvenance(namespace string, search map[string]interface{}) ([]json.RawMessage, Provenance, error) {
+	objects, err := m.getRaw(namespace, search)
+	if err != nil {
+		return nil, Provenance{}, err
+	}
+
+	state, _ := m.State(namespace)
+	provenance := Provenance{Source: SourceMirror, FetchedAt: state.LastSync}
+	if m.api != nil {
+		apiKey, _ := m.api.credentials(context.Background())
+		provenance.Authenticated = apiKey != ""
+	}
+
+	return objects, provenance, nil
+}
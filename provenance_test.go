@@ -0,0 +1,92 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetAnnotated(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace:  {{"id": 1, "asn": 64500, "name": "Network A"}},
+		facilityNamespace: {{"id": 1, "name": "Facility A"}},
+	})
+	search := map[string]interface{}{"id": 1}
+	start := time.Now().Add(-time.Second)
+
+	// Live API, authenticated or not
+	networks, err := GetAnnotated[Network](server.api(), search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || networks[0].Object.ASN != 64500 {
+		t.Fatalf("GetAnnotated, unexpected networks: %+v", networks)
+	}
+	if p := networks[0].Provenance; p.Source != SourceAPI || p.Authenticated || p.FetchedAt.Before(start) {
+		t.Errorf("GetAnnotated, unexpected API provenance: %+v", p)
+	}
+	networks, err = GetAnnotated[Network](NewAPIFromURLWithAPIKey(server.URL+"/api/", "key"), search)
+	if err != nil || len(networks) != 1 || !networks[0].Provenance.Authenticated {
+		t.Errorf("GetAnnotated, want authenticated provenance got %+v (%v)", networks, err)
+	}
+
+	// Disk cache
+	directory := t.TempDir()
+	api := server.api(WithDiskCache(directory, DiskCacheReadThrough))
+	for _, want := range []ProvenanceSource{SourceAPI, SourceDiskCache} {
+		networks, err = GetAnnotated[Network](api, search)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := networks[0].Provenance; p.Source != want || p.FetchedAt.Before(start.Truncate(time.Second)) {
+			t.Errorf("GetAnnotated, want %s got %+v", want, p)
+		}
+	}
+
+	// Memory cache of the API, with the time of the first lookup
+	api = server.api(WithMemoryCache(time.Minute, 0))
+	fetched := time.Now().Add(-time.Hour)
+	api.memoryCache.now = func() time.Time { return fetched }
+	for _, want := range []ProvenanceSource{SourceAPI, SourceMemoryCache} {
+		networks, err = GetAnnotated[Network](api, search)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := networks[0].Provenance; p.Source != want {
+			t.Errorf("GetAnnotated, want %s got %+v", want, p)
+		}
+	}
+	if p := networks[0].Provenance; !p.FetchedAt.Equal(fetched) {
+		t.Errorf("GetAnnotated, want memory cache fetched at %s got %+v", fetched, p)
+	}
+
+	// Mirror, memory cache and API through a layered client
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := mirror.State(networkNamespace)
+	networks, err = GetAnnotated[Network](mirror, search)
+	if err != nil || len(networks) != 1 || networks[0].Provenance.Source != SourceMirror || !networks[0].Provenance.FetchedAt.Equal(state.LastSync) {
+		t.Errorf("GetAnnotated, unexpected mirror result %+v (%v)", networks, err)
+	}
+
+	client := NewLayeredClient(server.api(), mirror, FreshnessPolicy{CacheTTL: time.Minute})
+	for _, want := range []ProvenanceSource{SourceMirror, SourceMemoryCache} {
+		networks, err = GetAnnotated[Network](client, search)
+		if err != nil || len(networks) != 1 || networks[0].Provenance.Source != want {
+			t.Errorf("GetAnnotated, want %s got %+v (%v)", want, networks, err)
+		}
+	}
+	facilities, err := GetAnnotated[Facility](client, search)
+	if err != nil || len(facilities) != 1 || facilities[0].Provenance.Source != SourceAPI {
+		t.Errorf("GetAnnotated, want API facility got %+v (%v)", facilities, err)
+	}
+
+	if _, err = GetAnnotated[FacilityTenant](client, search); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("GetAnnotated, want ErrUnknownNamespace got %v", err)
+	}
+}
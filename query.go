@@ -0,0 +1,155 @@
+package peeringdb
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SearchFilter is a filter of a search parameters map, made of a parameter
+// name, such as "asn__in", and of its value, as sent in the query string.
+// Filters built with Filter, InInts, InASNs or InStrings can be added to a
+// search parameters map with Where.
+type SearchFilter interface {
+	Parameter() string
+	Value() string
+}
+
+// Condition is a filter matching objects whose field value compares to a
+// value with an operator of the API.
+type Condition struct {
+	// Field is the name of the filtered field, such as "name" or "created".
+	Field string
+	// Operator is the operator of the API, such as "contains" or "gte". It is
+	// empty to match objects whose field value is equal to the value.
+	Operator string
+	// Text is the value of the filter, as sent in the query string.
+	Text string
+}
+
+// Parameter returns the name of the search parameter of the condition, such
+// as "name__contains".
+func (c Condition) Parameter() string {
+	if c.Operator == "" {
+		return c.Field
+	}
+
+	return c.Field + "__" + c.Operator
+}
+
+// Value returns the value of the search parameter of the condition.
+func (c Condition) Value() string {
+	return c.Text
+}
+
+// Search returns a search parameters map holding the condition only.
+func (c Condition) Search() map[string]interface{} {
+	return map[string]interface{}{c.Parameter(): c.Value()}
+}
+
+// FieldFilter builds the filters of a field, using the operators of the API.
+type FieldFilter struct {
+	field string
+}
+
+// Filter returns a builder of filters on the given field, such as:
+//
+//	search := peeringdb.Where(nil,
+//		peeringdb.Filter("asn").In(64500, 64501),
+//		peeringdb.Filter("name").Contains("transit"),
+//		peeringdb.Filter("created").Gte(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+//	)
+//
+// Values are formatted the way the API expects them: times are sent in the RFC
+// 3339 format, in UTC, AS numbers in the asplain format.
+func Filter(field string) FieldFilter {
+	return FieldFilter{field: field}
+}
+
+// condition returns a condition on the field with the given operator.
+func (f FieldFilter) condition(operator string, value interface{}) Condition {
+	return Condition{Field: f.field, Operator: operator, Text: formatFilterValue(value)}
+}
+
+// Equals returns a condition matching objects whose field value is the given
+// value.
+func (f FieldFilter) Equals(value interface{}) Condition {
+	return f.condition("", value)
+}
+
+// In returns a filter matching objects whose field value is one of the given
+// values, using the "__in" operator. Integers and strings held in slices can
+// be given with InInts and InStrings instead.
+func (f FieldFilter) In(values ...interface{}) InFilter {
+	texts := make([]string, len(values))
+	for i, value := range values {
+		texts[i] = formatFilterValue(value)
+	}
+
+	return InStrings(f.field, texts)
+}
+
+// Contains returns a condition matching objects whose field value contains
+// the given text, ignoring case.
+func (f FieldFilter) Contains(text string) Condition {
+	return f.condition("contains", text)
+}
+
+// StartsWith returns a condition matching objects whose field value starts
+// with the given text, ignoring case.
+func (f FieldFilter) StartsWith(text string) Condition {
+	return f.condition("startswith", text)
+}
+
+// Lt returns a condition matching objects whose field value is lower than the
+// given value.
+func (f FieldFilter) Lt(value interface{}) Condition {
+	return f.condition("lt", value)
+}
+
+// Lte returns a condition matching objects whose field value is lower than or
+// equal to the given value.
+func (f FieldFilter) Lte(value interface{}) Condition {
+	return f.condition("lte", value)
+}
+
+// Gt returns a condition matching objects whose field value is greater than
+// the given value.
+func (f FieldFilter) Gt(value interface{}) Condition {
+	return f.condition("gt", value)
+}
+
+// Gte returns a condition matching objects whose field value is greater than
+// or equal to the given value.
+func (f FieldFilter) Gte(value interface{}) Condition {
+	return f.condition("gte", value)
+}
+
+// Where returns a copy of the given search parameters map with the given
+// filters added. A filter replaces any parameter of the same name.
+func Where(search map[string]interface{}, filters ...SearchFilter) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(search)+len(filters))
+	for key, value := range search {
+		filtered[key] = value
+	}
+	for _, filter := range filters {
+		filtered[filter.Parameter()] = filter.Value()
+	}
+
+	return filtered
+}
+
+// formatFilterValue formats a value the way the API expects it in a query
+// string.
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,46 @@
+package peeringdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	search := Where(map[string]interface{}{"status": "ok"},
+		Filter("asn").In(64500, ASN(64501), 64500),
+		Filter("name").Contains("transit"),
+		Filter("name").StartsWith("Example"),
+		Filter("created").Gte(created),
+		Filter("info_ipv6").Equals(true),
+		Filter("id").Lt(10),
+	)
+
+	expected := "&asn__in=64500%2C64501&created__gte=2024-01-01T11%3A00%3A00Z&id__lt=10&info_ipv6=true" +
+		"&name__contains=transit&name__startswith=Example&status=ok"
+	if query := formatSearchParameters(search); query != expected {
+		t.Errorf("formatSearchParameters, want '%s' got '%s'", expected, query)
+	}
+}
+
+func TestFilterSearch(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Example Transit", "created": "2020-01-01T00:00:00Z"},
+			{"id": 2, "asn": 64501, "name": "Example Content", "created": "2024-06-01T00:00:00Z"},
+			{"id": 3, "asn": 64502, "name": "Other Transit", "created": "2024-06-01T00:00:00Z"},
+		},
+	})
+
+	networks, err := server.api().GetNetwork(Where(nil,
+		Filter("asn").In(64500, 64501, 64502),
+		Filter("name").StartsWith("example"),
+		Filter("created").Gte(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*networks) != 1 || (*networks)[0].ID != 2 {
+		t.Errorf("GetNetwork, want network 2 got %+v", *networks)
+	}
+}
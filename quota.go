@@ -0,0 +1,165 @@
+package peeringdb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// approximateObjectCounts is the approximate number of objects in each
+// namespace of PeeringDB. It is used to estimate the cost of operations
+// depending on the size of the database when no better value is known.
+var approximateObjectCounts = map[string]int{
+	organizationNamespace:               35000,
+	campusNamespace:                     100,
+	facilityNamespace:                   6000,
+	carrierNamespace:                    300,
+	carrierFacilityNamespace:            1000,
+	internetExchangeNamespace:           1300,
+	internetExchangeLANNamespace:        1300,
+	internetExchangePrefixNamespace:     2500,
+	internetExchangeFacilityNamespace:   3500,
+	networkNamespace:                    33000,
+	networkFacilityNamespace:            55000,
+	networkInternetExchangeLANNamespace: 55000,
+	networkContactNamespace:             40000,
+}
+
+// CallEstimate is a structure describing the number of API calls an operation
+// is expected to make, per namespace. It can be used to budget operations
+// against the API rate limit before running them.
+type CallEstimate struct {
+	Operation string
+	Calls     map[string]int
+}
+
+// Total returns the number of API calls of the estimate.
+func (e CallEstimate) Total() int {
+	var total int
+	for _, calls := range e.Calls {
+		total += calls
+	}
+
+	return total
+}
+
+// String returns the estimate as a human readable text, one line per
+// namespace.
+func (e CallEstimate) String() string {
+	namespaces := make([]string, 0, len(e.Calls))
+	for namespace := range e.Calls {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d API calls\n", e.Operation, e.Total())
+	for _, namespace := range namespaces {
+		fmt.Fprintf(&b, "  %-10s %d\n", namespace, e.Calls[namespace])
+	}
+
+	return b.String()
+}
+
+// chunkCount returns the number of queries needed to look up the given number
+// of IDs, given that they are split in chunks.
+func chunkCount(ids int) int {
+	return (ids + maxIDsPerQuery - 1) / maxIDsPerQuery
+}
+
+// EstimateBootstrap estimates the number of API calls made by the initial load
+// of a new mirror using the given options. The number of objects per namespace
+// can be given, approximate values are used for missing namespaces.
+func EstimateBootstrap(options MirrorOptions, counts map[string]int) CallEstimate {
+	if options.PageSize <= 0 {
+		options.PageSize = defaultMirrorPageSize
+	}
+	if len(options.Namespaces) == 0 {
+		options.Namespaces = namespaces
+	}
+
+	return estimateBootstrap(options, nil, counts)
+}
+
+// EstimateBootstrap estimates the number of API calls needed to finish the
+// initial load of the mirror. Namespaces already loaded do not need any call,
+// and only the remaining pages of a partially loaded namespace are counted.
+// The number of objects per namespace can be given, approximate values are
+// used for missing namespaces.
+func (m *Mirror) EstimateBootstrap(counts map[string]int) CallEstimate {
+	m.mutex.Lock()
+	state := make(map[string]MirrorNamespaceState, len(m.state))
+	for namespace, s := range m.state {
+		state[namespace] = *s
+	}
+	m.mutex.Unlock()
+
+	return estimateBootstrap(m.options, state, counts)
+}
+
+// estimateBootstrap estimates the calls of an initial load given the progress
+// made so far.
+func estimateBootstrap(options MirrorOptions, state map[string]MirrorNamespaceState, counts map[string]int) CallEstimate {
+	estimate := CallEstimate{Operation: "mirror bootstrap", Calls: make(map[string]int)}
+
+	for _, namespace := range options.Namespaces {
+		progress := state[namespace]
+		if progress.Complete {
+			estimate.Calls[namespace] = 0
+			continue
+		}
+
+		count, ok := counts[namespace]
+		if !ok {
+			count = approximateObjectCounts[namespace]
+		}
+
+		// Pages are fetched until one is not full, the last one can be
+		// empty, then one more call checks the consistency of the pages.
+		// Pages fetched concurrently are fetched by batches, the last
+		// batch can then go past the last page.
+		remaining := max(count-progress.Fetched, 0)
+		pages := remaining/options.PageSize + 1
+		if workers := options.PageWorkers[namespace]; workers > 1 {
+			pages = (pages + workers - 1) / workers * workers
+		}
+		estimate.Calls[namespace] = pages + 1
+	}
+
+	return estimate
+}
+
+// EstimateASNs estimates the number of API calls made by GetASNs, or by an
+// ASNResolver without any cached entry, to look up the given number of AS
+// numbers.
+func EstimateASNs(asns int) CallEstimate {
+	return CallEstimate{
+		Operation: "AS numbers lookup",
+		Calls:     map[string]int{networkNamespace: chunkCount(asns)},
+	}
+}
+
+// EstimateNetworkContacts estimates the number of API calls needed to fetch
+// the contacts of the given number of networks, querying them by network IDs.
+func EstimateNetworkContacts(networks int) CallEstimate {
+	return CallEstimate{
+		Operation: "network contacts fetch",
+		Calls:     map[string]int{networkContactNamespace: chunkCount(networks)},
+	}
+}
+
+// EstimateFacilityTenants estimates the number of API calls made by
+// GetFacilityTenants for a facility hosting the given numbers of networks and
+// Internet exchanges.
+func EstimateFacilityTenants(networks, ixs int) CallEstimate {
+	return CallEstimate{
+		Operation: "facility tenants",
+		Calls: map[string]int{
+			facilityNamespace:                 1,
+			networkFacilityNamespace:          1,
+			internetExchangeFacilityNamespace: 1,
+			networkNamespace:                  chunkCount(networks),
+			internetExchangeNamespace:         chunkCount(ixs),
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestEstimateBootstrap(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+			{"id": 2, "asn": 64501},
+			{"id": 3, "asn": 64502},
+			{"id": 4, "asn": 64503},
+		},
+		organizationNamespace: {
+			{"id": 1, "name": "Organization"},
+		},
+	})
+	options := MirrorOptions{
+		PageSize:   2,
+		Pacing:     -1,
+		Namespaces: []string{organizationNamespace, networkNamespace},
+	}
+	counts := map[string]int{organizationNamespace: 1, networkNamespace: 4}
+
+	estimate := EstimateBootstrap(options, counts)
+	// 1 page for organizations, 2 full pages and an empty one for networks,
+	// plus a consistency check for each namespace
+	if estimate.Calls[organizationNamespace] != 2 || estimate.Calls[networkNamespace] != 4 {
+		t.Errorf("EstimateBootstrap, unexpected estimate: %v", estimate.Calls)
+	}
+
+	mirror, err := NewMirror(server.api(), t.TempDir(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mirror.EstimateBootstrap(counts); got.Total() != estimate.Total() {
+		t.Errorf("EstimateBootstrap, want %d calls got %d", estimate.Total(), got.Total())
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	made := server.count(organizationNamespace) + server.count(networkNamespace)
+	if made != estimate.Total() {
+		t.Errorf("Bootstrap, estimated %d calls but made %d", estimate.Total(), made)
+	}
+
+	// Nothing left to do once loaded
+	if got := mirror.EstimateBootstrap(counts); got.Total() != 0 {
+		t.Errorf("EstimateBootstrap, want 0 calls got %d", got.Total())
+	}
+}
+
+func TestEstimateChunked(t *testing.T) {
+	if calls := EstimateASNs(250).Total(); calls != 3 {
+		t.Errorf("EstimateASNs, want 3 calls got %d", calls)
+	}
+	if calls := EstimateNetworkContacts(0).Total(); calls != 0 {
+		t.Errorf("EstimateNetworkContacts, want 0 calls got %d", calls)
+	}
+	if calls := EstimateFacilityTenants(100, 1).Total(); calls != 5 {
+		t.Errorf("EstimateFacilityTenants, want 5 calls got %d", calls)
+	}
+}
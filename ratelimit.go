@@ -0,0 +1,50 @@
+package peeringdb
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitError is the error returned when the API rate limit is exceeded.
+// It can be checked with errors.Is against ErrRateLimitExceeded.
+type RateLimitError struct {
+	// RetryAfter is the time to wait before making another call, as given
+	// by the Retry-After header of the response. It is 0 if unknown.
+	RetryAfter time.Duration
+}
+
+// Error returns a message telling how long to wait, if known.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return ErrRateLimitExceeded.Error()
+	}
+
+	return fmt.Sprintf("%s, retry after %s", ErrRateLimitExceeded, e.RetryAfter)
+}
+
+// Is tells if the target is ErrRateLimitExceeded.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimitExceeded
+}
+
+// parseRetryAfter returns the time to wait given by the value of a
+// Retry-After header, either a number of seconds or an HTTP date. It returns
+// 0 if the value is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
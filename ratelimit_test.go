@@ -0,0 +1,70 @@
+package peeringdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"30":                            30 * time.Second,
+		" 5 ":                           5 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Wed, 01 Jan 2020 00:01:00 GMT": time.Minute,
+		"Tue, 31 Dec 2019 23:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q), want %s got %s", value, want, got)
+		}
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	// The server throttles every other call
+	var calls atomic.Int32
+	retryAfter := "0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			http.Error(w, `{"message": "Request was throttled."}`, http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	// Not retried by default
+	api := NewAPIFromURL(server.URL + "/api/")
+	_, err := api.GetNetworkByID(1)
+	var rateLimit *RateLimitError
+	if !errors.Is(err, ErrRateLimitExceeded) || !errors.As(err, &rateLimit) {
+		t.Errorf("GetNetworkByID, want RateLimitError got %v", err)
+	}
+
+	calls.Store(0)
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, RetryRateLimited: true, MaxRetryAfter: time.Second}
+	api = NewAPIFromURL(server.URL+"/api/", WithRetryPolicy(policy))
+	network, err := api.GetNetworkByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.ASN != 64500 || calls.Load() != 2 {
+		t.Errorf("GetNetworkByID, unexpected network %+v after %d calls", network, calls.Load())
+	}
+
+	// Waiting longer than allowed
+	calls.Store(0)
+	retryAfter = "3600"
+	_, err = api.GetNetworkByID(1)
+	if !errors.As(err, &rateLimit) || rateLimit.RetryAfter != time.Hour || calls.Load() != 1 {
+		t.Errorf("GetNetworkByID, want RateLimitError after 1 call got %v after %d calls", err, calls.Load())
+	}
+}
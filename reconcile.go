@@ -0,0 +1,327 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OrganizationState is a structure describing the desired state of an
+// organization in PeeringDB: the fields of the organization and its networks
+// with their contacts, IX presences and facilities.
+//
+//	org_id: 1
+//	org:
+//	  website: https://example.net
+//	networks:
+//	  - asn: 64500
+//	    net:
+//	      name: Example
+//	    netfac:
+//	      - fac_id: 1
+//	    netixlan:
+//	      - ixlan_id: 2
+//	        ipaddr4: 192.0.2.1
+//	    poc:
+//	      - role: NOC
+//	        name: Operations
+//	        email: noc@example.net
+type OrganizationState struct {
+	OrganizationID int `yaml:"org_id"`
+	// Organization holds the fields of the organization to enforce.
+	Organization map[string]interface{} `yaml:"org"`
+	Networks     []NetworkState         `yaml:"networks"`
+}
+
+// NetworkState is a structure describing the desired state of a network of an
+// organization. Only the namespaces given in the objects are reconciled.
+type NetworkState struct {
+	ASN ASN `yaml:"asn"`
+	// Network holds the fields of the network to enforce.
+	Network map[string]interface{} `yaml:"net"`
+	// Objects holds the netfac, netixlan and poc objects of the network.
+	Objects ImportDefinitions `yaml:",inline"`
+}
+
+// ReadOrganizationState reads the desired state of an organization from a
+// YAML document.
+func ReadOrganizationState(r io.Reader) (*OrganizationState, error) {
+	state := &OrganizationState{}
+	if err := yaml.NewDecoder(r).Decode(state); err != nil {
+		return nil, fmt.Errorf("invalid organization state: %w", err)
+	}
+	if state.OrganizationID == 0 {
+		return nil, fmt.Errorf("invalid organization state: org_id is required")
+	}
+
+	return state, nil
+}
+
+// NetworkPlan is a structure holding the changes to apply to converge a
+// network to its desired state.
+type NetworkPlan struct {
+	ASN ASN
+	// NetworkID is the ID of the network, it is 0 if the network has to be
+	// created.
+	NetworkID int
+	// Changes are the changes to apply, in order. A change creating the
+	// network comes first.
+	Changes []ImportChange
+}
+
+// ReconcilePlan is a structure holding the changes to apply to converge an
+// organization to its desired state.
+type ReconcilePlan struct {
+	OrganizationID int
+	// Organization is the change updating the organization, if any.
+	Organization *ImportChange
+	Networks     []NetworkPlan
+	// Unmanaged are the AS numbers of the networks of the organization not
+	// part of the desired state. They are reported but left untouched.
+	Unmanaged []ASN
+}
+
+// InSync tells if the organization matches its desired state, there is no
+// change to apply.
+func (p *ReconcilePlan) InSync() bool {
+	if p.Organization != nil {
+		return false
+	}
+	for _, network := range p.Networks {
+		if len(network.Changes) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Drift returns a human readable description of the differences between the
+// organization and its desired state, one line per change or unmanaged
+// network.
+func (p *ReconcilePlan) Drift() []string {
+	var drift []string
+
+	if p.Organization != nil {
+		drift = append(drift, p.Organization.String())
+	}
+	for _, network := range p.Networks {
+		for _, change := range network.Changes {
+			drift = append(drift, fmt.Sprintf("AS%d: %s", network.ASN, change))
+		}
+	}
+	for _, asn := range p.Unmanaged {
+		drift = append(drift, fmt.Sprintf("AS%d: network not managed", asn))
+	}
+
+	return drift
+}
+
+// String returns the drift of the plan, or a message telling that the
+// organization is in sync.
+func (p *ReconcilePlan) String() string {
+	drift := p.Drift()
+	if len(drift) == 0 {
+		return fmt.Sprintf("organization %d is in sync", p.OrganizationID)
+	}
+
+	return strings.Join(drift, "\n")
+}
+
+// PlanReconcile compares an organization with its desired state and returns
+// the changes needed to converge it. Networks missing from PeeringDB are
+// created, and the objects of each network are planned like PlanImport does.
+// Networks of the organization not part of the desired state are reported
+// as unmanaged.
+func (api *API) PlanReconcile(state *OrganizationState) (*ReconcilePlan, error) {
+	plan := &ReconcilePlan{OrganizationID: state.OrganizationID}
+
+	organization, err := api.getRaw(organizationNamespace, map[string]interface{}{"id": state.OrganizationID})
+	if err != nil {
+		return nil, err
+	}
+	if len(organization) == 0 {
+		return nil, fmt.Errorf("no organization found for ID %d", state.OrganizationID)
+	}
+	if len(state.Organization) > 0 {
+		change, err := reconcileObject(organizationNamespace, organization[0], state.Organization)
+		if err != nil {
+			return nil, err
+		}
+		plan.Organization = change
+	}
+
+	// Networks of the organization, indexed by AS number
+	networks, err := api.getRaw(networkNamespace, map[string]interface{}{"org_id": state.OrganizationID})
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[ASN]json.RawMessage, len(networks))
+	for _, network := range networks {
+		var identified struct {
+			ASN ASN `json:"asn"`
+		}
+		if err = json.Unmarshal(network, &identified); err != nil {
+			return nil, err
+		}
+		current[identified.ASN] = network
+	}
+
+	managed := make(map[ASN]bool, len(state.Networks))
+	for _, network := range state.Networks {
+		if managed[network.ASN] {
+			return nil, fmt.Errorf("AS%d defined twice", network.ASN)
+		}
+		managed[network.ASN] = true
+
+		networkPlan, err := api.planNetwork(state.OrganizationID, network, current[network.ASN])
+		if err != nil {
+			return nil, fmt.Errorf("AS%d: %w", network.ASN, err)
+		}
+		plan.Networks = append(plan.Networks, *networkPlan)
+	}
+
+	for asn := range current {
+		if !managed[asn] {
+			plan.Unmanaged = append(plan.Unmanaged, asn)
+		}
+	}
+	sort.Slice(plan.Unmanaged, func(i, j int) bool {
+		return plan.Unmanaged[i] < plan.Unmanaged[j]
+	})
+
+	return plan, nil
+}
+
+// planNetwork returns the changes to apply to converge a network to its
+// desired state, given its current state if it exists.
+func (api *API) planNetwork(organizationID int, state NetworkState, current json.RawMessage) (*NetworkPlan, error) {
+	plan := &NetworkPlan{ASN: state.ASN}
+
+	if current == nil {
+		// The network has to be created in the organization first, unless
+		// it belongs to another one
+		existing, err := api.getRaw(networkNamespace, map[string]interface{}{"asn": state.ASN})
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("network belongs to another organization")
+		}
+
+		object, err := parseImportObject(state.Network, importColumns(networkNamespace))
+		if err != nil {
+			return nil, err
+		}
+		object["asn"] = int64(state.ASN)
+		object["org_id"] = int64(organizationID)
+		plan.Changes = append(plan.Changes, ImportChange{
+			Action:    ImportCreate,
+			Namespace: networkNamespace,
+			Object:    object,
+		})
+	} else {
+		id, err := objectID(current)
+		if err != nil {
+			return nil, err
+		}
+		plan.NetworkID = id
+
+		if len(state.Network) > 0 {
+			change, err := reconcileObject(networkNamespace, current, state.Network)
+			if err != nil {
+				return nil, err
+			}
+			if change != nil {
+				plan.Changes = append(plan.Changes, *change)
+			}
+		}
+	}
+
+	changes, err := api.PlanImport(plan.NetworkID, state.Objects)
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, changes...)
+
+	return plan, nil
+}
+
+// reconcileObject returns the change updating an object if some of the
+// desired fields differ from the current ones, nil otherwise.
+func reconcileObject(namespace string, current json.RawMessage, desired map[string]interface{}) (*ImportChange, error) {
+	columns := importColumns(namespace)
+
+	object, err := parseImportObject(desired, columns)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", namespace, err)
+	}
+	typed, err := importObject(current, columns)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := typed["id"].(int64)
+
+	change, ok := importUpdate(namespace, int(id), typed, object)
+	if !ok {
+		return nil, nil
+	}
+
+	return &change, nil
+}
+
+// ApplyReconcile applies the changes of a plan, in order, using the given
+// context for the API calls. When a network is created, the objects of the
+// network created afterwards are attached to it, the plan itself being left
+// unchanged. It stops at the first change which cannot be applied.
+func (api *API) ApplyReconcile(ctx context.Context, plan *ReconcilePlan) error {
+	if plan.Organization != nil {
+		if err := api.ApplyImport(ctx, []ImportChange{*plan.Organization}); err != nil {
+			return err
+		}
+	}
+
+	for _, network := range plan.Networks {
+		networkID := network.NetworkID
+
+		for _, change := range network.Changes {
+			if change.Namespace == networkNamespace && change.Action == ImportCreate {
+				created, err := api.write(ctx, http.MethodPost, networkNamespace, 0, change.Object, nil)
+				if err != nil {
+					return fmt.Errorf("AS%d: %s: %w", network.ASN, change, err)
+				}
+				if created == nil {
+					return fmt.Errorf("AS%d: %s: no network returned by the API", network.ASN, change)
+				}
+				if networkID, err = objectID(created); err != nil {
+					return fmt.Errorf("AS%d: %s: %w", network.ASN, change, err)
+				}
+				if networkID == 0 {
+					return fmt.Errorf("AS%d: %s: no ID for the network returned by the API", network.ASN, change)
+				}
+				continue
+			}
+
+			// Attach the object to the network without changing the plan
+			if change.Object != nil && change.Namespace != networkNamespace {
+				object := make(map[string]interface{}, len(change.Object)+1)
+				for name, value := range change.Object {
+					object[name] = value
+				}
+				object["net_id"] = int64(networkID)
+				change.Object = object
+			}
+			if err := api.ApplyImport(ctx, []ImportChange{change}); err != nil {
+				return fmt.Errorf("AS%d: %w", network.ASN, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package peeringdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		organizationNamespace: {
+			{"id": 1, "name": "Organization", "website": "https://old.example.net"},
+		},
+		networkNamespace: {
+			{"id": 10, "org_id": 1, "asn": 64500, "name": "Network A"},
+			{"id": 11, "org_id": 1, "asn": 64501, "name": "Network B"},
+		},
+		networkFacilityNamespace: {
+			{"id": 1, "net_id": 10, "fac_id": 100},
+		},
+		networkContactNamespace: {},
+	})
+	api := server.api()
+
+	state, err := ReadOrganizationState(strings.NewReader(`
+org_id: 1
+org:
+  website: https://example.net
+networks:
+  - asn: 64500
+    net:
+      name: Network A
+    netfac:
+      - fac_id: 100
+      - fac_id: 101
+  - asn: 64502
+    net:
+      name: Network C
+    poc:
+      - role: NOC
+        name: Operations
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := api.PlanReconcile(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"update org 1: website",
+		"AS64500: create netfac fac_id=101 net_id=10",
+		"AS64502: create net asn=64502 name=Network C org_id=1",
+		"AS64502: create poc name=Operations net_id=0 role=NOC",
+		"AS64501: network not managed",
+	}
+	if drift := plan.Drift(); strings.Join(drift, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("PlanReconcile, want drift:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(drift, "\n"))
+	}
+
+	if err = api.ApplyReconcile(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+	// The plan is left unchanged
+	if drift := plan.Drift(); strings.Join(drift, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("ApplyReconcile, plan changed:\n%s", strings.Join(drift, "\n"))
+	}
+
+	// The contact is attached to the created network
+	network, err := api.GetASN(64502)
+	if err != nil || network == nil {
+		t.Fatalf("ApplyReconcile, network not created: %v", err)
+	}
+	contacts, err := api.GetNetworkContact(map[string]interface{}{"net_id": network.ID})
+	if err != nil || len(*contacts) != 1 {
+		t.Fatalf("ApplyReconcile, contact not created: %v", err)
+	}
+
+	if plan, err = api.PlanReconcile(state); err != nil || !plan.InSync() {
+		t.Errorf("PlanReconcile, want organization in sync got:\n%s (%v)", plan, err)
+	}
+}
+
+func TestApplyReconcileNoNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	plan := &ReconcilePlan{Networks: []NetworkPlan{{
+		ASN: 64500,
+		Changes: []ImportChange{
+			{Action: ImportCreate, Namespace: networkNamespace, Object: map[string]interface{}{"asn": 64500, "name": "Network A", "org_id": 1}},
+			{Action: ImportCreate, Namespace: networkContactNamespace, Object: map[string]interface{}{"role": "NOC", "name": "Operations"}},
+		},
+	}}}
+	err := NewAPIFromURL(server.URL+"/api/").ApplyReconcile(context.Background(), plan)
+	if err == nil || !strings.Contains(err.Error(), "no network returned") {
+		t.Errorf("ApplyReconcile, want an error for the missing network got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package peeringdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotRecorded is the error that will be returned by a Recorder in replay
+// mode if it has no golden file for a request.
+var ErrNotRecorded = errors.New("no recorded response for request")
+
+// RecorderMode tells how a Recorder uses its golden files.
+type RecorderMode int
+
+const (
+	// RecorderReplayOrRecord replays the recorded responses, and records
+	// the responses to requests which were not recorded yet.
+	RecorderReplayOrRecord RecorderMode = iota
+	// RecorderRecord always sends requests and records their responses,
+	// refreshing the golden files.
+	RecorderRecord
+	// RecorderReplay only replays the recorded responses, never sending
+	// requests. Requests which were not recorded fail with ErrNotRecorded.
+	RecorderReplay
+)
+
+// recording is the content of a golden file.
+type recording struct {
+	Method     string          `json:"method"`
+	URL        string          `json:"url"`
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	Text       string          `json:"text,omitempty"`
+}
+
+// Recorder is an HTTP transport recording the responses of the API to golden
+// files the first time, and replaying them afterwards, so that tests do not
+// depend on the network and on live data. Golden files are named after the
+// hash of the method and of the canonical URL of the requests, without their
+// host, and can be committed with the tests. Request headers, carrying
+// credentials, are never recorded. It is given to an API structure with:
+//
+//	WithHTTPClient(&http.Client{Transport: NewRecorder(dir, mode, nil)})
+//
+// Unlike WithDiskCache, which only stores successful lookups, a Recorder
+// stores all responses, with their status code and headers, and works with
+// any HTTP client.
+type Recorder struct {
+	directory string
+	mode      RecorderMode
+	transport http.RoundTripper
+}
+
+// NewRecorder returns a pointer to a new Recorder keeping its golden files in
+// the given directory. Requests are sent with the given transport, or with
+// http.DefaultTransport if it is nil.
+func NewRecorder(directory string, mode RecorderMode, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{directory: directory, mode: mode, transport: transport}
+}
+
+// path returns the path of the golden file of the given request.
+func (r *Recorder) path(request *http.Request) (string, string) {
+	canonical := canonicalURL(request.URL.String())
+	hash := sha256.Sum256([]byte(request.Method + " " + canonical))
+
+	return filepath.Join(r.directory, hex.EncodeToString(hash[:])+".json"), canonical
+}
+
+// RoundTrip replays the recorded response to the request, or sends it and
+// records its response, depending on the mode of the recorder. It implements
+// http.RoundTripper.
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	path, canonical := r.path(request)
+
+	if r.mode != RecorderRecord {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			var recorded recording
+			if err = json.Unmarshal(data, &recorded); err != nil {
+				return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+			}
+			return recorded.response(request), nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		case r.mode == RecorderReplay:
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, request.Method, canonical)
+		}
+	}
+
+	response, err := r.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := recording{Method: request.Method, URL: canonical, StatusCode: response.StatusCode, Header: response.Header.Clone()}
+	recorded.Header.Del("Set-Cookie")
+	if json.Valid(body) {
+		recorded.Body = body
+	} else {
+		recorded.Text = string(body)
+	}
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(recorded); err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(r.directory, 0o755); err != nil {
+		return nil, err
+	}
+	if err = writeFileAtomic(path, data.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// response returns the recorded response to the given request.
+func (r recording) response(request *http.Request) *http.Response {
+	body := []byte(r.Text)
+	if len(r.Body) > 0 {
+		body = r.Body
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}
+}
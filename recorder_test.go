@@ -0,0 +1,65 @@
+package peeringdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/fac" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	directory := t.TempDir()
+	newAPI := func(mode RecorderMode) *API {
+		recorder := NewRecorder(directory, mode, nil)
+		return NewAPIFromURLWithAPIKey(server.URL+"/api/", "secret-key", WithHTTPClient(&http.Client{Transport: recorder}))
+	}
+
+	// Responses are recorded the first time, without the credentials
+	api := newAPI(RecorderReplayOrRecord)
+	if network, err := api.GetNetworkByID(1); err != nil || network.ASN != 64500 {
+		t.Fatalf("GetNetworkByID, unexpected result %+v: %v", network, err)
+	}
+	if _, err := api.GetFacilityByID(1); err == nil {
+		t.Error("GetFacilityByID, want an error")
+	}
+	files, err := filepath.Glob(filepath.Join(directory, "*.json"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("Glob, want 2 golden files got %v: %v", files, err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret-key") || strings.Contains(string(data), "127.0.0.1") {
+			t.Errorf("RoundTrip, credentials or host recorded in %s", data)
+		}
+	}
+
+	// Then replayed, status included, without the server
+	server.Close()
+	api = newAPI(RecorderReplay)
+	if network, err := api.GetNetworkByID(1); err != nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByID, unexpected result %+v: %v", network, err)
+	}
+	var apiError *APIError
+	if _, err = api.GetFacilityByID(1); !errors.As(err, &apiError) || apiError.StatusCode != http.StatusNotFound {
+		t.Errorf("GetFacilityByID, want a 404 error got %v", err)
+	}
+	recorder := NewRecorder(directory, RecorderReplay, nil)
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/api/ix", nil)
+	if _, err = recorder.RoundTrip(request); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("RoundTrip, want ErrNotRecorded got %v", err)
+	}
+}
@@ -0,0 +1,511 @@
+package peeringdb
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"text/template"
+	"time"
+)
+
+// ReportFormat is the format used to output the result of a report.
+type ReportFormat string
+
+const (
+	// ReportText outputs the report as an aligned text table.
+	ReportText ReportFormat = "text"
+	// ReportCSV outputs the report as CSV with a header line.
+	ReportCSV ReportFormat = "csv"
+	// ReportJSON outputs the report as a JSON array of objects.
+	ReportJSON ReportFormat = "json"
+)
+
+var (
+	// ErrUnknownReport is the error that will be returned if a report cannot
+	// be found given its name.
+	ErrUnknownReport = errors.New("unknown report")
+
+	// reports holds registered reports, indexed by name.
+	reports      = make(map[string]Report)
+	reportsMutex sync.RWMutex
+)
+
+// ReportParameters is a map holding the parameters given to a report.
+type ReportParameters map[string]string
+
+// Int returns the value of the named parameter as an integer. An error is
+// returned if the parameter is missing or is not an integer.
+func (p ReportParameters) Int(name string) (int, error) {
+	value, ok := p[name]
+	if !ok || value == "" {
+		return 0, fmt.Errorf("missing report parameter '%s'", name)
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("report parameter '%s' must be an integer", name)
+	}
+
+	return i, nil
+}
+
+// ASN returns the value of the named parameter as an AS number, written in
+// the asplain or the asdot format.
+func (p ReportParameters) ASN(name string) (ASN, error) {
+	value, ok := p[name]
+	if !ok || value == "" {
+		return 0, fmt.Errorf("missing report parameter '%s'", name)
+	}
+
+	asn, err := ParseASN(value)
+	if err != nil {
+		return 0, fmt.Errorf("report parameter '%s' must be an AS number", name)
+	}
+
+	return asn, nil
+}
+
+// Ints returns the value of the named parameter as a list of integers. The
+// value is expected to be a comma separated list.
+func (p ReportParameters) Ints(name string) ([]int, error) {
+	value, ok := p[name]
+	if !ok || value == "" {
+		return nil, fmt.Errorf("missing report parameter '%s'", name)
+	}
+
+	var ints []int
+	for _, item := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("report parameter '%s' must be a list of integers", name)
+		}
+		ints = append(ints, i)
+	}
+
+	return ints, nil
+}
+
+// ReportTable is the result of a report. It is a simple table made of named
+// columns and rows of values.
+type ReportTable struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Append adds a row to the table. Values are formatted with their default
+// format, except times which are formatted as RFC 3339 times so templates can
+// render them again.
+func (t *ReportTable) Append(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		if v, ok := value.(time.Time); ok {
+			row[i] = v.Format(time.RFC3339)
+			continue
+		}
+		row[i] = fmt.Sprintf("%v", value)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// Records returns the rows of the table as maps indexed by column names.
+func (t *ReportTable) Records() []map[string]string {
+	records := make([]map[string]string, len(t.Rows))
+	for i, row := range t.Rows {
+		records[i] = make(map[string]string, len(t.Columns))
+		for j, column := range t.Columns {
+			if j < len(row) {
+				records[i][column] = row[j]
+			}
+		}
+	}
+
+	return records
+}
+
+// Write outputs the table to the given writer using the given format.
+func (t *ReportTable) Write(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportText, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.Columns, "\t"))
+		// Values are user-supplied, they must not break the alignment of
+		// the columns nor mess up with the terminal
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = Sanitize(cell, SanitizeOptions{SingleLine: true})
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	case ReportCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(t.Columns)
+		cw.WriteAll(t.Rows)
+		return cw.Error()
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(t.Records())
+	default:
+		return fmt.Errorf("unknown report format '%s'", format)
+	}
+}
+
+// WriteTemplate outputs the table to the given writer using a text/template.
+// The template is executed with the table as data, so it can range over
+// .Rows or .Records. The "localtime" and "ago" functions of a TimeFormatter
+// using the local timezone are available to render times. The "sanitize"
+// function, taking a maximum length and a value, renders free-form texts as
+// plain text using Sanitize.
+func (t *ReportTable) WriteTemplate(w io.Writer, text string) error {
+	funcs := TimeFormatter{}.FuncMap()
+	funcs["sanitize"] = func(max int, value interface{}) string {
+		return Sanitize(fmt.Sprintf("%v", value), SanitizeOptions{MaxLength: max, StripMarkdown: true})
+	}
+
+	tmpl, err := template.New("report").Funcs(funcs).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, t)
+}
+
+// Report is a structure defining a named report. A report queries PeeringDB
+// and transforms the data it gets into a ReportTable.
+type Report struct {
+	Name        string
+	Description string
+	// Parameters are the names of the parameters the report accepts.
+	Parameters []string
+	Run        func(api *API, parameters ReportParameters) (*ReportTable, error)
+}
+
+// QueryReport is a structure defining a report made of a single query. It
+// allows to define reports without writing Go code, its search values being
+// templates executed with the report parameters.
+type QueryReport struct {
+	Name        string
+	Description string
+	Parameters  []string
+	// Namespace is the PeeringDB namespace to query, "net" for instance.
+	Namespace string
+	// Search is the search parameters map, values are text/template strings
+	// so that "{{.ix}}" is replaced by the value of the "ix" parameter.
+	Search map[string]string
+	// Columns are the JSON field names of the objects to put in the table.
+	Columns []string
+}
+
+// Report returns a Report running the query.
+func (q QueryReport) Report() Report {
+	return Report{
+		Name:        q.Name,
+		Description: q.Description,
+		Parameters:  q.Parameters,
+		Run: func(api *API, parameters ReportParameters) (*ReportTable, error) {
+			search := make(map[string]interface{}, len(q.Search))
+			for key, value := range q.Search {
+				tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+				if err != nil {
+					return nil, err
+				}
+				var buffer bytes.Buffer
+				if err = tmpl.Execute(&buffer, map[string]string(parameters)); err != nil {
+					return nil, err
+				}
+				search[key] = buffer.String()
+			}
+
+			resource, err := api.getRawResource(q.Namespace, search)
+			if err != nil {
+				return nil, err
+			}
+
+			table := &ReportTable{Columns: q.Columns}
+			for _, raw := range resource.Data {
+				var object map[string]interface{}
+				if err = json.Unmarshal(raw, &object); err != nil {
+					return nil, err
+				}
+				values := make([]interface{}, len(q.Columns))
+				for i, column := range q.Columns {
+					values[i] = object[column]
+				}
+				table.Append(values...)
+			}
+
+			return table, nil
+		},
+	}
+}
+
+// RegisterReport makes a report available given its name. An error is
+// returned if a report with the same name is already registered.
+func RegisterReport(report Report) error {
+	reportsMutex.Lock()
+	defer reportsMutex.Unlock()
+
+	if _, ok := reports[report.Name]; ok {
+		return fmt.Errorf("report '%s' already registered", report.Name)
+	}
+	reports[report.Name] = report
+
+	return nil
+}
+
+// Reports returns all registered reports sorted by name.
+func Reports() []Report {
+	reportsMutex.RLock()
+	defer reportsMutex.RUnlock()
+
+	list := make([]Report, 0, len(reports))
+	for _, report := range reports {
+		list = append(list, report)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+// LookupReport returns the registered report with the given name.
+func LookupReport(name string) (Report, bool) {
+	reportsMutex.RLock()
+	defer reportsMutex.RUnlock()
+
+	report, ok := reports[name]
+	return report, ok
+}
+
+// RunReport runs the registered report with the given name and parameters.
+func (api *API) RunReport(name string, parameters ReportParameters) (*ReportTable, error) {
+	report, ok := LookupReport(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownReport, name)
+	}
+
+	return report.Run(api, parameters)
+}
+
+func init() {
+	for _, report := range []Report{
+		{
+			Name:        "ix-growth",
+			Description: "Number of connections added to an IX per month",
+			Parameters:  []string{"ix"},
+			Run:         runIXGrowthReport,
+		},
+		{
+			Name:        "ix-compare",
+			Description: "Side by side comparison of IXs",
+			Parameters:  []string{"ix"},
+			Run:         runIXCompareReport,
+		},
+		{
+			Name:        "facility-tenants",
+			Description: "Networks present in a facility",
+			Parameters:  []string{"fac"},
+			Run:         runFacilityTenantsReport,
+		},
+		{
+			Name:        "cross-connect",
+			Description: "Sites where two networks can cross-connect",
+			Parameters:  []string{"asn-a", "asn-b"},
+			Run:         runCrossConnectReport,
+		},
+		{
+			Name:        "ipv6-adoption",
+			Description: "Share of IX participants using IPv6",
+			Parameters:  []string{"ix"},
+			Run:         runIPv6AdoptionReport,
+		},
+		{
+			Name:        "lint",
+			Description: "Data-quality findings for an organization",
+			Parameters:  []string{"org"},
+			Run:         runLintReport,
+		},
+		{
+			Name:        "metro-ring",
+			Description: "Networks present at the sites of an IX but not connected to it",
+			Parameters:  []string{"ix"},
+			Run:         runMetroRingReport,
+		},
+	} {
+		RegisterReport(report)
+	}
+}
+
+// runIXGrowthReport counts connections added to an IX per month. Only
+// connections still active are known, so the growth does not account for
+// networks which left the IX.
+func runIXGrowthReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	id, err := parameters.Int("ix")
+	if err != nil {
+		return nil, err
+	}
+
+	networkIXLANs, err := getChunked([]int{id}, "ix_id", api.GetNetworkInternetExchangeLAN)
+	if err != nil {
+		return nil, err
+	}
+
+	months := make(map[string]int)
+	for _, networkIXLAN := range networkIXLANs {
+		months[networkIXLAN.Created.Format("2006-01")]++
+	}
+	keys := make([]string, 0, len(months))
+	for month := range months {
+		keys = append(keys, month)
+	}
+	sort.Strings(keys)
+
+	table := &ReportTable{Columns: []string{"month", "added", "total"}}
+	total := 0
+	for _, month := range keys {
+		total += months[month]
+		table.Append(month, months[month], total)
+	}
+
+	return table, nil
+}
+
+func runIXCompareReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	ids, err := parameters.Ints("ix")
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, err := api.CompareIXes(ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ReportTable{Columns: []string{"id", "name", "members", "connections", "capacity", "route_server", "ipv6", "facilities"}}
+	for _, summary := range comparison.InternetExchanges {
+		table.Append(summary.InternetExchange.ID, summary.InternetExchange.Name,
+			summary.MemberCount, summary.ConnectionCount, summary.TotalCapacity,
+			summary.HasRouteServer(), summary.IPv6, len(summary.FacilityIDs))
+	}
+
+	return table, nil
+}
+
+func runFacilityTenantsReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	id, err := parameters.Int("fac")
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := api.GetFacilityTenants(id)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ReportTable{Columns: []string{"asn", "name", "policy", "traffic"}}
+	for _, network := range tenants.Networks {
+		table.Append(network.ASN, network.Name, network.PolicyGeneral, network.InfoTraffic)
+	}
+
+	return table, nil
+}
+
+func runCrossConnectReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	asnA, err := parameters.ASN("asn-a")
+	if err != nil {
+		return nil, err
+	}
+	asnB, err := parameters.ASN("asn-b")
+	if err != nil {
+		return nil, err
+	}
+
+	sites, err := api.GetCrossConnectSites(asnA, asnB)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ReportTable{Columns: []string{"kind", "name", "ports_a", "ports_b"}}
+	for _, site := range sites {
+		if site.Campus != nil {
+			table.Append("campus", site.Campus.Name, site.PortsA, site.PortsB)
+		} else {
+			table.Append("facility", site.Facilities[0].Name, site.PortsA, site.PortsB)
+		}
+	}
+
+	return table, nil
+}
+
+func runIPv6AdoptionReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	// All IXs are used if no ID is given
+	var ids []int
+	if parameters["ix"] != "" {
+		var err error
+		if ids, err = parameters.Ints("ix"); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := api.GetIPv6Adoption(ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ReportTable{Columns: []string{"id", "name", "participants", "ipv6_participants", "ratio"}}
+	for _, row := range rows {
+		table.Append(row.InternetExchangeID, row.Name, row.Participants, row.IPv6Participants,
+			fmt.Sprintf("%.2f", row.Ratio()))
+	}
+
+	return table, nil
+}
+
+func runLintReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	id, err := parameters.Int("org")
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := api.LintOrganization(id, LintOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ReportTable{Columns: []string{"severity", "namespace", "id", "field", "message"}}
+	for _, finding := range findings {
+		table.Append(finding.Severity, finding.Namespace, finding.ID, finding.Field, finding.Message)
+	}
+
+	return table, nil
+}
+
+func runMetroRingReport(api *API, parameters ReportParameters) (*ReportTable, error) {
+	id, err := parameters.Int("ix")
+	if err != nil {
+		return nil, err
+	}
+
+	ring, err := api.GetMetroRing(id)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &ReportTable{Columns: []string{"site", "asn", "name", "policy", "traffic"}}
+	for _, site := range ring.Sites {
+		for _, network := range site.Prospects {
+			table.Append(site.Name, network.ASN, network.Name, network.PolicyGeneral, network.InfoTraffic)
+		}
+	}
+
+	return table, nil
+}
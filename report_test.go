@@ -0,0 +1,98 @@
+package peeringdb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestQueryReport(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A", "info_type": "NSP"},
+			{"id": 2, "asn": 64501, "name": "Network B", "info_type": "Content"},
+		},
+	})
+
+	report := QueryReport{
+		Name:       "nets-by-type",
+		Parameters: []string{"type"},
+		Namespace:  networkNamespace,
+		Search:     map[string]string{"info_type": "{{.type}}"},
+		Columns:    []string{"asn", "name"},
+	}.Report()
+
+	table, err := report.Run(server.api(), ReportParameters{"type": "Content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if err = table.Write(&buffer, ReportCSV); err != nil {
+		t.Fatal(err)
+	}
+	expected := "asn,name\n64501,Network B\n"
+	if buffer.String() != expected {
+		t.Errorf("QueryReport, want '%s' got '%s'", expected, buffer.String())
+	}
+
+	// Missing parameters must be reported
+	if _, err = report.Run(server.api(), ReportParameters{}); err == nil {
+		t.Error("QueryReport, want error for missing parameter got nil")
+	}
+}
+
+func TestIXGrowthReport(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "ix_id": 26, "created": time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)},
+			{"id": 2, "ix_id": 26, "created": time.Date(2020, 1, 20, 0, 0, 0, 0, time.UTC)},
+			{"id": 3, "ix_id": 26, "created": time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{"id": 4, "ix_id": 27, "created": time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	})
+
+	table, err := server.api().RunReport("ix-growth", ReportParameters{"ix": "26"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if err = table.WriteTemplate(&buffer, "{{range .Rows}}{{index . 0}}={{index . 2}};{{end}}"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "2020-01=2;2020-03=3;"
+	if buffer.String() != expected {
+		t.Errorf("ix-growth, want '%s' got '%s'", expected, buffer.String())
+	}
+}
+
+func TestIPv6AdoptionReport(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX A"},
+			{"id": 2, "name": "IX B"},
+			{"id": 3, "name": "IX C"},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "ix_id": 1, "net_id": 1, "ipaddr6": "2001:db8::1", "operational": true},
+			{"id": 2, "ix_id": 1, "net_id": 1, "ipaddr6": "", "operational": true},
+			{"id": 3, "ix_id": 1, "net_id": 2, "ipaddr6": "2001:db8::2", "operational": false},
+			{"id": 4, "ix_id": 2, "net_id": 1, "ipaddr6": "2001:db8:1::1", "operational": true},
+		},
+	})
+
+	table, err := server.api().RunReport("ipv6-adoption", ReportParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if err = table.Write(&buffer, ReportCSV); err != nil {
+		t.Fatal(err)
+	}
+	expected := "id,name,participants,ipv6_participants,ratio\n2,IX B,1,1,1.00\n1,IX A,2,1,0.50\n"
+	if buffer.String() != expected {
+		t.Errorf("ipv6-adoption, want '%s' got '%s'", expected, buffer.String())
+	}
+}
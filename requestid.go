@@ -0,0 +1,97 @@
+package peeringdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultRequestIDHeader is the HTTP header used to send request IDs if no
+// other header is configured.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the key used to store a request ID in a context.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of the context carrying the given
+// request ID. API calls made with this context will send this ID instead of
+// generating a new one, allowing to correlate them with other systems.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by the context, or an
+// empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestIDHeader returns an option setting the HTTP header used to send
+// request IDs. An empty header disables request IDs.
+func WithRequestIDHeader(header string) Option {
+	return func(api *API) {
+		api.requestIDHeader = header
+	}
+}
+
+// WithRequestIDGenerator returns an option setting the function used to
+// generate request IDs when the context of a call does not carry one.
+func WithRequestIDGenerator(generator func() string) Option {
+	return func(api *API) {
+		api.requestIDGenerator = generator
+	}
+}
+
+// generateRequestID returns a random request ID made of 32 hexadecimal
+// characters.
+func generateRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestID returns the request ID to use for a call, taken from the context
+// or generated. It returns an empty string if request IDs are disabled.
+func (api *API) requestID(ctx context.Context) string {
+	if api.requestIDHeader == "" {
+		return ""
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		return id
+	}
+	if api.requestIDGenerator != nil {
+		return api.requestIDGenerator()
+	}
+
+	return generateRequestID()
+}
+
+// RequestError is the error returned when an API call fails, it carries the
+// ID of the request so it can be found in the logs of other systems. The
+// underlying error can be checked with errors.Is and errors.As.
+type RequestError struct {
+	RequestID string
+	Err       error
+}
+
+// Error returns the message of the underlying error followed by the request
+// ID.
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s (request ID %s)", e.Err, e.RequestID)
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// withRequestID wraps an error in a RequestError if there is a request ID.
+func withRequestID(err error, id string) error {
+	if err == nil || id == "" {
+		return err
+	}
+
+	return &RequestError{RequestID: id, Err: err}
+}
@@ -0,0 +1,59 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Correlation-ID"))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	api := NewAPIFromURL(server.URL+"/api/",
+		WithRequestIDHeader("X-Correlation-ID"),
+		WithRequestIDGenerator(func() string { return "generated" }))
+
+	// Generated ID, carried by the error
+	_, err := api.lookup(networkNamespace, nil)
+	var requestError *RequestError
+	if !errors.As(err, &requestError) || requestError.RequestID != "generated" {
+		t.Fatalf("lookup, want request error got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "500") || !strings.Contains(err.Error(), "generated") {
+		t.Errorf("lookup, unexpected error message: %s", err)
+	}
+
+	// ID taken from the context
+	ctx := ContextWithRequestID(context.Background(), "from-context")
+	if _, err = api.lookupWithContext(ctx, networkNamespace, nil); err == nil {
+		t.Fatal("lookupWithContext, want error got nil")
+	}
+
+	if len(received) != 2 || received[0] != "generated" || received[1] != "from-context" {
+		t.Errorf("header, unexpected IDs: %v", received)
+	}
+
+	// Disabled request IDs, errors are not wrapped
+	api = NewAPIFromURL(server.URL+"/api/", WithRequestIDHeader(""))
+	if _, err = api.lookup(networkNamespace, nil); errors.As(err, &requestError) {
+		t.Errorf("lookup, want plain error got: %v", err)
+	}
+	if received[2] != "" {
+		t.Errorf("header, want no ID got: %s", received[2])
+	}
+}
+
+func TestGenerateRequestID(t *testing.T) {
+	a, b := generateRequestID(), generateRequestID()
+	if len(a) != 32 || a == b {
+		t.Errorf("generateRequestID, unexpected IDs: %s %s", a, b)
+	}
+}
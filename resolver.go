@@ -0,0 +1,177 @@
+package peeringdb
+
+import (
+	"sync"
+	"time"
+)
+
+// ASNResolver is a memoizing AS number to Network resolver that is safe for
+// concurrent use. It is designed for pipelines doing a very large number of
+// lookups for a rather small set of AS numbers (flow tagging for instance).
+//
+// Results, including the absence of a network for an AS number, are kept for
+// the configured TTL. Concurrent lookups of the same AS number share a single
+// API call. When refreshing an expired entry fails, the stale entry is still
+// returned as long as it is not older than TTL + MaxStale.
+type ASNResolver struct {
+	api      *API
+	ttl      time.Duration
+	maxStale time.Duration
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	entries map[ASN]resolverEntry
+	calls   map[ASN]*resolverCall
+}
+
+// resolverEntry is a memoized lookup result.
+type resolverEntry struct {
+	network *Network
+	fetched time.Time
+}
+
+// resolverCall is an in-flight lookup that other goroutines can wait for.
+type resolverCall struct {
+	done    chan struct{}
+	network *Network
+	err     error
+}
+
+// NewASNResolver returns a pointer to a new ASNResolver using the given API to
+// fetch networks. Lookup results are kept for ttl and can be served for an
+// extra maxStale duration if the API cannot be reached to refresh them.
+func NewASNResolver(api *API, ttl, maxStale time.Duration) *ASNResolver {
+	return &ASNResolver{
+		api:      api,
+		ttl:      ttl,
+		maxStale: maxStale,
+		now:      time.Now,
+		entries:  make(map[ASN]resolverEntry),
+		calls:    make(map[ASN]*resolverCall),
+	}
+}
+
+// Resolve returns the Network matching the given AS number. If no network
+// exists for the AS number, nil is returned without error.
+func (r *ASNResolver) Resolve(asn ASN) (*Network, error) {
+	networks, err := r.ResolveMany([]ASN{asn})
+	if err != nil {
+		return nil, err
+	}
+
+	return networks[asn], nil
+}
+
+// ResolveMany returns the Network objects matching the given AS numbers. The
+// AS numbers that are not already known by the resolver are fetched in
+// batches. The returned map is indexed by AS number, AS numbers without a
+// matching network are absent from it.
+func (r *ASNResolver) ResolveMany(asns []ASN) (map[ASN]*Network, error) {
+	networks := make(map[ASN]*Network, len(asns))
+	stale := make(map[ASN]resolverEntry)
+	waiting := make(map[ASN]*resolverCall)
+	var missing []ASN
+
+	now := r.now()
+
+	r.mutex.Lock()
+	for _, asn := range asns {
+		if _, ok := networks[asn]; ok {
+			continue
+		}
+		if _, ok := waiting[asn]; ok {
+			continue
+		}
+
+		entry, known := r.entries[asn]
+		if known && now.Sub(entry.fetched) < r.ttl {
+			if entry.network != nil {
+				networks[asn] = entry.network
+			}
+			continue
+		}
+		if known {
+			stale[asn] = entry
+		}
+
+		// Someone is already looking for this AS number, wait for it
+		if call, ok := r.calls[asn]; ok {
+			waiting[asn] = call
+			continue
+		}
+
+		// We are the one looking for it
+		call := &resolverCall{done: make(chan struct{})}
+		r.calls[asn] = call
+		waiting[asn] = call
+		missing = append(missing, asn)
+	}
+	r.mutex.Unlock()
+
+	if len(missing) > 0 {
+		r.fetch(missing, waiting)
+	}
+
+	var err error
+	for asn, call := range waiting {
+		<-call.done
+
+		if call.err == nil {
+			if call.network != nil {
+				networks[asn] = call.network
+			}
+			continue
+		}
+
+		// Fall back to the stale entry if it is not too old
+		entry, ok := stale[asn]
+		if ok && now.Sub(entry.fetched) < r.ttl+r.maxStale {
+			if entry.network != nil {
+				networks[asn] = entry.network
+			}
+			continue
+		}
+		err = call.err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return networks, nil
+}
+
+// fetch queries the API for the given AS numbers and completes the matching
+// in-flight calls.
+func (r *ASNResolver) fetch(asns []ASN, calls map[ASN]*resolverCall) {
+	found, err := r.api.GetASNs(asns)
+	fetched := r.now()
+
+	r.mutex.Lock()
+	for _, asn := range asns {
+		call := calls[asn]
+		call.err = err
+		if err == nil {
+			call.network = found[asn]
+			r.entries[asn] = resolverEntry{network: call.network, fetched: fetched}
+		}
+		delete(r.calls, asn)
+		close(call.done)
+	}
+	r.mutex.Unlock()
+}
+
+// Forget removes the memoized result for the given AS number, if any, so that
+// the next lookup queries the API.
+func (r *ASNResolver) Forget(asn ASN) {
+	r.mutex.Lock()
+	delete(r.entries, asn)
+	r.mutex.Unlock()
+}
+
+// Purge removes all memoized results.
+func (r *ASNResolver) Purge() {
+	r.mutex.Lock()
+	r.entries = make(map[ASN]resolverEntry)
+	r.mutex.Unlock()
+}
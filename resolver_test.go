@@ -0,0 +1,131 @@
+package peeringdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestASNResolver(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A"},
+			{"id": 2, "asn": 64501, "name": "Network B"},
+		},
+	})
+	resolver := NewASNResolver(server.api(), time.Hour, time.Hour)
+
+	// Resolve several AS numbers concurrently, some of them do not exist
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := resolver.ResolveMany([]ASN{64500, 64501, 64502}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	network, err := resolver.Resolve(64501)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.Name != "Network B" {
+		t.Errorf("Resolve, want 'Network B' got '%v'", network)
+	}
+
+	network, err = resolver.Resolve(64502)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network != nil {
+		t.Errorf("Resolve, want nil got '%v'", network)
+	}
+
+	// Results must have been memoized, missing AS numbers included
+	before := server.count(networkNamespace)
+	resolver.ResolveMany([]ASN{64500, 64501, 64502})
+	if count := server.count(networkNamespace); count != before {
+		t.Errorf("ResolveMany, want %d API calls got %d", before, count)
+	}
+}
+
+func TestASNResolverConcurrent(t *testing.T) {
+	// Count the calls looking for the shared AS number, the API sharing
+	// identical calls itself, each lookup also asks for its own one
+	var calls atomic.Int32
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(strings.Split(r.URL.Query().Get("asn__in"), ","), "64500") && calls.Add(1) == 1 {
+			close(received)
+		}
+		<-release
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500, "name": "Network A"}]}`))
+	}))
+	defer server.Close()
+	resolver := NewASNResolver(NewAPIFromURL(server.URL+"/api/"), time.Hour, time.Hour)
+
+	// Lookups of the same AS number made while the first one is in flight
+	// wait for it, the ones made after it use its result
+	var started, wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			networks, err := resolver.ResolveMany([]ASN{64500, ASN(64600 + i)})
+			if err != nil || networks[64500] == nil || networks[64500].Name != "Network A" {
+				t.Errorf("ResolveMany, unexpected result %v, %v", networks, err)
+			}
+		}()
+	}
+	started.Wait()
+	<-received
+	// Give the other lookups the time to be made before answering
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if count := calls.Load(); count != 1 {
+		t.Errorf("ResolveMany, want 1 API call for the shared AS number got %d", count)
+	}
+}
+
+func TestASNResolverStale(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {{"id": 1, "asn": 64500, "name": "Network A"}},
+	})
+	resolver := NewASNResolver(server.api(), time.Minute, time.Hour)
+
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+	if _, err := resolver.Resolve(64500); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make the API unreachable, the stale entry must still be served
+	server.Close()
+	now = now.Add(30 * time.Minute)
+	network, err := resolver.Resolve(64500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.Name != "Network A" {
+		t.Errorf("Resolve, want 'Network A' got '%v'", network)
+	}
+
+	// Once too old, the error must be returned
+	now = now.Add(2 * time.Hour)
+	if _, err := resolver.Resolve(64500); err == nil {
+		t.Error("Resolve, want error got nil")
+	}
+}
@@ -0,0 +1,107 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// resource is the top-level structure when parsing the JSON output from the
+// API, holding objects of type T. This structure is not used if an object is
+// included as a field in another JSON object. It is used only if the proper
+// namespace is queried.
+type resource[T any] struct {
+	Meta struct {
+		Generated float64 `json:"generated,omitempty"`
+	} `json:"meta"`
+	Data []T `json:"data"`
+}
+
+// typeNamespaces gives the namespace of the objects represented by each
+// structure, it is the reverse of namespaceTypes.
+var typeNamespaces = func() map[reflect.Type]string {
+	namespaces := make(map[reflect.Type]string, len(namespaceTypes))
+	for namespace, t := range namespaceTypes {
+		namespaces[t] = namespace
+	}
+
+	return namespaces
+}()
+
+// namespaceOf returns the namespace of the objects represented by the
+// structure T.
+func namespaceOf[T Object]() string {
+	return typeNamespaces[reflect.TypeOf((*T)(nil)).Elem()]
+}
+
+// getResource returns a pointer to a resource structure corresponding to the
+// API JSON response for the given namespace. An error can be returned if
+// something went wrong.
+func getResource[T any](ctx context.Context, api *API, namespace string, search map[string]interface{}) (*resource[T], error) {
+	// Get the resource from the API
+	response, err := api.lookupWithContext(ctx, namespace, search)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ask for cleanup once we are done
+	defer response.Body.Close()
+
+	// Decode what the API has given to us
+	resource := &resource[T]{}
+	err = json.NewDecoder(response.Body).Decode(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// getObjects returns a pointer to a slice of the objects of type T matching
+// the given search parameters map. The returned slice is empty, but never nil,
+// if no object could be found.
+func getObjects[T Object](ctx context.Context, api *API, search map[string]interface{}) (*[]T, error) {
+	resource, err := getResource[T](ctx, api, namespaceOf[T](), search)
+	if err != nil {
+		return nil, err
+	}
+
+	return nonNilSlice(&resource.Data), nil
+}
+
+// getAllObjects returns a pointer to a slice of all the objects of type T,
+// fetched page by page.
+func getAllObjects[T Object](ctx context.Context, api *API) (*[]T, error) {
+	return getAllPages(ctx, api, namespaceOf[T](), func(ctx context.Context, search map[string]interface{}) (*[]T, error) {
+		return getObjects[T](ctx, api, search)
+	})
+}
+
+// getObjectByID returns a pointer to the object of type T matching the given
+// ID. If the ID is lesser than 0 or if no object matches it, nil is returned.
+// If for some reasons the API returns more than one object for the given ID
+// (but it must not) only the first will be used for the returned value.
+func getObjectByID[T Object](ctx context.Context, api *API, id int) (*T, error) {
+	// No point of looking for an object with an ID < 0
+	if id < 0 {
+		return nil, nil
+	}
+
+	// Ask for the object given its ID
+	search := make(map[string]interface{})
+	search["id"] = id
+
+	objects, err := getObjects[T](ctx, api, search)
+	if err != nil {
+		return nil, err
+	}
+
+	// No object matching the ID
+	if len(*objects) < 1 {
+		return nil, nil
+	}
+
+	// Only return the first match, they must be only one match (ID being
+	// unique)
+	return &(*objects)[0], nil
+}
@@ -0,0 +1,54 @@
+package peeringdb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNamespaceOf(t *testing.T) {
+	for expected, got := range map[string]string{
+		campusNamespace:                     namespaceOf[Campus](),
+		facilityNamespace:                   namespaceOf[Facility](),
+		internetExchangeLANNamespace:        namespaceOf[InternetExchangeLAN](),
+		networkNamespace:                    namespaceOf[Network](),
+		networkInternetExchangeLANNamespace: namespaceOf[NetworkInternetExchangeLAN](),
+		networkContactNamespace:             namespaceOf[NetworkContact](),
+	} {
+		if got != expected {
+			t.Errorf("namespaceOf, expected %s, got %s", expected, got)
+		}
+	}
+
+	// All namespaces have their own structure
+	if len(typeNamespaces) != len(namespaces) {
+		t.Errorf("namespaceOf, expected %d namespaces, got %d", len(namespaces), len(typeNamespaces))
+	}
+}
+
+func TestGetObjects(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		carrierNamespace: {
+			{"id": 1, "name": "Carrier A"},
+			{"id": 2, "name": "Carrier B"},
+		},
+	})
+	api := server.api(WithPageSize(1))
+
+	carriers, err := getAllObjects[Carrier](context.Background(), api)
+	if err != nil || len(*carriers) != 2 || (*carriers)[1].Name != "Carrier B" {
+		t.Fatalf("getAllObjects, unexpected result %v, %v", carriers, err)
+	}
+
+	carrier, err := getObjectByID[Carrier](context.Background(), api, 2)
+	if err != nil || carrier == nil || carrier.Name != "Carrier B" {
+		t.Errorf("getObjectByID, unexpected result %v, %v", carrier, err)
+	}
+	if carrier, err = getObjectByID[Carrier](context.Background(), api, 3); err != nil || carrier != nil {
+		t.Errorf("getObjectByID, expected no object, got %v, %v", carrier, err)
+	}
+
+	found, err := getObjects[Carrier](context.Background(), api, map[string]interface{}{"id": 3})
+	if err != nil || found == nil || len(*found) != 0 {
+		t.Errorf("getObjects, expected an empty slice, got %v, %v", found, err)
+	}
+}
@@ -0,0 +1,185 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy is a structure telling how lookups are retried when the API
+// fails with a transient error, that is a network error, a 5xx status or, if
+// enabled, an exceeded rate limit. Other errors, such as a missing
+// permission, are returned right away.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a lookup, including
+	// the first one. Lookups are not retried if it is lower than 2.
+	MaxAttempts int
+	// InitialBackoff is the time waited before the first retry. It doubles
+	// with each retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum time waited between two attempts. The time
+	// waited is not bounded if it is zero.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the backoff, between 0 and 1, by which the
+	// time waited is randomly changed so that clients do not retry all at
+	// once.
+	Jitter float64
+	// RetryRateLimited enables the retry of lookups failing because the
+	// rate limit is exceeded. The time given by the Retry-After header of
+	// the response is waited, instead of the backoff, if there is one.
+	RetryRateLimited bool
+	// MaxRetryAfter is the maximum time to wait for the rate limit to be
+	// lifted. Lookups are not retried if the API asks to wait longer. The
+	// time waited is not bounded if it is zero.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy is a retry policy suitable for most uses of the public
+// PeeringDB API.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:      4,
+	InitialBackoff:   500 * time.Millisecond,
+	MaxBackoff:       10 * time.Second,
+	Jitter:           0.2,
+	RetryRateLimited: true,
+	MaxRetryAfter:    time.Minute,
+}
+
+// WithRetryPolicy returns an option retrying lookups failing with transient
+// errors according to the given policy. Lookups are not retried by default.
+// Writes are never retried since they may have been applied before failing.
+// The policy can be changed for some lookups with ContextWithRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(api *API) {
+		api.retryPolicy = policy
+	}
+}
+
+// retryPolicyKey is the key used to store a retry policy in a context.
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy returns a copy of the context carrying the given
+// retry policy. Lookups made with this context, using GetNetworkWithContext
+// for instance, are retried according to this policy instead of the one of
+// the API structure, allowing batch jobs and interactive lookups to share a
+// single API structure.
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// ContextWithoutRetry returns a copy of the context disabling the retry of
+// lookups made with it. It suits latency sensitive calls, such as lookups
+// made while a user waits, which should rather fail fast.
+func ContextWithoutRetry(ctx context.Context) context.Context {
+	return ContextWithRetryPolicy(ctx, RetryPolicy{})
+}
+
+// retryKey is the key used to store, in the context of a call, the number of
+// times it was retried.
+type retryKey struct{}
+
+// retryFromContext returns the number of times the call made with the context
+// was retried, 0 for a first attempt.
+func retryFromContext(ctx context.Context) int {
+	retry, _ := ctx.Value(retryKey{}).(int)
+	return retry
+}
+
+// retryPolicyFor returns the retry policy to use for a lookup, taken from the
+// context if it carries one, else from the API structure.
+func (api *API) retryPolicyFor(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+
+	return api.retryPolicy
+}
+
+// backoff returns the time to wait before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < retry; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		backoff += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(backoff))
+	}
+
+	return backoff
+}
+
+// wait returns the time to wait before retrying a lookup failing with the
+// given error, and whether it can be retried.
+func (p RetryPolicy) wait(retry int, err error) (time.Duration, bool) {
+	if retry >= p.MaxAttempts {
+		return 0, false
+	}
+
+	var rateLimit *RateLimitError
+	if errors.As(err, &rateLimit) {
+		if !p.RetryRateLimited {
+			return 0, false
+		}
+		if rateLimit.RetryAfter <= 0 {
+			return p.backoff(retry), true
+		}
+		if p.MaxRetryAfter > 0 && rateLimit.RetryAfter > p.MaxRetryAfter {
+			return 0, false
+		}
+		return rateLimit.RetryAfter, true
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return p.backoff(retry), apiError.Temporary()
+	}
+
+	return 0, false
+}
+
+// get sends a GET request to the given URL, retrying it according to the
+// retry policy.
+func (api *API) get(ctx context.Context, namespace, url string) (*http.Response, error) {
+	policy := api.retryPolicyFor(ctx)
+	rotations := 0
+	for attempt := 1; ; attempt++ {
+		// Prepare the GET request to the API, no need to set a body since
+		// everything is in the URL
+		request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, ErrBuildingRequest
+		}
+
+		response, err := api.do(context.WithValue(ctx, retryKey{}, attempt-1), namespace, request)
+		if err == nil || ctx.Err() != nil {
+			return response, err
+		}
+		// Switching to another key of the pool does not count as an
+		// attempt, each key is tried once at most
+		if api.keyPool != nil && rotations < len(api.keyPool.keys) && api.rotateKey(ctx, err) {
+			rotations++
+			attempt--
+			continue
+		}
+		wait, ok := policy.wait(attempt, err)
+		if !ok {
+			return nil, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package peeringdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	// The server fails the first two calls of each lookup
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/poc":
+			calls.Add(1)
+			http.Error(w, `{"meta": {"error": "forbidden"}}`, http.StatusForbidden)
+			return
+		}
+		if calls.Add(1)%3 != 0 {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: 0.5}
+	api := NewAPIFromURL(server.URL+"/api/", WithRetryPolicy(policy))
+	network, err := api.GetNetworkByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network == nil || network.ASN != 64500 || calls.Load() != 3 {
+		t.Errorf("GetNetworkByID, unexpected network %+v after %d calls", network, calls.Load())
+	}
+
+	// Not enough attempts
+	calls.Store(0)
+	policy.MaxAttempts = 2
+	api = NewAPIFromURL(server.URL+"/api/", WithRetryPolicy(policy))
+	if _, err = api.GetNetworkByID(1); err == nil || calls.Load() != 2 {
+		t.Errorf("GetNetworkByID, want error after 2 calls got %v after %d calls", err, calls.Load())
+	}
+
+	// Errors which are not transient are not retried
+	calls.Store(0)
+	if _, err = api.GetNetworkContactByID(1); err == nil || calls.Load() != 1 {
+		t.Errorf("GetNetworkContactByID, want error after 1 call got %v after %d calls", err, calls.Load())
+	}
+
+	// Retries disabled for a single call, or another policy for it
+	calls.Store(0)
+	if _, err = api.GetNetworkByIDWithContext(ContextWithoutRetry(context.Background()), 1); err == nil || calls.Load() != 1 {
+		t.Errorf("GetNetworkByIDWithContext, want error after 1 call got %v after %d calls", err, calls.Load())
+	}
+	calls.Store(0)
+	policy.MaxAttempts = 3
+	if _, err = api.GetNetworkByIDWithContext(ContextWithRetryPolicy(context.Background(), policy), 1); err != nil || calls.Load() != 3 {
+		t.Errorf("GetNetworkByIDWithContext, want success after 3 calls got %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d), want %s got %s", retry, want, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("backoff(1), want 0.5s to 1.5s got %s", got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package peeringdb
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// rirRegisteredStatuses are the RIR statuses of AS numbers which are still
+// registered.
+var rirRegisteredStatuses = map[string]bool{
+	"ok":        true,
+	"assigned":  true,
+	"allocated": true,
+}
+
+// ASNLapsed tells if the RIR status of the network says that its AS number is
+// not registered anymore. It is false if the status is unknown.
+func (n Network) ASNLapsed() bool {
+	status := strings.ToLower(strings.TrimSpace(n.RIRStatus))
+	return status != "" && !rirRegisteredStatuses[status]
+}
+
+// GetNetworksByRIRStatus returns the networks having one of the given RIR
+// statuses, such as "available" or "reserved", sorted by AS number.
+func (api *API) GetNetworksByRIRStatus(statuses ...string) ([]Network, error) {
+	search := make(map[string]interface{})
+	search["rir_status__in"] = strings.Join(statuses, ",")
+
+	networks, err := api.GetNetwork(search)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(*networks, func(i, j int) bool {
+		return (*networks)[i].ASN < (*networks)[j].ASN
+	})
+
+	return *networks, nil
+}
+
+// RIRStatusCheck is the result of the check of the RIR status of several AS
+// numbers.
+type RIRStatusCheck struct {
+	// Lapsed are the networks whose AS number is not registered anymore,
+	// sorted by AS number.
+	Lapsed []Network
+	// NotFound are the AS numbers without a matching network.
+	NotFound []ASN
+}
+
+// CheckRIRStatus looks up the networks of the given AS numbers, such as the
+// ones of existing peers, and returns the ones whose AS number registration
+// has lapsed. If since is not zero, only networks whose RIR status changed
+// after it are returned. Networks are fetched in bulk.
+func (api *API) CheckRIRStatus(asns []ASN, since time.Time) (*RIRStatusCheck, error) {
+	networks, err := api.GetASNs(asns)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &RIRStatusCheck{}
+	seen := make(map[ASN]bool, len(asns))
+	for _, asn := range asns {
+		if seen[asn] {
+			continue
+		}
+		seen[asn] = true
+
+		network, ok := networks[asn]
+		switch {
+		case !ok:
+			check.NotFound = append(check.NotFound, asn)
+		case network.ASNLapsed() && (since.IsZero() || network.RIRStatusUpdated.After(since)):
+			check.Lapsed = append(check.Lapsed, *network)
+		}
+	}
+
+	sort.Slice(check.Lapsed, func(i, j int) bool {
+		return check.Lapsed[i].ASN < check.Lapsed[j].ASN
+	})
+
+	return check, nil
+}
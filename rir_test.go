@@ -0,0 +1,45 @@
+package peeringdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckRIRStatus(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "rir_status": "ok", "rir_status_updated": "2020-01-01T00:00:00Z"},
+			{"id": 2, "asn": 64501, "rir_status": "available", "rir_status_updated": "2020-01-01T00:00:00Z"},
+			{"id": 3, "asn": 64502, "rir_status": "reserved", "rir_status_updated": "2022-01-01T00:00:00Z"},
+			{"id": 4, "asn": 64503},
+		},
+	})
+	api := server.api()
+
+	check, err := api.CheckRIRStatus([]ASN{64503, 64502, 64501, 64500, 64500, 64510}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(check.Lapsed) != 2 || check.Lapsed[0].ASN != 64501 || check.Lapsed[1].ASN != 64502 {
+		t.Errorf("CheckRIRStatus, unexpected lapsed networks: %+v", check.Lapsed)
+	}
+	if len(check.NotFound) != 1 || check.NotFound[0] != 64510 {
+		t.Errorf("CheckRIRStatus, unexpected missing networks: %v", check.NotFound)
+	}
+
+	check, err = api.CheckRIRStatus([]ASN{64500, 64501, 64502}, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(check.Lapsed) != 1 || check.Lapsed[0].ASN != 64502 {
+		t.Errorf("CheckRIRStatus, unexpected lapsed networks since 2021: %+v", check.Lapsed)
+	}
+
+	networks, err := api.GetNetworksByRIRStatus("available", "reserved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 2 || networks[0].ASN != 64501 || networks[1].ASN != 64502 {
+		t.Errorf("GetNetworksByRIRStatus, unexpected networks: %+v", networks)
+	}
+}
@@ -0,0 +1,97 @@
+package peeringdb
+
+import "sort"
+
+// RouteServerPeering is a structure describing the presence of a network at
+// an Internet exchange point from a route server point of view.
+type RouteServerPeering struct {
+	InternetExchange InternetExchange
+	// RouteServerASNs are the AS numbers of the route servers of the IX LANs.
+	RouteServerASNs []ASN
+	// IsRSPeer tells if at least one connection of the network peers with the
+	// route servers.
+	IsRSPeer bool
+	// Connections are the connections of the network to the IX.
+	Connections []NetworkInternetExchangeLAN
+	// Peers are the connections of the other networks peering with the route
+	// servers of the IX.
+	Peers []NetworkInternetExchangeLAN
+}
+
+// GetRouteServerPeerings returns the Internet exchange points where the
+// network identified by the given AS number is present, telling if it peers
+// with the route servers of each IX and listing the other networks doing so.
+// It allows to find where route server sessions are in place and which peers
+// can be reached through them. IXs are sorted by name and peers by AS number.
+func (api *API) GetRouteServerPeerings(asn ASN) ([]RouteServerPeering, error) {
+	search := make(map[string]interface{})
+	search["asn"] = asn
+
+	connections, err := api.GetNetworkInternetExchangeLAN(search)
+	if err != nil {
+		return nil, err
+	}
+
+	peerings := make(map[int]*RouteServerPeering)
+	var ids []int
+	for _, connection := range *connections {
+		peering, ok := peerings[connection.InternetExchangeID]
+		if !ok {
+			peering = &RouteServerPeering{}
+			peerings[connection.InternetExchangeID] = peering
+			ids = append(ids, connection.InternetExchangeID)
+		}
+		peering.Connections = append(peering.Connections, connection)
+		peering.IsRSPeer = peering.IsRSPeer || connection.IsRSPeer
+	}
+
+	ixs, err := getChunked(ids, "id", api.GetInternetExchange)
+	if err != nil {
+		return nil, err
+	}
+	ixLANs, err := getChunked(ids, "ix_id", api.GetInternetExchangeLAN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only fetch connections peering with the route servers
+	peers, err := getChunked(ids, "ix_id", func(search map[string]interface{}) (*[]NetworkInternetExchangeLAN, error) {
+		search["is_rs_peer"] = true
+		return api.GetNetworkInternetExchangeLAN(search)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ix := range ixs {
+		if peering, ok := peerings[ix.ID]; ok {
+			peering.InternetExchange = ix
+		}
+	}
+	for _, ixLAN := range ixLANs {
+		peering, ok := peerings[ixLAN.InternetExchangeID]
+		if ok && ixLAN.RouteServerASN != 0 {
+			peering.RouteServerASNs = append(peering.RouteServerASNs, ixLAN.RouteServerASN)
+		}
+	}
+	for _, peer := range peers {
+		peering, ok := peerings[peer.InternetExchangeID]
+		if ok && peer.ASN != asn && peer.IsRSPeer {
+			peering.Peers = append(peering.Peers, peer)
+		}
+	}
+
+	result := make([]RouteServerPeering, 0, len(peerings))
+	for _, id := range ids {
+		peering := peerings[id]
+		sort.Slice(peering.Peers, func(i, j int) bool {
+			return peering.Peers[i].ASN < peering.Peers[j].ASN
+		})
+		result = append(result, *peering)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].InternetExchange.Name < result[j].InternetExchange.Name
+	})
+
+	return result, nil
+}
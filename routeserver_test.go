@@ -0,0 +1,43 @@
+package peeringdb
+
+import "testing"
+
+func TestGetRouteServerPeerings(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX B"},
+			{"id": 2, "name": "IX A"},
+		},
+		internetExchangeLANNamespace: {
+			{"id": 1, "ix_id": 1, "rs_asn": 64999},
+			{"id": 2, "ix_id": 2, "rs_asn": 0},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "ix_id": 1, "asn": 64500, "is_rs_peer": true},
+			{"id": 2, "ix_id": 2, "asn": 64500, "is_rs_peer": false},
+			{"id": 3, "ix_id": 1, "asn": 64502, "is_rs_peer": true},
+			{"id": 4, "ix_id": 1, "asn": 64501, "is_rs_peer": true},
+			{"id": 5, "ix_id": 1, "asn": 64503, "is_rs_peer": false},
+		},
+	})
+
+	peerings, err := server.api().GetRouteServerPeerings(64500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peerings) != 2 {
+		t.Fatalf("GetRouteServerPeerings, want 2 IXs got %d", len(peerings))
+	}
+
+	if peerings[0].InternetExchange.Name != "IX A" || peerings[0].IsRSPeer || len(peerings[0].Peers) != 0 {
+		t.Errorf("GetRouteServerPeerings, unexpected first peering: %+v", peerings[0])
+	}
+
+	peering := peerings[1]
+	if !peering.IsRSPeer || len(peering.RouteServerASNs) != 1 || peering.RouteServerASNs[0] != 64999 {
+		t.Errorf("GetRouteServerPeerings, unexpected second peering: %+v", peering)
+	}
+	if len(peering.Peers) != 2 || peering.Peers[0].ASN != 64501 || peering.Peers[1].ASN != 64502 {
+		t.Errorf("GetRouteServerPeerings, unexpected peers: %+v", peering.Peers)
+	}
+}
@@ -0,0 +1,132 @@
+package peeringdb
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ellipsis is appended to truncated texts.
+const ellipsis = "…"
+
+// SanitizeOptions is a structure telling how free-form texts, such as notes
+// or terms, are sanitized.
+type SanitizeOptions struct {
+	// MaxLength is the maximum number of characters of the text, including
+	// the ellipsis marking a truncated text. The text is not truncated if it
+	// is zero.
+	MaxLength int
+	// SingleLine joins the lines of the text with spaces.
+	SingleLine bool
+	// StripMarkdown converts the Markdown markup of the text, which most
+	// free-form fields support, to plain text.
+	StripMarkdown bool
+}
+
+// Sanitize returns a version of a free-form text, as entered by PeeringDB
+// users, safe to render in reports and terminals. Invalid UTF-8 sequences,
+// control characters and invisible formatting characters, such as
+// bidirectional overrides, are removed. Line endings and the various Unicode
+// spaces are normalized, runs of spaces are collapsed and blank lines are
+// limited to one in a row.
+func Sanitize(text string, options SanitizeOptions) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if options.StripMarkdown {
+		text = StripMarkdown(text)
+	}
+
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r':
+			if options.SingleLine {
+				return ' '
+			}
+			return '\n'
+		case unicode.IsSpace(r):
+			return ' '
+		// Keep the joiner used by emoji sequences
+		case r == '‍':
+			return r
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			return -1
+		default:
+			return r
+		}
+	}, text)
+
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(kept) == 0 || kept[len(kept)-1] == "") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	text = strings.TrimSpace(strings.Join(kept, "\n"))
+
+	return truncate(text, options.MaxLength)
+}
+
+// truncate returns the text cut to the given number of characters, ellipsis
+// included. It is cut at the end of a word if one ends late enough.
+func truncate(text string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	if max <= utf8.RuneCountInString(ellipsis) {
+		return string([]rune(ellipsis)[:max])
+	}
+
+	runes := []rune(text)
+	length := max - utf8.RuneCountInString(ellipsis)
+	cut := string(runes[:length])
+	if index := strings.LastIndexAny(cut, " \n"); !unicode.IsSpace(runes[length]) && index > len(cut)/2 {
+		cut = cut[:index]
+	}
+
+	return strings.TrimSpace(cut) + ellipsis
+}
+
+var (
+	markdownFence      = regexp.MustCompile("(?m)^\\s*(```|~~~).*$\n?")
+	markdownHeading    = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	markdownQuote      = regexp.MustCompile(`(?m)^\s{0,3}>\s?`)
+	markdownRule       = regexp.MustCompile(`(?m)^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	markdownBullet     = regexp.MustCompile(`(?m)^(\s*)[*+]\s+`)
+	markdownImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	markdownAutolink   = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	markdownStrong     = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	markdownEmphasis   = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*`)
+	markdownUnderscore = regexp.MustCompile(`(^|\W)_(\S(?:[^_]*?\S)?)_(\W|$)`)
+	markdownCode       = regexp.MustCompile("`([^`]+)`")
+)
+
+// StripMarkdown converts the Markdown markup of a text to plain text. Links
+// are kept as their text followed by their URL, list items are kept with a
+// dash, and emphasis, headings, quotes and code markers are removed.
+func StripMarkdown(text string) string {
+	text = markdownFence.ReplaceAllString(text, "")
+	text = markdownRule.ReplaceAllString(text, "")
+	text = markdownHeading.ReplaceAllString(text, "$1")
+	text = markdownQuote.ReplaceAllString(text, "")
+	text = markdownBullet.ReplaceAllString(text, "$1- ")
+	text = markdownImage.ReplaceAllString(text, "$1")
+	text = markdownLink.ReplaceAllStringFunc(text, func(link string) string {
+		parts := markdownLink.FindStringSubmatch(link)
+		if parts[1] == parts[2] {
+			return parts[1]
+		}
+		return parts[1] + " (" + parts[2] + ")"
+	})
+	text = markdownAutolink.ReplaceAllString(text, "$1")
+	text = markdownCode.ReplaceAllString(text, "$1")
+	text = markdownStrong.ReplaceAllString(text, "$1$2")
+	text = markdownEmphasis.ReplaceAllString(text, "$1$2")
+	text = markdownUnderscore.ReplaceAllString(text, "$1$2$3")
+
+	return text
+}
@@ -0,0 +1,69 @@
+package peeringdb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		text    string
+		options SanitizeOptions
+		want    string
+	}{
+		{"  Peering policy:\topen \r\n\r\n\r\n\r\nContact us\x07 ", SanitizeOptions{}, "Peering policy: open\n\nContact us"},
+		{"abc‮def​ghi\xff", SanitizeOptions{}, "abcdefghi"},
+		{"line one\nline two", SanitizeOptions{SingleLine: true}, "line one line two"},
+		{"open peering policy for everyone", SanitizeOptions{MaxLength: 20}, "open peering policy…"},
+		{"abcdefghijklmnopqrstuvwxyz", SanitizeOptions{MaxLength: 10}, "abcdefghi…"},
+		{"short", SanitizeOptions{MaxLength: 10}, "short"},
+		{"# Terms\n\nSee **our** [policy](https://example.com/policy).", SanitizeOptions{StripMarkdown: true, SingleLine: true},
+			"Terms See our policy (https://example.com/policy)."},
+	}
+	for _, test := range tests {
+		if got := Sanitize(test.text, test.options); got != test.want {
+			t.Errorf("Sanitize(%q), want %q got %q", test.text, test.want, got)
+		}
+	}
+}
+
+func TestStripMarkdown(t *testing.T) {
+	text := "## Heading ##\n" +
+		"> quoted *text*\n" +
+		"* item `code`\n" +
+		"+ item __strong__\n" +
+		"---\n" +
+		"```\nfenced\n```\n" +
+		"snake_case_name and _emphasis_ ![logo](logo.png) <https://example.com> [https://x.y](https://x.y)"
+	want := "Heading\n" +
+		"quoted text\n" +
+		"- item code\n" +
+		"- item strong\n" +
+		"\n" +
+		"fenced\n" +
+		"snake_case_name and emphasis logo https://example.com https://x.y"
+	if got := StripMarkdown(text); got != want {
+		t.Errorf("StripMarkdown, want %q got %q", want, got)
+	}
+}
+
+func TestReportSanitized(t *testing.T) {
+	table := &ReportTable{Columns: []string{"name", "notes"}}
+	table.Append("IX", "Open\tpolicy\nsee **terms**\x1b[31m")
+
+	var buffer bytes.Buffer
+	if err := table.Write(&buffer, ReportText); err != nil {
+		t.Fatal(err)
+	}
+	if want := "name  notes\nIX    Open policy see **terms**[31m\n"; buffer.String() != want {
+		t.Errorf("Write, want %q got %q", want, buffer.String())
+	}
+
+	buffer.Reset()
+	if err := table.WriteTemplate(&buffer, `{{range .Rows}}{{sanitize 14 (index . 1)}}{{end}}`); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Open policy…"; buffer.String() != want {
+		t.Errorf("WriteTemplate, want %q got %q", want, buffer.String())
+	}
+}
@@ -0,0 +1,78 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// APIKeyType is the type of an API key, it tells on behalf of whom the API
+// calls are made and which operations are allowed.
+type APIKeyType int
+
+const (
+	// UserAPIKey is a key created by a user. Calls are made with the
+	// permissions of the user: objects visible to the user can be read,
+	// including private contacts, and objects of the organizations the user
+	// is an administrator of can be created, updated and deleted.
+	UserAPIKey APIKeyType = iota
+	// OrganizationAPIKey is a key created by an organization. Calls are
+	// limited to the objects of the organization, with the permissions
+	// granted to the key by the administrators of the organization, per
+	// namespace: read, create, update and delete. Such keys are meant for
+	// automation that must not depend on a user account.
+	OrganizationAPIKey
+)
+
+// String returns the name of the API key type.
+func (t APIKeyType) String() string {
+	switch t {
+	case UserAPIKey:
+		return "user"
+	case OrganizationAPIKey:
+		return "organization"
+	default:
+		return fmt.Sprintf("APIKeyType(%d)", int(t))
+	}
+}
+
+// ErrInsufficientScope is the error that will be returned if the API rejects
+// a call because the API key does not allow it. Errors returned in such a case
+// are InsufficientScopeError values which can be checked with errors.Is.
+var ErrInsufficientScope = errors.New("insufficient api key scope")
+
+// InsufficientScopeError is the error returned when the API rejects a call
+// because the API key used, or the lack of it, does not allow it.
+type InsufficientScopeError struct {
+	// KeyType is the type of the API key used for the call.
+	KeyType APIKeyType
+	// Namespace is the namespace of the objects the call was about.
+	Namespace string
+	// Message is the message given by the API.
+	Message string
+}
+
+// Error returns a message describing the rejected call.
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("%s: %s key not allowed on %s: %s",
+		ErrInsufficientScope, e.KeyType, e.Namespace, e.Message)
+}
+
+// Is tells if the target is ErrInsufficientScope.
+func (e *InsufficientScopeError) Is(target error) bool {
+	return target == ErrInsufficientScope
+}
+
+// WithOrganizationAPIKey returns an option setting an organization API key to
+// authenticate API calls. It replaces any user API key given when creating the
+// API structure.
+func WithOrganizationAPIKey(apiKey string) Option {
+	return func(api *API) {
+		api.apiKey = apiKey
+		api.apiKeyType = OrganizationAPIKey
+	}
+}
+
+// APIKeyType returns the type of the API key used to authenticate API calls.
+func (api *API) APIKeyType() APIKeyType {
+	return api.apiKeyType
+}
@@ -0,0 +1,38 @@
+package peeringdb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInsufficientScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Api-Key org-key" {
+			t.Errorf("unexpected authorization header: %s", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"meta": {"error": "You do not have permission to perform this action."}, "data": []}`))
+	}))
+	defer server.Close()
+
+	api := NewAPIFromURLWithAPIKey(server.URL+"/api/", "user-key", WithOrganizationAPIKey("org-key"))
+	if api.APIKeyType() != OrganizationAPIKey {
+		t.Errorf("APIKeyType, want %s got %s", OrganizationAPIKey, api.APIKeyType())
+	}
+
+	_, err := api.GetNetworkContact(nil)
+	if !errors.Is(err, ErrInsufficientScope) {
+		t.Fatalf("GetNetworkContact, want ErrInsufficientScope got %v", err)
+	}
+
+	var scopeError *InsufficientScopeError
+	if !errors.As(err, &scopeError) {
+		t.Fatalf("GetNetworkContact, want InsufficientScopeError got %T", err)
+	}
+	if scopeError.KeyType != OrganizationAPIKey || scopeError.Namespace != networkContactNamespace ||
+		scopeError.Message != "You do not have permission to perform this action." {
+		t.Errorf("GetNetworkContact, unexpected error: %+v", scopeError)
+	}
+}
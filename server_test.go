@@ -0,0 +1,241 @@
+package peeringdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer is a minimal PeeringDB API speaking HTTP server used to test
+// functions without depending on the live API.
+type testServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	objects  map[string][]map[string]interface{}
+	requests map[string]int
+	// quota is the number of requests served before answering with HTTP 429
+	// errors, it is ignored if negative.
+	quota int
+}
+
+// newTestServer starts a test server serving the given objects, indexed by
+// namespace. The server is closed when the test ends.
+func newTestServer(t *testing.T, objects map[string][]map[string]interface{}) *testServer {
+	s := &testServer{objects: objects, requests: make(map[string]int), quota: -1}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// api returns an API pointing to the test server.
+func (s *testServer) api(options ...Option) *API {
+	return NewAPIFromURL(s.URL+"/api/", options...)
+}
+
+// setQuota sets the number of requests the server will answer before
+// reporting that the rate limit is exceeded. A negative value disables it.
+func (s *testServer) setQuota(quota int) {
+	s.mutex.Lock()
+	s.quota = quota
+	s.mutex.Unlock()
+}
+
+// count returns the number of requests received for a namespace.
+func (s *testServer) count(namespace string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests[namespace]
+}
+
+func (s *testServer) serve(w http.ResponseWriter, r *http.Request) {
+	namespace, id, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+
+	s.mutex.Lock()
+	s.requests[namespace]++
+	objects, ok := s.objects[namespace]
+	exceeded := s.quota == 0
+	if s.quota > 0 {
+		s.quota--
+	}
+	s.mutex.Unlock()
+
+	if exceeded {
+		http.Error(w, `{"message": "Request was throttled."}`, http.StatusTooManyRequests)
+		return
+	}
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		s.write(w, r, namespace, id)
+		return
+	}
+
+	// Like the API, deleted objects are only returned when asking for the
+	// objects updated since a given time
+	query := r.URL.Query()
+	var since time.Time
+	if timestamp, err := strconv.ParseInt(query.Get("since"), 10, 64); err == nil {
+		since = time.Unix(timestamp, 0)
+	}
+
+	data := []map[string]interface{}{}
+	for _, object := range objects {
+		if since.IsZero() && object["status"] == "deleted" {
+			continue
+		}
+		if !since.IsZero() {
+			updated, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", object["updated"]))
+			if err != nil || !updated.After(since) {
+				continue
+			}
+		}
+		if matches(object, query) {
+			data = append(data, object)
+		}
+	}
+
+	if skip, err := strconv.Atoi(query.Get("skip")); err == nil {
+		data = data[min(skip, len(data)):]
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		data = data[:min(limit, len(data))]
+	}
+	if fields := query.Get("fields"); fields != "" {
+		for i, object := range data {
+			projected := make(map[string]interface{})
+			for _, field := range strings.Split(fields, ",") {
+				if value, ok := object[field]; ok {
+					projected[field] = value
+				}
+			}
+			data[i] = projected
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"meta": map[string]interface{}{},
+		"data": data,
+	})
+}
+
+// write creates, updates or deletes an object of a namespace.
+func (s *testServer) write(w http.ResponseWriter, r *http.Request, namespace, id string) {
+	var object map[string]interface{}
+	if r.Method != http.MethodDelete {
+		if err := json.NewDecoder(r.Body).Decode(&object); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	objects := s.objects[namespace]
+	index := -1
+	for i, candidate := range objects {
+		if fmt.Sprintf("%v", candidate["id"]) == id {
+			index = i
+		}
+	}
+
+	status := http.StatusOK
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		maxID := 0
+		for _, candidate := range objects {
+			if value, _ := strconv.Atoi(fmt.Sprintf("%v", candidate["id"])); value > maxID {
+				maxID = value
+			}
+		}
+		object["id"] = maxID + 1
+		s.objects[namespace] = append(objects, object)
+		status = http.StatusCreated
+	case index < 0:
+		http.NotFound(w, r)
+		return
+	case r.Method == http.MethodPut || r.Method == http.MethodPatch:
+		for key, value := range object {
+			objects[index][key] = value
+		}
+		object = objects[index]
+	case r.Method == http.MethodDelete:
+		s.objects[namespace] = append(objects[:index], objects[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"meta": map[string]interface{}{},
+		"data": []interface{}{object},
+	})
+}
+
+// matches tells if an object matches all filters of a query. Only exact
+// matches and the "__in" and "__contains" operators are supported.
+func matches(object map[string]interface{}, query map[string][]string) bool {
+	for key, values := range query {
+		switch key {
+		case "depth", "limit", "skip", "fields", "since":
+			continue
+		}
+
+		field, operator, _ := strings.Cut(key, "__")
+		value := fmt.Sprintf("%v", object[field])
+
+		switch operator {
+		case "":
+			if value != values[0] {
+				return false
+			}
+		case "in":
+			found := false
+			for _, candidate := range strings.Split(values[0], ",") {
+				found = found || candidate == value
+			}
+			if !found {
+				return false
+			}
+		case "contains":
+			if !strings.Contains(strings.ToLower(value), strings.ToLower(values[0])) {
+				return false
+			}
+		case "startswith":
+			if !strings.HasPrefix(strings.ToLower(value), strings.ToLower(values[0])) {
+				return false
+			}
+		case "lt", "lte", "gt", "gte":
+			// Objects are not decoded from JSON, numbers can be integers
+			actual := object[field]
+			if i, ok := actual.(int); ok {
+				actual = float64(i)
+			}
+			if match, err := matchValue(actual, operator, values[0]); err != nil || !match {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,100 @@
+package peeringdb
+
+// ConnectionSide is one side of a network IX LAN connection, the facility
+// where the port of the network or the one of the IX is located.
+type ConnectionSide struct {
+	// FacilityID is the ID of the facility, it is 0 if the side is not
+	// known.
+	FacilityID int
+	// Facility is the facility of the side once resolved, it is nil if the
+	// side is not known or if the facility cannot be found.
+	Facility *Facility
+}
+
+// IsSet tells if the facility of the side is known.
+func (s ConnectionSide) IsSet() bool {
+	return s.FacilityID != 0
+}
+
+// NetworkSide returns the side of the network of the connection, without
+// resolving its facility.
+func (n NetworkInternetExchangeLAN) NetworkSide() ConnectionSide {
+	return ConnectionSide{FacilityID: n.NetworkSideID}
+}
+
+// InternetExchangeSide returns the side of the IX of the connection, without
+// resolving its facility.
+func (n NetworkInternetExchangeLAN) InternetExchangeSide() ConnectionSide {
+	return ConnectionSide{FacilityID: n.InternetExchangeSideID}
+}
+
+// ConnectionSides is a structure describing both sides of a network IX LAN
+// connection, as needed to generate a letter of authorization or to patch
+// the connection.
+type ConnectionSides struct {
+	NetworkInternetExchangeLAN NetworkInternetExchangeLAN
+	Network                    ConnectionSide
+	InternetExchange           ConnectionSide
+}
+
+// Complete tells if the facilities of both sides are known.
+func (s ConnectionSides) Complete() bool {
+	return s.Network.IsSet() && s.InternetExchange.IsSet()
+}
+
+// SameFacility tells if both sides are known and located in the same
+// facility, in which case the connection only needs a cross-connect within
+// it.
+func (s ConnectionSides) SameFacility() bool {
+	return s.Complete() && s.Network.FacilityID == s.InternetExchange.FacilityID
+}
+
+// GetConnectionSides returns the sides of the given network IX LAN
+// connections, in the same order, with their facilities resolved. Facilities
+// are fetched in bulk.
+func (api *API) GetConnectionSides(netixlans []NetworkInternetExchangeLAN) ([]ConnectionSides, error) {
+	var ids []int
+	for _, netixlan := range netixlans {
+		for _, id := range []int{netixlan.NetworkSideID, netixlan.InternetExchangeSideID} {
+			if id != 0 {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	facilities, err := getChunked(ids, "id", api.GetFacility)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*Facility, len(facilities))
+	for i := range facilities {
+		byID[facilities[i].ID] = &facilities[i]
+	}
+
+	sides := make([]ConnectionSides, len(netixlans))
+	for i, netixlan := range netixlans {
+		sides[i] = ConnectionSides{
+			NetworkInternetExchangeLAN: netixlan,
+			Network:                    netixlan.NetworkSide(),
+			InternetExchange:           netixlan.InternetExchangeSide(),
+		}
+		sides[i].Network.Facility = byID[netixlan.NetworkSideID]
+		sides[i].InternetExchange.Facility = byID[netixlan.InternetExchangeSideID]
+	}
+
+	return sides, nil
+}
+
+// GetConnectionSidesByASN returns the sides of the network IX LAN connections
+// of the network identified by the given AS number.
+func (api *API) GetConnectionSidesByASN(asn ASN) ([]ConnectionSides, error) {
+	search := make(map[string]interface{})
+	search["asn"] = asn
+
+	netixlans, err := api.GetNetworkInternetExchangeLAN(search)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetConnectionSides(*netixlans)
+}
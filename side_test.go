@@ -0,0 +1,44 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestGetConnectionSides(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		facilityNamespace: {
+			{"id": 1, "name": "Facility A"},
+			{"id": 2, "name": "Facility B"},
+		},
+		networkInternetExchangeLANNamespace: {
+			{"id": 10, "asn": 64500, "net_side_id": 1, "ix_side_id": 1},
+			{"id": 11, "asn": 64500, "net_side_id": 1, "ix_side_id": 2},
+			{"id": 12, "asn": 64500, "net_side_id": 3},
+			{"id": 13, "asn": 64501, "net_side_id": 2, "ix_side_id": 2},
+		},
+	})
+
+	sides, err := server.api().GetConnectionSidesByASN(64500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sides) != 3 {
+		t.Fatalf("GetConnectionSidesByASN, want 3 connections got %d", len(sides))
+	}
+
+	if !sides[0].SameFacility() || sides[0].Network.Facility == nil || sides[0].Network.Facility.Name != "Facility A" {
+		t.Errorf("GetConnectionSidesByASN, want both sides in facility A got %+v", sides[0])
+	}
+	if sides[1].SameFacility() || !sides[1].Complete() || sides[1].InternetExchange.Facility.Name != "Facility B" {
+		t.Errorf("GetConnectionSidesByASN, want sides in facilities A and B got %+v", sides[1])
+	}
+	// Unknown facility and unknown IX side
+	if sides[2].Complete() || sides[2].Network.Facility != nil || sides[2].InternetExchange.IsSet() {
+		t.Errorf("GetConnectionSidesByASN, want incomplete sides got %+v", sides[2])
+	}
+
+	// Facilities are fetched in a single call
+	if count := server.count(facilityNamespace); count != 1 {
+		t.Errorf("GetConnectionSidesByASN, want 1 facility call got %d", count)
+	}
+}
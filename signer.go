@@ -0,0 +1,38 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrSigningRequest is the error that will be returned if a request signer
+// fails to sign a request. It wraps the error returned by the signer.
+var ErrSigningRequest = errors.New("error while signing the request")
+
+// RequestSigner is a function adding headers to a request before it is sent,
+// such as an HMAC signature or a JWT required by an API gateway proxying
+// PeeringDB. The body of requests having one can be read with GetBody without
+// consuming it.
+type RequestSigner func(*http.Request) error
+
+// WithRequestSigner returns an option registering a function called to sign
+// each request, after the authentication and request ID headers are set and
+// right before it is sent. Signers are called in the order they are
+// registered, on each attempt of a retried lookup.
+func WithRequestSigner(signer RequestSigner) Option {
+	return func(api *API) {
+		api.signers = append(api.signers, signer)
+	}
+}
+
+// sign calls all request signers on the given request.
+func (api *API) sign(request *http.Request) error {
+	for _, signer := range api.signers {
+		if err := signer(request); err != nil {
+			return fmt.Errorf("%w: %w", ErrSigningRequest, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package peeringdb
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// hmacSigner signs requests with their method, path and body.
+func hmacSigner(key []byte) RequestSigner {
+	return func(request *http.Request) error {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(request.Method + " " + request.URL.RequestURI() + "\n"))
+		mac.Write([]byte(request.Header.Get("Authorization") + "\n"))
+		if request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return err
+			}
+			defer body.Close()
+			if _, err = io.Copy(mac, body); err != nil {
+				return err
+			}
+		}
+		request.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+		return nil
+	}
+}
+
+func TestWithRequestSigner(t *testing.T) {
+	key := []byte("secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+		mac.Write([]byte(r.Header.Get("Authorization") + "\n"))
+		mac.Write(body)
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	api := NewAPIFromURLWithAPIKey(server.URL+"/api/", "key", WithRequestSigner(hmacSigner(key)))
+	if _, err := api.GetNetworkByID(1); err != nil {
+		t.Errorf("GetNetworkByID, unexpected error: %v", err)
+	}
+	if _, err := api.write(context.Background(), http.MethodPost, networkNamespace, 0, map[string]interface{}{"asn": 64500}, nil); err != nil {
+		t.Errorf("write, unexpected error: %v", err)
+	}
+
+	// Unsigned requests are rejected by the gateway
+	if _, err := NewAPIFromURLWithAPIKey(server.URL+"/api/", "key").GetNetworkByID(1); err == nil {
+		t.Error("GetNetworkByID, want error for unsigned request")
+	}
+
+	failure := errors.New("no key available")
+	api = NewAPIFromURL(server.URL+"/api/", WithRequestSigner(func(*http.Request) error { return failure }))
+	if _, err := api.GetNetworkByID(1); !errors.Is(err, ErrSigningRequest) || !errors.Is(err, failure) {
+		t.Errorf("GetNetworkByID, want signing error got %v", err)
+	}
+}
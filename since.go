@@ -0,0 +1,25 @@
+package peeringdb
+
+import (
+	"time"
+)
+
+// UpdatedSince returns a copy of the given search parameters map asking the
+// API to only return objects created, updated or deleted after the given
+// time, using the "since" parameter of the API. It allows to fetch changes
+// incrementally:
+//
+//	networks, err := api.GetNetwork(peeringdb.UpdatedSince(nil, lastFetch))
+//
+// Unlike other lookups, such queries also return deleted objects, with their
+// Status field set to "deleted", so that callers can remove them. The API only
+// has a precision of one second.
+func UpdatedSince(search map[string]interface{}, t time.Time) map[string]interface{} {
+	updated := make(map[string]interface{}, len(search)+1)
+	for key, value := range search {
+		updated[key] = value
+	}
+	updated["since"] = t.Unix()
+
+	return updated
+}
@@ -0,0 +1,32 @@
+package peeringdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdatedSince(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "status": "ok", "updated": "2024-01-01T00:00:00Z"},
+			{"id": 2, "asn": 64501, "status": "ok", "updated": "2024-03-01T00:00:00Z"},
+			{"id": 3, "asn": 64502, "status": "deleted", "updated": "2024-03-02T00:00:00Z"},
+			{"id": 4, "asn": 64503, "status": "ok", "updated": "2024-03-03T00:00:00Z"},
+		},
+	})
+
+	since := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	search := UpdatedSince(map[string]interface{}{"asn__in": "64500,64501,64502"}, since)
+	if search["since"] != since.Unix() {
+		t.Errorf("UpdatedSince, unexpected parameters: %v", search)
+	}
+
+	// Deleted objects are returned too
+	networks, err := server.api().GetNetwork(search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*networks) != 2 || (*networks)[0].ID != 2 || (*networks)[1].Status != "deleted" {
+		t.Errorf("GetNetwork, want networks 2 and 3 got %+v", *networks)
+	}
+}
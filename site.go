@@ -0,0 +1,157 @@
+package peeringdb
+
+import "sort"
+
+// FacilityIXMatrix is a structure telling which Internet exchange points are
+// available in each facility of a set. Rows are facilities and columns are
+// IXs, it helps comparing candidate sites.
+type FacilityIXMatrix struct {
+	// Facilities are the rows of the matrix, in the order of the requested
+	// IDs.
+	Facilities []Facility
+	// InternetExchanges are the columns of the matrix, sorted by name. Only
+	// IXs available in at least one facility are listed.
+	InternetExchanges []InternetExchange
+	// Available tells if an IX is available in a facility, the first index
+	// being the row and the second one the column.
+	Available [][]bool
+}
+
+// InternetExchangesAt returns the Internet exchange points available in the
+// facility at the given row of the matrix.
+func (m *FacilityIXMatrix) InternetExchangesAt(row int) []InternetExchange {
+	var ixs []InternetExchange
+	for column, available := range m.Available[row] {
+		if available {
+			ixs = append(ixs, m.InternetExchanges[column])
+		}
+	}
+
+	return ixs
+}
+
+// GetFacilityIXMatrix returns a pointer to a FacilityIXMatrix structure for
+// the facilities matching the given IDs. IDs without a matching facility are
+// ignored. All objects are fetched in bulk.
+func (api *API) GetFacilityIXMatrix(ids ...int) (*FacilityIXMatrix, error) {
+	facilities, err := getChunked(ids, "id", api.GetFacility)
+	if err != nil {
+		return nil, err
+	}
+	ixFacilities, err := getChunked(ids, "fac_id", api.GetInternetExchangeFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	// IXs available in each facility
+	available := make(map[int]map[int]bool, len(facilities))
+	var ixIDs []int
+	seen := make(map[int]bool)
+	for _, ixFacility := range ixFacilities {
+		if available[ixFacility.FacilityID] == nil {
+			available[ixFacility.FacilityID] = make(map[int]bool)
+		}
+		available[ixFacility.FacilityID][ixFacility.InternetExchangeID] = true
+
+		if !seen[ixFacility.InternetExchangeID] {
+			seen[ixFacility.InternetExchangeID] = true
+			ixIDs = append(ixIDs, ixFacility.InternetExchangeID)
+		}
+	}
+
+	ixs, err := getChunked(ixIDs, "id", api.GetInternetExchange)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ixs, func(i, j int) bool {
+		return ixs[i].Name < ixs[j].Name
+	})
+
+	found := make(map[int]Facility, len(facilities))
+	for _, facility := range facilities {
+		found[facility.ID] = facility
+	}
+
+	matrix := &FacilityIXMatrix{InternetExchanges: ixs}
+	for _, id := range ids {
+		facility, ok := found[id]
+		if !ok {
+			continue
+		}
+		// Avoid listing the same facility twice if its ID was given twice
+		delete(found, id)
+
+		row := make([]bool, len(ixs))
+		for column, ix := range ixs {
+			row[column] = available[id][ix.ID]
+		}
+		matrix.Facilities = append(matrix.Facilities, facility)
+		matrix.Available = append(matrix.Available, row)
+	}
+
+	return matrix, nil
+}
+
+// CommonCarrier is a carrier present in two facilities.
+type CommonCarrier struct {
+	Carrier Carrier
+	// FacilityCount is the total number of facilities where the carrier is
+	// present, a larger footprint giving more transport options.
+	FacilityCount int
+}
+
+// GetCommonCarriers returns the carriers present in both facilities matching
+// the given IDs. Carriers are ranked by footprint, the ones present in the
+// most facilities coming first, then by name.
+func (api *API) GetCommonCarriers(facilityIDA, facilityIDB int) ([]CommonCarrier, error) {
+	carrierFacilities, err := getChunked([]int{facilityIDA, facilityIDB}, "fac_id", api.GetCarrierFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	// Facilities of the pair where each carrier is present
+	present := make(map[int]map[int]bool)
+	for _, carrierFacility := range carrierFacilities {
+		if present[carrierFacility.CarrierID] == nil {
+			present[carrierFacility.CarrierID] = make(map[int]bool)
+		}
+		present[carrierFacility.CarrierID][carrierFacility.FacilityID] = true
+	}
+
+	var ids []int
+	for id, facilities := range present {
+		if facilities[facilityIDA] && facilities[facilityIDB] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	carriers, err := getChunked(ids, "id", api.GetCarrier)
+	if err != nil {
+		return nil, err
+	}
+	footprints, err := getChunked(ids, "carrier_id", api.GetCarrierFacility)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(ids))
+	for _, carrierFacility := range footprints {
+		counts[carrierFacility.CarrierID]++
+	}
+
+	common := make([]CommonCarrier, 0, len(carriers))
+	for _, carrier := range carriers {
+		common = append(common, CommonCarrier{Carrier: carrier, FacilityCount: counts[carrier.ID]})
+	}
+	sort.Slice(common, func(i, j int) bool {
+		if common[i].FacilityCount != common[j].FacilityCount {
+			return common[i].FacilityCount > common[j].FacilityCount
+		}
+		return common[i].Carrier.Name < common[j].Carrier.Name
+	})
+
+	return common, nil
+}
@@ -0,0 +1,70 @@
+package peeringdb
+
+import "testing"
+
+func TestGetFacilityIXMatrix(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		facilityNamespace: {
+			{"id": 1, "name": "Facility A"},
+			{"id": 2, "name": "Facility B"},
+		},
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX B"},
+			{"id": 2, "name": "IX A"},
+		},
+		internetExchangeFacilityNamespace: {
+			{"id": 1, "ix_id": 1, "fac_id": 1},
+			{"id": 2, "ix_id": 2, "fac_id": 1},
+			{"id": 3, "ix_id": 2, "fac_id": 2},
+		},
+	})
+
+	matrix, err := server.api().GetFacilityIXMatrix(2, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matrix.Facilities) != 2 || matrix.Facilities[0].ID != 2 || len(matrix.InternetExchanges) != 2 {
+		t.Fatalf("GetFacilityIXMatrix, unexpected matrix: %+v", matrix)
+	}
+	if matrix.InternetExchanges[0].Name != "IX A" {
+		t.Errorf("GetFacilityIXMatrix, IXs not sorted: %+v", matrix.InternetExchanges)
+	}
+
+	expected := [][]bool{{true, false}, {true, true}}
+	for row := range expected {
+		for column := range expected[row] {
+			if matrix.Available[row][column] != expected[row][column] {
+				t.Errorf("GetFacilityIXMatrix, unexpected availability at %d,%d", row, column)
+			}
+		}
+	}
+	if ixs := matrix.InternetExchangesAt(1); len(ixs) != 2 {
+		t.Errorf("InternetExchangesAt, want 2 IXs got %d", len(ixs))
+	}
+}
+
+func TestGetCommonCarriers(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		carrierNamespace: {
+			{"id": 1, "name": "Carrier A"},
+			{"id": 2, "name": "Carrier B"},
+			{"id": 3, "name": "Carrier C"},
+		},
+		carrierFacilityNamespace: {
+			{"id": 1, "carrier_id": 1, "fac_id": 1},
+			{"id": 2, "carrier_id": 1, "fac_id": 2},
+			{"id": 3, "carrier_id": 2, "fac_id": 1},
+			{"id": 4, "carrier_id": 2, "fac_id": 2},
+			{"id": 5, "carrier_id": 2, "fac_id": 3},
+			{"id": 6, "carrier_id": 3, "fac_id": 1},
+		},
+	})
+
+	carriers, err := server.api().GetCommonCarriers(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(carriers) != 2 || carriers[0].Carrier.ID != 2 || carriers[0].FacilityCount != 3 || carriers[1].Carrier.ID != 1 {
+		t.Errorf("GetCommonCarriers, unexpected carriers: %+v", carriers)
+	}
+}
@@ -0,0 +1,124 @@
+// Package sqlbridge loads the objects of a PeeringDB mirror into a SQL
+// database, so that they can be queried with SQL. It works with any
+// database/sql driver; embedded databases such as DuckDB or SQLite are well
+// suited to run analytical queries like:
+//
+//	SELECT net.name FROM netfac
+//	JOIN net ON net.id = netfac.net_id
+//	JOIN netixlan ON netixlan.net_id = net.id
+//	WHERE netfac.fac_id = 1 AND netixlan.ix_id = 26
+//
+// The database can also keep the state of long-running tools with a
+// StateStore.
+//
+// This package does not depend on any driver, it is up to the caller to
+// import the one of its choice and to open the database.
+package sqlbridge
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// Options is a structure used to tune how tables are loaded.
+type Options struct {
+	// TablePrefix is prepended to the namespace to name tables.
+	TablePrefix string
+	// Placeholder returns the placeholder of the n-th parameter of a
+	// statement, starting at 1. Question marks are used if it is nil, use
+	// DollarPlaceholder for PostgreSQL-like databases.
+	Placeholder func(n int) string
+	// DropExisting drops tables before creating them, allowing to reload a
+	// database.
+	DropExisting bool
+}
+
+// QuestionPlaceholder returns "?" whatever the parameter position. It is used
+// by SQLite, DuckDB and MySQL drivers.
+func QuestionPlaceholder(int) string {
+	return "?"
+}
+
+// DollarPlaceholder returns "$n" for the n-th parameter. It is used by
+// PostgreSQL drivers.
+func DollarPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// Load creates one table per namespace completely loaded in the mirror and
+// inserts all objects in it. Everything is done in a single transaction.
+func Load(ctx context.Context, db *sql.DB, mirror *peeringdb.Mirror, options Options) error {
+	tables, err := mirror.Tables()
+	if err != nil {
+		return err
+	}
+
+	return LoadTables(ctx, db, tables, options)
+}
+
+// LoadTables creates the given tables and inserts their rows. Everything is
+// done in a single transaction.
+func LoadTables(ctx context.Context, db *sql.DB, tables []*peeringdb.Table, options Options) error {
+	if options.Placeholder == nil {
+		options.Placeholder = QuestionPlaceholder
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		if err = loadTable(ctx, tx, table, options); err != nil {
+			return fmt.Errorf("loading table %s: %w", table.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// loadTable creates a table and inserts its rows with a prepared statement.
+func loadTable(ctx context.Context, tx *sql.Tx, table *peeringdb.Table, options Options) error {
+	name := quoteIdentifier(options.TablePrefix + table.Name)
+
+	if options.DropExisting {
+		if _, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+name); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, table.CreateTableSQL(options.TablePrefix)); err != nil {
+		return err
+	}
+
+	columns := make([]string, len(table.Columns))
+	placeholders := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = quoteIdentifier(column.Name)
+		placeholders[i] = options.Placeholder(i + 1)
+	}
+
+	statement, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		name, strings.Join(columns, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, row := range table.Rows {
+		if _, err = statement.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quoteIdentifier quotes a table or column name.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
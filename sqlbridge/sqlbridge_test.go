@@ -0,0 +1,112 @@
+package sqlbridge
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// recorder is a database/sql driver recording executed statements.
+type recorder struct {
+	mutex      sync.Mutex
+	statements []string
+	committed  bool
+}
+
+func (r *recorder) Open(string) (driver.Conn, error) { return &recorderConn{r}, nil }
+
+type recorderConn struct{ r *recorder }
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return &recorderStmt{c.r, query}, nil
+}
+func (c *recorderConn) Close() error              { return nil }
+func (c *recorderConn) Begin() (driver.Tx, error) { return &recorderTx{c.r}, nil }
+
+type recorderTx struct{ r *recorder }
+
+func (t *recorderTx) Commit() error {
+	t.r.mutex.Lock()
+	t.r.committed = true
+	t.r.mutex.Unlock()
+	return nil
+}
+func (t *recorderTx) Rollback() error { return nil }
+
+type recorderStmt struct {
+	r     *recorder
+	query string
+}
+
+func (s *recorderStmt) Close() error  { return nil }
+func (s *recorderStmt) NumInput() int { return -1 }
+func (s *recorderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.r.mutex.Lock()
+	defer s.r.mutex.Unlock()
+
+	statement := s.query
+	for _, arg := range args {
+		statement = strings.Replace(statement, "?", driverValue(arg), 1)
+	}
+	s.r.statements = append(s.r.statements, statement)
+
+	return driver.RowsAffected(1), nil
+}
+func (s *recorderStmt) Query([]driver.Value) (driver.Rows, error) { return nil, driver.ErrSkip }
+
+func driverValue(value driver.Value) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + v + "'"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func TestLoadTables(t *testing.T) {
+	r := &recorder{}
+	sql.Register("recorder", r)
+	db, err := sql.Open("recorder", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := &peeringdb.Table{
+		Name:    "net",
+		Columns: []peeringdb.Column{{Name: "id", Type: peeringdb.ColumnInteger}, {Name: "name", Type: peeringdb.ColumnText}},
+		Rows:    [][]interface{}{{int64(1), "Network A"}, {int64(2), nil}},
+	}
+
+	err = LoadTables(context.Background(), db, []*peeringdb.Table{table}, Options{TablePrefix: "pdb_", DropExisting: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.committed {
+		t.Error("LoadTables, want transaction to be committed")
+	}
+
+	expected := []string{
+		`DROP TABLE IF EXISTS "pdb_net"`,
+		"CREATE TABLE \"pdb_net\" (\n  \"id\" BIGINT PRIMARY KEY,\n  \"name\" TEXT\n);\n",
+		`INSERT INTO "pdb_net" ("id", "name") VALUES (1, 'Network A')`,
+		`INSERT INTO "pdb_net" ("id", "name") VALUES (2, NULL)`,
+	}
+	if len(r.statements) != len(expected) {
+		t.Fatalf("LoadTables, want %d statements got %d: %q", len(expected), len(r.statements), r.statements)
+	}
+	for i, statement := range expected {
+		if r.statements[i] != statement {
+			t.Errorf("LoadTables, want '%s' got '%s'", statement, r.statements[i])
+		}
+	}
+}
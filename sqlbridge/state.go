@@ -0,0 +1,113 @@
+package sqlbridge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// stateTable is the name of the table holding states, after the prefix.
+const stateTable = "state"
+
+// StateStore is a peeringdb.StateStore keeping states in a table of a SQL
+// database, such as a SQLite file shared by the tools of a host. It is safe
+// for concurrent use.
+type StateStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+// NewStateStore returns a pointer to a new StateStore keeping states in the
+// "state" table of the given database, prefixed with the table prefix of the
+// options. The table is created if it does not exist.
+func NewStateStore(ctx context.Context, db *sql.DB, options Options) (*StateStore, error) {
+	if options.Placeholder == nil {
+		options.Placeholder = QuestionPlaceholder
+	}
+
+	store := &StateStore{
+		db:          db,
+		table:       quoteIdentifier(options.TablePrefix + stateTable),
+		placeholder: options.Placeholder,
+	}
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s ("key" TEXT PRIMARY KEY, "value" TEXT NOT NULL)`, store.table))
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Get returns the state stored under the given key.
+func (s *StateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT "value" FROM %s WHERE "key" = %s`, s.table, s.placeholder(1)), key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", peeringdb.ErrStateNotFound, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+// Put stores a state under the given key, replacing the previous one. The
+// previous state is deleted and the new one inserted in a single transaction,
+// which works with any database.
+func (s *StateStore) Put(ctx context.Context, key string, value []byte) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE "key" = %s`, s.table, s.placeholder(1)), key); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s ("key", "value") VALUES (%s, %s)`, s.table, s.placeholder(1), s.placeholder(2)), key, string(value))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the state stored under the given key, if any.
+func (s *StateStore) Delete(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE "key" = %s`, s.table, s.placeholder(1)), key)
+	return err
+}
+
+// Keys returns the sorted keys of the states whose key starts with the given
+// prefix.
+func (s *StateStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT "key" FROM %s`, s.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
@@ -0,0 +1,143 @@
+package sqlbridge
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gmazoyer/peeringdb"
+)
+
+// memory is a database/sql driver understanding the statements of a
+// StateStore, keeping states in a map.
+type memory struct {
+	mutex  sync.Mutex
+	states map[string]string
+}
+
+func (m *memory) Open(string) (driver.Conn, error) { return &memoryConn{m}, nil }
+
+type memoryConn struct{ m *memory }
+
+func (c *memoryConn) Prepare(query string) (driver.Stmt, error) {
+	return &memoryStmt{c.m, query}, nil
+}
+func (c *memoryConn) Close() error              { return nil }
+func (c *memoryConn) Begin() (driver.Tx, error) { return memoryTx{}, nil }
+
+type memoryTx struct{}
+
+func (memoryTx) Commit() error   { return nil }
+func (memoryTx) Rollback() error { return nil }
+
+type memoryStmt struct {
+	m     *memory
+	query string
+}
+
+func (s *memoryStmt) Close() error  { return nil }
+func (s *memoryStmt) NumInput() int { return -1 }
+func (s *memoryStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.m.mutex.Lock()
+	defer s.m.mutex.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, `CREATE TABLE IF NOT EXISTS "pdb_state"`):
+	case strings.HasPrefix(s.query, `DELETE FROM "pdb_state" WHERE "key" = ?`):
+		delete(s.m.states, args[0].(string))
+	case strings.HasPrefix(s.query, `INSERT INTO "pdb_state" ("key", "value") VALUES (?, ?)`):
+		s.m.states[args[0].(string)] = args[1].(string)
+	default:
+		return nil, errors.New("unexpected statement: " + s.query)
+	}
+
+	return driver.RowsAffected(1), nil
+}
+func (s *memoryStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.m.mutex.Lock()
+	defer s.m.mutex.Unlock()
+
+	rows := &memoryRows{column: "key"}
+	switch {
+	case strings.HasPrefix(s.query, `SELECT "value" FROM "pdb_state" WHERE "key" = ?`):
+		rows.column = "value"
+		if value, ok := s.m.states[args[0].(string)]; ok {
+			rows.values = append(rows.values, value)
+		}
+	case strings.HasPrefix(s.query, `SELECT "key" FROM "pdb_state"`):
+		for key := range s.m.states {
+			rows.values = append(rows.values, key)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(rows.values)))
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+
+	return rows, nil
+}
+
+type memoryRows struct {
+	column string
+	values []string
+}
+
+func (r *memoryRows) Columns() []string { return []string{r.column} }
+func (r *memoryRows) Close() error      { return nil }
+func (r *memoryRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func TestStateStore(t *testing.T) {
+	sql.Register("memory", &memory{states: make(map[string]string)})
+	db, err := sql.Open("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	store, err := NewStateStore(ctx, db, Options{TablePrefix: "pdb_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.Get(ctx, "watch/net"); !errors.Is(err, peeringdb.ErrStateNotFound) {
+		t.Errorf("Get, want ErrStateNotFound got %v", err)
+	}
+
+	for _, key := range []string{"watch/net", "watch/ix", "report"} {
+		if err = peeringdb.SaveState(ctx, store, key, map[string]int{"id": 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = peeringdb.SaveState(ctx, store, "watch/net", map[string]int{"id": 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	var state map[string]int
+	if err = peeringdb.LoadState(ctx, store, "watch/net", &state); err != nil || state["id"] != 2 {
+		t.Errorf("LoadState, want the last state got %v (%v)", state, err)
+	}
+
+	keys, err := store.Keys(ctx, "watch/")
+	if err != nil || strings.Join(keys, ",") != "watch/ix,watch/net" {
+		t.Errorf("Keys, unexpected keys %q (%v)", keys, err)
+	}
+
+	if err = store.Delete(ctx, "watch/net"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get(ctx, "watch/net"); !errors.Is(err, peeringdb.ErrStateNotFound) {
+		t.Errorf("Get, want ErrStateNotFound got %v", err)
+	}
+}
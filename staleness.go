@@ -0,0 +1,149 @@
+package peeringdb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MirrorStaleness is a structure telling how up to date the objects of a
+// namespace of a mirror are.
+type MirrorStaleness struct {
+	// Namespace is the namespace of the objects.
+	Namespace string
+	// Complete tells if the bootstrap of the namespace is complete.
+	Complete bool
+	// LastSync is the time at which the last successful synchronization
+	// started, it is zero if the namespace was never synchronized.
+	LastSync time.Time
+	// LastAttempt is the time at which the last synchronization started,
+	// whether it succeeded or not.
+	LastAttempt time.Time
+	// LastError is the error of the last synchronization, it is empty if it
+	// succeeded.
+	LastError string
+	// Lag is the time elapsed since the last successful synchronization, or
+	// since the bootstrap if the namespace was never synchronized. It is zero
+	// if the bootstrap is not complete.
+	Lag time.Duration
+}
+
+// Staleness returns how up to date the objects of each namespace of the
+// mirror are, sorted by namespace. It can be used to notice a synchronization
+// which silently stopped working.
+func (m *Mirror) Staleness() []MirrorStaleness {
+	now := m.now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	staleness := make([]MirrorStaleness, 0, len(m.state))
+	for namespace, state := range m.state {
+		s := MirrorStaleness{
+			Namespace:   namespace,
+			Complete:    state.Complete,
+			LastSync:    state.LastSync,
+			LastAttempt: state.LastAttempt,
+			LastError:   state.LastError,
+		}
+		if state.Complete {
+			// Synchronizations fetch objects updated since the bootstrap
+			// started until one succeeds
+			since := state.LastSync
+			if since.IsZero() {
+				since = state.Started
+			}
+			s.Lag = now.Sub(since)
+		}
+		staleness = append(staleness, s)
+	}
+	sort.Slice(staleness, func(i, j int) bool {
+		return staleness[i].Namespace < staleness[j].Namespace
+	})
+
+	return staleness
+}
+
+// stalenessMetric is a gauge exported by WriteStalenessMetrics.
+type stalenessMetric struct {
+	name  string
+	help  string
+	value func(s MirrorStaleness) (float64, bool)
+}
+
+// stalenessMetrics are the gauges exported by WriteStalenessMetrics.
+var stalenessMetrics = []stalenessMetric{
+	{
+		name: "peeringdb_mirror_complete",
+		help: "Whether the bootstrap of the namespace is complete.",
+		value: func(s MirrorStaleness) (float64, bool) {
+			return boolGauge(s.Complete), true
+		},
+	},
+	{
+		name: "peeringdb_mirror_last_sync_timestamp_seconds",
+		help: "Time of the last successful synchronization of the namespace.",
+		value: func(s MirrorStaleness) (float64, bool) {
+			return timestampGauge(s.LastSync), !s.LastSync.IsZero()
+		},
+	},
+	{
+		name: "peeringdb_mirror_last_attempt_timestamp_seconds",
+		help: "Time of the last synchronization attempt of the namespace.",
+		value: func(s MirrorStaleness) (float64, bool) {
+			return timestampGauge(s.LastAttempt), !s.LastAttempt.IsZero()
+		},
+	},
+	{
+		name: "peeringdb_mirror_lag_seconds",
+		help: "Time elapsed since the objects of the namespace were last synchronized.",
+		value: func(s MirrorStaleness) (float64, bool) {
+			return s.Lag.Seconds(), s.Complete
+		},
+	},
+	{
+		name: "peeringdb_mirror_sync_failing",
+		help: "Whether the last synchronization of the namespace failed.",
+		value: func(s MirrorStaleness) (float64, bool) {
+			return boolGauge(s.LastError != ""), true
+		},
+	},
+}
+
+// WriteStalenessMetrics writes the staleness of each namespace of the mirror
+// as gauges in the Prometheus text exposition format, so that they can be
+// served on a metrics endpoint or written for the node exporter textfile
+// collector.
+func (m *Mirror) WriteStalenessMetrics(w io.Writer) error {
+	staleness := m.Staleness()
+
+	buffer := bufio.NewWriter(w)
+	for _, metric := range stalenessMetrics {
+		fmt.Fprintf(buffer, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(buffer, "# TYPE %s gauge\n", metric.name)
+		for _, s := range staleness {
+			if value, ok := metric.value(s); ok {
+				fmt.Fprintf(buffer, "%s{namespace=%q} %s\n", metric.name, s.Namespace, strconv.FormatFloat(value, 'f', -1, 64))
+			}
+		}
+	}
+
+	return buffer.Flush()
+}
+
+// boolGauge returns the value of a gauge for a boolean.
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// timestampGauge returns the value of a gauge for a time, in seconds since
+// the Unix epoch.
+func timestampGauge(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
@@ -0,0 +1,77 @@
+package peeringdb
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMirrorStaleness(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+		},
+	})
+	directory := t.TempDir()
+	options := MirrorOptions{PageSize: 10, Pacing: -1, Namespaces: []string{networkNamespace}}
+
+	mirror, err := NewMirror(server.api(), directory, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mirror.now = func() time.Time { return now }
+
+	if staleness := mirror.Staleness(); len(staleness) != 0 {
+		t.Errorf("Staleness, want nothing before bootstrap got %+v", staleness)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err = mirror.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	synced := now
+
+	// The synchronization stops working, the lag keeps growing
+	server.setQuota(0)
+	now = now.Add(2 * time.Hour)
+	if err = mirror.Sync(); err == nil {
+		t.Fatal("Sync, want error got nil")
+	}
+
+	// The state is kept on disk for other processes to read
+	mirror, err = NewMirror(server.api(), directory, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirror.now = func() time.Time { return now.Add(time.Hour) }
+
+	staleness := mirror.Staleness()
+	if len(staleness) != 1 {
+		t.Fatalf("Staleness, want 1 namespace got %+v", staleness)
+	}
+	s := staleness[0]
+	if s.Namespace != networkNamespace || !s.Complete || !s.LastSync.Equal(synced) || !s.LastAttempt.Equal(now) {
+		t.Errorf("Staleness, unexpected times: %+v", s)
+	}
+	if s.Lag != 3*time.Hour || s.LastError == "" {
+		t.Errorf("Staleness, want 3h lag and an error got %+v", s)
+	}
+
+	var metrics strings.Builder
+	if err = mirror.WriteStalenessMetrics(&metrics); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE peeringdb_mirror_lag_seconds gauge",
+		`peeringdb_mirror_lag_seconds{namespace="net"} 10800`,
+		`peeringdb_mirror_last_sync_timestamp_seconds{namespace="net"} 1704070800`,
+		`peeringdb_mirror_sync_failing{namespace="net"} 1`,
+	} {
+		if !strings.Contains(metrics.String(), line+"\n") {
+			t.Errorf("WriteStalenessMetrics, want %q in:\n%s", line, metrics.String())
+		}
+	}
+}
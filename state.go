@@ -0,0 +1,167 @@
+package peeringdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrStateNotFound is the error that will be returned by a StateStore if no
+// state is stored under the given key.
+var ErrStateNotFound = errors.New("state not found")
+
+// stateFileExtension is the extension of the files of a FileStateStore.
+const stateFileExtension = ".state"
+
+// StateStore is the interface implemented by the stores persisting the state
+// of long-running tools between runs, such as the last time changes were
+// looked for or snapshots of objects to compare with. States are opaque
+// values stored under keys, LoadState and SaveState store them as JSON.
+//
+// NewFileStateStore returns a store keeping states in a directory, the
+// sqlbridge package provides a store keeping them in a SQL database.
+type StateStore interface {
+	// Get returns the state stored under the given key, or an error wrapping
+	// ErrStateNotFound if there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores a state under the given key, replacing the previous one.
+	Put(ctx context.Context, key string, value []byte) error
+	// Delete removes the state stored under the given key, if any.
+	Delete(ctx context.Context, key string) error
+	// Keys returns the sorted keys of the states whose key starts with the
+	// given prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// LoadState decodes the JSON state stored under the given key into v. It
+// returns an error wrapping ErrStateNotFound if there is no such state.
+func LoadState(ctx context.Context, store StateStore, key string, v interface{}) error {
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid state '%s': %w", key, err)
+	}
+
+	return nil
+}
+
+// SaveState stores v encoded as canonical JSON under the given key, so that
+// stored states can be compared and diffed.
+func SaveState(ctx context.Context, store StateStore, key string, v interface{}) error {
+	data, err := MarshalCanonicalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, key, append(data, '\n'))
+}
+
+// FileStateStore is a StateStore keeping each state in a file of a directory.
+// It is safe for concurrent use, a state being replaced atomically.
+type FileStateStore struct {
+	directory string
+}
+
+// NewFileStateStore returns a pointer to a new FileStateStore keeping states
+// in the given directory, which is created if it does not exist.
+func NewFileStateStore(directory string) (*FileStateStore, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileStateStore{directory: directory}, nil
+}
+
+// path returns the path of the file holding the state stored under the given
+// key. Keys are escaped so that they cannot point outside of the directory.
+func (s *FileStateStore) path(key string) string {
+	return filepath.Join(s.directory, url.QueryEscape(key)+stateFileExtension)
+}
+
+// Get returns the state stored under the given key.
+func (s *FileStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrStateNotFound, key)
+	}
+
+	return data, err
+}
+
+// Put stores a state under the given key, replacing the previous one.
+func (s *FileStateStore) Put(ctx context.Context, key string, value []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.directory, ".state-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(value); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(key))
+}
+
+// Delete removes the state stored under the given key, if any.
+func (s *FileStateStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Keys returns the sorted keys of the states whose key starts with the given
+// prefix.
+func (s *FileStateStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), stateFileExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		key, err := url.QueryUnescape(name)
+		if err != nil || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
@@ -0,0 +1,54 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileStateStore(t *testing.T) {
+	ctx := context.Background()
+	directory := filepath.Join(t.TempDir(), "state")
+	store, err := NewFileStateStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seen time.Time
+	if err = LoadState(ctx, store, "watch/net", &seen); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("LoadState, want ErrStateNotFound got %v", err)
+	}
+
+	// Keys are escaped and cannot escape the directory
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, key := range []string{"watch/net", "watch/ix", "../report"} {
+		if err = SaveState(ctx, store, key, last); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries, _ := os.ReadDir(directory); len(entries) != 3 {
+		t.Errorf("SaveState, want 3 files got %d", len(entries))
+	}
+	if err = LoadState(ctx, store, "watch/net", &seen); err != nil || !seen.Equal(last) {
+		t.Errorf("LoadState, want %s got %s (%v)", last, seen, err)
+	}
+
+	keys, err := store.Keys(ctx, "watch/")
+	if err != nil || strings.Join(keys, ",") != "watch/ix,watch/net" {
+		t.Errorf("Keys, unexpected keys %q (%v)", keys, err)
+	}
+
+	if err = store.Delete(ctx, "watch/net"); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Delete(ctx, "watch/net"); err != nil {
+		t.Errorf("Delete, unexpected error for a missing state: %v", err)
+	}
+	if _, err = store.Get(ctx, "watch/net"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("Get, want ErrStateNotFound got %v", err)
+	}
+}
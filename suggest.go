@@ -0,0 +1,75 @@
+package peeringdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// suggestRequiredFields are the fields which must be set when suggesting an
+// object of a namespace. The organization is not required, suggested objects
+// being owned by the PeeringDB suggestions organization until they are
+// reviewed.
+var suggestRequiredFields = map[string][]string{
+	facilityNamespace:         {"name", "city", "country"},
+	internetExchangeNamespace: {"name", "city", "country"},
+}
+
+// suggest creates an object of type T with the suggest flag set, so that it is
+// reviewed by the PeeringDB staff before being published. The object created
+// is returned as given by the API.
+func suggest[T Object](ctx context.Context, api *API, object *T) (*T, error) {
+	namespace := namespaceOf[T]()
+	if object == nil {
+		return nil, fmt.Errorf("cannot suggest %s object: nil object", namespace)
+	}
+
+	fields, err := writableFields(object)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s object: %w", namespace, err)
+	}
+	if isZeroValue(fields["org_id"]) {
+		delete(fields, "org_id")
+	}
+	fields["suggest"] = true
+
+	created, err := api.write(ctx, http.MethodPost, namespace, 0, objectFields(fields), suggestRequiredFields[namespace])
+	if err != nil {
+		return nil, err
+	}
+
+	return writtenObject[T](namespace, created)
+}
+
+// SuggestFacility submits the given Facility as a suggestion, so that
+// data center teams can file new sites without owning their organization in
+// PeeringDB. The suggested facility is reviewed by the PeeringDB staff before
+// being published. Its organization does not need to be set. The facility
+// created is returned as given by the API, with its ID and its status.
+// Suggesting requires an API key. If an error occurs, the returned error will
+// be non-nil.
+func (api *API) SuggestFacility(facility *Facility) (*Facility, error) {
+	return api.SuggestFacilityWithContext(context.Background(), facility)
+}
+
+// SuggestFacilityWithContext is the same as SuggestFacility but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) SuggestFacilityWithContext(ctx context.Context, facility *Facility) (*Facility, error) {
+	return suggest(ctx, api, facility)
+}
+
+// SuggestInternetExchange submits the given InternetExchange as a suggestion,
+// reviewed by the PeeringDB staff before being published. Its organization
+// does not need to be set. The IX created is returned as given by the API,
+// with its ID and its status. Suggesting requires an API key. If an error
+// occurs, the returned error will be non-nil.
+func (api *API) SuggestInternetExchange(ix *InternetExchange) (*InternetExchange, error) {
+	return api.SuggestInternetExchangeWithContext(context.Background(), ix)
+}
+
+// SuggestInternetExchangeWithContext is the same as SuggestInternetExchange
+// but uses the given context for the API call, allowing to cancel it or to set
+// a deadline.
+func (api *API) SuggestInternetExchangeWithContext(ctx context.Context, ix *InternetExchange) (*InternetExchange, error) {
+	return suggest(ctx, api, ix)
+}
@@ -0,0 +1,29 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSuggestFacility(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		facilityNamespace: {{"id": 1, "org_id": 1, "name": "Facility A"}},
+	})
+	api := server.api()
+
+	facility, err := api.SuggestFacility(&Facility{Name: "Facility B", City: "Paris", Country: "FR"})
+	if err != nil || facility == nil || facility.ID != 2 || facility.Name != "Facility B" {
+		t.Fatalf("SuggestFacility, unexpected result %v, %v", facility, err)
+	}
+	suggested := server.objects[facilityNamespace][1]
+	if suggested["suggest"] != true || suggested["name"] != "Facility B" {
+		t.Errorf("SuggestFacility, unexpected object sent %v", suggested)
+	}
+	if _, ok := suggested["org_id"]; ok {
+		t.Errorf("SuggestFacility, unexpected organization sent %v", suggested["org_id"])
+	}
+
+	if _, err = api.SuggestFacility(&Facility{Name: "Facility C", Country: "FR"}); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("SuggestFacility, expected ErrInvalidObject, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package peeringdb
+
+import (
+	"context"
+	"time"
+)
+
+// defaultSyncInterval is the interval between synchronizations used when the
+// given one is not positive.
+const defaultSyncInterval = 15 * time.Minute
+
+// MirrorSyncer is a Service synchronizing a mirror periodically, the way a
+// daemon keeping a local copy of PeeringDB up to date would. Synchronizations
+// failing do not stop it, their errors are told by the Staleness of the
+// mirror.
+type MirrorSyncer struct {
+	lifecycle
+
+	mirror   *Mirror
+	interval time.Duration
+}
+
+// NewMirrorSyncer returns a pointer to a new MirrorSyncer synchronizing the
+// given mirror every interval once it runs. An interval which is not positive
+// is replaced by 15 minutes.
+func NewMirrorSyncer(mirror *Mirror, interval time.Duration) *MirrorSyncer {
+	if interval <= 0 {
+		interval = defaultSyncInterval
+	}
+
+	return &MirrorSyncer{mirror: mirror, interval: interval}
+}
+
+// Run synchronizes the mirror right away, then every interval, until the
+// given context is done or Close is called. A synchronization in progress is
+// finished before Run returns, so that the mirror is left consistent.
+func (s *MirrorSyncer) Run(ctx context.Context) error {
+	ctx, finish, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		s.mirror.Sync()
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// Close stops the synchronizations, waiting for the one in progress, if any,
+// to finish.
+func (s *MirrorSyncer) Close() error {
+	s.close()
+	return nil
+}
@@ -0,0 +1,95 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMirrorSyncer(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+		},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	bootstrapped := server.count(networkNamespace)
+
+	// The mirror is synchronized until the context is done
+	var service Service = NewMirrorSyncer(mirror, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- service.Run(ctx) }()
+	for server.count(networkNamespace) < bootstrapped+2 {
+		time.Sleep(time.Millisecond)
+	}
+	if err = service.Run(ctx); !errors.Is(err, ErrServiceRunning) {
+		t.Errorf("Run, want ErrServiceRunning got %v", err)
+	}
+	cancel()
+	if err = <-stopped; err != nil {
+		t.Errorf("Run, unexpected error: %v", err)
+	}
+
+	// It can run again until it is closed
+	synced := server.count(networkNamespace)
+	go func() { stopped <- service.Run(context.Background()) }()
+	for server.count(networkNamespace) < synced+2 {
+		time.Sleep(time.Millisecond)
+	}
+	if err = service.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-stopped; err != nil {
+		t.Errorf("Run, unexpected error: %v", err)
+	}
+	if err = service.Run(context.Background()); !errors.Is(err, ErrServiceClosed) {
+		t.Errorf("Run, want ErrServiceClosed got %v", err)
+	}
+	if err = service.Close(); err != nil {
+		t.Errorf("Close, unexpected error closing twice: %v", err)
+	}
+}
+
+func TestMirrorSyncerInterval(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500},
+		},
+	})
+	mirror, err := NewMirror(server.api(), t.TempDir(), MirrorOptions{Pacing: -1, Namespaces: []string{networkNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mirror.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	bootstrapped := server.count(networkNamespace)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		syncer := NewMirrorSyncer(mirror, interval)
+		if syncer.interval != defaultSyncInterval {
+			t.Errorf("NewMirrorSyncer(%s), want the default interval got %s", interval, syncer.interval)
+		}
+	}
+
+	// The mirror is synchronized right away, without waiting for the
+	// default interval
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- NewMirrorSyncer(mirror, 0).Run(ctx) }()
+	for server.count(networkNamespace) == bootstrapped {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err = <-stopped; err != nil {
+		t.Errorf("Run, unexpected error: %v", err)
+	}
+}
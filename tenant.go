@@ -0,0 +1,117 @@
+package peeringdb
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FacilityTenant is a summary of a network present in a facility.
+type FacilityTenant struct {
+	NetworkID     int
+	ASN           ASN
+	LocalASN      ASN
+	Name          string
+	PolicyGeneral string
+	InfoTraffic   string
+	InfoRatio     string
+	InfoType      string
+}
+
+// FacilityTenants is a structure listing the networks and the Internet
+// exchange points that can be found in a facility.
+type FacilityTenants struct {
+	Facility          Facility
+	Networks          []FacilityTenant
+	InternetExchanges []InternetExchange
+}
+
+// GetFacilityTenants returns a pointer to a FacilityTenants structure for the
+// facility matching the given ID. Networks and Internet exchange points are
+// fetched in bulk, so the number of API calls does not grow linearly with
+// the number of tenants. Networks are sorted by AS number and Internet
+// exchange points by name.
+func (api *API) GetFacilityTenants(facilityID int) (*FacilityTenants, error) {
+	facility, err := api.GetFacilityByID(facilityID)
+	if err != nil {
+		return nil, err
+	}
+	if facility == nil {
+		return nil, fmt.Errorf("no facility found for ID %d", facilityID)
+	}
+
+	search := make(map[string]interface{})
+	search["fac_id"] = facilityID
+
+	// Find which networks are present in the facility
+	networkFacilities, err := api.GetNetworkFacility(search)
+	if err != nil {
+		return nil, err
+	}
+	// Networks embedded in the objects, with a depth of 2, do not need to be
+	// looked up again
+	var networks []Network
+	networkIDs := make([]int, 0, len(*networkFacilities))
+	localASNs := make(map[int]ASN, len(*networkFacilities))
+	for _, networkFacility := range *networkFacilities {
+		localASNs[networkFacility.NetworkID] = networkFacility.LocalASN
+		if api.embedsObjects() && networkFacility.Network.ID == networkFacility.NetworkID {
+			networks = append(networks, networkFacility.Network)
+			continue
+		}
+		networkIDs = append(networkIDs, networkFacility.NetworkID)
+	}
+
+	// Find which IXs are present in the facility
+	ixFacilities, err := api.GetInternetExchangeFacility(search)
+	if err != nil {
+		return nil, err
+	}
+	var ixs []InternetExchange
+	ixIDs := make([]int, 0, len(*ixFacilities))
+	for _, ixFacility := range *ixFacilities {
+		if api.embedsObjects() && ixFacility.InternetExchange.ID == ixFacility.InternetExchangeID {
+			ixs = append(ixs, ixFacility.InternetExchange)
+			continue
+		}
+		ixIDs = append(ixIDs, ixFacility.InternetExchangeID)
+	}
+
+	// Get missing details in bulk
+	fetchedNetworks, err := getChunked(networkIDs, "id", api.GetNetwork)
+	if err != nil {
+		return nil, err
+	}
+	networks = append(networks, fetchedNetworks...)
+	fetchedIXs, err := getChunked(ixIDs, "id", api.GetInternetExchange)
+	if err != nil {
+		return nil, err
+	}
+	ixs = append(ixs, fetchedIXs...)
+
+	tenants := &FacilityTenants{
+		Facility:          *facility,
+		Networks:          make([]FacilityTenant, 0, len(networks)),
+		InternetExchanges: ixs,
+	}
+	for _, network := range networks {
+		tenants.Networks = append(tenants.Networks, FacilityTenant{
+			NetworkID:     network.ID,
+			ASN:           network.ASN,
+			LocalASN:      localASNs[network.ID],
+			Name:          network.Name,
+			PolicyGeneral: network.PolicyGeneral,
+			InfoTraffic:   network.InfoTraffic,
+			InfoRatio:     network.InfoRatio,
+			InfoType:      network.InfoType,
+		})
+	}
+
+	sort.Slice(tenants.Networks, func(i, j int) bool {
+		return tenants.Networks[i].ASN < tenants.Networks[j].ASN
+	})
+	sort.Slice(tenants.InternetExchanges, func(i, j int) bool {
+		return tenants.InternetExchanges[i].Name < tenants.InternetExchanges[j].Name
+	})
+
+	return tenants, nil
+}
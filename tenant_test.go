@@ -0,0 +1,81 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestGetFacilityTenants(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		facilityNamespace: {
+			{"id": 1, "name": "Facility A"},
+			{"id": 2, "name": "Facility B"},
+		},
+		networkFacilityNamespace: {
+			// Network embedded with a depth of 2
+			{"id": 1, "net_id": 10, "fac_id": 1, "local_asn": 64510, "net": map[string]interface{}{"id": 10, "asn": 64510, "name": "Network A"}},
+			// Networks to look up
+			{"id": 2, "net_id": 11, "fac_id": 1, "local_asn": 64600},
+			{"id": 3, "net_id": 12, "fac_id": 1, "local_asn": 64500},
+		},
+		networkNamespace: {
+			{"id": 11, "asn": 64511, "name": "Network B", "info_type": "Content"},
+			{"id": 12, "asn": 64500, "name": "Network C"},
+		},
+		internetExchangeFacilityNamespace: {
+			{"id": 1, "ix_id": 1, "fac_id": 1},
+			{"id": 2, "ix_id": 2, "fac_id": 1},
+		},
+		internetExchangeNamespace: {
+			{"id": 1, "name": "IX-B"},
+			{"id": 2, "name": "IX-A"},
+		},
+	})
+	api := server.api(WithDepth(2))
+
+	tenants, err := api.GetFacilityTenants(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenants.Facility.Name != "Facility A" {
+		t.Errorf("GetFacilityTenants, unexpected facility %+v", tenants.Facility)
+	}
+
+	// Networks are sorted by AS number, the embedded one is not looked up
+	// again and the others are looked up with a single call
+	if len(tenants.Networks) != 3 {
+		t.Fatalf("GetFacilityTenants, want 3 networks got %+v", tenants.Networks)
+	}
+	for i, want := range []FacilityTenant{
+		{NetworkID: 12, ASN: 64500, LocalASN: 64500, Name: "Network C"},
+		{NetworkID: 10, ASN: 64510, LocalASN: 64510, Name: "Network A"},
+		{NetworkID: 11, ASN: 64511, LocalASN: 64600, Name: "Network B", InfoType: "Content"},
+	} {
+		if tenants.Networks[i] != want {
+			t.Errorf("GetFacilityTenants, want network %+v got %+v", want, tenants.Networks[i])
+		}
+	}
+	if count := server.count(networkNamespace); count != 1 {
+		t.Errorf("GetFacilityTenants, want 1 network lookup got %d", count)
+	}
+
+	// IXs are sorted by name
+	if len(tenants.InternetExchanges) != 2 || tenants.InternetExchanges[0].Name != "IX-A" || tenants.InternetExchanges[1].Name != "IX-B" {
+		t.Errorf("GetFacilityTenants, unexpected IXs %+v", tenants.InternetExchanges)
+	}
+
+	// A facility without tenants
+	tenants, err = api.GetFacilityTenants(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants.Networks) != 0 || len(tenants.InternetExchanges) != 0 {
+		t.Errorf("GetFacilityTenants, want no tenants got %+v", tenants)
+	}
+	if count := server.count(networkNamespace); count != 1 {
+		t.Errorf("GetFacilityTenants, want no network lookup got %d", count-1)
+	}
+
+	if _, err = api.GetFacilityTenants(3); err == nil {
+		t.Error("GetFacilityTenants, want error for unknown facility got nil")
+	}
+}
@@ -0,0 +1,128 @@
+package peeringdb
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// DefaultTimeLayout is the layout used to format times if no other layout is
+// configured.
+const DefaultTimeLayout = "2006-01-02 15:04 MST"
+
+// TimeFormatter renders times, such as the Created and Updated fields of
+// objects, in a given timezone or relatively to the current time. The zero
+// value uses the local timezone and DefaultTimeLayout.
+type TimeFormatter struct {
+	// Location is the timezone used to render times, time.Local if nil.
+	Location *time.Location
+	// Layout is the layout used to render times, DefaultTimeLayout if empty.
+	Layout string
+
+	// now returns the current time, it is time.Now if nil.
+	now func() time.Time
+}
+
+// Format returns the time rendered in the timezone of the formatter. The zero
+// time is rendered as an empty string.
+func (f TimeFormatter) Format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	location := f.Location
+	if location == nil {
+		location = time.Local
+	}
+	layout := f.Layout
+	if layout == "" {
+		layout = DefaultTimeLayout
+	}
+
+	return t.In(location).Format(layout)
+}
+
+// Relative returns the time relatively to the current time, "3 months ago"
+// or "in 2 days" for instance. The zero time is rendered as an empty string.
+func (f TimeFormatter) Relative(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	now := time.Now
+	if f.now != nil {
+		now = f.now
+	}
+
+	return RelativeTime(t, now())
+}
+
+// FuncMap returns template functions rendering times with the formatter.
+// "localtime" renders a time with Format and "ago" with Relative, both accept
+// time.Time values and strings holding RFC 3339 times, dates or UNIX
+// timestamps.
+func (f TimeFormatter) FuncMap() template.FuncMap {
+	toTime := func(value interface{}) time.Time {
+		switch v := value.(type) {
+		case time.Time:
+			return v
+		case *time.Time:
+			if v != nil {
+				return *v
+			}
+		default:
+			if t, ok := parseTime(fmt.Sprintf("%v", v)); ok {
+				return t
+			}
+		}
+		return time.Time{}
+	}
+
+	return template.FuncMap{
+		"localtime": func(value interface{}) string { return f.Format(toTime(value)) },
+		"ago":       func(value interface{}) string { return f.Relative(toTime(value)) },
+	}
+}
+
+// relativeUnits are the units used to render relative times, from the
+// largest to the smallest.
+var relativeUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+}
+
+// RelativeTime returns the time t relatively to the time now, using the
+// largest unit fitting the difference, "updated 3 months ago" being easier
+// to read than a date in reports. Differences under a minute are rendered as
+// "just now".
+func RelativeTime(t, now time.Time) string {
+	difference := now.Sub(t)
+	future := difference < 0
+	if future {
+		difference = -difference
+	}
+
+	for _, unit := range relativeUnits {
+		count := int(difference / unit.duration)
+		if count < 1 {
+			continue
+		}
+
+		name := unit.name
+		if count > 1 {
+			name += "s"
+		}
+		if future {
+			return fmt.Sprintf("in %d %s", count, name)
+		}
+		return fmt.Sprintf("%d %s ago", count, name)
+	}
+
+	return "just now"
+}
@@ -0,0 +1,55 @@
+package peeringdb
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{now.Add(-5 * time.Hour), "5 hours ago"},
+		{now.AddDate(0, -3, 0), "3 months ago"},
+		{now.AddDate(-2, 0, -1), "2 years ago"},
+		{now.Add(48 * time.Hour), "in 2 days"},
+	}
+
+	for _, test := range tests {
+		if got := RelativeTime(test.t, now); got != test.expected {
+			t.Errorf("RelativeTime(%s), want '%s' got '%s'", test.t, test.expected, got)
+		}
+	}
+}
+
+func TestTimeFormatter(t *testing.T) {
+	location := time.FixedZone("CEST", 2*60*60)
+	formatter := TimeFormatter{
+		Location: location,
+		now:      func() time.Time { return time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC) },
+	}
+
+	updated := time.Date(2020, 5, 31, 10, 0, 0, 0, time.UTC)
+	if got := formatter.Format(updated); got != "2020-05-31 12:00 CEST" {
+		t.Errorf("Format, want '2020-05-31 12:00 CEST' got '%s'", got)
+	}
+	if got := formatter.Format(time.Time{}); got != "" {
+		t.Errorf("Format, want empty string got '%s'", got)
+	}
+
+	tmpl := template.Must(template.New("test").Funcs(formatter.FuncMap()).Parse(`{{localtime .}} ({{ago .}})`))
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, "2020-05-31T10:00:00Z"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "2020-05-31 12:00 CEST (1 day ago)"; buffer.String() != expected {
+		t.Errorf("FuncMap, want '%s' got '%s'", expected, buffer.String())
+	}
+}
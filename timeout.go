@@ -0,0 +1,33 @@
+package peeringdb
+
+import (
+	"context"
+	"time"
+)
+
+// callTimeoutKey is the key used to store the timeout of API calls in a
+// context.
+type callTimeoutKey struct{}
+
+// ContextWithCallTimeout returns a copy of the context carrying the given
+// timeout. API calls made with this context, using GetNetworkWithContext for
+// instance, are bounded by this timeout instead of the one given with
+// WithTimeout, so that interactive lookups and bulk jobs can share a single
+// API structure with very different limits. A value of 0 means no timeout.
+//
+// The timeout applies to each call: a lookup fetching several pages, or
+// retried, may take longer. A deadline set on the context itself, with
+// context.WithTimeout, bounds the whole lookup instead.
+func ContextWithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// timeoutFor returns the timeout of a call made with the given context, taken
+// from the context if it carries one, else from the API structure.
+func (api *API) timeoutFor(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+
+	return api.timeout
+}
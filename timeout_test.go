@@ -0,0 +1,35 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextWithCallTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+	api := NewAPIFromURL(server.URL+"/api/", WithTimeout(time.Hour))
+
+	// Interactive lookups can be bounded more strictly than the others
+	ctx := ContextWithCallTimeout(context.Background(), 10*time.Millisecond)
+	if _, err := api.GetNetworkByIDWithContext(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetNetworkByIDWithContext, want deadline exceeded error got %v", err)
+	}
+
+	// And bulk ones not bounded at all
+	api = NewAPIFromURL(server.URL+"/api/", WithTimeout(10*time.Millisecond))
+	ctx = ContextWithCallTimeout(context.Background(), 0)
+	if network, err := api.GetNetworkByIDWithContext(ctx, 1); err != nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByIDWithContext, unexpected result %+v: %v", network, err)
+	}
+}
@@ -0,0 +1,216 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidTraffic is the error that will be returned if a traffic level
+	// cannot be parsed.
+	ErrInvalidTraffic = errors.New("invalid traffic level")
+	// ErrInvalidTrafficRatio is the error that will be returned if a traffic
+	// ratio cannot be parsed.
+	ErrInvalidTrafficRatio = errors.New("invalid traffic ratio")
+)
+
+// trafficPattern matches the traffic levels used by PeeringDB, such as
+// "100-200Gbps" or "100+Tbps".
+var trafficPattern = regexp.MustCompile(`^(\d+)(?:-(\d+)|(\+))\s*([MGT])bps$`)
+
+// trafficUnits gives the number of bits per second of each unit prefix.
+var trafficUnits = map[string]uint64{
+	"M": 1e6,
+	"G": 1e9,
+	"T": 1e12,
+}
+
+// TrafficRange is a range of traffic, in bits per second, as declared by a
+// network. Ranges can be added to estimate the traffic of several networks.
+type TrafficRange struct {
+	// Min is the lower bound of the range.
+	Min uint64
+	// Max is the upper bound of the range, it is 0 if the range is
+	// unbounded.
+	Max uint64
+	// Unbounded tells if the range has no upper bound, such as "100+Tbps".
+	Unbounded bool
+}
+
+// ParseTraffic parses a traffic level as found in the InfoTraffic field of a
+// network. An empty level, meaning that the traffic is not disclosed, gives a
+// zero range.
+func ParseTraffic(value string) (TrafficRange, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return TrafficRange{}, nil
+	}
+
+	matches := trafficPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return TrafficRange{}, fmt.Errorf("%w: %q", ErrInvalidTraffic, value)
+	}
+
+	unit := trafficUnits[matches[4]]
+	min, _ := strconv.ParseUint(matches[1], 10, 64)
+	if matches[3] == "+" {
+		return TrafficRange{Min: min * unit, Unbounded: true}, nil
+	}
+
+	max, _ := strconv.ParseUint(matches[2], 10, 64)
+	if max < min {
+		return TrafficRange{}, fmt.Errorf("%w: %q", ErrInvalidTraffic, value)
+	}
+
+	return TrafficRange{Min: min * unit, Max: max * unit}, nil
+}
+
+// IsZero tells if the range is empty, which is the case when the traffic is
+// not disclosed.
+func (r TrafficRange) IsZero() bool {
+	return r.Min == 0 && r.Max == 0 && !r.Unbounded
+}
+
+// Add returns the range of the sum of the traffic of both ranges.
+func (r TrafficRange) Add(other TrafficRange) TrafficRange {
+	sum := TrafficRange{
+		Min:       r.Min + other.Min,
+		Unbounded: r.Unbounded || other.Unbounded,
+	}
+	if !sum.Unbounded {
+		sum.Max = r.Max + other.Max
+	}
+
+	return sum
+}
+
+// String returns the range as a human readable text, such as "1Gbps-5Gbps".
+func (r TrafficRange) String() string {
+	if r.Unbounded {
+		return formatBitrate(r.Min) + "+"
+	}
+
+	return fmt.Sprintf("%s-%s", formatBitrate(r.Min), formatBitrate(r.Max))
+}
+
+// formatBitrate returns a number of bits per second using the largest unit
+// giving an integer value.
+func formatBitrate(bps uint64) string {
+	for _, unit := range []string{"T", "G", "M"} {
+		if bps >= trafficUnits[unit] && bps%trafficUnits[unit] == 0 {
+			return fmt.Sprintf("%d%sbps", bps/trafficUnits[unit], unit)
+		}
+	}
+
+	return fmt.Sprintf("%dbps", bps)
+}
+
+// TrafficDirection is the main direction of the traffic of a network.
+type TrafficDirection int
+
+const (
+	// DirectionUnknown is used when the ratio is not disclosed.
+	DirectionUnknown TrafficDirection = iota
+	// DirectionOutbound is used for networks sending more than they
+	// receive.
+	DirectionOutbound
+	// DirectionBalanced is used for networks sending as much as they
+	// receive.
+	DirectionBalanced
+	// DirectionInbound is used for networks receiving more than they send.
+	DirectionInbound
+)
+
+// TrafficRatio is the ratio of the traffic of a network, as found in the
+// InfoRatio field of a network.
+type TrafficRatio int
+
+// Traffic ratios used by PeeringDB, from the most outbound to the most
+// inbound.
+const (
+	RatioNotDisclosed TrafficRatio = iota
+	RatioHeavyOutbound
+	RatioMostlyOutbound
+	RatioBalanced
+	RatioMostlyInbound
+	RatioHeavyInbound
+)
+
+// trafficRatios gives the names used by PeeringDB for each ratio.
+var trafficRatios = map[TrafficRatio]string{
+	RatioNotDisclosed:   "Not Disclosed",
+	RatioHeavyOutbound:  "Heavy Outbound",
+	RatioMostlyOutbound: "Mostly Outbound",
+	RatioBalanced:       "Balanced",
+	RatioMostlyInbound:  "Mostly Inbound",
+	RatioHeavyInbound:   "Heavy Inbound",
+}
+
+// ParseTrafficRatio parses a traffic ratio as found in the InfoRatio field of
+// a network. An empty ratio is not disclosed.
+func ParseTrafficRatio(value string) (TrafficRatio, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return RatioNotDisclosed, nil
+	}
+
+	for ratio, name := range trafficRatios {
+		if strings.EqualFold(value, name) {
+			return ratio, nil
+		}
+	}
+
+	return RatioNotDisclosed, fmt.Errorf("%w: %q", ErrInvalidTrafficRatio, value)
+}
+
+// String returns the name used by PeeringDB for the ratio.
+func (r TrafficRatio) String() string {
+	return trafficRatios[r]
+}
+
+// Direction returns the main direction of the traffic.
+func (r TrafficRatio) Direction() TrafficDirection {
+	switch r {
+	case RatioHeavyOutbound, RatioMostlyOutbound:
+		return DirectionOutbound
+	case RatioBalanced:
+		return DirectionBalanced
+	case RatioMostlyInbound, RatioHeavyInbound:
+		return DirectionInbound
+	default:
+		return DirectionUnknown
+	}
+}
+
+// Traffic returns the parsed traffic level of the network.
+func (n Network) Traffic() (TrafficRange, error) {
+	return ParseTraffic(n.InfoTraffic)
+}
+
+// TrafficRatio returns the parsed traffic ratio of the network.
+func (n Network) TrafficRatio() (TrafficRatio, error) {
+	return ParseTrafficRatio(n.InfoRatio)
+}
+
+// SumTraffic returns the range of the sum of the traffic of the given
+// networks. Networks not disclosing their traffic, or declaring an invalid
+// one, are ignored and returned apart.
+func SumTraffic(networks []Network) (TrafficRange, []Network) {
+	var (
+		sum     TrafficRange
+		ignored []Network
+	)
+	for _, network := range networks {
+		traffic, err := network.Traffic()
+		if err != nil || traffic.IsZero() {
+			ignored = append(ignored, network)
+			continue
+		}
+		sum = sum.Add(traffic)
+	}
+
+	return sum, ignored
+}
@@ -0,0 +1,76 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTraffic(t *testing.T) {
+	tests := []struct {
+		value string
+		want  TrafficRange
+		err   error
+	}{
+		{"", TrafficRange{}, nil},
+		{"0-20Mbps", TrafficRange{Min: 0, Max: 20e6}, nil},
+		{"100-1000Mbps", TrafficRange{Min: 100e6, Max: 1000e6}, nil},
+		{"100-200Gbps", TrafficRange{Min: 100e9, Max: 200e9}, nil},
+		{"100+Tbps", TrafficRange{Min: 100e12, Unbounded: true}, nil},
+		{"lots", TrafficRange{}, ErrInvalidTraffic},
+		{"20-10Gbps", TrafficRange{}, ErrInvalidTraffic},
+	}
+	for _, test := range tests {
+		got, err := ParseTraffic(test.value)
+		if got != test.want || !errors.Is(err, test.err) {
+			t.Errorf("ParseTraffic(%q), want %+v (%v) got %+v (%v)", test.value, test.want, test.err, got, err)
+		}
+	}
+
+	if got := (TrafficRange{Min: 1e9, Max: 5e9}).String(); got != "1Gbps-5Gbps" {
+		t.Errorf("String, want 1Gbps-5Gbps got %s", got)
+	}
+}
+
+func TestSumTraffic(t *testing.T) {
+	networks := []Network{
+		{ASN: 64500, InfoTraffic: "1-5Gbps"},
+		{ASN: 64501, InfoTraffic: "100-1000Mbps"},
+		{ASN: 64502, InfoTraffic: ""},
+		{ASN: 64503, InfoTraffic: "invalid"},
+	}
+
+	sum, ignored := SumTraffic(networks)
+	if sum != (TrafficRange{Min: 1.1e9, Max: 6e9}) {
+		t.Errorf("SumTraffic, unexpected sum %+v", sum)
+	}
+	if len(ignored) != 2 || ignored[0].ASN != 64502 || ignored[1].ASN != 64503 {
+		t.Errorf("SumTraffic, unexpected ignored networks %+v", ignored)
+	}
+
+	networks = append(networks, Network{ASN: 64504, InfoTraffic: "100+Tbps"})
+	if sum, _ = SumTraffic(networks); !sum.Unbounded || sum.Max != 0 || sum.String() != "100001100Mbps+" {
+		t.Errorf("SumTraffic, unexpected unbounded sum %+v (%s)", sum, sum)
+	}
+}
+
+func TestParseTrafficRatio(t *testing.T) {
+	tests := []struct {
+		value     string
+		want      TrafficRatio
+		direction TrafficDirection
+		err       error
+	}{
+		{"", RatioNotDisclosed, DirectionUnknown, nil},
+		{"Not Disclosed", RatioNotDisclosed, DirectionUnknown, nil},
+		{"Heavy Outbound", RatioHeavyOutbound, DirectionOutbound, nil},
+		{"mostly inbound", RatioMostlyInbound, DirectionInbound, nil},
+		{"Balanced", RatioBalanced, DirectionBalanced, nil},
+		{"Sideways", RatioNotDisclosed, DirectionUnknown, ErrInvalidTrafficRatio},
+	}
+	for _, test := range tests {
+		got, err := (Network{InfoRatio: test.value}).TrafficRatio()
+		if got != test.want || got.Direction() != test.direction || !errors.Is(err, test.err) {
+			t.Errorf("TrafficRatio(%q), want %s (%v) got %s (%v)", test.value, test.want, test.err, got, err)
+		}
+	}
+}
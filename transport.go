@@ -0,0 +1,154 @@
+package peeringdb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// transport returns the HTTP transport of the API structure, to tune it. The
+// client and its transport are copied first, so that the ones given with
+// WithHTTPClient, which may be shared, are never changed. It returns nil if
+// the client uses a transport which is not an *http.Transport.
+func (api *API) transport() *http.Transport {
+	var transport *http.Transport
+	switch t := api.client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil
+	}
+
+	client := *api.client
+	client.Transport = transport
+	api.client = &client
+
+	return transport
+}
+
+// WithProxy returns an option sending API calls through the given HTTP or
+// HTTPS proxy. Without this option, the proxy given by the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables is used, if any. Like the
+// other options tuning the transport, it applies to the HTTP client given with
+// WithHTTPClient if that option comes first, and is ignored if the client uses
+// a transport which is not an *http.Transport.
+func WithProxy(proxy *url.URL) Option {
+	return func(api *API) {
+		if transport := api.transport(); transport != nil {
+			transport.Proxy = http.ProxyURL(proxy)
+		}
+	}
+}
+
+// WithTLSConfig returns an option setting the TLS configuration used to
+// connect to the API, to trust a private certificate authority or to present
+// a client certificate for instance. Such a configuration can be loaded from
+// files with LoadTLSConfig.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(api *API) {
+		if transport := api.transport(); transport != nil {
+			transport.TLSClientConfig = config
+		}
+	}
+}
+
+// LoadTLSConfig returns a TLS configuration trusting the certificate
+// authorities of the system and the ones of the given PEM bundle, and
+// presenting the client certificate of the given PEM files. Files can be
+// empty strings to skip the bundle or the client certificate. It suits
+// self-hosted instances of PeeringDB running with a private PKI.
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		bundle, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificate found in %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// TransportOptions is a structure used to tune the connections to the API,
+// for heavy uses making thousands of lookups. Zero values keep the defaults
+// of the Go HTTP transport.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept open to
+	// the API, 2 by default. It should match the number of concurrent
+	// lookups so that connections are reused instead of opened again.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the number of connections to the API, idle or
+	// not. Connections are not limited by default.
+	MaxConnsPerHost int
+	// IdleConnTimeout is the time an idle connection is kept open, 90
+	// seconds by default.
+	IdleConnTimeout time.Duration
+	// DisableKeepAlives opens a new connection for each API call.
+	DisableKeepAlives bool
+	// DisableHTTP2 makes API calls with HTTP/1.1 only, HTTP/2 is used when
+	// the server supports it otherwise.
+	DisableHTTP2 bool
+}
+
+// WithTransportOptions returns an option tuning the connections to the API
+// with the given options. Like WithProxy, it applies to the HTTP client given
+// with WithHTTPClient if that option comes first.
+func WithTransportOptions(options TransportOptions) Option {
+	return func(api *API) {
+		transport := api.transport()
+		if transport == nil {
+			return
+		}
+
+		if options.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+			if transport.MaxIdleConns > 0 && transport.MaxIdleConns < options.MaxIdleConnsPerHost {
+				transport.MaxIdleConns = options.MaxIdleConnsPerHost
+			}
+		}
+		if options.MaxConnsPerHost > 0 {
+			transport.MaxConnsPerHost = options.MaxConnsPerHost
+		}
+		if options.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = options.IdleConnTimeout
+		}
+		transport.DisableKeepAlives = transport.DisableKeepAlives || options.DisableKeepAlives
+		if options.DisableHTTP2 {
+			// A non-nil empty map disables HTTP/2 in the Go HTTP transport
+			transport.ForceAttemptHTTP2 = false
+			transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+			if config := transport.TLSClientConfig; config != nil {
+				transport.TLSClientConfig = config.Clone()
+				transport.TLSClientConfig.NextProtos = nil
+				for _, protocol := range config.NextProtos {
+					if protocol != "h2" {
+						transport.TLSClientConfig.NextProtos = append(transport.TLSClientConfig.NextProtos, protocol)
+					}
+				}
+			}
+		}
+	}
+}
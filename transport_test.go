@@ -0,0 +1,93 @@
+package peeringdb
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer server.Close()
+
+	// The certificate of the server is not trusted by default
+	if _, err := NewAPIFromURL(server.URL + "/api/").GetNetworkByID(1); err == nil {
+		t.Error("GetNetworkByID, want a certificate error")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, bundle, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadTLSConfig(caFile, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{}
+	api := NewAPIFromURL(server.URL+"/api/", WithHTTPClient(client), WithTLSConfig(config))
+	if network, err := api.GetNetworkByID(1); err != nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByID, unexpected result %+v: %v", network, err)
+	}
+	if client.Transport != nil {
+		t.Error("WithTLSConfig, the given client was changed")
+	}
+
+	if _, err = LoadTLSConfig(filepath.Join(t.TempDir(), "missing.pem"), "", ""); err == nil {
+		t.Error("LoadTLSConfig, want error for a missing bundle")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(`{"meta": {}, "data": [{"id": 1, "asn": 64500}]}`))
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	api := NewAPIFromURL("http://peeringdb.example/api/", WithProxy(proxyURL))
+	if network, err := api.GetNetworkByID(1); err != nil || network.ASN != 64500 {
+		t.Errorf("GetNetworkByID, unexpected result %+v: %v", network, err)
+	}
+	if proxied != "http://peeringdb.example/api/net?depth=1&id=1" {
+		t.Errorf("WithProxy, unexpected proxied URL '%s'", proxied)
+	}
+}
+
+func TestWithTransportOptions(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	options := TransportOptions{MaxIdleConnsPerHost: 200, MaxConnsPerHost: 300, IdleConnTimeout: time.Minute}
+	for _, disableHTTP2 := range []bool{false, true} {
+		options.DisableHTTP2 = disableHTTP2
+		api := NewAPIFromURL(server.URL+"/api/", WithHTTPClient(server.Client()), WithTransportOptions(options))
+
+		transport := api.client.Transport.(*http.Transport)
+		if transport.MaxIdleConnsPerHost != 200 || (transport.MaxIdleConns != 0 && transport.MaxIdleConns < 200) || transport.MaxConnsPerHost != 300 || transport.IdleConnTimeout != time.Minute {
+			t.Errorf("WithTransportOptions, unexpected transport %+v", transport)
+		}
+
+		response, err := api.client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if expected := map[bool]string{false: "HTTP/2.0", true: "HTTP/1.1"}[disableHTTP2]; response.Proto != expected {
+			t.Errorf("WithTransportOptions, want %s got %s", expected, response.Proto)
+		}
+	}
+}
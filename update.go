@@ -0,0 +1,361 @@
+package peeringdb
+
+import (
+	"context"
+)
+
+// UpdateCampus replaces the Campus object having the ID of the given structure
+// with its values and returns the Campus object updated, see Update.
+func (api *API) UpdateCampus(object *Campus) (*Campus, error) {
+	return api.UpdateCampusWithContext(context.Background(), object)
+}
+
+// UpdateCampusWithContext is the same as UpdateCampus but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) UpdateCampusWithContext(ctx context.Context, object *Campus) (*Campus, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateCarrier replaces the Carrier object having the ID of the given
+// structure with its values and returns the Carrier object updated, see Update.
+func (api *API) UpdateCarrier(object *Carrier) (*Carrier, error) {
+	return api.UpdateCarrierWithContext(context.Background(), object)
+}
+
+// UpdateCarrierWithContext is the same as UpdateCarrier but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) UpdateCarrierWithContext(ctx context.Context, object *Carrier) (*Carrier, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateCarrierFacility replaces the CarrierFacility object having the ID of
+// the given structure with its values and returns the CarrierFacility object
+// updated, see Update.
+func (api *API) UpdateCarrierFacility(object *CarrierFacility) (*CarrierFacility, error) {
+	return api.UpdateCarrierFacilityWithContext(context.Background(), object)
+}
+
+// UpdateCarrierFacilityWithContext is the same as UpdateCarrierFacility but
+// uses the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) UpdateCarrierFacilityWithContext(ctx context.Context, object *CarrierFacility) (*CarrierFacility, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateFacility replaces the Facility object having the ID of the given
+// structure with its values and returns the Facility object updated, see
+// Update.
+func (api *API) UpdateFacility(object *Facility) (*Facility, error) {
+	return api.UpdateFacilityWithContext(context.Background(), object)
+}
+
+// UpdateFacilityWithContext is the same as UpdateFacility but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) UpdateFacilityWithContext(ctx context.Context, object *Facility) (*Facility, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateInternetExchange replaces the InternetExchange object having the ID of
+// the given structure with its values and returns the InternetExchange object
+// updated, see Update.
+func (api *API) UpdateInternetExchange(object *InternetExchange) (*InternetExchange, error) {
+	return api.UpdateInternetExchangeWithContext(context.Background(), object)
+}
+
+// UpdateInternetExchangeWithContext is the same as UpdateInternetExchange but
+// uses the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) UpdateInternetExchangeWithContext(ctx context.Context, object *InternetExchange) (*InternetExchange, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateInternetExchangeFacility replaces the InternetExchangeFacility object
+// having the ID of the given structure with its values and returns the
+// InternetExchangeFacility object updated, see Update.
+func (api *API) UpdateInternetExchangeFacility(object *InternetExchangeFacility) (*InternetExchangeFacility, error) {
+	return api.UpdateInternetExchangeFacilityWithContext(context.Background(), object)
+}
+
+// UpdateInternetExchangeFacilityWithContext is the same as
+// UpdateInternetExchangeFacility but uses the given context for the API call,
+// allowing to cancel it or to set a deadline.
+func (api *API) UpdateInternetExchangeFacilityWithContext(ctx context.Context, object *InternetExchangeFacility) (*InternetExchangeFacility, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateInternetExchangeLAN replaces the InternetExchangeLAN object having the
+// ID of the given structure with its values and returns the InternetExchangeLAN
+// object updated, see Update.
+func (api *API) UpdateInternetExchangeLAN(object *InternetExchangeLAN) (*InternetExchangeLAN, error) {
+	return api.UpdateInternetExchangeLANWithContext(context.Background(), object)
+}
+
+// UpdateInternetExchangeLANWithContext is the same as UpdateInternetExchangeLAN
+// but uses the given context for the API call, allowing to cancel it or to set
+// a deadline.
+func (api *API) UpdateInternetExchangeLANWithContext(ctx context.Context, object *InternetExchangeLAN) (*InternetExchangeLAN, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateInternetExchangePrefix replaces the InternetExchangePrefix object
+// having the ID of the given structure with its values and returns the
+// InternetExchangePrefix object updated, see Update.
+func (api *API) UpdateInternetExchangePrefix(object *InternetExchangePrefix) (*InternetExchangePrefix, error) {
+	return api.UpdateInternetExchangePrefixWithContext(context.Background(), object)
+}
+
+// UpdateInternetExchangePrefixWithContext is the same as
+// UpdateInternetExchangePrefix but uses the given context for the API call,
+// allowing to cancel it or to set a deadline.
+func (api *API) UpdateInternetExchangePrefixWithContext(ctx context.Context, object *InternetExchangePrefix) (*InternetExchangePrefix, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateNetwork replaces the Network object having the ID of the given
+// structure with its values and returns the Network object updated, see Update.
+func (api *API) UpdateNetwork(object *Network) (*Network, error) {
+	return api.UpdateNetworkWithContext(context.Background(), object)
+}
+
+// UpdateNetworkWithContext is the same as UpdateNetwork but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) UpdateNetworkWithContext(ctx context.Context, object *Network) (*Network, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateNetworkContact replaces the NetworkContact object having the ID of the
+// given structure with its values and returns the NetworkContact object
+// updated, see Update.
+func (api *API) UpdateNetworkContact(object *NetworkContact) (*NetworkContact, error) {
+	return api.UpdateNetworkContactWithContext(context.Background(), object)
+}
+
+// UpdateNetworkContactWithContext is the same as UpdateNetworkContact but uses
+// the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) UpdateNetworkContactWithContext(ctx context.Context, object *NetworkContact) (*NetworkContact, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateNetworkFacility replaces the NetworkFacility object having the ID of
+// the given structure with its values and returns the NetworkFacility object
+// updated, see Update.
+func (api *API) UpdateNetworkFacility(object *NetworkFacility) (*NetworkFacility, error) {
+	return api.UpdateNetworkFacilityWithContext(context.Background(), object)
+}
+
+// UpdateNetworkFacilityWithContext is the same as UpdateNetworkFacility but
+// uses the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) UpdateNetworkFacilityWithContext(ctx context.Context, object *NetworkFacility) (*NetworkFacility, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateNetworkInternetExchangeLAN replaces the NetworkInternetExchangeLAN
+// object having the ID of the given structure with its values and returns the
+// NetworkInternetExchangeLAN object updated, see Update.
+func (api *API) UpdateNetworkInternetExchangeLAN(object *NetworkInternetExchangeLAN) (*NetworkInternetExchangeLAN, error) {
+	return api.UpdateNetworkInternetExchangeLANWithContext(context.Background(), object)
+}
+
+// UpdateNetworkInternetExchangeLANWithContext is the same as
+// UpdateNetworkInternetExchangeLAN but uses the given context for the API call,
+// allowing to cancel it or to set a deadline.
+func (api *API) UpdateNetworkInternetExchangeLANWithContext(ctx context.Context, object *NetworkInternetExchangeLAN) (*NetworkInternetExchangeLAN, error) {
+	return Update(ctx, api, object)
+}
+
+// UpdateOrganization replaces the Organization object having the ID of the
+// given structure with its values and returns the Organization object updated,
+// see Update.
+func (api *API) UpdateOrganization(object *Organization) (*Organization, error) {
+	return api.UpdateOrganizationWithContext(context.Background(), object)
+}
+
+// UpdateOrganizationWithContext is the same as UpdateOrganization but uses the
+// given context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) UpdateOrganizationWithContext(ctx context.Context, object *Organization) (*Organization, error) {
+	return Update(ctx, api, object)
+}
+
+// PatchCampus changes the given fields of the Campus object having the given
+// ID, leaving its other fields as they are, and returns the Campus object
+// updated, see Patch.
+func (api *API) PatchCampus(id int, fields map[string]interface{}) (*Campus, error) {
+	return api.PatchCampusWithContext(context.Background(), id, fields)
+}
+
+// PatchCampusWithContext is the same as PatchCampus but uses the given context
+// for the API call, allowing to cancel it or to set a deadline.
+func (api *API) PatchCampusWithContext(ctx context.Context, id int, fields map[string]interface{}) (*Campus, error) {
+	return Patch[Campus](ctx, api, id, fields)
+}
+
+// PatchCarrier changes the given fields of the Carrier object having the given
+// ID, leaving its other fields as they are, and returns the Carrier object
+// updated, see Patch.
+func (api *API) PatchCarrier(id int, fields map[string]interface{}) (*Carrier, error) {
+	return api.PatchCarrierWithContext(context.Background(), id, fields)
+}
+
+// PatchCarrierWithContext is the same as PatchCarrier but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) PatchCarrierWithContext(ctx context.Context, id int, fields map[string]interface{}) (*Carrier, error) {
+	return Patch[Carrier](ctx, api, id, fields)
+}
+
+// PatchCarrierFacility changes the given fields of the CarrierFacility object
+// having the given ID, leaving its other fields as they are, and returns the
+// CarrierFacility object updated, see Patch.
+func (api *API) PatchCarrierFacility(id int, fields map[string]interface{}) (*CarrierFacility, error) {
+	return api.PatchCarrierFacilityWithContext(context.Background(), id, fields)
+}
+
+// PatchCarrierFacilityWithContext is the same as PatchCarrierFacility but uses
+// the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) PatchCarrierFacilityWithContext(ctx context.Context, id int, fields map[string]interface{}) (*CarrierFacility, error) {
+	return Patch[CarrierFacility](ctx, api, id, fields)
+}
+
+// PatchFacility changes the given fields of the Facility object having the
+// given ID, leaving its other fields as they are, and returns the Facility
+// object updated, see Patch.
+func (api *API) PatchFacility(id int, fields map[string]interface{}) (*Facility, error) {
+	return api.PatchFacilityWithContext(context.Background(), id, fields)
+}
+
+// PatchFacilityWithContext is the same as PatchFacility but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) PatchFacilityWithContext(ctx context.Context, id int, fields map[string]interface{}) (*Facility, error) {
+	return Patch[Facility](ctx, api, id, fields)
+}
+
+// PatchInternetExchange changes the given fields of the InternetExchange object
+// having the given ID, leaving its other fields as they are, and returns the
+// InternetExchange object updated, see Patch.
+func (api *API) PatchInternetExchange(id int, fields map[string]interface{}) (*InternetExchange, error) {
+	return api.PatchInternetExchangeWithContext(context.Background(), id, fields)
+}
+
+// PatchInternetExchangeWithContext is the same as PatchInternetExchange but
+// uses the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) PatchInternetExchangeWithContext(ctx context.Context, id int, fields map[string]interface{}) (*InternetExchange, error) {
+	return Patch[InternetExchange](ctx, api, id, fields)
+}
+
+// PatchInternetExchangeFacility changes the given fields of the
+// InternetExchangeFacility object having the given ID, leaving its other fields
+// as they are, and returns the InternetExchangeFacility object updated, see
+// Patch.
+func (api *API) PatchInternetExchangeFacility(id int, fields map[string]interface{}) (*InternetExchangeFacility, error) {
+	return api.PatchInternetExchangeFacilityWithContext(context.Background(), id, fields)
+}
+
+// PatchInternetExchangeFacilityWithContext is the same as
+// PatchInternetExchangeFacility but uses the given context for the API call,
+// allowing to cancel it or to set a deadline.
+func (api *API) PatchInternetExchangeFacilityWithContext(ctx context.Context, id int, fields map[string]interface{}) (*InternetExchangeFacility, error) {
+	return Patch[InternetExchangeFacility](ctx, api, id, fields)
+}
+
+// PatchInternetExchangeLAN changes the given fields of the InternetExchangeLAN
+// object having the given ID, leaving its other fields as they are, and returns
+// the InternetExchangeLAN object updated, see Patch.
+func (api *API) PatchInternetExchangeLAN(id int, fields map[string]interface{}) (*InternetExchangeLAN, error) {
+	return api.PatchInternetExchangeLANWithContext(context.Background(), id, fields)
+}
+
+// PatchInternetExchangeLANWithContext is the same as PatchInternetExchangeLAN
+// but uses the given context for the API call, allowing to cancel it or to set
+// a deadline.
+func (api *API) PatchInternetExchangeLANWithContext(ctx context.Context, id int, fields map[string]interface{}) (*InternetExchangeLAN, error) {
+	return Patch[InternetExchangeLAN](ctx, api, id, fields)
+}
+
+// PatchInternetExchangePrefix changes the given fields of the
+// InternetExchangePrefix object having the given ID, leaving its other fields
+// as they are, and returns the InternetExchangePrefix object updated, see
+// Patch.
+func (api *API) PatchInternetExchangePrefix(id int, fields map[string]interface{}) (*InternetExchangePrefix, error) {
+	return api.PatchInternetExchangePrefixWithContext(context.Background(), id, fields)
+}
+
+// PatchInternetExchangePrefixWithContext is the same as
+// PatchInternetExchangePrefix but uses the given context for the API call,
+// allowing to cancel it or to set a deadline.
+func (api *API) PatchInternetExchangePrefixWithContext(ctx context.Context, id int, fields map[string]interface{}) (*InternetExchangePrefix, error) {
+	return Patch[InternetExchangePrefix](ctx, api, id, fields)
+}
+
+// PatchNetwork changes the given fields of the Network object having the given
+// ID, leaving its other fields as they are, and returns the Network object
+// updated, see Patch. Its IRR AS-SET can be changed alone for instance:
+//
+//	network, err := api.PatchNetwork(20, map[string]interface{}{"irr_as_set": "AS-FOO"})
+func (api *API) PatchNetwork(id int, fields map[string]interface{}) (*Network, error) {
+	return api.PatchNetworkWithContext(context.Background(), id, fields)
+}
+
+// PatchNetworkWithContext is the same as PatchNetwork but uses the given
+// context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) PatchNetworkWithContext(ctx context.Context, id int, fields map[string]interface{}) (*Network, error) {
+	return Patch[Network](ctx, api, id, fields)
+}
+
+// PatchNetworkContact changes the given fields of the NetworkContact object
+// having the given ID, leaving its other fields as they are, and returns the
+// NetworkContact object updated, see Patch.
+func (api *API) PatchNetworkContact(id int, fields map[string]interface{}) (*NetworkContact, error) {
+	return api.PatchNetworkContactWithContext(context.Background(), id, fields)
+}
+
+// PatchNetworkContactWithContext is the same as PatchNetworkContact but uses
+// the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) PatchNetworkContactWithContext(ctx context.Context, id int, fields map[string]interface{}) (*NetworkContact, error) {
+	return Patch[NetworkContact](ctx, api, id, fields)
+}
+
+// PatchNetworkFacility changes the given fields of the NetworkFacility object
+// having the given ID, leaving its other fields as they are, and returns the
+// NetworkFacility object updated, see Patch.
+func (api *API) PatchNetworkFacility(id int, fields map[string]interface{}) (*NetworkFacility, error) {
+	return api.PatchNetworkFacilityWithContext(context.Background(), id, fields)
+}
+
+// PatchNetworkFacilityWithContext is the same as PatchNetworkFacility but uses
+// the given context for the API call, allowing to cancel it or to set a
+// deadline.
+func (api *API) PatchNetworkFacilityWithContext(ctx context.Context, id int, fields map[string]interface{}) (*NetworkFacility, error) {
+	return Patch[NetworkFacility](ctx, api, id, fields)
+}
+
+// PatchNetworkInternetExchangeLAN changes the given fields of the
+// NetworkInternetExchangeLAN object having the given ID, leaving its other
+// fields as they are, and returns the NetworkInternetExchangeLAN object
+// updated, see Patch.
+func (api *API) PatchNetworkInternetExchangeLAN(id int, fields map[string]interface{}) (*NetworkInternetExchangeLAN, error) {
+	return api.PatchNetworkInternetExchangeLANWithContext(context.Background(), id, fields)
+}
+
+// PatchNetworkInternetExchangeLANWithContext is the same as
+// PatchNetworkInternetExchangeLAN but uses the given context for the API call,
+// allowing to cancel it or to set a deadline.
+func (api *API) PatchNetworkInternetExchangeLANWithContext(ctx context.Context, id int, fields map[string]interface{}) (*NetworkInternetExchangeLAN, error) {
+	return Patch[NetworkInternetExchangeLAN](ctx, api, id, fields)
+}
+
+// PatchOrganization changes the given fields of the Organization object having
+// the given ID, leaving its other fields as they are, and returns the
+// Organization object updated, see Patch.
+func (api *API) PatchOrganization(id int, fields map[string]interface{}) (*Organization, error) {
+	return api.PatchOrganizationWithContext(context.Background(), id, fields)
+}
+
+// PatchOrganizationWithContext is the same as PatchOrganization but uses the
+// given context for the API call, allowing to cancel it or to set a deadline.
+func (api *API) PatchOrganizationWithContext(ctx context.Context, id int, fields map[string]interface{}) (*Organization, error) {
+	return Patch[Organization](ctx, api, id, fields)
+}
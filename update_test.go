@@ -0,0 +1,74 @@
+package peeringdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateObject(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkInternetExchangeLANNamespace: {
+			{"id": 1, "net_id": 1, "ixlan_id": 1, "speed": 1000, "ipaddr4": "192.0.2.1", "created": "2020-01-01T00:00:00Z"},
+		},
+	})
+	api := server.api()
+
+	netixlan, err := api.GetNetworkInternetExchangeLANByID(1)
+	if err != nil || netixlan == nil {
+		t.Fatalf("GetNetworkInternetExchangeLANByID, unexpected result %v, %v", netixlan, err)
+	}
+	netixlan.Speed = 10000
+	returned, err := api.UpdateNetworkInternetExchangeLAN(netixlan)
+	if err != nil {
+		t.Fatalf("UpdateNetworkInternetExchangeLAN, unexpected error %v", err)
+	}
+	if returned == nil || returned.ID != 1 || returned.Speed != 10000 || returned.Created.IsZero() {
+		t.Errorf("UpdateNetworkInternetExchangeLAN, unexpected object returned %v", returned)
+	}
+
+	updated := server.objects[networkInternetExchangeLANNamespace][0]
+	if speed, _ := updated["speed"].(float64); speed != 10000 || updated["ipaddr4"] != "192.0.2.1" {
+		t.Errorf("UpdateNetworkInternetExchangeLAN, unexpected object %v", updated)
+	}
+	// Embedded objects and fields set by the API are not sent
+	for _, field := range []string{"net", "ixlan"} {
+		if value, ok := updated[field]; ok {
+			t.Errorf("UpdateNetworkInternetExchangeLAN, unexpected %s field sent: %v", field, value)
+		}
+	}
+	if updated["created"] != "2020-01-01T00:00:00Z" {
+		t.Errorf("UpdateNetworkInternetExchangeLAN, unexpected created field sent: %v", updated["created"])
+	}
+
+	if _, err = api.UpdateNetwork(&Network{Name: "No ID"}); !errors.Is(err, ErrMissingID) {
+		t.Errorf("UpdateNetwork, expected ErrMissingID, got %v", err)
+	}
+}
+
+func TestPatchObject(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {{"id": 20, "asn": 64500, "irr_as_set": "AS-OLD", "policy_url": "https://example.net/policy"}},
+	})
+	api := server.api()
+
+	network, err := api.PatchNetwork(20, map[string]interface{}{"irr_as_set": "AS-FOO"})
+	if err != nil {
+		t.Fatalf("PatchNetwork, unexpected error %v", err)
+	}
+	if network == nil || network.ASN != 64500 || network.IRRASSet != "AS-FOO" {
+		t.Errorf("PatchNetwork, unexpected object returned %v", network)
+	}
+	network, err = api.GetNetworkByID(20)
+	if err != nil || network == nil || network.IRRASSet != "AS-FOO" || network.PolicyURL != "https://example.net/policy" {
+		t.Errorf("PatchNetwork, unexpected object %v, %v", network, err)
+	}
+
+	// Nothing to change, no call is made
+	requests := server.count(networkNamespace)
+	if network, err = api.PatchNetwork(20, nil); err != nil || network != nil || server.count(networkNamespace) != requests {
+		t.Errorf("PatchNetwork, unexpected call or error %v", err)
+	}
+	if _, err = api.PatchNetwork(0, map[string]interface{}{"name": "No ID"}); !errors.Is(err, ErrMissingID) {
+		t.Errorf("PatchNetwork, expected ErrMissingID, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package peeringdb
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URLBuilder is the interface used to build the URL of API calls. A custom
+// implementation can be given with WithURLBuilder to target another layout of
+// the API without changing anything else.
+type URLBuilder interface {
+	// URL returns the URL used to query objects of the given namespace from
+	// the API at the given base URL. An empty string is returned if the URL
+	// cannot be built.
+	URL(base, namespace string, search map[string]interface{}) string
+}
+
+// ObjectURLBuilder is an optional interface of a URLBuilder building the URL
+// used to write objects. A URLBuilder not implementing it gets write URLs
+// derived from the URL of the namespace, stripped of its search parameters.
+type ObjectURLBuilder interface {
+	// ObjectURL returns the URL of the object with the given ID of a
+	// namespace, or of the namespace itself if the ID is 0, from the API at
+	// the given base URL.
+	ObjectURL(base, namespace string, id int) string
+}
+
+// URLBuilderFunc is an adapter to use a function as a URLBuilder.
+type URLBuilderFunc func(base, namespace string, search map[string]interface{}) string
+
+// URL calls the function.
+func (f URLBuilderFunc) URL(base, namespace string, search map[string]interface{}) string {
+	return f(base, namespace, search)
+}
+
+// StandardURLBuilder is the URLBuilder used by default. Its zero value builds
+// URLs for the current PeeringDB API, objects being requested with a depth of
+// 1 unless another depth is given in the search parameters.
+type StandardURLBuilder struct {
+	// Version is a path segment inserted between the base URL and the
+	// namespace, for instance "v2" to query "<base>/v2/net".
+	Version string
+	// Parameters are added to the search parameters of every call. Search
+	// parameters given to a call take precedence over them.
+	Parameters map[string]interface{}
+}
+
+// URL returns the URL used to query objects of the given namespace.
+func (b StandardURLBuilder) URL(base, namespace string, search map[string]interface{}) string {
+	parameters := make(map[string]interface{}, len(b.Parameters)+len(search))
+	for key, value := range b.Parameters {
+		parameters[key] = value
+	}
+	for key, value := range search {
+		parameters[key] = value
+	}
+
+	// Depth always comes first, as it has always been
+	var depth interface{} = 1
+	if value, ok := parameters["depth"]; ok {
+		depth = value
+		delete(parameters, "depth")
+	}
+
+	path := namespace
+	if version := strings.Trim(b.Version, "/"); version != "" {
+		path = version + "/" + namespace
+	}
+
+	return fmt.Sprintf("%s%s?depth=%s%s", base, path, url.QueryEscape(formatFilterValue(depth)),
+		formatSearchParameters(parameters))
+}
+
+// ObjectURL returns the URL used to write objects of the given namespace.
+// Global parameters, except the depth, are kept as search parameters.
+func (b StandardURLBuilder) ObjectURL(base, namespace string, id int) string {
+	parameters := make(map[string]interface{}, len(b.Parameters))
+	for key, value := range b.Parameters {
+		if key != "depth" {
+			parameters[key] = value
+		}
+	}
+
+	path := namespace
+	if version := strings.Trim(b.Version, "/"); version != "" {
+		path = version + "/" + namespace
+	}
+	if id != 0 {
+		path += "/" + strconv.Itoa(id)
+	}
+	if len(parameters) == 0 {
+		return base + path
+	}
+
+	return base + path + "?" + searchValues(parameters).Encode()
+}
+
+// WithURLBuilder returns an option setting the builder used to build the URL
+// of API calls.
+func WithURLBuilder(builder URLBuilder) Option {
+	return func(api *API) {
+		api.urlBuilder = builder
+	}
+}
@@ -0,0 +1,66 @@
+package peeringdb
+
+import (
+	"testing"
+)
+
+func TestStandardURLBuilder(t *testing.T) {
+	base := "https://example.net/api/"
+
+	builder := StandardURLBuilder{
+		Version:    "v2",
+		Parameters: map[string]interface{}{"status": "ok", "depth": 0},
+	}
+
+	expected := "https://example.net/api/v2/net?depth=0&asn=64500&status=ok"
+	if url := builder.URL(base, networkNamespace, map[string]interface{}{"asn": 64500}); url != expected {
+		t.Errorf("URL, want '%s' got '%s'", expected, url)
+	}
+
+	// Search parameters take precedence over global ones
+	expected = "https://example.net/api/v2/net?depth=2&status=deleted"
+	url := builder.URL(base, networkNamespace, map[string]interface{}{"depth": 2, "status": "deleted"})
+	if url != expected {
+		t.Errorf("URL, want '%s' got '%s'", expected, url)
+	}
+}
+
+func TestStandardURLBuilderObjectURL(t *testing.T) {
+	base := "https://example.net/api/"
+
+	expected := "https://example.net/api/net/1"
+	if url := (StandardURLBuilder{}).ObjectURL(base, networkNamespace, 1); url != expected {
+		t.Errorf("ObjectURL, want '%s' got '%s'", expected, url)
+	}
+
+	// The depth is not a parameter of writes
+	builder := StandardURLBuilder{
+		Version:    "v2",
+		Parameters: map[string]interface{}{"depth": 0, "key": "value"},
+	}
+	expected = "https://example.net/api/v2/net?key=value"
+	if url := builder.ObjectURL(base, networkNamespace, 0); url != expected {
+		t.Errorf("ObjectURL, want '%s' got '%s'", expected, url)
+	}
+}
+
+func TestWithURLBuilder(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkNamespace: {
+			{"id": 1, "asn": 64500, "name": "Network A"},
+		},
+	})
+
+	var namespaces []string
+	api := server.api(WithURLBuilder(URLBuilderFunc(func(base, namespace string, search map[string]interface{}) string {
+		namespaces = append(namespaces, namespace)
+		return formatURL(base, namespace, search)
+	})))
+
+	if _, err := api.GetASN(64500); err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 1 || namespaces[0] != networkNamespace {
+		t.Errorf("URLBuilder, unexpected calls: %v", namespaces)
+	}
+}
@@ -0,0 +1,210 @@
+package peeringdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidObject is the error that will be returned if an object is not
+// valid and is not sent to the API.
+var ErrInvalidObject = errors.New("invalid object")
+
+// isoCountryCodes are the ISO 3166-1 alpha-2 country codes.
+const isoCountryCodes = "AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI " +
+	"BJ BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN " +
+	"CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK " +
+	"FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM " +
+	"HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN " +
+	"KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK " +
+	"ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP " +
+	"NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW " +
+	"SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF " +
+	"TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI " +
+	"VN VU WF WS YE YT ZA ZM ZW"
+
+// countryCodes are the ISO 3166-1 alpha-2 codes accepted as countries.
+var countryCodes = func() map[string]bool {
+	codes := make(map[string]bool)
+	for _, code := range strings.Fields(isoCountryCodes) {
+		codes[code] = true
+	}
+
+	return codes
+}()
+
+// requiredFields are the fields which must be set when writing an object of
+// a namespace.
+var requiredFields = map[string][]string{
+	campusNamespace:                     {"org_id", "name"},
+	carrierNamespace:                    {"org_id", "name"},
+	carrierFacilityNamespace:            {"carrier_id", "fac_id"},
+	facilityNamespace:                   {"org_id", "name", "city", "country"},
+	internetExchangeNamespace:           {"org_id", "name", "city", "country"},
+	internetExchangeFacilityNamespace:   {"ix_id", "fac_id"},
+	internetExchangeLANNamespace:        {"ix_id"},
+	internetExchangePrefixNamespace:     {"ixlan_id", "protocol", "prefix"},
+	networkNamespace:                    {"org_id", "name", "asn"},
+	networkContactNamespace:             {"net_id", "role", "visible"},
+	networkFacilityNamespace:            {"net_id", "fac_id"},
+	networkInternetExchangeLANNamespace: {"net_id", "ixlan_id", "speed"},
+	organizationNamespace:               {"name"},
+}
+
+// FieldError is a structure describing why the value of a field is not valid.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// String returns a human readable representation of the error.
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError is the error returned when an object is not valid, before
+// sending it to the API. It lists the invalid fields. It can be checked with
+// errors.Is against ErrInvalidObject.
+type ValidationError struct {
+	Namespace string
+	// ID is the ID of the object, it is 0 for a creation.
+	ID     int
+	Fields []FieldError
+}
+
+// Error returns a message listing the invalid fields.
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field.String()
+	}
+
+	return fmt.Sprintf("%s: %s object: %s", ErrInvalidObject, e.Namespace, strings.Join(fields, ", "))
+}
+
+// Is tells if the target is ErrInvalidObject.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidObject
+}
+
+// Validate checks an object of type T before writing it, so that mistakes are
+// caught without making API calls: fields required by the API must be set, IP
+// addresses, prefixes, country codes and email addresses must be valid, and
+// AS numbers and speeds must be positive. A *ValidationError is returned if
+// the object is not valid. Objects are validated by Create and Update, fields
+// with a zero value being considered as not set. The fields given to Patch or
+// to the other writes are all validated too, even with a zero value, except
+// for the required ones.
+func Validate[T Object](object *T) error {
+	namespace := namespaceOf[T]()
+	if object == nil {
+		return &ValidationError{Namespace: namespace, Fields: []FieldError{{Field: "object", Message: "missing object"}}}
+	}
+
+	fields, err := writableFields(object)
+	if err != nil {
+		return err
+	}
+
+	return validateObject(namespace, idOf(object), objectFields(fields).set(), requiredFields[namespace])
+}
+
+// validateObject checks the given fields of an object of a namespace, and
+// that the required ones are set. Every given field is checked, even with a
+// zero value, only the fields which are not given being ignored.
+func validateObject(namespace string, id int, fields map[string]interface{}, required []string) error {
+	var errs []FieldError
+
+	for _, name := range required {
+		if value, ok := fields[name]; !ok || isZeroValue(value) {
+			errs = append(errs, FieldError{Field: name, Message: "required field not set"})
+		}
+	}
+
+	for name, value := range fields {
+		if message := validateValue(name, value); message != "" {
+			errs = append(errs, FieldError{Field: name, Message: message})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	// Report fields in a stable order
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return &ValidationError{Namespace: namespace, ID: id, Fields: errs}
+}
+
+// validateValue checks the value of a field, it returns why it is not valid or
+// an empty string if it is. A null value or an empty string clears a field and
+// is valid, except for AS numbers and speeds which must always be positive.
+func validateValue(name string, value interface{}) string {
+	text := strings.TrimSpace(fmt.Sprintf("%v", value))
+	if value == nil {
+		text = ""
+	}
+	if text == "" && name != "asn" && name != "local_asn" && name != "speed" {
+		return ""
+	}
+
+	if name == "email" || strings.HasSuffix(name, "_email") {
+		if address, err := mail.ParseAddress(text); err != nil || address.Address != text {
+			return fmt.Sprintf("invalid email address '%s'", text)
+		}
+		return ""
+	}
+
+	switch name {
+	case "ipaddr4", "ipaddr6":
+		address, err := netip.ParseAddr(text)
+		if err != nil {
+			return fmt.Sprintf("invalid IP address '%s'", text)
+		}
+		if name == "ipaddr4" && !address.Is4() {
+			return fmt.Sprintf("'%s' is not an IPv4 address", text)
+		}
+		if name == "ipaddr6" && (!address.Is6() || address.Is4In6()) {
+			return fmt.Sprintf("'%s' is not an IPv6 address", text)
+		}
+	case "prefix":
+		if _, err := netip.ParsePrefix(text); err != nil {
+			return fmt.Sprintf("invalid prefix '%s'", text)
+		}
+	case "country":
+		if !countryCodes[text] {
+			return fmt.Sprintf("invalid country code '%s'", text)
+		}
+	case "asn", "local_asn":
+		if number, err := strconv.ParseFloat(text, 64); err != nil || number <= 0 || number > math.MaxUint32 {
+			return fmt.Sprintf("invalid AS number '%s'", text)
+		}
+	case "speed":
+		if number, err := strconv.ParseFloat(text, 64); err != nil || number <= 0 {
+			return fmt.Sprintf("speed must be positive, got '%s'", text)
+		}
+	}
+
+	return ""
+}
+
+// isZeroValue tells if a value decoded from JSON, or given as a field of an
+// object to write, is not set.
+func isZeroValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case bool:
+		return false
+	default:
+		return fmt.Sprintf("%v", v) == "0"
+	}
+}
@@ -0,0 +1,90 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := &NetworkInternetExchangeLAN{NetworkID: 1, InternetExchangeLANID: 1, Speed: 10000, IPAddr4: "192.0.2.1", IPAddr6: "2001:db8::1"}
+	if err := Validate(valid); err != nil {
+		t.Errorf("Validate, unexpected error %v", err)
+	}
+
+	err := Validate(&NetworkInternetExchangeLAN{NetworkID: 1, IPAddr4: "2001:db8::1", IPAddr6: "192.0.2.1"})
+	var validation *ValidationError
+	if !errors.As(err, &validation) || !errors.Is(err, ErrInvalidObject) {
+		t.Fatalf("Validate, expected a validation error, got %v", err)
+	}
+	expected := []string{"ipaddr4", "ipaddr6", "ixlan_id", "speed"}
+	if len(validation.Fields) != len(expected) {
+		t.Fatalf("Validate, expected %d invalid fields, got %v", len(expected), validation.Fields)
+	}
+	for i, field := range validation.Fields {
+		if field.Field != expected[i] {
+			t.Errorf("Validate, expected invalid field %s, got %s", expected[i], field)
+		}
+	}
+
+	for _, test := range []struct {
+		object  *NetworkContact
+		invalid bool
+	}{
+		{&NetworkContact{NetworkID: 1, Role: "NOC", Visible: "Public", Email: "noc@example.net"}, false},
+		{&NetworkContact{NetworkID: 1, Role: "NOC", Visible: "Public", Email: "NOC <noc@example.net>"}, true},
+		{&NetworkContact{NetworkID: 1, Role: "NOC", Visible: "Public", Email: "noc.example.net"}, true},
+	} {
+		if err := Validate(test.object); (err != nil) != test.invalid {
+			t.Errorf("Validate(%s), unexpected result %v", test.object.Email, err)
+		}
+	}
+
+	for _, test := range []struct {
+		object  *Facility
+		invalid bool
+	}{
+		{&Facility{OrganizationID: 1, Name: "Facility", City: "Paris", Country: "FR"}, false},
+		{&Facility{OrganizationID: 1, Name: "Facility", City: "Paris", Country: "FX"}, true},
+		{&Facility{OrganizationID: 1, Name: "Facility", City: "Paris", Country: "France"}, true},
+		{&Facility{OrganizationID: 1, Name: "Facility", Country: "FR"}, true},
+	} {
+		if err := Validate(test.object); (err != nil) != test.invalid {
+			t.Errorf("Validate(%s), unexpected result %v", test.object.Country, err)
+		}
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkInternetExchangeLANNamespace: {{"id": 1, "net_id": 1, "ixlan_id": 1, "speed": 1000}},
+	})
+	api := server.api()
+
+	if _, err := Create(context.Background(), api, &NetworkInternetExchangeLAN{NetworkID: 1, InternetExchangeLANID: 1}); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("Create, expected ErrInvalidObject, got %v", err)
+	}
+	if _, err := api.PatchNetworkInternetExchangeLAN(1, map[string]interface{}{"ipaddr4": "192.0.2.256"}); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("PatchNetworkInternetExchangeLAN, expected ErrInvalidObject, got %v", err)
+	}
+	if _, err := api.PatchNetworkInternetExchangeLAN(1, map[string]interface{}{"speed": -1}); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("PatchNetworkInternetExchangeLAN, expected ErrInvalidObject, got %v", err)
+	}
+	// Zero values given explicitly are checked too
+	for _, fields := range []map[string]interface{}{{"speed": 0}, {"asn": 0}, {"speed": 1000, "local_asn": 0}} {
+		if _, err := api.PatchNetworkInternetExchangeLAN(1, fields); !errors.Is(err, ErrInvalidObject) {
+			t.Errorf("PatchNetworkInternetExchangeLAN(%v), expected ErrInvalidObject, got %v", fields, err)
+		}
+		if _, err := DiffPatch[NetworkInternetExchangeLAN](context.Background(), api, 1, fields); !errors.Is(err, ErrInvalidObject) {
+			t.Errorf("DiffPatch(%v), expected ErrInvalidObject, got %v", fields, err)
+		}
+	}
+	if count := server.count(networkInternetExchangeLANNamespace); count != 0 {
+		t.Errorf("invalid objects sent to the API %d times", count)
+	}
+
+	// Empty strings and null values clear fields
+	if _, err := api.PatchNetworkInternetExchangeLAN(1, map[string]interface{}{"ipaddr4": "", "ipaddr6": nil}); err != nil {
+		t.Errorf("PatchNetworkInternetExchangeLAN, unexpected error %v", err)
+	}
+}
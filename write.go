@@ -0,0 +1,259 @@
+package peeringdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// objectURL returns the URL of an object of a namespace, or of the namespace
+// itself if the ID is 0. Such URLs are used to write objects and are built by
+// the URL builder of the API.
+func (api *API) objectURL(namespace string, id int) string {
+	if builder, ok := api.urlBuilder.(ObjectURLBuilder); ok {
+		return builder.ObjectURL(api.url, namespace, id)
+	}
+
+	u, _, _ := strings.Cut(api.urlBuilder.URL(api.url, namespace, nil), "?")
+	if u == "" || id == 0 {
+		return u
+	}
+
+	return u + "/" + strconv.Itoa(id)
+}
+
+// write sends a request changing an object of a namespace: POST to create it,
+// PUT to update it, PATCH to change some of its fields or DELETE to delete it.
+// The object is encoded as JSON in the request body, if it is not nil. The
+// object returned by the API, if any, is returned as raw JSON. The fields of
+// the object are validated first, the given required ones having to be set.
+// Writing requires an API key.
+func (api *API) write(ctx context.Context, method, namespace string, id int, object interface{}, required []string) (json.RawMessage, error) {
+	// Catch invalid fields without making an API call
+	var fields map[string]interface{}
+	switch o := object.(type) {
+	case map[string]interface{}:
+		fields = o
+	case objectFields:
+		fields = o.set()
+	}
+	if fields != nil {
+		if err := validateObject(namespace, id, fields, required); err != nil {
+			return nil, err
+		}
+	}
+
+	if api.explain != nil {
+		api.explain.record(method, namespace, api.objectURL(namespace, id), nil)
+		return nil, nil
+	}
+
+	var body bytes.Buffer
+	if object != nil {
+		if err := json.NewEncoder(&body).Encode(object); err != nil {
+			return nil, fmt.Errorf("cannot encode %s object: %w", namespace, err)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, api.objectURL(namespace, id), &body)
+	if err != nil {
+		return nil, ErrBuildingRequest
+	}
+	if object != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := api.do(ctx, namespace, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// Deletions do not return any object
+	if response.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	resource := &rawResource{}
+	if err = json.NewDecoder(response.Body).Decode(resource); err != nil {
+		return nil, err
+	}
+	if len(resource.Data) == 0 {
+		return nil, nil
+	}
+
+	return resource.Data[0], nil
+}
+
+// ErrMissingID is the error that will be returned if an object to update or
+// to delete has no ID.
+var ErrMissingID = errors.New("object without id")
+
+// readOnlyFields are the fields of objects set by the API, which are not sent
+// when writing objects.
+var readOnlyFields = []string{"id", "created", "updated"}
+
+// writableFields returns the fields of an object which are sent to the API to
+// write it, indexed by name. Objects embedded in the object, the sets of IDs
+// of the objects referencing it and the fields set by the API are left out.
+func writableFields(object interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(object)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	writable := make(map[string]interface{}, len(fields))
+	for _, column := range tableColumns(t) {
+		if value, ok := fields[column.Name]; ok && !strings.HasSuffix(column.Name, "_set") {
+			writable[column.Name] = value
+		}
+	}
+	for _, field := range readOnlyFields {
+		delete(writable, field)
+	}
+
+	return writable, nil
+}
+
+// objectFields are the writable fields of an object encoded from its
+// structure, in which a field with a zero value cannot be told apart from a
+// field which is not set.
+type objectFields map[string]interface{}
+
+// set returns the fields having a value other than a zero one.
+func (f objectFields) set() map[string]interface{} {
+	fields := make(map[string]interface{}, len(f))
+	for name, value := range f {
+		if !isZeroValue(value) {
+			fields[name] = value
+		}
+	}
+
+	return fields
+}
+
+// idOf returns the ID of an object of type T.
+func idOf[T Object](object *T) int {
+	return int(reflect.ValueOf(object).Elem().FieldByName("ID").Int())
+}
+
+// writtenObject decodes the object returned by the API after writing it,
+// with the fields set by the API such as its ID and its creation time. It
+// returns nil if the API returned no object.
+func writtenObject[T Object](namespace string, written json.RawMessage) (*T, error) {
+	if written == nil {
+		return nil, nil
+	}
+
+	object := new(T)
+	if err := json.Unmarshal(written, object); err != nil {
+		return nil, fmt.Errorf("cannot decode %s object: %w", namespace, err)
+	}
+
+	return object, nil
+}
+
+// Create creates an object of type T in its namespace, using a POST request,
+// and returns the object created as returned by the API, with the ID given to
+// it, its creation time and its status. The ID of the given object, the fields
+// set by the API and embedded objects are not sent. The object is validated
+// first, as done by Validate:
+//
+//	netfac, err := peeringdb.Create(ctx, api, &peeringdb.NetworkFacility{NetworkID: 1, FacilityID: 2})
+//
+// Like the other writes, it requires an API key allowed to write the object
+// and goes through the same authentication, retries and error handling as
+// lookups.
+func Create[T Object](ctx context.Context, api *API, object *T) (*T, error) {
+	namespace := namespaceOf[T]()
+	if object == nil {
+		return nil, fmt.Errorf("cannot create %s object: nil object", namespace)
+	}
+
+	fields, err := writableFields(object)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s object: %w", namespace, err)
+	}
+
+	created, err := api.write(ctx, http.MethodPost, namespace, 0, objectFields(fields), requiredFields[namespace])
+	if err != nil {
+		return nil, err
+	}
+
+	return writtenObject[T](namespace, created)
+}
+
+// Update replaces the object of type T having the ID of the given object with
+// it, using a PUT request, and returns the object updated as returned by the
+// API. Fields set by the API and embedded objects are not sent. The object is
+// validated first, as done by Validate. ErrMissingID is returned if the
+// object has no ID.
+func Update[T Object](ctx context.Context, api *API, object *T) (*T, error) {
+	namespace := namespaceOf[T]()
+	if object == nil || idOf(object) <= 0 {
+		return nil, fmt.Errorf("cannot update %s object: %w", namespace, ErrMissingID)
+	}
+
+	fields, err := writableFields(object)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s object: %w", namespace, err)
+	}
+
+	updated, err := api.write(ctx, http.MethodPut, namespace, idOf(object), objectFields(fields), requiredFields[namespace])
+	if err != nil {
+		return nil, err
+	}
+
+	return writtenObject[T](namespace, updated)
+}
+
+// Patch changes the given fields of the object of type T having the given ID,
+// using a PATCH request, and returns the object updated as returned by the
+// API. Unlike Update, which sends all fields of an object, it leaves the
+// fields which are not given as they are, so that changes made meanwhile, in
+// the web interface for instance, are not overwritten. Fields are indexed by
+// their names in the API. No call is made, and nil is returned, if no field is
+// given. ErrMissingID is returned if the ID is not a valid one.
+func Patch[T Object](ctx context.Context, api *API, id int, fields map[string]interface{}) (*T, error) {
+	namespace := namespaceOf[T]()
+	if id <= 0 {
+		return nil, fmt.Errorf("cannot patch %s object: %w", namespace, ErrMissingID)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	patched, err := api.write(ctx, http.MethodPatch, namespace, id, fields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return writtenObject[T](namespace, patched)
+}
+
+// Delete deletes the object of type T having the given ID, using a DELETE
+// request. Deleted objects are kept by PeeringDB with the "deleted" status.
+// ErrMissingID is returned if the ID is not a valid one.
+func Delete[T Object](ctx context.Context, api *API, id int) error {
+	namespace := namespaceOf[T]()
+	if id <= 0 {
+		return fmt.Errorf("cannot delete %s object: %w", namespace, ErrMissingID)
+	}
+
+	_, err := api.write(ctx, http.MethodDelete, namespace, id, nil, nil)
+	return err
+}
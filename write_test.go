@@ -0,0 +1,116 @@
+package peeringdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestWritableFields(t *testing.T) {
+	fields, err := writableFields(&Facility{ID: 1, Name: "Facility", OrganizationID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["name"] != "Facility" || fields["org_id"] != float64(2) {
+		t.Errorf("writableFields, unexpected fields %v", fields)
+	}
+	for _, field := range []string{"id", "org", "created", "updated", "netfac_set"} {
+		if _, ok := fields[field]; ok {
+			t.Errorf("writableFields, unexpected %s field", field)
+		}
+	}
+}
+
+func TestCreateDelete(t *testing.T) {
+	server := newTestServer(t, map[string][]map[string]interface{}{
+		networkFacilityNamespace: {{"id": 1, "net_id": 1, "fac_id": 1}},
+	})
+	api := server.api()
+	ctx := context.Background()
+
+	returned, err := Create(ctx, api, &NetworkFacility{ID: 1, NetworkID: 1, FacilityID: 2, LocalASN: 64500})
+	if err != nil || returned == nil || returned.ID != 2 || returned.LocalASN != 64500 {
+		t.Fatalf("Create, unexpected result %v, %v", returned, err)
+	}
+	id := returned.ID
+	created, err := api.GetNetworkFacilityByID(id)
+	if err != nil || created == nil || created.FacilityID != 2 || created.LocalASN != 64500 {
+		t.Fatalf("Create, unexpected object created %v, %v", created, err)
+	}
+
+	created.LocalASN = 64501
+	if returned, err = Update(ctx, api, created); err != nil || returned == nil || returned.LocalASN != 64501 {
+		t.Fatalf("Update, unexpected result %v, %v", returned, err)
+	}
+	if updated, _ := api.GetNetworkFacilityByID(id); updated == nil || updated.LocalASN != 64501 {
+		t.Errorf("Update, unexpected object %v", updated)
+	}
+
+	if err = Delete[NetworkFacility](ctx, api, id); err != nil {
+		t.Fatalf("Delete, unexpected error %v", err)
+	}
+	if deleted, _ := api.GetNetworkFacilityByID(id); deleted != nil {
+		t.Errorf("Delete, object still found %v", deleted)
+	}
+	if err = Delete[NetworkFacility](ctx, api, 0); !errors.Is(err, ErrMissingID) {
+		t.Errorf("Delete, expected ErrMissingID, got %v", err)
+	}
+}
+
+func TestWriteURLBuilder(t *testing.T) {
+	var (
+		mutex sync.Mutex
+		urls  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		urls = append(urls, r.Method+" "+r.URL.String())
+		mutex.Unlock()
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": 2, "net_id": 1, "fac_id": 2, "local_asn": 64500}]}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	// Writes follow the layout of a versioned builder
+	api := NewAPIFromURL(server.URL+"/api/", WithURLBuilder(StandardURLBuilder{Version: "v2"}))
+	if _, err := Create(ctx, api, &NetworkFacility{NetworkID: 1, FacilityID: 2, LocalASN: 64500}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Update(ctx, api, &NetworkFacility{ID: 2, NetworkID: 1, FacilityID: 2, LocalASN: 64500}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete[NetworkFacility](ctx, api, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// A custom builder gives write URLs without its search parameters
+	api = NewAPIFromURL(server.URL+"/api/", WithURLBuilder(URLBuilderFunc(func(base, namespace string, search map[string]interface{}) string {
+		return base + "v3/" + namespace + "?format=json"
+	})))
+	if err := Delete[NetworkFacility](ctx, api, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST /api/v2/netfac",
+		"PUT /api/v2/netfac/2",
+		"DELETE /api/v2/netfac/2",
+		"DELETE /api/v3/netfac/2",
+	}
+	if len(urls) != len(expected) {
+		t.Fatalf("write, want URLs %v got %v", expected, urls)
+	}
+	for i := range expected {
+		if urls[i] != expected[i] {
+			t.Errorf("write, want URL '%s' got '%s'", expected[i], urls[i])
+		}
+	}
+}